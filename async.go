@@ -0,0 +1,219 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+// DefaultPollInterval is the wait between status checks of PollStatus when no
+// interval is given.
+const DefaultPollInterval = 2 * time.Second
+
+// Defaults of WaitForFinalStatus.
+const (
+	DefaultWaitInitialDelay = time.Second
+	DefaultWaitMaxDelay     = 30 * time.Second
+	DefaultWaitTimeout      = 2 * time.Minute
+)
+
+// WaitOption configures WaitForFinalStatus.
+type WaitOption func(*waitConfig)
+
+type waitConfig struct {
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	timeout      time.Duration
+	isFinal      func(*platon.Response) bool
+	runOpts      []RunOption
+}
+
+// WithWaitBackoff sets the delay before the second status check and the cap
+// of the delay, which doubles after every pending answer.
+func WithWaitBackoff(initial, max time.Duration) WaitOption {
+	return func(c *waitConfig) {
+		c.initialDelay = initial
+		c.maxDelay = max
+	}
+}
+
+// WithWaitTimeout bounds the whole wait by d. Zero or less waits until the
+// context is done.
+func WithWaitTimeout(d time.Duration) WaitOption {
+	return func(c *waitConfig) {
+		c.timeout = d
+	}
+}
+
+// WithWaitPredicate replaces the check that decides whether a status response
+// is final. The default is isFinalStatus: everything but
+// platon.Response.IsPending, plus a completed HOLD.
+func WithWaitPredicate(isFinal func(*platon.Response) bool) WaitOption {
+	return func(c *waitConfig) {
+		c.isFinal = isFinal
+	}
+}
+
+// WithWaitRunOptions passes RunOption values to every status call.
+func WithWaitRunOptions(opts ...RunOption) WaitOption {
+	return func(c *waitConfig) {
+		c.runOpts = append(c.runOpts, opts...)
+	}
+}
+
+func collectWaitOptions(opts []WaitOption) waitConfig {
+	cfg := waitConfig{
+		initialDelay: DefaultWaitInitialDelay,
+		maxDelay:     DefaultWaitMaxDelay,
+		timeout:      DefaultWaitTimeout,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.initialDelay <= 0 {
+		cfg.initialDelay = DefaultWaitInitialDelay
+	}
+	if cfg.maxDelay < cfg.initialDelay {
+		cfg.maxDelay = cfg.initialDelay
+	}
+
+	return cfg
+}
+
+// PaymentAsync sends a SALE with async=Y. Platon answers before the payment is
+// processed, so the response carries order_id and trans_id but usually not
+// the final status; follow up with PollStatus or the callback.
+func (c *client) PaymentAsync(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.PaymentAsyncWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) PaymentAsyncWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.payment(ctx, request, true, "payment async", runOpts)
+}
+
+// PollStatus calls Status every interval until the transaction is no longer
+// pending (see platon.Response.IsPending) or is a completed HOLD, Status
+// fails, or timeout elapses.
+// On timeout it returns the last response together with an error wrapping
+// context.DeadlineExceeded. A zero interval uses DefaultPollInterval and a
+// zero timeout only stops when ctx is done.
+func (c *client) PollStatus(ctx context.Context, request *Request, interval, timeout time.Duration) (*platon.Response, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	return c.waitForStatus(ctx, request, waitConfig{initialDelay: interval, maxDelay: interval, timeout: timeout}, "poll status")
+}
+
+// WaitForFinalStatus checks Status until the response is final, with a delay
+// that starts at DefaultWaitInitialDelay and doubles up to
+// DefaultWaitMaxDelay. Status uses GET_TRANS_STATUS when
+// PaymentData.PlatonTransID is set and GET_TRANS_STATUS_BY_ORDER otherwise.
+// A completed HOLD (status PENDING with a successful SALE) is final. A DECLINED or ERROR answer ends the wait with its error. After
+// DefaultWaitTimeout, or when ctx is done, it returns the last pending
+// response together with an error wrapping ctx.Err() or
+// context.DeadlineExceeded.
+func (c *client) WaitForFinalStatus(ctx context.Context, request *Request, opts ...WaitOption) (*platon.Response, error) {
+	return c.waitForStatus(ctx, request, collectWaitOptions(opts), "wait for final status")
+}
+
+func (c *client) waitForStatus(ctx context.Context, request *Request, cfg waitConfig, op string) (*platon.Response, error) {
+	if request == nil {
+		return nil, fmt.Errorf("%s: %w", op, platon.ErrRequestIsNil)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	isFinal := cfg.isFinal
+	if isFinal == nil {
+		isFinal = isFinalStatus
+	}
+
+	waitCtx := ctx
+	if cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, cfg.timeout)
+		defer cancel()
+	}
+
+	var last *platon.Response
+	delay := cfg.initialDelay
+	for {
+		response, err := c.StatusWithContext(waitCtx, request, cfg.runOpts...)
+		if err != nil {
+			if waitCtx.Err() != nil {
+				return last, waitTimeoutError(ctx, waitCtx, cfg.timeout, op)
+			}
+			return response, fmt.Errorf("%s: %w", op, err)
+		}
+		if isFinal(response) {
+			return response, nil
+		}
+		last = response
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-waitCtx.Done():
+			timer.Stop()
+			return last, waitTimeoutError(ctx, waitCtx, cfg.timeout, op)
+		case <-timer.C:
+		}
+
+		if delay *= 2; delay > cfg.maxDelay {
+			delay = cfg.maxDelay
+		}
+	}
+}
+
+// isFinalStatus reports whether polling can stop at response. A completed
+// HOLD also reports status PENDING, so a pending hold whose SALE succeeded
+// (see platon.Response.IsPendingHold) is final as well.
+func isFinalStatus(response *platon.Response) bool {
+	if !response.IsPending() {
+		return true
+	}
+
+	return response.IsPendingHold() && response.HasSuccessfulSale()
+}
+
+// waitTimeoutError tells a cancelled caller context apart from the timeout of
+// the wait itself.
+func waitTimeoutError(ctx, waitCtx context.Context, timeout time.Duration, op string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s: still pending after %v: %w", op, timeout, context.DeadlineExceeded)
+	}
+
+	return fmt.Errorf("%s: %w", op, waitCtx.Err())
+}
@@ -0,0 +1,307 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+func TestPaymentAsync_SendsAsyncFlag(t *testing.T) {
+	var async string
+	cl := NewClient(
+		WithClock(testClock),
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(r *http.Request) (*http.Response, error) {
+						if err := r.ParseForm(); err != nil {
+							return nil, err
+						}
+						async = r.PostForm.Get("async")
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(`{"action":"SALE","result":"ACCEPTED","status":"PENDING","order_id":"order-1","trans_id":"trans-1"}`)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
+	req := newCardPANPaymentRequest()
+	req.Merchant.ClientIP = ref("203.0.113.10")
+
+	resp, err := cl.PaymentAsync(req)
+	if err != nil {
+		t.Fatalf("PaymentAsync() error: %v", err)
+	}
+	if async != "Y" {
+		t.Fatalf("async = %q, want Y", async)
+	}
+	if resp.OrderId == nil || *resp.OrderId != "order-1" || resp.TransId == nil || *resp.TransId != "trans-1" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	if _, err := cl.Payment(req); err != nil {
+		t.Fatalf("Payment() error: %v", err)
+	}
+	if async != "" {
+		t.Fatalf("Payment() must not send async, got %q", async)
+	}
+}
+
+func pollTransport(pending int32, calls *atomic.Int32) roundTripperFunc {
+	return func(r *http.Request) (*http.Response, error) {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		if action := r.PostForm.Get("action"); action != "GET_TRANS_STATUS_BY_ORDER" {
+			return nil, errors.New("unexpected action " + action)
+		}
+
+		body := `{"action":"GET_TRANS_STATUS_BY_ORDER","result":"ACCEPTED","status":"PENDING","order_id":"order-1","trans_id":"trans-1"}`
+		if calls.Add(1) > pending {
+			body = `{"action":"GET_TRANS_STATUS_BY_ORDER","result":"ACCEPTED","status":"SETTLED","order_id":"order-1","trans_id":"trans-1"}`
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+}
+
+func newPollRequest() *Request {
+	return &Request{
+		Merchant:    &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+		PaymentData: &PaymentData{PaymentID: ref("order-1")},
+	}
+}
+
+func TestPollStatus_PendingTwiceThenAccepted(t *testing.T) {
+	var calls atomic.Int32
+	cl := NewClient(WithClient(&http.Client{Transport: pollTransport(2, &calls)}))
+
+	resp, err := cl.PollStatus(context.Background(), newPollRequest(), time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatalf("PollStatus() error: %v", err)
+	}
+	if resp.Status == nil || *resp.Status != "SETTLED" {
+		t.Fatalf("expected SETTLED status, got %+v", resp)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("expected 3 status calls, got %d", got)
+	}
+}
+
+func TestPollStatus_TimeoutReturnsLastResponse(t *testing.T) {
+	var calls atomic.Int32
+	cl := NewClient(WithClient(&http.Client{Transport: pollTransport(1000, &calls)}))
+
+	resp, err := cl.PollStatus(context.Background(), newPollRequest(), 5*time.Millisecond, 30*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if resp == nil || !resp.IsPending() {
+		t.Fatalf("expected the last pending response, got %+v", resp)
+	}
+}
+
+func TestPollStatus_ContextCancelled(t *testing.T) {
+	var calls atomic.Int32
+	cl := NewClient(WithClient(&http.Client{Transport: pollTransport(1000, &calls)}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := cl.PollStatus(ctx, newPollRequest(), time.Hour, 0)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("cancellation should stop the wait, took %v", elapsed)
+	}
+}
+
+func TestWaitForFinalStatus_PendingTwiceThenAccepted(t *testing.T) {
+	var mu sync.Mutex
+	var actions []string
+	var arrivals []time.Time
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+
+				mu.Lock()
+				actions = append(actions, r.PostForm.Get("action"))
+				arrivals = append(arrivals, time.Now())
+				n := len(actions)
+				mu.Unlock()
+
+				status := "3DS"
+				if n == 2 {
+					status = "PENDING"
+				} else if n > 2 {
+					status = "SETTLED"
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"action":"GET_TRANS_STATUS","result":"ACCEPTED","status":"` + status + `","order_id":"order-1","trans_id":"trans-1"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	cl := NewClient(WithBaseURL(srv.URL))
+	request := &Request{
+		Merchant:     &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+		PersonalData: &PersonalData{Email: ref("payer@example.com")},
+		PaymentData:  &PaymentData{PlatonTransID: ref("trans-1")},
+	}
+
+	resp, err := cl.WaitForFinalStatus(context.Background(), request, WithWaitBackoff(10*time.Millisecond, time.Second))
+	if err != nil {
+		t.Fatalf("WaitForFinalStatus() error: %v", err)
+	}
+	if resp.Status == nil || *resp.Status != "SETTLED" {
+		t.Fatalf("expected SETTLED status, got %+v", resp)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(actions) != 3 {
+		t.Fatalf("expected 3 status calls, got %v", actions)
+	}
+	for _, action := range actions {
+		if action != "GET_TRANS_STATUS" {
+			t.Fatalf("a request with PlatonTransID must use GET_TRANS_STATUS, got %v", actions)
+		}
+	}
+	// The second delay doubles the first one.
+	if first, second := arrivals[1].Sub(arrivals[0]), arrivals[2].Sub(arrivals[1]); first < 10*time.Millisecond || second < 20*time.Millisecond {
+		t.Fatalf("expected exponential backoff, got delays %v and %v", first, second)
+	}
+}
+
+func TestWaitForFinalStatus_TimeoutReturnsLastResponse(t *testing.T) {
+	var calls atomic.Int32
+	cl := NewClient(WithClient(&http.Client{Transport: pollTransport(1000, &calls)}))
+
+	resp, err := cl.WaitForFinalStatus(
+		context.Background(), newPollRequest(),
+		WithWaitBackoff(time.Millisecond, 5*time.Millisecond),
+		WithWaitTimeout(40*time.Millisecond),
+	)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if resp == nil || !resp.IsPending() {
+		t.Fatalf("expected the last pending response, got %+v", resp)
+	}
+	if calls.Load() < 2 {
+		t.Fatalf("expected several status calls, got %d", calls.Load())
+	}
+}
+
+func TestWaitForFinalStatus_CompletedHoldIsFinal(t *testing.T) {
+	var calls atomic.Int32
+	cl := NewClient(
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(*http.Request) (*http.Response, error) {
+						body := `{"action":"GET_TRANS_STATUS_BY_ORDER","result":"ACCEPTED","status":"PENDING","order_id":"order-1","trans_id":"trans-1"}`
+						if calls.Add(1) > 1 {
+							body = `{"action":"GET_TRANS_STATUS_BY_ORDER","result":"ACCEPTED","status":"PENDING","order_id":"order-1","trans_id":"trans-1",` +
+								`"transactions":[{"type":"SALE","status":"SUCCESS","amount":"1.00"}]}`
+						}
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(body)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
+	resp, err := cl.WaitForFinalStatus(context.Background(), newPollRequest(), WithWaitBackoff(time.Millisecond, time.Millisecond), WithWaitTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("WaitForFinalStatus() error: %v", err)
+	}
+	if !resp.IsPendingHold() || !resp.HasSuccessfulSale() {
+		t.Fatalf("expected the authorized hold, got %+v", resp)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected 2 status calls (pending, then the authorized hold), got %d", got)
+	}
+}
+
+func TestWaitForFinalStatus_PredicateAndDecline(t *testing.T) {
+	var calls atomic.Int32
+	cl := NewClient(WithClient(&http.Client{Transport: pollTransport(1000, &calls)}))
+
+	resp, err := cl.WaitForFinalStatus(
+		context.Background(), newPollRequest(),
+		WithWaitPredicate(func(*platon.Response) bool { return true }),
+	)
+	if err != nil || resp == nil || calls.Load() != 1 {
+		t.Fatalf("custom predicate should accept the first answer, got %+v, %v after %d calls", resp, err, calls.Load())
+	}
+
+	declined := NewClient(
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(*http.Request) (*http.Response, error) {
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(`{"result":"DECLINED","decline_reason":"Insufficient funds"}`)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+	if _, err := declined.WaitForFinalStatus(context.Background(), newPollRequest()); !errors.Is(err, platon.ErrDeclined) {
+		t.Fatalf("expected the decline to end the wait, got %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+// BatchResult is the outcome of a single request in a batch call.
+type BatchResult struct {
+	// Index is the position of the request in the input slice.
+	Index int
+	// Request is the input request.
+	Request *Request
+	// Response is the gateway response (may be set together with Err on declines).
+	Response *platon.Response
+	// Err is the error for this request, if any.
+	Err error
+}
+
+// CreditResult is the outcome of one payout in CreditBatch.
+type CreditResult = BatchResult
+
+// Succeeded reports whether the request completed without error.
+func (r BatchResult) Succeeded() bool {
+	return r.Err == nil
+}
+
+// BatchError summarizes a batch call with at least one failed request.
+// Inspect the per-request results for details.
+type BatchError struct {
+	Op        string
+	Succeeded int
+	Failed    int
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%s: %d succeeded, %d failed", e.Op, e.Succeeded, e.Failed)
+}
+
+// ErrOutcomeUnknown marks a batch result whose call panicked. The request may
+// or may not have reached Platon, so check its status before sending it again.
+var ErrOutcomeUnknown = errors.New("outcome unknown")
+
+// OutcomeUnknownError is the Err of a batch result whose call panicked.
+// It matches ErrOutcomeUnknown with errors.Is.
+type OutcomeUnknownError struct {
+	// Panic is the value recovered from the call.
+	Panic any
+}
+
+func (e *OutcomeUnknownError) Error() string {
+	return fmt.Sprintf("outcome unknown: batch item panicked: %v", e.Panic)
+}
+
+func (e *OutcomeUnknownError) Is(target error) bool {
+	return target == ErrOutcomeUnknown
+}
+
+// CreditBatch sends CREDIT2CARD payouts with at most concurrency requests in flight.
+//
+// Results are returned in input order. A failed request never stops the rest of
+// the batch; requests not started before ctx is done fail with ctx.Err().
+// Every call goes through the client's recorder and observer like a single
+// Credit. The returned error is a *BatchError when any request failed.
+//
+// A payout whose call panicked fails with *OutcomeUnknownError: it may have
+// been sent, so do not re-pay it without checking its status first.
+func (c *client) CreditBatch(ctx context.Context, requests []*Request, concurrency int, runOpts ...RunOption) ([]CreditResult, error) {
+	results := runBatch(
+		ctx, requests, concurrency, func(_ context.Context, request *Request) (*platon.Response, error) {
+			return c.CreditWithContext(ctx, request, runOpts...)
+		},
+	)
+
+	return results, summarizeBatch("credit batch", results)
+}
+
+// DefaultBatchConcurrency is the number of StatusBatch requests in flight
+// when WithBatchConcurrency is not set.
+const DefaultBatchConcurrency = 5
+
+// BatchOption configures StatusBatch.
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	concurrency int
+	timeout     time.Duration
+	runOpts     []RunOption
+}
+
+// WithBatchConcurrency sets how many requests are in flight at once.
+func WithBatchConcurrency(n int) BatchOption {
+	return func(c *batchConfig) {
+		c.concurrency = n
+	}
+}
+
+// WithBatchTimeout bounds every single request in the batch by d.
+func WithBatchTimeout(d time.Duration) BatchOption {
+	return func(c *batchConfig) {
+		c.timeout = d
+	}
+}
+
+// WithBatchRunOptions passes RunOption values (e.g. DryRun) to every call in
+// the batch. Handlers may be called from several goroutines at once.
+func WithBatchRunOptions(opts ...RunOption) BatchOption {
+	return func(c *batchConfig) {
+		c.runOpts = append(c.runOpts, opts...)
+	}
+}
+
+func collectBatchOptions(opts []BatchOption) batchConfig {
+	cfg := batchConfig{concurrency: DefaultBatchConcurrency}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.concurrency <= 0 {
+		cfg.concurrency = DefaultBatchConcurrency
+	}
+
+	return cfg
+}
+
+func (c *client) StatusBatch(requests []*Request, opts ...BatchOption) ([]*platon.Response, []error) {
+	return c.StatusBatchWithContext(context.Background(), requests, opts...)
+}
+
+// StatusBatchWithContext sends GET_TRANS_STATUS_BY_ORDER for every request
+// through a worker pool (DefaultBatchConcurrency by default).
+//
+// Responses and errors are indexed like requests; a failed request never stops
+// the rest of the batch. Once ctx is done no new request is sent and the
+// remaining ones fail with ctx.Err().
+func (c *client) StatusBatchWithContext(ctx context.Context, requests []*Request, opts ...BatchOption) ([]*platon.Response, []error) {
+	cfg := collectBatchOptions(opts)
+
+	results := runBatch(
+		ctx, requests, cfg.concurrency, func(ctx context.Context, request *Request) (*platon.Response, error) {
+			if cfg.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+				defer cancel()
+			}
+			return c.StatusWithContext(ctx, request, cfg.runOpts...)
+		},
+	)
+
+	responses := make([]*platon.Response, len(results))
+	errs := make([]error, len(results))
+	for idx, result := range results {
+		responses[idx] = result.Response
+		errs[idx] = result.Err
+	}
+
+	return responses, errs
+}
+
+// runBatch calls fn for every request using a bounded worker pool.
+func runBatch(
+	ctx context.Context,
+	requests []*Request,
+	concurrency int,
+	fn func(context.Context, *Request) (*platon.Response, error),
+) []BatchResult {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if concurrency > len(requests) {
+		concurrency = len(requests)
+	}
+
+	results := make([]BatchResult, len(requests))
+	for idx, request := range requests {
+		results[idx] = BatchResult{Index: idx, Request: request}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				if err := ctx.Err(); err != nil {
+					results[idx].Err = err
+					continue
+				}
+				results[idx].Response, results[idx].Err = callBatchItem(ctx, results[idx].Request, fn)
+			}
+		}()
+	}
+
+	for idx := range requests {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func callBatchItem(
+	ctx context.Context,
+	request *Request,
+	fn func(context.Context, *Request) (*platon.Response, error),
+) (response *platon.Response, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			response, err = nil, &OutcomeUnknownError{Panic: recovered}
+		}
+	}()
+
+	return fn(ctx, request)
+}
+
+func summarizeBatch(op string, results []BatchResult) error {
+	summary := &BatchError{Op: op}
+	for _, result := range results {
+		if result.Succeeded() {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+	if summary.Failed == 0 {
+		return nil
+	}
+
+	return summary
+}
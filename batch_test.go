@@ -0,0 +1,447 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stremovskyy/go-platon/currency"
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+func newBatchCreditRequest(orderID string) *Request {
+	return &Request{
+		Merchant: &Merchant{
+			MerchantKey: "CLIENT_KEY",
+			SecretKey:   "CLIENT_PASS",
+		},
+		PaymentData: &PaymentData{
+			PaymentID:   ref(orderID),
+			Amount:      100,
+			Currency:    currency.UAH,
+			Description: "nightly payout",
+		},
+		PaymentMethod: &PaymentMethod{
+			Card: &Card{Token: ref("CARD_TOKEN")},
+		},
+	}
+}
+
+type batchObserver struct {
+	started atomic.Int32
+	ended   atomic.Int32
+}
+
+func (o *batchObserver) OnRequestStart(RequestEvent) { o.started.Add(1) }
+
+func (o *batchObserver) OnRequestEnd(RequestEvent, time.Duration, int, error) { o.ended.Add(1) }
+
+func TestCreditBatch_BoundedConcurrencyWithObserverAndRecorder(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+
+	observer := &batchObserver{}
+	rec := &tagRecorder{}
+	cl := NewClient(
+		WithObserver(observer),
+		WithRecorder(rec),
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(req *http.Request) (*http.Response, error) {
+						current := inFlight.Add(1)
+						defer inFlight.Add(-1)
+						for {
+							seen := maxInFlight.Load()
+							if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+								break
+							}
+						}
+						time.Sleep(10 * time.Millisecond)
+
+						body, _ := io.ReadAll(req.Body)
+						values, _ := url.ParseQuery(string(body))
+
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"result":"ACCEPTED","order_id":%q}`, values.Get("order_id")))),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
+	requests := make([]*Request, 5)
+	for i := range requests {
+		requests[i] = newBatchCreditRequest(fmt.Sprintf("payout-%d", i))
+	}
+
+	var results []CreditResult
+	results, err := cl.CreditBatch(context.Background(), requests, 2)
+	if err != nil {
+		t.Fatalf("CreditBatch() error: %v", err)
+	}
+	if got := maxInFlight.Load(); got != 2 {
+		t.Fatalf("expected at most 2 requests in flight (and some overlap), got %d", got)
+	}
+	for idx, result := range results {
+		if !result.Succeeded() || result.Index != idx || result.Request != requests[idx] {
+			t.Fatalf("result %d out of order or failed: %+v", idx, result)
+		}
+		if result.Response.OrderId == nil || *result.Response.OrderId != fmt.Sprintf("payout-%d", idx) {
+			t.Fatalf("result %d has the wrong response: %+v", idx, result.Response)
+		}
+	}
+	if observer.started.Load() != 5 || observer.ended.Load() != 5 {
+		t.Fatalf("observer saw %d starts and %d ends, want 5 each", observer.started.Load(), observer.ended.Load())
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.requests) != 5 || len(rec.responses) != 5 {
+		t.Fatalf("recorder saw %d requests and %d responses, want 5 each", len(rec.requests), len(rec.responses))
+	}
+}
+
+func TestCreditBatch_MixedOutcomes(t *testing.T) {
+	var inFlight, maxInFlight int32
+	var mu sync.Mutex
+
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(
+			func(req *http.Request) (*http.Response, error) {
+				current := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				mu.Lock()
+				if current > maxInFlight {
+					maxInFlight = current
+				}
+				mu.Unlock()
+				time.Sleep(5 * time.Millisecond)
+
+				body, _ := io.ReadAll(req.Body)
+				values, _ := url.ParseQuery(string(body))
+				orderID := values.Get("order_id")
+
+				var payload string
+				switch {
+				case strings.HasSuffix(orderID, "-declined"):
+					payload = `{"result":"DECLINED","decline_reason":"Insufficient funds"}`
+				case strings.HasSuffix(orderID, "-down"):
+					return nil, errors.New("connection reset")
+				default:
+					payload = fmt.Sprintf(`{"result":"ACCEPTED","order_id":%q}`, orderID)
+				}
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(payload)),
+				}, nil
+			},
+		),
+	}
+
+	orderIDs := []string{"p-1", "p-2-declined", "p-3", "p-4-down", "p-5", "p-6", "p-7-declined"}
+	requests := make([]*Request, 0, len(orderIDs)+1)
+	for _, orderID := range orderIDs {
+		requests = append(requests, newBatchCreditRequest(orderID))
+	}
+	requests = append(requests, nil)
+
+	cl := NewClient(WithClient(httpClient))
+	results, err := cl.CreditBatch(context.Background(), requests, 3)
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected *BatchError, got %v", err)
+	}
+	if batchErr.Succeeded != 4 || batchErr.Failed != 4 {
+		t.Fatalf("summary mismatch: got %q", batchErr.Error())
+	}
+	if batchErr.Error() != "credit batch: 4 succeeded, 4 failed" {
+		t.Fatalf("summary message mismatch: got %q", batchErr.Error())
+	}
+	if maxInFlight > 3 {
+		t.Fatalf("concurrency exceeded: max in flight %d", maxInFlight)
+	}
+
+	if len(results) != len(requests) {
+		t.Fatalf("results length mismatch: want %d, got %d", len(requests), len(results))
+	}
+	for idx, result := range results {
+		if result.Index != idx || result.Request != requests[idx] {
+			t.Fatalf("result %d is out of order", idx)
+		}
+		if idx == len(orderIDs) {
+			if result.Succeeded() {
+				t.Fatalf("expected nil request to fail")
+			}
+			continue
+		}
+
+		orderID := orderIDs[idx]
+		wantSuccess := !strings.HasSuffix(orderID, "-declined") && !strings.HasSuffix(orderID, "-down")
+		if result.Succeeded() != wantSuccess {
+			t.Fatalf("%s: success mismatch: want %v, got err=%v", orderID, wantSuccess, result.Err)
+		}
+		if wantSuccess && (result.Response == nil || result.Response.OrderId == nil || *result.Response.OrderId != orderID) {
+			t.Fatalf("%s: unexpected response %+v", orderID, result.Response)
+		}
+	}
+}
+
+func TestCreditBatch_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cl := NewDefaultClient()
+	results, err := cl.CreditBatch(ctx, []*Request{newBatchCreditRequest("c-1"), newBatchCreditRequest("c-2")}, 2, DryRun())
+	if err == nil {
+		t.Fatalf("expected batch error")
+	}
+	for _, result := range results {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", result.Err)
+		}
+	}
+}
+
+func TestCreditBatch_PanicIsOutcomeUnknown(t *testing.T) {
+	cl := NewClient(
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(req *http.Request) (*http.Response, error) {
+						body, _ := io.ReadAll(req.Body)
+						if strings.Contains(string(body), "order_id=c-2") {
+							panic("transport exploded")
+						}
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(`{"action":"CREDIT2CARD","result":"SUCCESS","status":"SETTLED","order_id":"c-1","trans_id":"t-1"}`)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
+	results, err := cl.CreditBatch(context.Background(), []*Request{newBatchCreditRequest("c-1"), newBatchCreditRequest("c-2")}, 2)
+	if err == nil {
+		t.Fatalf("expected batch error")
+	}
+	if results[0].Err != nil {
+		t.Fatalf("first payout: unexpected error %v", results[0].Err)
+	}
+	if !errors.Is(results[1].Err, ErrOutcomeUnknown) {
+		t.Fatalf("second payout: expected ErrOutcomeUnknown, got %v", results[1].Err)
+	}
+	var unknown *OutcomeUnknownError
+	if !errors.As(results[1].Err, &unknown) || unknown.Panic != "transport exploded" {
+		t.Fatalf("expected *OutcomeUnknownError with the panic value, got %#v", results[1].Err)
+	}
+	if results[1].Response != nil {
+		t.Fatalf("expected no response for the panicked payout, got %+v", results[1].Response)
+	}
+}
+
+func newBatchStatusRequest(orderID string) *Request {
+	return &Request{
+		Merchant: &Merchant{
+			MerchantKey: "CLIENT_KEY",
+			SecretKey:   "CLIENT_PASS",
+		},
+		PaymentData: &PaymentData{PaymentID: ref(orderID)},
+	}
+}
+
+func TestStatusBatch_BoundedConcurrencyAndOrdering(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				current := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					seen := maxInFlight.Load()
+					if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+						break
+					}
+				}
+				_ = r.ParseForm()
+				orderID := r.PostForm.Get("order_id")
+				// Later orders answer first, so completion order differs from input order.
+				if strings.HasSuffix(orderID, "-1") {
+					time.Sleep(20 * time.Millisecond)
+				} else {
+					time.Sleep(5 * time.Millisecond)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				if strings.HasSuffix(orderID, "-missing") {
+					_, _ = fmt.Fprint(w, `{"result":"ERROR","error_message":"Order not found"}`)
+					return
+				}
+				_, _ = fmt.Fprintf(w, `{"status":"SALE","order_id":%q}`, orderID)
+			},
+		),
+	)
+	defer server.Close()
+
+	orderIDs := []string{"s-1", "s-2", "s-3-missing", "s-4", "s-5", "s-6", "s-7", "s-8"}
+	requests := make([]*Request, 0, len(orderIDs)+1)
+	for _, orderID := range orderIDs {
+		requests = append(requests, newBatchStatusRequest(orderID))
+	}
+	requests = append(requests, nil)
+
+	cl := NewClient(WithBaseURL(server.URL))
+	responses, errs := cl.StatusBatch(requests, WithBatchConcurrency(3))
+
+	if got := maxInFlight.Load(); got > 3 || got < 2 {
+		t.Fatalf("max in flight = %d, want between 2 and 3", got)
+	}
+	if len(responses) != len(requests) || len(errs) != len(requests) {
+		t.Fatalf("outputs length mismatch: %d responses, %d errors", len(responses), len(errs))
+	}
+	for idx, orderID := range orderIDs {
+		if strings.HasSuffix(orderID, "-missing") {
+			if errs[idx] == nil {
+				t.Fatalf("%s: expected error", orderID)
+			}
+			continue
+		}
+		if errs[idx] != nil {
+			t.Fatalf("%s: unexpected error %v", orderID, errs[idx])
+		}
+		if responses[idx] == nil || responses[idx].OrderId == nil || *responses[idx].OrderId != orderID {
+			t.Fatalf("%s: response out of order: %+v", orderID, responses[idx])
+		}
+	}
+	if errs[len(orderIDs)] == nil {
+		t.Fatal("expected nil request to fail")
+	}
+}
+
+func TestStatusBatch_DefaultsAndPerRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			},
+		),
+	)
+	defer server.Close()
+	defer close(release)
+
+	cl := NewClient(WithBaseURL(server.URL))
+	_, errs := cl.StatusBatch(
+		[]*Request{newBatchStatusRequest("t-1"), newBatchStatusRequest("t-2")},
+		WithBatchTimeout(20*time.Millisecond),
+	)
+	for idx, err := range errs {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("request %d: error = %v, want context.DeadlineExceeded", idx, err)
+		}
+	}
+
+	if cfg := collectBatchOptions(nil); cfg.concurrency != DefaultBatchConcurrency {
+		t.Fatalf("default concurrency = %d, want %d", cfg.concurrency, DefaultBatchConcurrency)
+	}
+}
+
+func TestStatusBatch_CancelledContextStopsScheduling(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls atomic.Int32
+	cl := NewClient(
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(*http.Request) (*http.Response, error) {
+						calls.Add(1)
+						return nil, errors.New("unexpected call")
+					},
+				),
+			},
+		),
+	)
+	_, errs := cl.StatusBatchWithContext(ctx, []*Request{newBatchStatusRequest("c-1"), newBatchStatusRequest("c-2")})
+	for idx, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("request %d: error = %v, want context.Canceled", idx, err)
+		}
+	}
+	if got := calls.Load(); got != 0 {
+		t.Fatalf("sent %d requests after cancellation", got)
+	}
+}
+
+func TestStatusBatch_DryRunCapturesEveryRequest(t *testing.T) {
+	var mu sync.Mutex
+	captured := make(map[string]bool)
+
+	cl := NewDefaultClient()
+	_, errs := cl.StatusBatch(
+		[]*Request{newBatchStatusRequest("d-1"), newBatchStatusRequest("d-2"), newBatchStatusRequest("d-3")},
+		WithBatchRunOptions(
+			DryRun(
+				func(_ string, payload any) {
+					if request, ok := payload.(*platon.Request); ok && request.OrderID != nil {
+						mu.Lock()
+						captured[*request.OrderID] = true
+						mu.Unlock()
+					}
+				},
+			),
+		),
+	)
+	for idx, err := range errs {
+		if err != nil {
+			t.Fatalf("request %d: unexpected error %v", idx, err)
+		}
+	}
+	if len(captured) != 3 || !captured["d-1"] || !captured["d-2"] || !captured["d-3"] {
+		t.Fatalf("captured = %v, want d-1..d-3", captured)
+	}
+}
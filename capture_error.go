@@ -0,0 +1,65 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import "fmt"
+
+// AlreadyCapturedError is returned by Capture when Platon rejects a CAPTURE as
+// already captured but GET_TRANS_STATUS does not confirm that the requested
+// amount was captured.
+type AlreadyCapturedError struct {
+	TransID string
+	// Requested is the amount of the rejected CAPTURE in minor units.
+	Requested int
+	// Captured is the amount GET_TRANS_STATUS reports as captured in minor
+	// units. It is only meaningful when CapturedKnown is true.
+	Captured      int
+	CapturedKnown bool
+	// Err is the status lookup error, or the CAPTURE error when the lookup
+	// succeeded.
+	Err error
+}
+
+func (e *AlreadyCapturedError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+	if e.CapturedKnown {
+		return fmt.Sprintf(
+			"capture: transaction %s already captured with amount %d, requested %d (minor units)",
+			e.TransID, e.Captured, e.Requested,
+		)
+	}
+
+	return fmt.Sprintf("capture: transaction %s reported as already captured, but the captured amount could not be confirmed: %v", e.TransID, e.Err)
+}
+
+func (e *AlreadyCapturedError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+
+	return e.Err
+}
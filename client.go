@@ -26,17 +26,22 @@ package go_platon
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
-	"github.com/stremovskyy/go-platon/consts"
+	"github.com/stremovskyy/go-platon/currency"
 	internalhttp "github.com/stremovskyy/go-platon/internal/http"
+	"github.com/stremovskyy/go-platon/internal/utils"
 	"github.com/stremovskyy/go-platon/log"
 	"github.com/stremovskyy/go-platon/platon"
 	"github.com/stremovskyy/recorder"
@@ -44,6 +49,19 @@ import (
 
 type client struct {
 	platonClient *internalhttp.Client
+
+	defaultCurrency currency.Code
+	phonePrefixes   []string
+	clock           platon.Clock
+	credentialStore CredentialStore
+	idempotency     *IdempotencyConfig
+	logSink         log.Sink
+	endpoints       *Endpoints
+	endpointsErr    error
+	// submerchantCache is nil unless WithSubmerchantCache is set.
+	submerchantCache *submerchantCache
+	// clockSkewThreshold is set with WithClockSkewWarnThreshold.
+	clockSkewThreshold time.Duration
 }
 
 var _ Platon = (*client)(nil)
@@ -52,6 +70,11 @@ const (
 	platonMetaFlow = "platon_flow"
 	platonFlowA2C  = "a2c"
 
+	platonMetaRecurringFirstTransID = "recurring_first_trans_id"
+	recurringExt3                   = "recurring"
+	platonMetaReqToken              = "req_token"
+	platonMetaRecurringInit         = "recurring_init"
+
 	defaultA2CFirstName = "Payer"
 	defaultA2CLastName  = "Cardholder"
 	defaultA2CAddress   = "N/A"
@@ -65,6 +88,11 @@ func (c *client) SetLogLevel(levelDebug log.Level) {
 	log.SetLevel(levelDebug)
 }
 
+// Stats returns a snapshot of the client's rate limiters for monitoring.
+func (c *client) Stats() ClientStats {
+	return c.platonClient.Stats()
+}
+
 func NewDefaultClient() Platon {
 	return NewClient()
 }
@@ -74,62 +102,78 @@ func NewClientWithRecorder(rec recorder.Recorder) Platon {
 }
 
 func (c *client) Verification(request *Request, runOpts ...RunOption) (*url.URL, error) {
+	return c.VerificationWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) VerificationWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*url.URL, error) {
 	if request == nil {
 		return nil, platon.ErrRequestIsNil
 	}
 
-	form, err := BuildClientServerVerificationForm(request)
+	opts := collectRunOptions(runOpts)
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	request, err = opts.withTokenizationMetadata(request)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := c.paymentAuthURL()
+	if err != nil {
+		return nil, err
+	}
+	form, err := buildClientServerVerificationForm(c.applyDefaults(request), endpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	opts := collectRunOptions(runOpts)
 	if opts.isDryRun() {
-		opts.handleDryRun(consts.ApiPaymentAuthURL, form)
+		opts.handleDryRun(endpoint, form)
 		return nil, nil
 	}
 
-	return resolveClientServerVerificationURL(form)
+	return resolveClientServerVerificationURL(ctx, form, c.platonClient, c.logSink)
 }
 
 func (c *client) VerificationLink(request *Request, runOpts ...RunOption) (*url.URL, error) {
 	return c.Verification(request, runOpts...)
 }
 
+func (c *client) VerificationLinkWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*url.URL, error) {
+	return c.VerificationWithContext(ctx, request, runOpts...)
+}
+
 func (c *client) Status(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.StatusWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) StatusWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
 	if request == nil {
 		return nil, platon.ErrRequestIsNil
 	}
 
 	opts := collectRunOptions(runOpts)
 
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
 	transID := request.GetPlatonTransID()
 	if transID != nil && strings.TrimSpace(*transID) != "" {
-		statusRequest := platon.NewRequest(platon.ActionCodeGetTransStatus).
-			WithAuth(request.GetAuth()).
-			WithClientKey(request.GetMerchantKey()).
-			WithTransID(transID).
-			WithHashEmail(request.GetPayerEmail()).
-			SignForAction(platon.HashTypeGetTransStatus)
-
-		if opts.isDryRun() {
-			opts.handleDryRun(consts.ApiGetTransStatus, statusRequest)
-			return nil, nil
-		}
-
-		return c.platonClient.Api(statusRequest, consts.ApiGetTransStatus)
+		return c.transStatus(ctx, request, transID, opts, "status")
 	}
 
 	orderID := request.GetPaymentID()
 	if orderID == nil || strings.TrimSpace(*orderID) == "" {
-		return nil, fmt.Errorf("status: order_id is required (set PaymentData.PaymentID) or use PaymentData.PlatonTransID for GET_TRANS_STATUS")
+		return nil, platon.NewValidationError("status", "order_id", "is required (set PaymentData.PaymentID) or use PaymentData.PlatonTransID for GET_TRANS_STATUS")
 	}
 
 	isA2C := isA2CStatusRequest(request)
 	statusHashType := platon.HashTypeGetTransStatusByOrder
-	statusURL := consts.ApiGetTransStatus
 	if isA2C {
-		statusURL = consts.ApiP2PUnqURL
 		statusHashType = platon.HashTypeGetTransStatusByOrderA2C
 	}
 
@@ -139,27 +183,155 @@ func (c *client) Status(request *Request, runOpts ...RunOption) (*platon.Respons
 		WithOrderID(orderID).
 		SignForAction(statusHashType)
 
+	statusURL, err := c.endpointFor(statusRequest)
+	if err != nil {
+		return nil, fmt.Errorf("status: %w", err)
+	}
+
 	if opts.isDryRun() {
-		opts.handleDryRun(statusURL, statusRequest)
+		c.dryRun(ctx, opts, statusURL, statusRequest)
 		return nil, nil
 	}
 
-	return c.platonClient.Api(statusRequest, statusURL)
+	response, err := c.api(ctx, statusRequest, statusURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("status API call: %w", err)
+	}
+
+	return response, nil
 }
 
-func (c *client) SubmerchantAvailableForSplit(request *Request, runOpts ...RunOption) (bool, error) {
+func (c *client) StatusByTransID(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.StatusByTransIDWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) StatusByTransIDWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
 	if request == nil {
-		return false, platon.ErrRequestIsNil
+		return nil, platon.ErrRequestIsNil
+	}
+
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	transID := request.GetPlatonTransID()
+	if transID == nil || strings.TrimSpace(*transID) == "" {
+		return nil, platon.NewValidationError("status by trans id", "trans_id", "is required (set PaymentData.PlatonTransID)")
+	}
+
+	return c.transStatus(ctx, request, transID, collectRunOptions(runOpts), "status by trans id")
+}
+
+// transStatus sends GET_TRANS_STATUS for transID, over /p2p-unq/ when the
+// request carries the A2C flow metadata.
+func (c *client) transStatus(ctx context.Context, request *Request, transID *string, opts *runOptions, op string) (*platon.Response, error) {
+	statusHashType := platon.HashTypeGetTransStatus
+	if isA2CStatusRequest(request) {
+		statusHashType = platon.HashTypeGetTransStatusA2C
+	}
+
+	statusRequest := platon.NewRequest(platon.ActionCodeGetTransStatus).
+		WithAuth(request.GetAuth()).
+		WithClientKey(request.GetMerchantKey()).
+		WithTransID(transID).
+		WithHashEmail(request.GetPayerEmail()).
+		SignForAction(statusHashType)
+
+	statusURL, err := c.endpointFor(statusRequest)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if opts.isDryRun() {
+		c.dryRun(ctx, opts, statusURL, statusRequest)
+		return nil, nil
+	}
+
+	response, err := c.api(ctx, statusRequest, statusURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s API call: %w", op, err)
+	}
+
+	return response, nil
+}
+
+// Details sends GET_TRANS_DETAILS for PaymentData.PlatonTransID. Besides the
+// status it reports the card mask, payer name and fee of the transaction.
+func (c *client) Details(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.DetailsWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) DetailsWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	if request == nil {
+		return nil, platon.ErrRequestIsNil
 	}
 
 	opts := collectRunOptions(runOpts)
 
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := request.PaymentData.RequireIDs(platon.ActionCodeGetTransDetails); err != nil {
+		return nil, fmt.Errorf("details: %w", err)
+	}
 	if request.GetMerchantKey() == "" {
-		return false, fmt.Errorf("split availability: merchant client_key is required")
+		return nil, platon.NewClientKeyRequiredError("details")
+	}
+
+	apiRequest := platon.NewRequest(platon.ActionCodeGetTransDetails).
+		WithAuth(request.GetAuth()).
+		WithClientKey(request.GetMerchantKey()).
+		WithTransID(request.GetPlatonTransID()).
+		WithHashEmail(request.GetPayerEmail()).
+		SignForAction(platon.HashTypeGetTransDetails)
+
+	apiURL, err := c.endpointFor(apiRequest)
+	if err != nil {
+		return nil, fmt.Errorf("details: %w", err)
+	}
+
+	if opts.isDryRun() {
+		c.dryRun(ctx, opts, apiURL, apiRequest)
+		return nil, nil
+	}
+
+	response, err := c.api(ctx, apiRequest, apiURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("details API call: %w", err)
+	}
+
+	return response, nil
+}
+
+func (c *client) Submerchant(request *Request, runOpts ...RunOption) (*SubmerchantInfo, error) {
+	return c.SubmerchantWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) SubmerchantWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*SubmerchantInfo, error) {
+	if request == nil {
+		return nil, platon.ErrRequestIsNil
+	}
+
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.submerchant(ctx, request, collectRunOptions(runOpts), "submerchant")
+}
+
+// submerchant sends GET_SUBMERCHANT for the resolved request. It returns a
+// nil SubmerchantInfo in dry-run mode.
+func (c *client) submerchant(ctx context.Context, request *Request, opts *runOptions, op string) (*SubmerchantInfo, error) {
+	if request.GetMerchantKey() == "" {
+		return nil, platon.NewClientKeyRequiredError(op)
 	}
 	submerchantID := request.GetSubmerchantID()
 	if submerchantID == nil || *submerchantID == "" {
-		return false, fmt.Errorf("split availability: submerchant_id is required")
+		return nil, platon.NewValidationError(op, "submerchant_id", "is required")
 	}
 
 	apiRequest := platon.NewRequest(platon.ActionCodeGetSubmerchant).
@@ -168,310 +340,907 @@ func (c *client) SubmerchantAvailableForSplit(request *Request, runOpts ...RunOp
 		WithSubmerchantID(submerchantID).
 		SignForAction(platon.HashTypeGetSubmerchant)
 
+	apiURL, err := c.endpointFor(apiRequest)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	if opts.isDryRun() {
-		opts.handleDryRun(consts.ApiGetSubmerchant, apiRequest)
-		return false, nil
+		c.dryRun(ctx, opts, apiURL, apiRequest)
+		return nil, nil
 	}
 
-	response, err := c.platonClient.Api(apiRequest, consts.ApiGetSubmerchant)
+	response, err := c.api(ctx, apiRequest, apiURL, opts)
 	if err != nil {
-		return false, fmt.Errorf("split availability API call: %w", err)
+		return nil, fmt.Errorf("%s API call: %w", op, err)
 	}
-	if response == nil {
-		return false, fmt.Errorf("split availability: empty response")
+
+	return submerchantInfo(response, *submerchantID, op)
+}
+
+func (c *client) SubmerchantAvailableForSplit(request *Request, runOpts ...RunOption) (bool, error) {
+	return c.SubmerchantAvailableForSplitWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) SubmerchantAvailableForSplitWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (bool, error) {
+	if request == nil {
+		return false, platon.ErrRequestIsNil
 	}
 
-	status, ok := response.SubmerchantIDStatus()
-	if ok {
-		switch strings.ToUpper(strings.TrimSpace(status)) {
-		case "ENABLED":
-			return true, nil
-		case "DISABLED", "LOCKED":
-			return false, nil
-		default:
-			return false, fmt.Errorf("split availability: unknown submerchant_id_status %q", status)
-		}
+	opts := collectRunOptions(runOpts)
+
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return false, err
 	}
 
-	if response.Status != nil {
-		apiStatus := strings.ToUpper(strings.TrimSpace(*response.Status))
-		if apiStatus == "FAILED" {
-			return false, fmt.Errorf("split availability: request failed (status=FAILED)")
+	submerchantID := request.GetSubmerchantID()
+	if !opts.isDryRun() && submerchantID != nil {
+		if available, ok := c.submerchantCache.get(request.GetMerchantKey(), *submerchantID); ok {
+			return available, nil
 		}
-		if apiStatus != "" {
-			return false, fmt.Errorf("split availability: response status %q without submerchant_id_status", apiStatus)
+	}
+
+	info, err := c.submerchant(ctx, request, opts, "split availability")
+	if err != nil || info == nil {
+		return false, err
+	}
+	if !info.Status.IsKnown() {
+		return false, fmt.Errorf("split availability: unknown submerchant_id_status %q", info.RawStatus)
+	}
+
+	available := info.Status.IsSplitEligible()
+	c.submerchantCache.put(request.GetMerchantKey(), *submerchantID, available)
+
+	return available, nil
+}
+
+// checkSplitSubmerchants checks every split rules submerchant of apiRequest
+// with GET_SUBMERCHANT when ValidateSplitSubmerchants is set.
+func (c *client) checkSplitSubmerchants(ctx context.Context, request *Request, apiRequest *platon.Request, opts *runOptions, op string) error {
+	if !opts.shouldValidateSplitSubmerchants() || len(apiRequest.SplitRules) == 0 {
+		return nil
+	}
+
+	submerchantIDs := make([]string, 0, len(apiRequest.SplitRules))
+	for submerchantID := range apiRequest.SplitRules {
+		submerchantIDs = append(submerchantIDs, submerchantID)
+	}
+	sort.Strings(submerchantIDs)
+
+	var notEnabled []string
+	var errs []error
+	for _, submerchantID := range submerchantIDs {
+		available, err := c.SubmerchantAvailableForSplitWithContext(
+			ctx, &Request{
+				Merchant:    request.Merchant,
+				PaymentData: &PaymentData{SubmerchantID: &submerchantID},
+			},
+		)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("submerchant %s: %w", submerchantID, err))
+		case !available:
+			notEnabled = append(notEnabled, submerchantID)
 		}
 	}
 
-	return false, fmt.Errorf("split availability: response does not contain submerchant_id_status")
+	if len(notEnabled) > 0 {
+		errs = append(errs, fmt.Errorf("split submerchants are not enabled: %s", strings.Join(notEnabled, ", ")))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s: %w", op, errors.Join(errs...))
+	}
+
+	return nil
 }
 
 func (c *client) Payment(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.PaymentWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) PaymentWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.payment(ctx, request, false, "payment", runOpts)
+}
+
+// payment sends a SALE; async asks Platon to answer before the payment is
+// processed (async=Y).
+func (c *client) payment(ctx context.Context, request *Request, async bool, op string, runOpts []RunOption) (*platon.Response, error) {
+	if request == nil {
+		return nil, platon.ErrRequestIsNil
+	}
+
+	opts := collectRunOptions(runOpts)
+
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	apiRequest, apiURL, err := c.buildIAPaymentRequest(request, false)
+	if err != nil {
+		return nil, err
+	}
+	if async {
+		apiRequest.UseAsync()
+	}
+
+	if opts.isDryRun() {
+		c.dryRun(ctx, opts, apiURL, apiRequest)
+		return nil, nil
+	}
+	if err := requireRealPayerIP(apiRequest, op); err != nil {
+		return nil, err
+	}
+	if err := c.checkSplitSubmerchants(ctx, request, apiRequest, opts, op); err != nil {
+		return nil, err
+	}
+
+	response, err := c.api(ctx, apiRequest, apiURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s API call: %w", op, err)
+	}
+
+	return response, nil
+}
+
+func (c *client) PaymentByCard(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.PaymentByCardWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) PaymentByCardWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	if request == nil {
+		return nil, fmt.Errorf("payment by card: %w", platon.ErrRequestIsNil)
+	}
+
+	opts := collectRunOptions(runOpts)
+
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	request = c.applyDefaults(request)
+
+	splitRules, err := checkIAPaymentRequest(request, "payment by card")
+	if err != nil {
+		return nil, err
+	}
+	if pan := request.GetCardPan(); pan == nil || strings.TrimSpace(*pan) == "" {
+		return nil, platon.NewValidationError("payment by card", "card_number", "is required (set PaymentMethod.Card.Pan)")
+	}
+	if err := checkCardPANData(request, "payment by card"); err != nil {
+		return nil, err
+	}
+
+	apiRequest := buildCardPANRequest(request, splitRules, false)
+
+	apiURL, err := c.endpointFor(apiRequest)
+	if err != nil {
+		return nil, fmt.Errorf("payment by card: %w", err)
+	}
+
+	if opts.isDryRun() {
+		c.dryRun(ctx, opts, apiURL, apiRequest)
+		return nil, nil
+	}
+	if err := requireRealPayerIP(apiRequest, "payment by card"); err != nil {
+		return nil, err
+	}
+	if err := c.checkSplitSubmerchants(ctx, request, apiRequest, opts, "payment by card"); err != nil {
+		return nil, err
+	}
+
+	response, err := c.api(ctx, apiRequest, apiURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("payment by card API call: %w", err)
+	}
+
+	return response, nil
+}
+
+func (c *client) Hold(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.HoldWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) HoldWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
 	if request == nil {
 		return nil, platon.ErrRequestIsNil
 	}
 
-	opts := collectRunOptions(runOpts)
+	opts := collectRunOptions(runOpts)
+
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	apiRequest, apiURL, err := c.buildIAPaymentRequest(request, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.isDryRun() {
+		c.dryRun(ctx, opts, apiURL, apiRequest)
+		return nil, nil
+	}
+	if err := requireRealPayerIP(apiRequest, "hold"); err != nil {
+		return nil, err
+	}
+	if err := c.checkSplitSubmerchants(ctx, request, apiRequest, opts, "hold"); err != nil {
+		return nil, err
+	}
+
+	response, err := c.api(ctx, apiRequest, apiURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("hold API call: %w", err)
+	}
+
+	return response, nil
+}
+
+func (c *client) buildIAPaymentRequest(request *Request, hold bool) (*platon.Request, string, error) {
+	request = c.applyDefaults(request)
+	splitRules, err := checkIAPaymentRequest(request, "payment")
+	if err != nil {
+		return nil, "", err
+	}
+
+	common := func(action platon.ActionCode) *platon.Request {
+		return newIAPaymentRequest(request, action, hold)
+	}
+
+	switch request.PaymentMethodKind() {
+	case PaymentMethodApplePay:
+		container, err := request.GetAppleContainer()
+		if err != nil {
+			return nil, "", fmt.Errorf("payment: cannot get Apple Pay container: %w", err)
+		}
+		apiRequest := common(platon.ActionCodeAPPLEPAY).
+			WithPaymentToken(container).
+			WithSplitRules(splitRules).
+			SignForAction(platon.HashTypeApplePay)
+		return c.withEndpoint(apiRequest)
+
+	case PaymentMethodGooglePay:
+		token, info, err := request.GetGooglePayDetails()
+		if err != nil {
+			return nil, "", fmt.Errorf("payment: cannot get Google Pay token: %w", err)
+		}
+		apiRequest := common(platon.ActionCodeGOOGLEPAY).
+			WithPaymentToken(token).
+			WithCardNetwork(info.CardNetwork).
+			WithSplitRules(splitRules).
+			SignForAction(platon.HashTypeGooglePay)
+		return c.withEndpoint(apiRequest)
+
+	case PaymentMethodCardToken:
+		// One-click by CARD_TOKEN.
+		apiRequest := common(platon.ActionCodeSALE).
+			WithCardToken(request.GetCardToken()).
+			WithSplitRules(splitRules).
+			SignForAction(platon.HashTypeCardTokenPayment)
+		return c.withEndpoint(apiRequest)
+
+	case PaymentMethodCardPAN:
+		if err := checkCardPANData(request, "payment"); err != nil {
+			return nil, "", err
+		}
+		return c.withEndpoint(buildCardPANRequest(request, splitRules, hold))
+	}
+
+	return nil, "", platon.NewValidationError("payment", "PaymentMethod", "is unsupported (expected CARD_TOKEN, card PAN, Apple Pay, or Google Pay data)")
+}
+
+// requireRealPayerIP rejects payer_ip values that are missing, unparsable,
+// loopback or unspecified. It is checked for real payments only: DryRun and
+// tests may rely on the 127.0.0.1 fallback of WithPayerIP, but Platon fraud
+// scoring needs the payer's address.
+func requireRealPayerIP(apiRequest *platon.Request, op string) error {
+	const hint = "set PaymentData.PayerIP, set Merchant.ClientIP or use Merchant.SetClientIPFromHTTP"
+
+	if apiRequest == nil || apiRequest.PayerIp == nil || strings.TrimSpace(*apiRequest.PayerIp) == "" {
+		return platon.NewValidationError(op, "payer_ip", fmt.Sprintf("is required (%s)", hint))
+	}
+
+	ip := net.ParseIP(strings.TrimSpace(*apiRequest.PayerIp))
+	if ip == nil {
+		return platon.NewValidationError(op, "payer_ip", fmt.Sprintf("%q is not a valid IP address (%s)", *apiRequest.PayerIp, hint))
+	}
+	if ip.IsLoopback() || ip.IsUnspecified() {
+		return platon.NewValidationError(op, "payer_ip", fmt.Sprintf("%q is not a real client address (%s)", *apiRequest.PayerIp, hint))
+	}
+
+	return nil
+}
+
+// applyDefaults returns request with client-level defaults (currently the
+// default currency) filled in. The caller's request is never modified.
+func (c *client) applyDefaults(request *Request) *Request {
+	if c == nil || c.defaultCurrency == "" || request == nil || request.PaymentData == nil {
+		return request
+	}
+	if request.PaymentData.Currency != "" {
+		return request
+	}
+
+	withDefaults := *request
+	paymentData := *request.PaymentData
+	paymentData.Currency = c.defaultCurrency
+	withDefaults.PaymentData = &paymentData
+
+	return &withDefaults
+}
+
+// checkIAPaymentRequest runs the checks shared by every IA SALE flow and
+// returns the parsed split rules. op prefixes the returned errors.
+func checkIAPaymentRequest(request *Request, op string) (platon.SplitRules, error) {
+	if request == nil {
+		return nil, platon.ErrRequestIsNil
+	}
+	if request.PaymentData == nil {
+		return nil, platon.NewValidationError(op, "PaymentData", "is nil")
+	}
+	if request.GetMerchantKey() == "" {
+		return nil, platon.NewClientKeyRequiredError(op)
+	}
+	if err := request.PaymentData.RequireIDs(platon.ActionCodeSALE); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := checkExtFields(request, op); err != nil {
+		return nil, err
+	}
+	if request.GetCurrency() == "" {
+		return nil, platon.NewValidationError(op, "order_currency", "is required")
+	}
+	if request.GetDescription() == "" {
+		return nil, platon.NewValidationError(op, "order_description", "is required")
+	}
+	splitRules, err := request.GetSplitRules()
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid split rules: %w", op, err)
+	}
+
+	return splitRules, nil
+}
+
+// newIAPaymentRequest fills the fields common to IA SALE flows.
+func newIAPaymentRequest(request *Request, action platon.ActionCode, hold bool) *platon.Request {
+	base := platon.NewRequest(action).
+		WithAuth(request.GetAuth()).
+		WithClientKey(request.GetMerchantKey()).
+		WithOrderID(request.GetPaymentID()).
+		WithOrderAmountMinorUnits(request.PaymentData.Amount).
+		ForCurrency(request.GetCurrency()).
+		WithDescription(request.GetDescription()).
+		WithPayerIP(request.GetClientIP()).
+		WithTermsURL(request.GetTermsURL()).
+		WithPayerEmail(request.GetPayerEmail()).
+		WithPayerPhone(request.GetPayerPhone())
+
+	if request.PersonalData != nil {
+		base.WithPayerFirstName(request.PersonalData.FirstName).
+			WithPayerLastName(request.PersonalData.LastName)
+	}
+
+	applyExtFields(base, request)
+
+	if hold {
+		base.WithHoldAuth()
+	}
+
+	return base
+}
+
+// buildCardPANRequest builds a signed SALE by card PAN. req_token and
+// recurring_init are taken from the metadata flags of the same names.
+func buildCardPANRequest(request *Request, splitRules platon.SplitRules, hold bool) *platon.Request {
+	metadata := request.GetMetadata()
+
+	return newIAPaymentRequest(request, platon.ActionCodeSALE, hold).
+		WithCardNumber(request.GetCardPan()).
+		WithCardExpiry(utils.SafeString(request.GetCardExpMonth()), utils.SafeString(request.GetCardExpYear())).
+		WithCardCvv2(request.GetCardCvv2()).
+		WithReqToken(metadataFlag(metadata, platonMetaReqToken)).
+		WithRecurringInitFlag(metadataFlag(metadata, platonMetaRecurringInit)).
+		WithSplitRules(splitRules).
+		SignForAction(platon.HashTypeCardPayment)
+}
+
+// metadataFlag reports whether metadata[key] is "Y"/"yes"/"true"/"1".
+func metadataFlag(metadata map[string]string, key string) bool {
+	switch strings.ToUpper(strings.TrimSpace(metadata[key])) {
+	case "Y", "YES", "TRUE", "1":
+		return true
+	default:
+		return false
+	}
+}
+
+// checkCardPANData reports missing expiration or CVV2 for a card PAN payment.
+func checkCardPANData(request *Request, op string) error {
+	if value := request.GetCardExpMonth(); value == nil || strings.TrimSpace(*value) == "" {
+		return platon.NewValidationError(op, "card_exp_month", "is required (set PaymentMethod.Card.ExpirationMonth)")
+	}
+	if value := request.GetCardExpYear(); value == nil || strings.TrimSpace(*value) == "" {
+		return platon.NewValidationError(op, "card_exp_year", "is required (set PaymentMethod.Card.ExpirationYear)")
+	}
+	if value := request.GetCardCvv2(); value == nil || strings.TrimSpace(*value) == "" {
+		return platon.NewValidationError(op, "card_cvv2", "is required (set PaymentMethod.Card.Cvv2)")
+	}
+
+	return nil
+}
+
+func (c *client) withEndpoint(apiRequest *platon.Request) (*platon.Request, string, error) {
+	endpoint, err := c.endpointFor(apiRequest)
+	if err != nil {
+		return nil, "", fmt.Errorf("payment: %w", err)
+	}
+
+	return apiRequest, endpoint, nil
+}
+
+func (c *client) Recurring(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.RecurringWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) RecurringWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	if request == nil {
+		return nil, fmt.Errorf("recurring: %w", platon.ErrRequestIsNil)
+	}
+
+	opts := collectRunOptions(runOpts)
+
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	request = c.applyDefaults(request)
+
+	splitRules, err := checkIAPaymentRequest(request, "recurring")
+	if err != nil {
+		return nil, err
+	}
+	if request.PaymentData.Amount <= 0 {
+		return nil, platon.NewValidationError("recurring", "PaymentData.Amount", "(minor units) must be > 0")
+	}
+	token := request.GetCardToken()
+	if token == nil || strings.TrimSpace(*token) == "" {
+		return nil, platon.NewValidationError("recurring", "card_token", "is required (set PaymentMethod.Card.Token)")
+	}
+	if request.GetPayerEmail() == nil || strings.TrimSpace(*request.GetPayerEmail()) == "" {
+		return nil, platon.NewValidationError("recurring", "payer_email", "is required")
+	}
+	firstTransID := request.GetRecurringFirstTransID()
+	if firstTransID == nil {
+		return nil, platon.NewValidationError("recurring", "recurring_first_trans_id", "is required (set PaymentData.RecurringFirstTransID)")
+	}
+
+	apiRequest := newIAPaymentRequest(request, platon.ActionCodeSALE, false).
+		WithCardToken(token).
+		WithExt3(utils.Ref(recurringExt3)).
+		WithRecurringFirstTransID(firstTransID).
+		WithSplitRules(splitRules).
+		SignForAction(platon.HashTypeRecurring)
 
-	apiRequest, apiURL, err := c.buildIAPaymentRequest(request, false)
+	apiURL, err := c.endpointFor(apiRequest)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("recurring: %w", err)
 	}
 
 	if opts.isDryRun() {
-		opts.handleDryRun(apiURL, apiRequest)
+		c.dryRun(ctx, opts, apiURL, apiRequest)
 		return nil, nil
 	}
 
-	response, err := c.platonClient.Api(apiRequest, apiURL)
+	response, err := c.api(ctx, apiRequest, apiURL, opts)
 	if err != nil {
-		return nil, fmt.Errorf("payment API call: %w", err)
+		return nil, fmt.Errorf("recurring API call: %w", err)
 	}
 
 	return response, nil
 }
 
-func (c *client) Hold(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+func (c *client) Capture(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.CaptureWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) CaptureWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
 	if request == nil {
-		return nil, platon.ErrRequestIsNil
+		return nil, fmt.Errorf("capture: %w", platon.ErrRequestIsNil)
 	}
 
 	opts := collectRunOptions(runOpts)
 
-	apiRequest, apiURL, err := c.buildIAPaymentRequest(request, true)
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := request.PaymentData.RequireIDs(platon.ActionCodeCAPTURE); err != nil {
+		return nil, fmt.Errorf("capture: %w", err)
+	}
+	transID := request.GetPlatonTransID()
+	if request.GetMerchantKey() == "" {
+		return nil, platon.NewClientKeyRequiredError("capture")
+	}
+	if request.PaymentData == nil {
+		return nil, platon.NewValidationError("capture", "PaymentData", "is nil")
+	}
+	if request.PaymentData.Amount <= 0 {
+		return nil, platon.NewValidationError("capture", "PaymentData.Amount", "(minor units) must be > 0")
+	}
+	if original := request.PaymentData.OriginalAmount; original != nil && request.PaymentData.Amount > *original {
+		return nil, platon.NewValidationError(
+			"capture", "amount",
+			fmt.Sprintf("%d exceeds original amount %d (minor units)", request.PaymentData.Amount, *original),
+		)
+	}
+	splitRules, err := request.GetSplitRules()
 	if err != nil {
+		return nil, fmt.Errorf("capture: invalid split rules: %w", err)
+	}
+
+	apiRequest := platon.NewRequest(platon.ActionCodeCAPTURE).
+		WithAuth(request.GetAuth()).
+		WithClientKey(request.GetMerchantKey()).
+		WithTransID(transID).
+		WithAmountMinorUnits(request.PaymentData.Amount).
+		WithOriginalAmount(request.PaymentData.OriginalAmount).
+		WithSplitRules(splitRules).
+		WithHashEmail(request.GetPayerEmail()).
+		SignForAction(platon.HashTypeCapture)
+	if err := checkExtFields(request, "capture"); err != nil {
 		return nil, err
 	}
+	applyExtFields(apiRequest, request)
+
+	apiURL, err := c.endpointFor(apiRequest)
+	if err != nil {
+		return nil, fmt.Errorf("capture: %w", err)
+	}
 
 	if opts.isDryRun() {
-		opts.handleDryRun(apiURL, apiRequest)
+		c.dryRun(ctx, opts, apiURL, apiRequest)
 		return nil, nil
 	}
 
-	response, err := c.platonClient.Api(apiRequest, apiURL)
+	response, err := c.api(ctx, apiRequest, apiURL, opts)
+	if err != nil && response.IsAlreadyCaptured() {
+		// A retried CAPTURE whose first attempt went through is not an error,
+		// but only once GET_TRANS_STATUS confirms the requested amount.
+		if err := c.confirmCaptured(ctx, request, err, runOpts); err != nil {
+			return response, err
+		}
+		return response, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("hold API call: %w", err)
+		return nil, fmt.Errorf("capture API call: %w", err)
 	}
 
 	return response, nil
 }
 
-func (c *client) buildIAPaymentRequest(request *Request, hold bool) (*platon.Request, string, error) {
-	if request == nil {
-		return nil, "", platon.ErrRequestIsNil
+// confirmCaptured looks up a transaction that Platon reported as already
+// captured and returns an *AlreadyCapturedError unless exactly
+// PaymentData.Amount was captured. captureErr is the CAPTURE error.
+func (c *client) confirmCaptured(ctx context.Context, request *Request, captureErr error, runOpts []RunOption) error {
+	mismatch := &AlreadyCapturedError{
+		TransID:   utils.SafeString(request.GetPlatonTransID()),
+		Requested: request.PaymentData.Amount,
+		Err:       captureErr,
 	}
-	if request.PaymentData == nil {
-		return nil, "", fmt.Errorf("payment: PaymentData is nil")
+
+	status, err := c.StatusByTransIDWithContext(ctx, request, runOpts...)
+	if err != nil {
+		mismatch.Err = err
+		return mismatch
 	}
-	if request.GetMerchantKey() == "" {
-		return nil, "", fmt.Errorf("payment: merchant client_key is required")
+	mismatch.Captured, mismatch.CapturedKnown = status.CapturedAmount()
+	if mismatch.CapturedKnown && mismatch.Captured == mismatch.Requested {
+		return nil
 	}
-	if request.GetPaymentID() == nil || *request.GetPaymentID() == "" {
-		return nil, "", fmt.Errorf("payment: order_id (PaymentData.PaymentID) is required")
+
+	return mismatch
+}
+
+// CaptureFull captures what is left of the hold on PaymentData.PlatonTransID.
+// The hold is read with GET_TRANS_STATUS first, so PaymentData.Amount is
+// ignored, and transactions that are not a pending hold are refused. With
+// DryRun the hold cannot be read, so the CAPTURE is built for
+// PaymentData.Amount.
+func (c *client) CaptureFull(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.CaptureFullWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) CaptureFullWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	if request == nil {
+		return nil, fmt.Errorf("capture full: %w", platon.ErrRequestIsNil)
 	}
-	if request.GetCurrency() == "" {
-		return nil, "", fmt.Errorf("payment: order_currency is required")
+	if request.PaymentData == nil {
+		return nil, platon.NewValidationError("capture full", "PaymentData", "is nil")
 	}
-	if request.GetDescription() == "" {
-		return nil, "", fmt.Errorf("payment: order_description is required")
+	transID := request.GetPlatonTransID()
+	if transID == nil || strings.TrimSpace(*transID) == "" {
+		return nil, platon.NewValidationError("capture full", "trans_id", "is required (set PaymentData.PlatonTransID)")
 	}
-	splitRules, err := request.GetSplitRules()
+
+	status, err := c.StatusByTransIDWithContext(ctx, request, runOpts...)
 	if err != nil {
-		return nil, "", fmt.Errorf("payment: invalid split rules: %w", err)
+		return nil, fmt.Errorf("capture full: %w", err)
 	}
-
-	common := func(action platon.ActionCode) *platon.Request {
-		base := platon.NewRequest(action).
-			WithAuth(request.GetAuth()).
-			WithClientKey(request.GetMerchantKey()).
-			WithOrderID(request.GetPaymentID()).
-			WithOrderAmountMinorUnits(request.PaymentData.Amount).
-			ForCurrency(request.GetCurrency()).
-			WithDescription(request.GetDescription()).
-			WithPayerIP(request.GetClientIP()).
-			WithTermsURL(request.GetTermsURL()).
-			WithPayerEmail(request.GetPayerEmail()).
-			WithPayerPhone(request.GetPayerPhone())
-
-		if request.PersonalData != nil {
-			base.WithPayerFirstName(request.PersonalData.FirstName).
-				WithPayerLastName(request.PersonalData.LastName)
-		}
-
-		applyExtFieldsFromMetadata(base, request.GetMetadata())
-
-		if hold {
-			base.WithHoldAuth()
-		}
-
-		return base
+	if collectRunOptions(runOpts).isDryRun() {
+		return c.CaptureWithContext(ctx, request, runOpts...)
 	}
-
-	// Mobile payments.
-	if request.IsApplePay() {
-		container, err := request.GetAppleContainer()
-		if err != nil {
-			return nil, "", fmt.Errorf("payment: cannot get Apple Pay container: %w", err)
-		}
-		apiRequest := common(platon.ActionCodeAPPLEPAY).
-			WithPaymentToken(container).
-			WithSplitRules(splitRules).
-			SignForAction(platon.HashTypeApplePay)
-		return apiRequest, consts.ApiPostURL, nil
+	if !status.IsPendingHold() {
+		return nil, fmt.Errorf(
+			"capture full: trans_id %q is not a pending hold (status %s)",
+			*transID, utils.SafeString(status.Status),
+		)
 	}
-
-	if request.PaymentMethod != nil && request.PaymentMethod.GoogleToken != nil {
-		token, err := request.GetGoogleToken()
-		if err != nil {
-			return nil, "", fmt.Errorf("payment: cannot get Google Pay token: %w", err)
-		}
-		apiRequest := common(platon.ActionCodeGOOGLEPAY).
-			WithPaymentToken(token).
-			WithSplitRules(splitRules).
-			SignForAction(platon.HashTypeGooglePay)
-		return apiRequest, consts.ApiPostURL, nil
+	held, ok := status.AuthAmount()
+	if !ok || held <= 0 {
+		return nil, fmt.Errorf("capture full: no held amount found for trans_id %q", *transID)
 	}
-
-	// One-click by CARD_TOKEN.
-	if token := request.GetCardToken(); token != nil && *token != "" {
-		apiRequest := common(platon.ActionCodeSALE).
-			WithCardToken(token).
-			WithSplitRules(splitRules).
-			SignForAction(platon.HashTypeCardTokenPayment)
-		return apiRequest, consts.ApiPostUnqURL, nil
+	remaining, ok := status.RemainingAuth()
+	if !ok || remaining <= 0 {
+		return nil, fmt.Errorf("capture full: nothing left to capture for trans_id %q", *transID)
 	}
 
-	return nil, "", fmt.Errorf("payment: unsupported payment method (expected CARD_TOKEN, Apple Pay, or Google Pay data)")
+	capture := *request
+	captureData := *request.PaymentData
+	captureData.Amount = remaining
+	captureData.OriginalAmount = &held
+	capture.PaymentData = &captureData
+
+	return c.CaptureWithContext(ctx, &capture, runOpts...)
 }
 
-func (c *client) Capture(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+func (c *client) Refund(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.RefundWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) RefundWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
 	if request == nil {
-		return nil, fmt.Errorf("capture: %w", platon.ErrRequestIsNil)
+		return nil, fmt.Errorf("refund: %w", platon.ErrRequestIsNil)
 	}
 
 	opts := collectRunOptions(runOpts)
 
-	transID := request.GetPlatonTransID()
-	if transID == nil || *transID == "" {
-		return nil, fmt.Errorf("capture: trans_id is required (set PaymentData.PlatonTransID or PaymentData.PlatonPaymentID)")
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return nil, err
 	}
+
+	if err := request.PaymentData.RequireIDs(platon.ActionCodeCREDITVOID); err != nil {
+		return nil, fmt.Errorf("refund: %w", err)
+	}
+	transID := request.GetPlatonTransID()
 	if request.GetMerchantKey() == "" {
-		return nil, fmt.Errorf("capture: merchant client_key is required")
+		return nil, platon.NewClientKeyRequiredError("refund")
 	}
 	if request.PaymentData == nil {
-		return nil, fmt.Errorf("capture: PaymentData is nil")
+		return nil, platon.NewValidationError("refund", "PaymentData", "is nil")
 	}
 	if request.PaymentData.Amount <= 0 {
-		return nil, fmt.Errorf("capture: PaymentData.Amount (minor units) must be > 0")
+		return nil, platon.NewValidationError("refund", "PaymentData.Amount", "(minor units) must be > 0")
 	}
 	splitRules, err := request.GetSplitRules()
 	if err != nil {
-		return nil, fmt.Errorf("capture: invalid split rules: %w", err)
+		return nil, fmt.Errorf("refund: invalid split rules: %w", err)
 	}
 
-	apiRequest := platon.NewRequest(platon.ActionCodeCAPTURE).
+	apiRequest := platon.NewRequest(platon.ActionCodeCREDITVOID).
 		WithAuth(request.GetAuth()).
 		WithClientKey(request.GetMerchantKey()).
 		WithTransID(transID).
 		WithAmountMinorUnits(request.PaymentData.Amount).
 		WithSplitRules(splitRules).
-		WithHashEmail(request.GetPayerEmail()).
-		SignForAction(platon.HashTypeCapture)
-	applyExtFieldsFromMetadata(apiRequest, request.GetMetadata())
+		WithHashEmail(request.GetPayerEmail())
+	if err := checkExtFields(request, "refund"); err != nil {
+		return nil, err
+	}
+	applyExtFields(apiRequest, request)
+
+	// Optional fast refund flag, set by the Immediately run option or, for
+	// backward compatibility, by PaymentData.Metadata["immediately"] = "Y"/"true"/"1".
+	// The run option wins when both are given.
+	if opts.immediatelyOr(metadataFlag(request.PaymentData.Metadata, "immediately")) {
+		apiRequest.WithImmediately(true)
+	}
+
+	apiRequest.SignForAction(platon.HashTypeCreditVoid)
+
+	apiURL, err := c.endpointFor(apiRequest)
+	if err != nil {
+		return nil, fmt.Errorf("refund: %w", err)
+	}
 
 	if opts.isDryRun() {
-		opts.handleDryRun(consts.ApiPostUnqURL, apiRequest)
+		c.dryRun(ctx, opts, apiURL, apiRequest)
+		return nil, nil
+	}
+
+	response, err := c.api(ctx, apiRequest, apiURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("refund API call: %w", err)
+	}
+
+	return response, nil
+}
+
+func (c *client) RefundByOrder(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.RefundByOrderWithContext(context.Background(), request, runOpts...)
+}
+
+// RefundByOrderWithContext resolves trans_id with GET_TRANS_STATUS_BY_ORDER
+// and then refunds it. With DryRun only the status lookup is reported, since
+// the refund cannot be built without the resolved trans_id.
+func (c *client) RefundByOrderWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	if request == nil {
+		return nil, fmt.Errorf("refund by order: %w", platon.ErrRequestIsNil)
+	}
+	if request.PaymentData == nil {
+		return nil, platon.NewValidationError("refund by order", "PaymentData", "is nil")
+	}
+	orderID := request.GetPaymentID()
+	if orderID == nil || strings.TrimSpace(*orderID) == "" {
+		return nil, platon.NewValidationError("refund by order", "order_id", "is required (set PaymentData.PaymentID)")
+	}
+
+	// Look up by order even if the caller also set a trans_id.
+	lookup := *request
+	lookupData := *request.PaymentData
+	lookupData.PlatonTransID = nil
+	lookupData.PlatonPaymentID = nil
+	lookup.PaymentData = &lookupData
+
+	status, err := c.StatusWithContext(ctx, &lookup, runOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("refund by order: %w", err)
+	}
+	if collectRunOptions(runOpts).isDryRun() {
 		return nil, nil
 	}
+	if status == nil || status.TransId == nil || strings.TrimSpace(*status.TransId) == "" {
+		return nil, fmt.Errorf("refund by order: no trans_id found for order_id %q", *orderID)
+	}
+
+	refund := *request
+	refundData := *request.PaymentData
+	refundData.PlatonTransID = utils.Ref(strings.TrimSpace(*status.TransId))
+	refundData.PlatonPaymentID = nil
+	refund.PaymentData = &refundData
 
-	return c.platonClient.Api(apiRequest, consts.ApiPostUnqURL)
+	return c.RefundWithContext(ctx, &refund, runOpts...)
 }
 
-func (c *client) Refund(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+// Void cancels a HOLD that was never captured. Unlike Refund it sends CREDITVOID
+// without amount, so PaymentData.Amount and split rules must be empty.
+func (c *client) Void(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.VoidWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) VoidWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
 	if request == nil {
-		return nil, fmt.Errorf("refund: %w", platon.ErrRequestIsNil)
+		return nil, fmt.Errorf("void: %w", platon.ErrRequestIsNil)
 	}
 
 	opts := collectRunOptions(runOpts)
 
-	transID := request.GetPlatonTransID()
-	if transID == nil || *transID == "" {
-		return nil, fmt.Errorf("refund: trans_id is required (set PaymentData.PlatonTransID or PaymentData.PlatonPaymentID)")
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return nil, err
 	}
-	if request.GetMerchantKey() == "" {
-		return nil, fmt.Errorf("refund: merchant client_key is required")
+
+	if err := request.PaymentData.RequireIDs(platon.ActionCodeCREDITVOID); err != nil {
+		return nil, fmt.Errorf("void: %w", err)
 	}
-	if request.PaymentData == nil {
-		return nil, fmt.Errorf("refund: PaymentData is nil")
+	if request.GetMerchantKey() == "" {
+		return nil, platon.NewClientKeyRequiredError("void")
 	}
-	if request.PaymentData.Amount <= 0 {
-		return nil, fmt.Errorf("refund: PaymentData.Amount (minor units) must be > 0")
+	if request.PaymentData.Amount != 0 {
+		return nil, platon.NewValidationError("void", "PaymentData.Amount", "must be empty when voiding a HOLD (use Refund to return a settled amount)")
 	}
-	splitRules, err := request.GetSplitRules()
-	if err != nil {
-		return nil, fmt.Errorf("refund: invalid split rules: %w", err)
+	if len(request.PaymentData.SplitRules) > 0 {
+		return nil, platon.NewValidationError("void", "split_rules", "are not supported")
 	}
 
 	apiRequest := platon.NewRequest(platon.ActionCodeCREDITVOID).
 		WithAuth(request.GetAuth()).
 		WithClientKey(request.GetMerchantKey()).
-		WithTransID(transID).
-		WithAmountMinorUnits(request.PaymentData.Amount).
-		WithSplitRules(splitRules).
-		WithHashEmail(request.GetPayerEmail())
-	applyExtFieldsFromMetadata(apiRequest, request.GetMetadata())
-
-	// Optional fast refund flag. If user sets PaymentData.Metadata["immediately"] to "Y"/"true"/"1",
-	// send `immediately=Y` as per IA docs.
-	if request.PaymentData.Metadata != nil {
-		switch strings.ToUpper(strings.TrimSpace(request.PaymentData.Metadata["immediately"])) {
-		case "Y", "YES", "TRUE", "1":
-			apiRequest.WithImmediately(true)
-		}
+		WithTransID(request.GetPlatonTransID()).
+		WithHashEmail(request.GetPayerEmail()).
+		ForVoid()
+	if err := checkExtFields(request, "void"); err != nil {
+		return nil, err
 	}
+	applyExtFields(apiRequest, request)
 
-	apiRequest.SignForAction(platon.HashTypeCreditVoid)
+	apiURL, err := c.endpointFor(apiRequest)
+	if err != nil {
+		return nil, fmt.Errorf("void: %w", err)
+	}
 
 	if opts.isDryRun() {
-		opts.handleDryRun(consts.ApiPostUnqURL, apiRequest)
+		c.dryRun(ctx, opts, apiURL, apiRequest)
 		return nil, nil
 	}
 
-	return c.platonClient.Api(apiRequest, consts.ApiPostUnqURL)
+	response, err := c.api(ctx, apiRequest, apiURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("void API call: %w", err)
+	}
+
+	return response, nil
 }
 
 func (c *client) Credit(request *Request, runOpts ...RunOption) (*platon.Response, error) {
+	return c.CreditWithContext(context.Background(), request, runOpts...)
+}
+
+func (c *client) CreditWithContext(ctx context.Context, request *Request, runOpts ...RunOption) (*platon.Response, error) {
 	if request == nil {
 		return nil, fmt.Errorf("credit: %w", platon.ErrRequestIsNil)
 	}
 
 	opts := collectRunOptions(runOpts)
+
+	request, err := c.resolveMerchant(ctx, request)
+	if err != nil {
+		return nil, err
+	}
 	if request.GetMerchantKey() == "" {
-		return nil, fmt.Errorf("credit: merchant client_key is required")
+		return nil, platon.NewClientKeyRequiredError("credit")
 	}
 	if request.PaymentData == nil {
-		return nil, fmt.Errorf("credit: PaymentData is nil")
+		return nil, platon.NewValidationError("credit", "PaymentData", "is nil")
 	}
-	if request.GetPaymentID() == nil || *request.GetPaymentID() == "" {
-		return nil, fmt.Errorf("credit: order_id (PaymentData.PaymentID) is required")
+	if err := request.PaymentData.RequireIDs(platon.ActionCodeCREDIT2CARD); err != nil {
+		return nil, fmt.Errorf("credit: %w", err)
 	}
 	if request.PaymentData.Amount <= 0 {
-		return nil, fmt.Errorf("credit: PaymentData.Amount (minor units) must be > 0")
+		return nil, platon.NewValidationError("credit", "PaymentData.Amount", "(minor units) must be > 0")
 	}
+	request = c.applyDefaults(request)
 	if request.GetCurrency() == "" {
-		return nil, fmt.Errorf("credit: order_currency is required")
+		return nil, platon.NewValidationError("credit", "order_currency", "is required")
 	}
 	if request.GetDescription() == "" {
-		return nil, fmt.Errorf("credit: order_description is required")
+		return nil, platon.NewValidationError("credit", "order_description", "is required")
 	}
 
 	if splitRules, err := request.GetSplitRules(); err != nil {
 		return nil, fmt.Errorf("credit: invalid split rules: %w", err)
 	} else if len(splitRules) > 0 {
-		return nil, fmt.Errorf("credit: split rules are not supported for CREDIT2CARD")
+		return nil, platon.NewValidationError("credit", "split_rules", "are not supported for CREDIT2CARD")
+	}
+
+	token := request.GetCardToken()
+	pan := request.GetCardPan()
+	useToken := token != nil && *token != ""
+	if !useToken && (pan == nil || strings.TrimSpace(*pan) == "") {
+		return nil, platon.NewValidationError("credit", "card_number", "is required (set PaymentMethod.Card.Pan or PaymentMethod.Card.Token)")
 	}
 
+	// Token payouts fall back to placeholder payer data; PAN payouts require real payer identity.
 	a2cPayer := resolveA2CPayerData(request)
+	if !useToken {
+		var err error
+		a2cPayer, err = resolveRequiredA2CPayerData(request, "credit")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := platon.ValidateCountryCode(*a2cPayer.Country); err != nil {
+		return nil, fmt.Errorf("credit: payer_country: %w", err)
+	}
+	if err := platon.ValidateStateCode(*a2cPayer.State); err != nil {
+		return nil, fmt.Errorf("credit: payer_state: %w", err)
+	}
+
 	apiRequest := platon.NewRequest(platon.ActionCodeCREDIT2CARD).
 		WithAuth(request.GetAuth()).
 		WithClientKey(request.GetMerchantKey()).
@@ -489,19 +1258,67 @@ func (c *client) Credit(request *Request, runOpts ...RunOption) (*platon.Respons
 		WithPayerEmail(request.GetPayerEmail()).
 		WithPayerPhone(request.GetPayerPhone())
 
-	if token := request.GetCardToken(); token != nil && *token != "" {
+	if useToken {
 		apiRequest.WithCardToken(token).SignForAction(platon.HashTypeCredit2CardToken)
 	} else {
-		return nil, fmt.Errorf("credit: card_token is required")
+		apiRequest.WithCardNumber(stringRef(strings.TrimSpace(*pan))).SignForAction(platon.HashTypeCredit2Card)
+	}
+	if err := checkExtFields(request, "credit"); err != nil {
+		return nil, err
+	}
+	applyExtFields(apiRequest, request)
+
+	apiURL, err := c.endpointFor(apiRequest)
+	if err != nil {
+		return nil, fmt.Errorf("credit: %w", err)
 	}
-	applyExtFieldsFromMetadata(apiRequest, request.GetMetadata())
 
 	if opts.isDryRun() {
-		opts.handleDryRun(consts.ApiP2PUnqURL, apiRequest)
+		c.dryRun(ctx, opts, apiURL, apiRequest)
 		return nil, nil
 	}
 
-	return c.platonClient.Api(apiRequest, consts.ApiP2PUnqURL)
+	response, err := c.api(ctx, apiRequest, apiURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("credit API call: %w", err)
+	}
+
+	return response, nil
+}
+
+// dryRun records a request that is skipped by DryRun (tagged dry_run=true)
+// and passes it to the dry-run handler.
+func (c *client) dryRun(ctx context.Context, opts *runOptions, endpoint string, payload any) {
+	if request, ok := payload.(*platon.Request); ok {
+		c.applyClock(request)
+		c.platonClient.RecordDryRun(ctx, endpoint, request)
+	}
+	opts.handleDryRun(endpoint, payload)
+}
+
+// applyClock sets the client clock on apiRequest unless it has its own.
+func (c *client) applyClock(apiRequest *platon.Request) {
+	if c.clock != nil && apiRequest != nil && apiRequest.Clock == nil {
+		apiRequest.WithClock(c.clock)
+	}
+}
+
+func (c *client) api(ctx context.Context, apiRequest *platon.Request, apiURL string, opts *runOptions) (*platon.Response, error) {
+	if len(c.phonePrefixes) > 0 && apiRequest != nil && apiRequest.PayerPhonePrefixes == nil {
+		apiRequest.WithPayerPhonePrefixes(c.phonePrefixes...)
+	}
+	c.applyClock(apiRequest)
+	if c.idempotency != nil && apiRequest != nil && apiRequest.IdempotencyKey != "" {
+		return c.idempotentAPI(ctx, apiRequest, apiURL, opts)
+	}
+
+	return c.platonClient.ApiWithContext(ctx, apiRequest, apiURL, opts.callOptions()...)
+}
+
+// MeasureClockSkew returns receivedAt minus the callback date and logs a
+// warning when it exceeds the WithClockSkewWarnThreshold threshold.
+func (c *client) MeasureClockSkew(form *platon.WebhookForm, receivedAt time.Time) time.Duration {
+	return platon.ClockSkewProbe{WarnThreshold: c.clockSkewThreshold, Sink: c.logSink}.Measure(form, receivedAt)
 }
 
 // ParseWebhookXML parses legacy XML webhook payload.
@@ -551,27 +1368,32 @@ func resolveA2CPayerData(request *Request) a2cPayerData {
 		stringRef(defaultA2CLastName),
 	)
 	address := firstNonEmptyPointer(
+		billingAddressField(request, func(a *BillingAddress) *string { return a.Address }),
 		stringPointerFromMetadata(metadata, "payer_address"),
 		stringRef(defaultA2CAddress),
 	)
 	country := normalizeTwoLetterValue(
 		firstNonEmptyPointer(
+			billingAddressField(request, func(a *BillingAddress) *string { return a.Country }),
 			stringPointerFromMetadata(metadata, "payer_country"),
 			stringRef(defaultA2CCountry),
 		), defaultA2CCountry,
 	)
 	state := normalizeTwoLetterValue(
 		firstNonEmptyPointer(
+			billingAddressField(request, func(a *BillingAddress) *string { return a.State }),
 			stringPointerFromMetadata(metadata, "payer_state"),
 			stringPointerFromMetadata(metadata, "payer_country"),
 			stringRef(defaultA2CState),
 		), defaultA2CState,
 	)
 	city := firstNonEmptyPointer(
+		billingAddressField(request, func(a *BillingAddress) *string { return a.City }),
 		stringPointerFromMetadata(metadata, "payer_city"),
 		stringRef(defaultA2CCity),
 	)
 	zip := firstNonEmptyPointer(
+		billingAddressField(request, func(a *BillingAddress) *string { return a.Zip }),
 		stringPointerFromMetadata(metadata, "payer_zip"),
 		stringRef(defaultA2CZip),
 	)
@@ -587,6 +1409,75 @@ func resolveA2CPayerData(request *Request) a2cPayerData {
 	}
 }
 
+// resolveRequiredA2CPayerData resolves payer identity for CREDIT2CARD by PAN.
+// Unlike resolveA2CPayerData it does not substitute placeholders and reports
+// every missing field as a ValidationError of op.
+func resolveRequiredA2CPayerData(request *Request, op string) (a2cPayerData, error) {
+	metadata := request.GetMetadata()
+
+	data := a2cPayerData{
+		FirstName: firstNonEmptyPointer(
+			pointerStringFromPersonalData(request, func(data *PersonalData) *string { return data.FirstName }),
+			stringPointerFromMetadata(metadata, "payer_first_name"),
+		),
+		LastName: firstNonEmptyPointer(
+			pointerStringFromPersonalData(request, func(data *PersonalData) *string { return data.LastName }),
+			stringPointerFromMetadata(metadata, "payer_last_name"),
+		),
+		Address: firstNonEmptyPointer(
+			billingAddressField(request, func(a *BillingAddress) *string { return a.Address }),
+			stringPointerFromMetadata(metadata, "payer_address"),
+		),
+		Country: firstNonEmptyPointer(
+			billingAddressField(request, func(a *BillingAddress) *string { return a.Country }),
+			stringPointerFromMetadata(metadata, "payer_country"),
+		),
+		State: firstNonEmptyPointer(
+			billingAddressField(request, func(a *BillingAddress) *string { return a.State }),
+			stringPointerFromMetadata(metadata, "payer_state"),
+		),
+		City: firstNonEmptyPointer(
+			billingAddressField(request, func(a *BillingAddress) *string { return a.City }),
+			stringPointerFromMetadata(metadata, "payer_city"),
+		),
+		Zip: firstNonEmptyPointer(
+			billingAddressField(request, func(a *BillingAddress) *string { return a.Zip }),
+			stringPointerFromMetadata(metadata, "payer_zip"),
+		),
+	}
+
+	var errs []error
+	if data.FirstName == nil {
+		errs = append(errs, platon.NewValidationError(op, "payer_first_name", `is required (set PersonalData.FirstName or Metadata["payer_first_name"])`))
+	}
+	if data.LastName == nil {
+		errs = append(errs, platon.NewValidationError(op, "payer_last_name", `is required (set PersonalData.LastName or Metadata["payer_last_name"])`))
+	}
+	for _, field := range []struct {
+		key   string
+		field string
+		value *string
+	}{
+		{key: "payer_address", field: "Address", value: data.Address},
+		{key: "payer_country", field: "Country", value: data.Country},
+		{key: "payer_state", field: "State", value: data.State},
+		{key: "payer_city", field: "City", value: data.City},
+		{key: "payer_zip", field: "Zip", value: data.Zip},
+	} {
+		if field.value == nil {
+			errs = append(errs, platon.NewValidationError(op, field.key, fmt.Sprintf("is required (set PersonalData.BillingAddress.%s or Metadata[%q])", field.field, field.key)))
+		}
+	}
+	if len(errs) > 0 {
+		return a2cPayerData{}, errors.Join(errs...)
+	}
+
+	data.Country = normalizeTwoLetterValue(data.Country, defaultA2CCountry)
+	data.State = normalizeTwoLetterValue(data.State, defaultA2CState)
+
+	return data, nil
+}
+
 func pointerStringFromPersonalData(request *Request, getter func(*PersonalData) *string) *string {
 	if request == nil || request.PersonalData == nil || getter == nil {
 		return nil
@@ -595,6 +1486,14 @@ func pointerStringFromPersonalData(request *Request, getter func(*PersonalData)
 	return getter(request.PersonalData)
 }
 
+func billingAddressField(request *Request, getter func(*BillingAddress) *string) *string {
+	if request == nil || request.PersonalData == nil || request.PersonalData.BillingAddress == nil || getter == nil {
+		return nil
+	}
+
+	return getter(request.PersonalData.BillingAddress)
+}
+
 func stringPointerFromMetadata(metadata map[string]string, key string) *string {
 	if metadata == nil {
 		return nil
@@ -612,6 +1511,40 @@ func stringPointerFromMetadata(metadata map[string]string, key string) *string {
 	return &trimmed
 }
 
+// applyExtFields copies ext1..ext10 from metadata and then the reference from
+// PaymentData.Reference, which takes ext10 over. It also carries over
+// PaymentData.IdempotencyKey.
+func applyExtFields(apiRequest *platon.Request, request *Request) {
+	applyExtFieldsFromMetadata(apiRequest, request.GetMetadata())
+	if request != nil && request.PaymentData != nil {
+		if request.PaymentData.Reference != nil {
+			apiRequest.WithReference(*request.PaymentData.Reference)
+		}
+		apiRequest.WithIdempotencyKey(request.PaymentData.IdempotencyKey)
+	}
+}
+
+// maxExtFieldLength is the longest ext1..ext10 value Platon accepts.
+const maxExtFieldLength = 1024
+
+// checkExtFields rejects metadata ext1..ext10 values (after trimming) and a
+// reference longer than maxExtFieldLength characters.
+func checkExtFields(request *Request, op string) error {
+	metadata := request.GetMetadata()
+	for idx := 1; idx <= 10; idx++ {
+		key := fmt.Sprintf("ext%d", idx)
+		if value := stringPointerFromMetadata(metadata, key); value != nil && utf8.RuneCountInString(*value) > maxExtFieldLength {
+			return platon.NewValidationError(op, key, fmt.Sprintf("must be <= %d characters", maxExtFieldLength))
+		}
+	}
+	if request != nil && request.PaymentData != nil && request.PaymentData.Reference != nil &&
+		utf8.RuneCountInString(*request.PaymentData.Reference) > maxExtFieldLength {
+		return platon.NewValidationError(op, "ext10", fmt.Sprintf("(PaymentData.Reference) must be <= %d characters", maxExtFieldLength))
+	}
+
+	return nil
+}
+
 func applyExtFieldsFromMetadata(apiRequest *platon.Request, metadata map[string]string) {
 	if apiRequest == nil || metadata == nil {
 		return
@@ -643,6 +1576,8 @@ func firstNonEmptyPointer(values ...*string) *string {
 	return nil
 }
 
+// normalizeTwoLetterValue upper-cases and trims value. Longer values are kept
+// as is so that validation reports them instead of silently truncating.
 func normalizeTwoLetterValue(value *string, fallback string) *string {
 	if value == nil {
 		return &fallback
@@ -652,9 +1587,6 @@ func normalizeTwoLetterValue(value *string, fallback string) *string {
 	if normalized == "" {
 		return &fallback
 	}
-	if len(normalized) > 2 {
-		normalized = normalized[:2]
-	}
 	return &normalized
 }
 
@@ -662,8 +1594,17 @@ func stringRef(value string) *string {
 	return &value
 }
 
-func resolveClientServerVerificationURL(form *platon.ClientServerVerificationForm) (*url.URL, error) {
-	logger := log.NewLogger("Platon Verification: ")
+// resolveClientServerVerificationURL posts form and returns the purchase URL.
+// The request goes through the transport of platonClient, so its proxy and TLS
+// settings apply, and bodies are logged with its sensitive keys.
+func resolveClientServerVerificationURL(
+	ctx context.Context, form *platon.ClientServerVerificationForm, platonClient *internalhttp.Client, sink log.Sink,
+) (*url.URL, error) {
+	logger := log.NewLogger("Platon Verification: ").WithSink(sink)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
 	if form == nil {
 		err := fmt.Errorf("verification form is nil")
@@ -681,10 +1622,10 @@ func resolveClientServerVerificationURL(form *platon.ClientServerVerificationFor
 	logger.Debug(
 		"Request (%s):\n%s",
 		internalhttp.FormURLEncodedContentType,
-		internalhttp.PrettyPrintFormURLEncodedBody(encodedForm),
+		platonClient.FormatBodyForDebug(internalhttp.FormURLEncodedContentType, []byte(encodedForm)),
 	)
 
-	req, err := http.NewRequest(http.MethodPost, form.Endpoint, strings.NewReader(encodedForm))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, form.Endpoint, strings.NewReader(encodedForm))
 	if err != nil {
 		err = fmt.Errorf("cannot build verification request: %w", err)
 		logger.Error("%v", err)
@@ -692,11 +1633,14 @@ func resolveClientServerVerificationURL(form *platon.ClientServerVerificationFor
 	}
 	req.Header.Set("Content-Type", internalhttp.FormURLEncodedContentType)
 
-	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
+	httpClient := platonClient.NoRedirectHTTPClient()
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout: 30 * time.Second,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
 	}
 
 	resp, err := httpClient.Do(req)
@@ -717,29 +1661,32 @@ func resolveClientServerVerificationURL(form *platon.ClientServerVerificationFor
 	logger.Debug("Response body size: %d bytes", len(body))
 	if len(body) == 0 {
 		logger.Debug("Response: <empty>")
-	} else if internalhttp.IsFormURLEncodedContentType(resp.Header.Get("Content-Type")) {
+	} else if contentType := resp.Header.Get("Content-Type"); internalhttp.IsFormURLEncodedContentType(contentType) {
 		logger.Debug(
 			"Response (%s):\n%s",
 			internalhttp.FormURLEncodedContentType,
-			truncateVerificationBodyForLog([]byte(internalhttp.PrettyPrintFormURLEncodedBody(string(body)))),
+			truncateVerificationBodyForLog([]byte(platonClient.FormatBodyForDebug(contentType, body))),
 		)
 	} else {
-		logger.Debug("Response: %s", truncateVerificationBodyForLog(body))
+		logger.Debug("Response: %s", truncateVerificationBodyForLog([]byte(platonClient.FormatBodyForDebug(contentType, body))))
 	}
 
+	// The purchase page is served by the gateway the form was posted to, so a
+	// relative URL is resolved against the configured endpoint.
 	if location := strings.TrimSpace(resp.Header.Get("Location")); location != "" {
 		logger.Debug("Response location: %s", location)
-		return parsePurchaseURL(location)
+		return resolvePurchaseURL(req.URL, location)
 	}
 
-	absRe := regexp.MustCompile(`https://secure\.platononline\.com/payment/purchase\?token=[A-Za-z0-9]+`)
+	origin := (&url.URL{Scheme: req.URL.Scheme, Host: req.URL.Host}).String()
+	absRe := regexp.MustCompile(regexp.QuoteMeta(origin) + `(?:/[\w./-]*)?/payment/purchase\?token=[A-Za-z0-9]+`)
 	if match := absRe.Find(body); match != nil {
 		return parsePurchaseURL(string(match))
 	}
 
 	relRe := regexp.MustCompile(`/payment/purchase\?token=[A-Za-z0-9]+`)
 	if match := relRe.Find(body); match != nil {
-		return parsePurchaseURL("https://secure.platononline.com" + string(match))
+		return resolvePurchaseURL(req.URL, string(match))
 	}
 
 	errMsg := fmt.Sprintf("verification purchase URL was not returned (status=%d)", resp.StatusCode)
@@ -759,6 +1706,16 @@ func truncateVerificationBodyForLog(raw []byte) string {
 	return string(raw[:max]) + "...(truncated)"
 }
 
+// resolvePurchaseURL parses raw relative to the verification endpoint.
+func resolvePurchaseURL(endpoint *url.URL, raw string) (*url.URL, error) {
+	parsedURL, err := endpoint.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse verification URL %q: %w", raw, err)
+	}
+
+	return parsePurchaseURL(parsedURL.String())
+}
+
 func parsePurchaseURL(raw string) (*url.URL, error) {
 	parsedURL, err := url.Parse(raw)
 	if err != nil {
@@ -0,0 +1,519 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+func TestCapture_OriginalAmount(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      int
+		original    *int
+		wantPartial bool
+		wantErr     string
+	}{
+		{name: "unset", amount: 1000},
+		{name: "equal", amount: 1000, original: intRef(1000)},
+		{name: "smaller", amount: 400, original: intRef(1000), wantPartial: true},
+		{name: "larger", amount: 1200, original: intRef(1000), wantErr: "capture: amount 1200 exceeds original amount 1000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				request := &Request{
+					Merchant: &Merchant{
+						MerchantKey: "CLIENT_KEY",
+						SecretKey:   "CLIENT_PASS",
+					},
+					PaymentData: &PaymentData{
+						PlatonTransID:  ref("632508054"),
+						Amount:         tt.amount,
+						OriginalAmount: tt.original,
+					},
+				}
+
+				if got := request.IsPartialCapture(); got != tt.wantPartial {
+					t.Fatalf("IsPartialCapture() mismatch: want %v, got %v", tt.wantPartial, got)
+				}
+
+				var captured *platon.Request
+				c := &client{}
+				_, err := c.Capture(
+					request, DryRun(
+						func(_ string, payload any) {
+							captured, _ = payload.(*platon.Request)
+						},
+					),
+				)
+
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+					}
+					var validationErr *platon.ValidationError
+					if !errors.As(err, &validationErr) || validationErr.Field != "amount" {
+						t.Fatalf("expected amount *platon.ValidationError, got %T", err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Capture() unexpected error: %v", err)
+				}
+				if captured == nil {
+					t.Fatal("Capture() captured request is nil")
+				}
+				if _, err := captured.SignAndPrepare(); err != nil {
+					t.Fatalf("SignAndPrepare() error: %v", err)
+				}
+			},
+		)
+	}
+}
+
+func intRef(v int) *int { return &v }
+
+func TestCapture_AlreadyCapturedIsIdempotent(t *testing.T) {
+	const alreadyCaptured = `{"action":"CAPTURE","result":"ERROR","status":"SETTLED","trans_id":"632508054","amount":"10.00","error_message":"Transaction already captured"}`
+
+	tests := []struct {
+		name         string
+		capture      string
+		status       string
+		wantErr      string
+		wantMismatch bool
+	}{
+		{
+			name:    "status confirms amount",
+			capture: alreadyCaptured,
+			status:  `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"SETTLED","trans_id":"632508054","amount":"10.00"}`,
+		},
+		{
+			name:         "status reports different amount",
+			capture:      alreadyCaptured,
+			status:       `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"SETTLED","trans_id":"632508054","amount":4.5}`,
+			wantErr:      "transaction 632508054 already captured with amount 450, requested 1000",
+			wantMismatch: true,
+		},
+		{
+			name:         "status does not confirm capture",
+			capture:      `{"action":"CAPTURE","result":"ERROR","trans_id":"632508054","error_message":"Transaction is already settled"}`,
+			status:       `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"REVERSAL","trans_id":"632508054","amount":"10.00"}`,
+			wantErr:      "captured amount could not be confirmed",
+			wantMismatch: true,
+		},
+		{
+			name:         "status lookup fails",
+			capture:      alreadyCaptured,
+			status:       `{"action":"GET_TRANS_STATUS","result":"ERROR","error_message":"Temporary failure"}`,
+			wantErr:      "could not be confirmed: status by trans id API call: platon api error: Temporary failure",
+			wantMismatch: true,
+		},
+		{
+			name:    "other error",
+			capture: `{"action":"CAPTURE","result":"ERROR","trans_id":"632508054","error_message":"Invalid hash"}`,
+			wantErr: "capture API call: platon api error: Invalid hash",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				httpClient := &http.Client{
+					Transport: roundTripperFunc(
+						func(r *http.Request) (*http.Response, error) {
+							if err := r.ParseForm(); err != nil {
+								return nil, err
+							}
+							body := tt.capture
+							if r.PostForm.Get("action") == "GET_TRANS_STATUS" {
+								body = tt.status
+							}
+							return &http.Response{
+								StatusCode: http.StatusOK,
+								Header:     http.Header{"Content-Type": []string{"application/json"}},
+								Body:       io.NopCloser(strings.NewReader(body)),
+							}, nil
+						},
+					),
+				}
+
+				cl := NewClient(WithClient(httpClient))
+				resp, err := cl.Capture(
+					&Request{
+						Merchant: &Merchant{
+							MerchantKey: "CLIENT_KEY",
+							SecretKey:   "CLIENT_PASS",
+						},
+						PaymentData: &PaymentData{
+							PlatonTransID: ref("632508054"),
+							Amount:        1000,
+						},
+					},
+				)
+
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+					}
+					var mismatch *AlreadyCapturedError
+					if errors.As(err, &mismatch) != tt.wantMismatch {
+						t.Fatalf("errors.As(*AlreadyCapturedError) = %v, want %v", !tt.wantMismatch, tt.wantMismatch)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Capture() unexpected error: %v", err)
+				}
+				if resp == nil || !resp.IsAlreadyCaptured() {
+					t.Fatalf("expected already-captured response, got %+v", resp)
+				}
+			},
+		)
+	}
+}
+
+func TestCapture_TypedErrors(t *testing.T) {
+	newRequest := func() *Request {
+		return &Request{
+			Merchant: &Merchant{
+				MerchantKey: "CLIENT_KEY",
+				SecretKey:   "CLIENT_PASS",
+			},
+			PaymentData: &PaymentData{
+				PlatonTransID: ref("632508054"),
+				Amount:        1000,
+			},
+		}
+	}
+	respond := func(status int, body string) *http.Client {
+		return &http.Client{
+			Transport: roundTripperFunc(
+				func(*http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: status,
+						Header:     http.Header{"Content-Type": []string{"application/json"}},
+						Body:       io.NopCloser(strings.NewReader(body)),
+					}, nil
+				},
+			),
+		}
+	}
+
+	t.Run(
+		"validation", func(t *testing.T) {
+			request := newRequest()
+			request.Merchant.MerchantKey = ""
+
+			_, err := NewClient().Capture(request)
+
+			var validationErr *platon.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected *platon.ValidationError, got %v", err)
+			}
+			if validationErr.Op != "capture" || validationErr.Field != "client_key" {
+				t.Fatalf("unexpected validation error: %+v", validationErr)
+			}
+			if err.Error() != "capture: merchant client_key is required" {
+				t.Fatalf("error = %q, want the original message", err)
+			}
+			if !errors.Is(err, platon.ErrValidation) {
+				t.Fatalf("errors.Is(err, platon.ErrValidation) should be true")
+			}
+		},
+	)
+
+	t.Run(
+		"gateway", func(t *testing.T) {
+			cl := NewClient(WithClient(respond(http.StatusBadGateway, "upstream unavailable")))
+
+			_, err := cl.Capture(newRequest())
+
+			var gatewayErr *platon.GatewayError
+			if !errors.As(err, &gatewayErr) {
+				t.Fatalf("expected *platon.GatewayError, got %v", err)
+			}
+			if gatewayErr.StatusCode != http.StatusBadGateway {
+				t.Fatalf("status mismatch: got %d", gatewayErr.StatusCode)
+			}
+			if !errors.Is(err, platon.ErrGateway) {
+				t.Fatalf("errors.Is(err, platon.ErrGateway) should be true")
+			}
+		},
+	)
+
+	t.Run(
+		"decline", func(t *testing.T) {
+			cl := NewClient(WithClient(respond(http.StatusOK, `{"action":"CAPTURE","result":"DECLINED","decline_reason":"51: Insufficient funds"}`)))
+
+			_, err := cl.Capture(newRequest())
+
+			var declineErr *platon.DeclineError
+			if !errors.As(err, &declineErr) {
+				t.Fatalf("expected *platon.DeclineError, got %v", err)
+			}
+			if declineErr.Code != 51 || declineErr.Reason != "Insufficient funds" {
+				t.Fatalf("unexpected decline: %+v", declineErr)
+			}
+			if !errors.Is(err, platon.ErrDeclined) {
+				t.Fatalf("errors.Is(err, platon.ErrDeclined) should be true")
+			}
+		},
+	)
+}
+
+func TestCaptureFull_UsesHeldAmountFromStatus(t *testing.T) {
+	var actions []string
+	var capturedAmount string
+	cl := NewClient(
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(r *http.Request) (*http.Response, error) {
+						if err := r.ParseForm(); err != nil {
+							return nil, err
+						}
+						action := r.PostForm.Get("action")
+						actions = append(actions, action)
+
+						body := `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"PENDING","trans_id":"632508054","amount":"125.50"}`
+						if action == "CAPTURE" {
+							capturedAmount = r.PostForm.Get("amount")
+							body = `{"action":"CAPTURE","result":"SUCCESS","status":"SETTLED","trans_id":"632508054","amount":"125.50"}`
+						}
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(body)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
+	resp, err := cl.CaptureFull(
+		&Request{
+			Merchant:    &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+			PaymentData: &PaymentData{PlatonTransID: ref("632508054"), Amount: 1},
+		},
+	)
+	if err != nil {
+		t.Fatalf("CaptureFull() error: %v", err)
+	}
+	if resp == nil || resp.Status == nil || *resp.Status != "SETTLED" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if strings.Join(actions, ",") != "GET_TRANS_STATUS,CAPTURE" {
+		t.Fatalf("actions = %v, want GET_TRANS_STATUS then CAPTURE", actions)
+	}
+	if capturedAmount != "125.50" {
+		t.Fatalf("captured amount = %q, want 125.50", capturedAmount)
+	}
+}
+
+func TestCaptureFull_StatusLookupFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{name: "gateway error", status: http.StatusBadGateway, body: `bad gateway`, wantErr: "capture full:"},
+		{name: "no amount", status: http.StatusOK, body: `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"PENDING","trans_id":"632508054"}`, wantErr: `no held amount found for trans_id "632508054"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var actions []string
+				cl := NewClient(
+					WithClient(
+						&http.Client{
+							Transport: roundTripperFunc(
+								func(r *http.Request) (*http.Response, error) {
+									if err := r.ParseForm(); err != nil {
+										return nil, err
+									}
+									actions = append(actions, r.PostForm.Get("action"))
+									return &http.Response{
+										StatusCode: tt.status,
+										Header:     http.Header{"Content-Type": []string{"application/json"}},
+										Body:       io.NopCloser(strings.NewReader(tt.body)),
+									}, nil
+								},
+							),
+						},
+					),
+				)
+
+				_, err := cl.CaptureFull(
+					&Request{
+						Merchant:    &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+						PaymentData: &PaymentData{PlatonTransID: ref("632508054")},
+					},
+				)
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				for _, action := range actions {
+					if action == "CAPTURE" {
+						t.Fatal("CAPTURE must not be sent when the status lookup fails")
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestCaptureFull_HoldState(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		wantAmount string
+		wantErr    string
+	}{
+		{
+			name:       "part of the hold already captured",
+			status:     `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"PENDING","trans_id":"632508054","amount":"125.50","transactions":[{"type":"SALE","status":"SUCCESS","amount":"125.50"},{"type":"CAPTURE","status":"SUCCESS","amount":"25.50"}]}`,
+			wantAmount: "100.00",
+		},
+		{
+			name:    "already settled",
+			status:  `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"SETTLED","trans_id":"632508054","amount":"125.50"}`,
+			wantErr: `trans_id "632508054" is not a pending hold (status SETTLED)`,
+		},
+		{
+			name:    "reversed",
+			status:  `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"REVERSAL","trans_id":"632508054","amount":"125.50"}`,
+			wantErr: "is not a pending hold (status REVERSAL)",
+		},
+		{
+			name:    "fully captured",
+			status:  `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"PENDING","trans_id":"632508054","amount":"125.50","transactions":[{"type":"SALE","status":"SUCCESS","amount":"125.50"},{"type":"CAPTURE","status":"SUCCESS","amount":"125.50"}]}`,
+			wantErr: `nothing left to capture for trans_id "632508054"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var captured *http.Request
+				cl := NewClient(
+					WithClient(
+						&http.Client{
+							Transport: roundTripperFunc(
+								func(r *http.Request) (*http.Response, error) {
+									if err := r.ParseForm(); err != nil {
+										return nil, err
+									}
+									body := tt.status
+									if r.PostForm.Get("action") == "CAPTURE" {
+										captured = r
+										body = `{"action":"CAPTURE","result":"SUCCESS","status":"SETTLED","trans_id":"632508054"}`
+									}
+									return &http.Response{
+										StatusCode: http.StatusOK,
+										Header:     http.Header{"Content-Type": []string{"application/json"}},
+										Body:       io.NopCloser(strings.NewReader(body)),
+									}, nil
+								},
+							),
+						},
+					),
+				)
+
+				_, err := cl.CaptureFull(
+					&Request{
+						Merchant:    &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+						PaymentData: &PaymentData{PlatonTransID: ref("632508054")},
+					},
+				)
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+					}
+					if captured != nil {
+						t.Fatal("CAPTURE must not be sent")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("CaptureFull() error: %v", err)
+				}
+				if captured == nil {
+					t.Fatal("CAPTURE was not sent")
+				}
+				if got := captured.PostForm.Get("amount"); got != tt.wantAmount {
+					t.Fatalf("captured amount = %q, want %q", got, tt.wantAmount)
+				}
+			},
+		)
+	}
+}
+
+func TestCaptureFull_DryRunRecordsCapture(t *testing.T) {
+	cl := NewClient(
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(r *http.Request) (*http.Response, error) {
+						t.Fatalf("dry run must not send %s", r.URL)
+						return nil, nil
+					},
+				),
+			},
+		),
+	)
+
+	var actions []string
+	_, err := cl.CaptureFull(
+		&Request{
+			Merchant:    &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+			PaymentData: &PaymentData{PlatonTransID: ref("632508054"), Amount: 12550},
+		},
+		DryRun(
+			func(_ string, payload any) {
+				if request, ok := payload.(*platon.Request); ok {
+					actions = append(actions, request.Action)
+				}
+			},
+		),
+	)
+	if err != nil {
+		t.Fatalf("CaptureFull() error: %v", err)
+	}
+	if strings.Join(actions, ",") != "GET_TRANS_STATUS,CAPTURE" {
+		t.Fatalf("dry run recorded %v, want GET_TRANS_STATUS then CAPTURE", actions)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCaptureWithContext_CancelAbortsRequest(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			},
+		),
+	)
+	defer srv.Close()
+	defer close(release)
+
+	target, _ := url.Parse(srv.URL)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(
+			func(req *http.Request) (*http.Response, error) {
+				req.URL.Scheme = target.Scheme
+				req.URL.Host = target.Host
+				return transport.RoundTrip(req)
+			},
+		),
+	}
+	cl := NewClient(WithClient(httpClient))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := cl.CaptureWithContext(
+		ctx, &Request{
+			Merchant: &Merchant{
+				MerchantKey: "CLIENT_KEY",
+				SecretKey:   "CLIENT_PASS",
+			},
+			PaymentData: &PaymentData{
+				PlatonTransID: ref("632508054"),
+				Amount:        100,
+			},
+		},
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "capture API call: ") {
+		t.Fatalf("error should name the operation, got %v", err)
+	}
+}
+
+func TestPaymentWithContext_CancelAbortsRequest(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			},
+		),
+	)
+	defer srv.Close()
+	defer close(release)
+
+	target, _ := url.Parse(srv.URL)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	cl := NewClient(
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(req *http.Request) (*http.Response, error) {
+						req.URL.Scheme = target.Scheme
+						req.URL.Host = target.Host
+						return transport.RoundTrip(req)
+					},
+				),
+			},
+		),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	_, err := cl.PaymentWithContext(
+		ctx, &Request{
+			Merchant: &Merchant{
+				MerchantKey: "CLIENT_KEY",
+				SecretKey:   "CLIENT_PASS",
+				TermsURL:    ref("https://example.com/3ds"),
+				ClientIP:    ref("203.0.113.10"),
+			},
+			PaymentMethod: &PaymentMethod{
+				Card: &Card{Token: ref("CARD_TOKEN")},
+			},
+			PaymentData: &PaymentData{
+				PaymentID:   ref("order-1"),
+				Amount:      100,
+				Currency:    "UAH",
+				Description: "desc",
+			},
+			PersonalData: &PersonalData{
+				Email: ref("payer@example.com"),
+			},
+		},
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "payment API call: ") {
+		t.Fatalf("error should name the operation, got %v", err)
+	}
+}
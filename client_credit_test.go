@@ -25,6 +25,8 @@
 package go_platon
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stremovskyy/go-platon/consts"
@@ -213,3 +215,382 @@ func TestStatus_DryRun_TransIDFlow_UsesGetTransStatusRequest(t *testing.T) {
 		t.Fatalf("Status() action mismatch: want %q, got %q", platon.ActionCodeGetTransStatus.String(), capturedRequest.Action)
 	}
 }
+
+func TestStatusByTransID_DryRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		metadata     map[string]string
+		wantEndpoint string
+		wantHashType platon.HashType
+	}{
+		{name: "IE", wantEndpoint: consts.ApiGetTransStatus, wantHashType: platon.HashTypeGetTransStatus},
+		{name: "A2C", metadata: map[string]string{platonMetaFlow: platonFlowA2C}, wantEndpoint: consts.ApiP2PUnqURL, wantHashType: platon.HashTypeGetTransStatusA2C},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var capturedEndpoint string
+				var capturedRequest *platon.Request
+
+				c := &client{}
+				request := &Request{
+					Merchant: &Merchant{
+						MerchantKey: "CLIENT_KEY",
+						SecretKey:   "CLIENT_PASS",
+					},
+					PersonalData: &PersonalData{
+						Email: ref("payer@example.com"),
+					},
+					PaymentData: &PaymentData{
+						PaymentID:     ref("ORDER-4"),
+						PlatonTransID: ref("632508054"),
+						Metadata:      tt.metadata,
+					},
+				}
+
+				_, err := c.StatusByTransID(
+					request, DryRun(
+						func(endpoint string, payload any) {
+							capturedEndpoint = endpoint
+							capturedRequest, _ = payload.(*platon.Request)
+						},
+					),
+				)
+				if err != nil {
+					t.Fatalf("StatusByTransID() unexpected error: %v", err)
+				}
+
+				if capturedEndpoint != tt.wantEndpoint {
+					t.Fatalf("StatusByTransID() endpoint mismatch: want %q, got %q", tt.wantEndpoint, capturedEndpoint)
+				}
+				if capturedRequest == nil {
+					t.Fatal("StatusByTransID() captured request is nil")
+				}
+				if capturedRequest.HashType != tt.wantHashType {
+					t.Fatalf("StatusByTransID() hash type mismatch: want %q, got %q", tt.wantHashType, capturedRequest.HashType)
+				}
+				if capturedRequest.Action != platon.ActionCodeGetTransStatus.String() {
+					t.Fatalf("StatusByTransID() action mismatch: want %q, got %q", platon.ActionCodeGetTransStatus.String(), capturedRequest.Action)
+				}
+				if capturedRequest.HashEmail == nil || *capturedRequest.HashEmail != "payer@example.com" {
+					t.Fatalf("StatusByTransID() hash email mismatch: got %v", capturedRequest.HashEmail)
+				}
+				if capturedRequest.OrderID != nil {
+					t.Fatalf("StatusByTransID() must not send order_id, got %q", *capturedRequest.OrderID)
+				}
+			},
+		)
+	}
+}
+
+func TestStatusByTransID_SignsWithPayerEmail(t *testing.T) {
+	sign := func(email *string) string {
+		t.Helper()
+
+		var captured *platon.Request
+		_, err := (&client{}).StatusByTransID(
+			&Request{
+				Merchant:     &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+				PersonalData: &PersonalData{Email: email},
+				PaymentData:  &PaymentData{PlatonTransID: ref("632508054")},
+			}, DryRun(
+				func(_ string, payload any) {
+					captured = payload.(*platon.Request)
+				},
+			),
+		)
+		if err != nil {
+			t.Fatalf("StatusByTransID() unexpected error: %v", err)
+		}
+
+		signed, err := captured.SignAndPrepare()
+		if err != nil {
+			t.Fatalf("SignAndPrepare() unexpected error: %v", err)
+		}
+		if _, ok := signed.ToMap()["email"]; ok {
+			t.Fatal("hash email must not be sent as a form field")
+		}
+
+		return signed.Hash
+	}
+
+	withEmail := sign(ref("payer@example.com"))
+	withoutEmail := sign(nil)
+	if withEmail == "" || withEmail == withoutEmail {
+		t.Fatalf("payer email must be part of the GET_TRANS_STATUS signature, got %q and %q", withEmail, withoutEmail)
+	}
+}
+
+func TestStatusByTransID_RequiresTransID(t *testing.T) {
+	c := &client{}
+	request := &Request{
+		Merchant:    &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+		PaymentData: &PaymentData{PaymentID: ref("ORDER-5")},
+	}
+
+	_, err := c.StatusByTransID(request, DryRun(func(string, any) {}))
+	if err == nil || !strings.Contains(err.Error(), "PaymentData.PlatonTransID") {
+		t.Fatalf("StatusByTransID() expected trans_id error, got %v", err)
+	}
+}
+
+func TestCredit_CardPAN_DryRun_BuildsA2CRequest(t *testing.T) {
+	var capturedEndpoint string
+	var capturedRequest *platon.Request
+
+	c := &client{}
+	request := &Request{
+		Merchant: &Merchant{
+			MerchantKey: "CLIENT_KEY",
+			SecretKey:   "CLIENT_PASS",
+		},
+		PersonalData: &PersonalData{
+			FirstName: ref("Ivan"),
+			LastName:  ref("Petrenko"),
+		},
+		PaymentData: &PaymentData{
+			PaymentID:   ref("ORDER-PAN-1"),
+			Amount:      2500,
+			Currency:    currency.UAH,
+			Description: "A2C payout by PAN",
+			Metadata: map[string]string{
+				"payer_address": "Khreshchatyk 1",
+				"payer_country": "ua",
+				"payer_state":   "ua",
+				"payer_city":    "Kyiv",
+				"payer_zip":     "01001",
+			},
+		},
+		PaymentMethod: &PaymentMethod{
+			Card: &Card{Pan: ref("4111111111111111")},
+		},
+	}
+
+	_, err := c.Credit(
+		request, DryRun(
+			func(endpoint string, payload any) {
+				capturedEndpoint = endpoint
+				capturedRequest, _ = payload.(*platon.Request)
+			},
+		),
+	)
+	if err != nil {
+		t.Fatalf("Credit() unexpected error: %v", err)
+	}
+
+	if capturedEndpoint != consts.ApiP2PUnqURL {
+		t.Fatalf("Credit() endpoint mismatch: want %q, got %q", consts.ApiP2PUnqURL, capturedEndpoint)
+	}
+	if capturedRequest == nil {
+		t.Fatal("Credit() captured request is nil")
+	}
+	if capturedRequest.HashType != platon.HashTypeCredit2Card {
+		t.Fatalf("Credit() hash type mismatch: want %q, got %q", platon.HashTypeCredit2Card, capturedRequest.HashType)
+	}
+	if capturedRequest.CardNumber == nil || *capturedRequest.CardNumber != "4111111111111111" {
+		t.Fatalf("Credit() card_number mismatch: got %v", capturedRequest.CardNumber)
+	}
+	if capturedRequest.CardToken != nil {
+		t.Fatalf("Credit() card_token should be empty for PAN payouts")
+	}
+	if capturedRequest.PayerCountry == nil || *capturedRequest.PayerCountry != "UA" {
+		t.Fatalf("Credit() payer_country mismatch: got %v", capturedRequest.PayerCountry)
+	}
+	if capturedRequest.PayerZip == nil || *capturedRequest.PayerZip != "01001" {
+		t.Fatalf("Credit() payer_zip mismatch: got %v", capturedRequest.PayerZip)
+	}
+
+	if _, err := capturedRequest.SignAndPrepare(); err != nil {
+		t.Fatalf("SignAndPrepare() unexpected error: %v", err)
+	}
+}
+
+func TestCredit_BillingAddressOverridesMetadataAndDefaults(t *testing.T) {
+	for _, card := range []*Card{{Token: ref("CARD_TOKEN")}, {Pan: ref("4111111111111111")}} {
+		var capturedRequest *platon.Request
+
+		c := &client{}
+		request := &Request{
+			Merchant: &Merchant{
+				MerchantKey: "CLIENT_KEY",
+				SecretKey:   "CLIENT_PASS",
+			},
+			PersonalData: &PersonalData{
+				FirstName: ref("Ivan"),
+				LastName:  ref("Petrenko"),
+				BillingAddress: &BillingAddress{
+					Address: ref("Svobody 5"),
+					Country: ref("pl"),
+					State:   ref("pl"),
+					City:    ref("Warsaw"),
+					Zip:     ref("00-001"),
+				},
+			},
+			PaymentData: &PaymentData{
+				PaymentID:   ref("ORDER-BILLING-1"),
+				Amount:      2500,
+				Currency:    currency.UAH,
+				Description: "A2C payout",
+				Metadata: map[string]string{
+					"payer_address": "Khreshchatyk 1",
+					"payer_country": "UA",
+					"payer_state":   "UA",
+					"payer_city":    "Kyiv",
+					"payer_zip":     "01001",
+				},
+			},
+			PaymentMethod: &PaymentMethod{Card: card},
+		}
+
+		_, err := c.Credit(
+			request, DryRun(
+				func(_ string, payload any) {
+					capturedRequest, _ = payload.(*platon.Request)
+				},
+			),
+		)
+		if err != nil {
+			t.Fatalf("Credit() unexpected error: %v", err)
+		}
+		if capturedRequest == nil {
+			t.Fatal("Credit() captured request is nil")
+		}
+
+		for want, got := range map[string]*string{
+			"Svobody 5": capturedRequest.PayerAddress,
+			"PL":        capturedRequest.PayerCountry,
+			"Warsaw":    capturedRequest.PayerCity,
+			"00-001":    capturedRequest.PayerZip,
+		} {
+			if got == nil || *got != want {
+				t.Fatalf("Credit() payer field mismatch: want %q, got %v", want, got)
+			}
+		}
+		if capturedRequest.PayerState == nil || *capturedRequest.PayerState != "PL" {
+			t.Fatalf("Credit() payer_state mismatch: got %v", capturedRequest.PayerState)
+		}
+	}
+}
+
+func TestCredit_PartialBillingAddressFallsBackToDefaults(t *testing.T) {
+	var capturedRequest *platon.Request
+
+	c := &client{}
+	request := &Request{
+		Merchant: &Merchant{
+			MerchantKey: "CLIENT_KEY",
+			SecretKey:   "CLIENT_PASS",
+		},
+		PersonalData: &PersonalData{
+			BillingAddress: &BillingAddress{
+				Country: ref("  "),
+				City:    ref("Lviv"),
+			},
+		},
+		PaymentData: &PaymentData{
+			PaymentID:   ref("ORDER-BILLING-2"),
+			Amount:      2500,
+			Currency:    currency.UAH,
+			Description: "A2C payout",
+		},
+		PaymentMethod: &PaymentMethod{Card: &Card{Token: ref("CARD_TOKEN")}},
+	}
+
+	_, err := c.Credit(
+		request, DryRun(
+			func(_ string, payload any) {
+				capturedRequest, _ = payload.(*platon.Request)
+			},
+		),
+	)
+	if err != nil {
+		t.Fatalf("Credit() unexpected error: %v", err)
+	}
+	if capturedRequest == nil {
+		t.Fatal("Credit() captured request is nil")
+	}
+
+	for field, got := range map[string]struct {
+		value *string
+		want  string
+	}{
+		"payer_address": {capturedRequest.PayerAddress, defaultA2CAddress},
+		"payer_country": {capturedRequest.PayerCountry, defaultA2CCountry},
+		"payer_state":   {capturedRequest.PayerState, defaultA2CState},
+		"payer_city":    {capturedRequest.PayerCity, "Lviv"},
+		"payer_zip":     {capturedRequest.PayerZip, defaultA2CZip},
+	} {
+		if got.value == nil || *got.value != got.want {
+			t.Fatalf("Credit() %s = %v, want %q", field, got.value, got.want)
+		}
+	}
+}
+
+func TestCredit_CardPAN_RequiresPayerFields(t *testing.T) {
+	c := &client{}
+	request := &Request{
+		Merchant: &Merchant{
+			MerchantKey: "CLIENT_KEY",
+			SecretKey:   "CLIENT_PASS",
+		},
+		PaymentData: &PaymentData{
+			PaymentID:   ref("ORDER-PAN-2"),
+			Amount:      2500,
+			Currency:    currency.UAH,
+			Description: "A2C payout by PAN",
+			Metadata: map[string]string{
+				"payer_city": "Kyiv",
+			},
+		},
+		PaymentMethod: &PaymentMethod{
+			Card: &Card{Pan: ref("4111111111111111")},
+		},
+	}
+
+	_, err := c.Credit(request, DryRun(func(string, any) {}))
+	if !errors.Is(err, platon.ErrValidation) {
+		t.Fatalf("Credit() error = %v, want a validation error for missing payer fields", err)
+	}
+	for _, field := range []string{"payer_first_name", "payer_last_name", "payer_address", "payer_country", "payer_state", "payer_zip"} {
+		if !strings.Contains(err.Error(), field+" is required") {
+			t.Fatalf("Credit() error should mention %s, got %v", field, err)
+		}
+	}
+	if strings.Contains(err.Error(), "payer_city") {
+		t.Fatalf("Credit() error should not mention payer_city, got %v", err)
+	}
+}
+
+func TestCredit_RejectsInvalidCountryAndState(t *testing.T) {
+	c := &client{}
+
+	cases := []struct {
+		metadata map[string]string
+		want     string
+	}{
+		{map[string]string{"payer_country": "XX"}, "credit: payer_country: invalid country code \"XX\""},
+		{map[string]string{"payer_country": "UKR"}, "credit: payer_country: invalid country code \"UKR\""},
+		{map[string]string{"payer_country": "UA", "payer_state": "QQ"}, "credit: payer_state: invalid state code \"QQ\""},
+	}
+	for _, tc := range cases {
+		request := &Request{
+			Merchant: &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+			PaymentData: &PaymentData{
+				PaymentID:   ref("ORDER-1"),
+				Amount:      2500,
+				Currency:    currency.UAH,
+				Description: "A2C payout",
+				Metadata:    tc.metadata,
+			},
+			PaymentMethod: &PaymentMethod{
+				Card: &Card{Token: ref("CARD_TOKEN")},
+			},
+		}
+
+		_, err := c.Credit(request, DryRun(func(string, any) {}))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("metadata %v: expected %q, got %v", tc.metadata, tc.want, err)
+		}
+	}
+}
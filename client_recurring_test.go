@@ -0,0 +1,187 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stremovskyy/go-platon/consts"
+	"github.com/stremovskyy/go-platon/currency"
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+func newRecurringRequest() *Request {
+	return &Request{
+		Merchant: &Merchant{
+			MerchantKey: "CLIENT_KEY",
+			SecretKey:   "CLIENT_PASS",
+			TermsURL:    ref("https://example.com/3ds"),
+		},
+		PaymentMethod: &PaymentMethod{
+			Card: &Card{Token: ref("CARD_TOKEN")},
+		},
+		PersonalData: &PersonalData{
+			Email: ref("payer@example.com"),
+		},
+		PaymentData: &PaymentData{
+			PaymentID:             ref("order-42"),
+			Amount:                1500,
+			Currency:              currency.UAH,
+			Description:           "Monthly subscription",
+			RecurringFirstTransID: ref("632508054"),
+		},
+	}
+}
+
+func TestRecurring_DryRun_UsesRecurringHashType(t *testing.T) {
+	var capturedEndpoint string
+	var capturedRequest *platon.Request
+
+	c := &client{}
+	_, err := c.Recurring(
+		newRecurringRequest(), DryRun(
+			func(endpoint string, payload any) {
+				capturedEndpoint = endpoint
+				capturedRequest, _ = payload.(*platon.Request)
+			},
+		),
+	)
+	if err != nil {
+		t.Fatalf("Recurring() unexpected error: %v", err)
+	}
+
+	if capturedEndpoint != consts.ApiPostUnqURL {
+		t.Fatalf("Recurring() endpoint mismatch: want %q, got %q", consts.ApiPostUnqURL, capturedEndpoint)
+	}
+	if capturedRequest == nil {
+		t.Fatal("Recurring() captured request is nil")
+	}
+	if capturedRequest.Action != platon.ActionCodeSALE.String() {
+		t.Fatalf("Recurring() action mismatch: want %q, got %q", platon.ActionCodeSALE.String(), capturedRequest.Action)
+	}
+	if capturedRequest.HashType != platon.HashTypeRecurring {
+		t.Fatalf("Recurring() hash type mismatch: want %q, got %q", platon.HashTypeRecurring, capturedRequest.HashType)
+	}
+	if capturedRequest.Ext3 == nil || *capturedRequest.Ext3 != "recurring" {
+		t.Fatalf("Recurring() ext3 mismatch: got %v", capturedRequest.Ext3)
+	}
+	if capturedRequest.CardToken == nil || *capturedRequest.CardToken != "CARD_TOKEN" {
+		t.Fatalf("Recurring() card_token mismatch: got %v", capturedRequest.CardToken)
+	}
+	if capturedRequest.RecurringFirstTransID == nil || *capturedRequest.RecurringFirstTransID != "632508054" {
+		t.Fatalf("Recurring() recurring_first_trans_id mismatch: got %v", capturedRequest.RecurringFirstTransID)
+	}
+	if capturedRequest.OrderAmount != "15.00" {
+		t.Fatalf("Recurring() order_amount mismatch: got %q", capturedRequest.OrderAmount)
+	}
+
+	if _, err := capturedRequest.SignAndPrepare(); err != nil {
+		t.Fatalf("SignAndPrepare() error: %v", err)
+	}
+}
+
+func TestRecurring_RequiresTokenAndPayerEmail(t *testing.T) {
+	c := &client{}
+
+	noToken := newRecurringRequest()
+	noToken.PaymentMethod = nil
+	if _, err := c.Recurring(noToken, DryRun(func(string, any) {})); err == nil || !strings.Contains(err.Error(), "recurring: card_token is required") {
+		t.Fatalf("expected card_token error, got %v", err)
+	}
+
+	noEmail := newRecurringRequest()
+	noEmail.PersonalData = nil
+	if _, err := c.Recurring(noEmail, DryRun(func(string, any) {})); err == nil || !strings.Contains(err.Error(), "recurring: payer_email is required") {
+		t.Fatalf("expected payer_email error, got %v", err)
+	}
+
+	noDescription := newRecurringRequest()
+	noDescription.PaymentData.Description = ""
+	if _, err := c.Recurring(noDescription, DryRun(func(string, any) {})); err == nil || !strings.Contains(err.Error(), "recurring: order_description is required") {
+		t.Fatalf("expected order_description error, got %v", err)
+	}
+}
+
+func TestRecurring_RequiresPositiveAmount(t *testing.T) {
+	c := &client{}
+
+	for _, amount := range []int{0, -100} {
+		req := newRecurringRequest()
+		req.PaymentData.Amount = amount
+
+		_, err := c.Recurring(req, DryRun(func(string, any) { t.Fatal("Recurring() must not reach the dry run") }))
+		var validationErr *platon.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Op != "recurring" || validationErr.Field != "PaymentData.Amount" {
+			t.Fatalf("amount %d: expected recurring PaymentData.Amount validation error, got %v", amount, err)
+		}
+	}
+}
+
+func TestRecurring_RequiresFirstTransID(t *testing.T) {
+	c := &client{}
+
+	req := newRecurringRequest()
+	req.PaymentData.RecurringFirstTransID = ref("  ")
+	if _, err := c.Recurring(req, DryRun(func(string, any) {})); err == nil || !strings.Contains(err.Error(), "recurring: recurring_first_trans_id is required") {
+		t.Fatalf("expected recurring_first_trans_id error, got %v", err)
+	}
+}
+
+func TestRecurring_FirstTransIDFromMetadata(t *testing.T) {
+	var capturedRequest *platon.Request
+
+	req := newRecurringRequest()
+	req.PaymentData.RecurringFirstTransID = nil
+	req.PaymentData.Metadata = map[string]string{
+		"recurring_first_trans_id": "632508055",
+		"ext4":                     "campaign-7",
+	}
+
+	c := &client{}
+	_, err := c.Recurring(
+		req, DryRun(
+			func(_ string, payload any) {
+				capturedRequest, _ = payload.(*platon.Request)
+			},
+		),
+	)
+	if err != nil {
+		t.Fatalf("Recurring() unexpected error: %v", err)
+	}
+	if capturedRequest == nil {
+		t.Fatal("Recurring() captured request is nil")
+	}
+	if capturedRequest.RecurringFirstTransID == nil || *capturedRequest.RecurringFirstTransID != "632508055" {
+		t.Fatalf("Recurring() recurring_first_trans_id mismatch: got %v", capturedRequest.RecurringFirstTransID)
+	}
+	if capturedRequest.Ext4 == nil || *capturedRequest.Ext4 != "campaign-7" {
+		t.Fatalf("Recurring() ext4 mismatch: got %v", capturedRequest.Ext4)
+	}
+	if capturedRequest.Ext3 == nil || *capturedRequest.Ext3 != "recurring" {
+		t.Fatalf("Recurring() ext3 mismatch: got %v", capturedRequest.Ext3)
+	}
+}
@@ -25,10 +25,15 @@
 package go_platon
 
 import (
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"testing"
+
+	"github.com/stremovskyy/go-platon/currency"
 )
 
 type splitRoundTripFunc func(*http.Request) (*http.Response, error)
@@ -116,3 +121,139 @@ func TestSubmerchantAvailableForSplit_FailedStatusReturnsError(t *testing.T) {
 		t.Fatalf("expected FAILED status in error, got %q", err.Error())
 	}
 }
+
+func TestPayment_ValidateSplitSubmerchants_LockedSubmerchantBlocksSale(t *testing.T) {
+	var mu sync.Mutex
+	var actions []string
+	statuses := map[string]string{"sub-a": "ENABLED", "sub-b": "LOCKED"}
+
+	client := NewClient(
+		WithClient(
+			&http.Client{
+				Transport: splitRoundTripFunc(
+					func(req *http.Request) (*http.Response, error) {
+						if err := req.ParseForm(); err != nil {
+							return nil, err
+						}
+						mu.Lock()
+						actions = append(actions, req.PostForm.Get("action"))
+						mu.Unlock()
+
+						body := `{"result":"SUCCESS","status":"SALE"}`
+						if submerchantID := req.PostForm.Get("submerchant_id"); submerchantID != "" {
+							body = fmt.Sprintf(
+								`{"status":"SUCCESS","action":"GET_SUBMERCHANT","submerchant_id":%q,"submerchant_id_status":%q}`,
+								submerchantID, statuses[submerchantID],
+							)
+						}
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(body)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
+	req := &Request{
+		Merchant: &Merchant{
+			MerchantKey: "CLIENT_KEY",
+			SecretKey:   "CLIENT_PASS",
+			TermsURL:    ref("https://example.com/3ds"),
+			ClientIP:    ref("203.0.113.10"),
+		},
+		PaymentMethod: &PaymentMethod{Card: &Card{Token: ref("CARD_TOKEN")}},
+		PaymentData: &PaymentData{
+			PaymentID:   ref("order-split-1"),
+			Amount:      1000,
+			Currency:    currency.UAH,
+			Description: "split payment",
+			SplitRules: []SplitRule{
+				{SubmerchantIdentification: "sub-a", Amount: 600},
+				{SubmerchantIdentification: "sub-b", Amount: 400},
+			},
+		},
+		PersonalData: &PersonalData{Email: ref("payer@example.com")},
+	}
+
+	_, err := client.Payment(req, ValidateSplitSubmerchants())
+	if err == nil {
+		t.Fatal("Payment() expected error for a LOCKED submerchant")
+	}
+	if !strings.Contains(err.Error(), "split submerchants are not enabled: sub-b") || strings.Contains(err.Error(), "sub-a") {
+		t.Fatalf("Payment() error = %q, want only sub-b listed", err.Error())
+	}
+	for _, action := range actions {
+		if action != "GET_SUBMERCHANT" {
+			t.Fatalf("sent %v, want only GET_SUBMERCHANT checks", actions)
+		}
+	}
+	if len(actions) != 2 {
+		t.Fatalf("sent %d GET_SUBMERCHANT checks, want 2", len(actions))
+	}
+
+	statuses["sub-b"] = "ENABLED"
+	actions = nil
+	if _, err := client.Payment(req, ValidateSplitSubmerchants()); err != nil {
+		t.Fatalf("Payment() error: %v", err)
+	}
+	if len(actions) != 3 || actions[2] != "SALE" {
+		t.Fatalf("sent %v, want two checks and the SALE", actions)
+	}
+}
+
+func TestPayment_SplitRulesUseCurrencyExponent(t *testing.T) {
+	var form url.Values
+	client := NewClient(
+		WithClient(
+			&http.Client{
+				Transport: splitRoundTripFunc(
+					func(req *http.Request) (*http.Response, error) {
+						if err := req.ParseForm(); err != nil {
+							return nil, err
+						}
+						form = req.PostForm
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(`{"result":"SUCCESS","status":"SALE"}`)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
+	req := &Request{
+		Merchant: &Merchant{
+			MerchantKey: "CLIENT_KEY",
+			SecretKey:   "CLIENT_PASS",
+			TermsURL:    ref("https://example.com/3ds"),
+			ClientIP:    ref("203.0.113.10"),
+		},
+		PaymentMethod: &PaymentMethod{Card: &Card{Token: ref("CARD_TOKEN")}},
+		PaymentData: &PaymentData{
+			PaymentID:   ref("order-split-jpy"),
+			Amount:      1000,
+			Currency:    currency.JPY,
+			Description: "split payment",
+			SplitRules: []SplitRule{
+				{SubmerchantIdentification: "sub-a", Amount: 600},
+				{SubmerchantIdentification: "sub-b", Amount: 400},
+			},
+		},
+		PersonalData: &PersonalData{Email: ref("payer@example.com")},
+	}
+
+	if _, err := client.Payment(req); err != nil {
+		t.Fatalf("Payment() error: %v", err)
+	}
+	if got := form.Get("order_amount"); got != "1000" {
+		t.Fatalf("order_amount = %q, want %q", got, "1000")
+	}
+	if got, want := form.Get("split_rules"), `{"sub-a":"600","sub-b":"400"}`; got != want {
+		t.Fatalf("split_rules = %q, want %q", got, want)
+	}
+}
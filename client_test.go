@@ -25,16 +25,28 @@
 package go_platon
 
 import (
+	"context"
 	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stremovskyy/go-platon/consts"
 	"github.com/stremovskyy/go-platon/currency"
 	"github.com/stremovskyy/go-platon/platon"
+	"github.com/stremovskyy/recorder"
 )
 
 func ref(s string) *string { return &s }
 
+const testApplePayContainerJSON = `{"token":{"version":"EC_v1","data":"ZA==","signature":"cw==",` +
+	`"header":{"ephemeralPublicKey":"aw==","publicKeyHash":"aA==","transactionId":"t1"}}}`
+
 func TestBuildIAPaymentRequest_ApplePay(t *testing.T) {
 	merchant := &Merchant{
 		MerchantKey: "CLIENT_KEY",
@@ -42,8 +54,8 @@ func TestBuildIAPaymentRequest_ApplePay(t *testing.T) {
 		TermsURL:    ref("https://example.com/3ds"),
 	}
 
-	// Minimal Apple Pay container for GetAppleContainer(): it extracts top-level "token".
-	containerJSON := `{"token":{"foo":"bar"}}`
+	// Minimal Apple Pay container for GetAppleContainer(): it unwraps top-level "token".
+	containerJSON := testApplePayContainerJSON
 	containerB64 := base64.StdEncoding.EncodeToString([]byte(containerJSON))
 
 	req := &Request{
@@ -94,7 +106,7 @@ func TestBuildIAPaymentRequest_ApplePay_WithSplitRules(t *testing.T) {
 		TermsURL:    ref("https://example.com/3ds"),
 	}
 
-	containerJSON := `{"token":{"foo":"bar"}}`
+	containerJSON := testApplePayContainerJSON
 	containerB64 := base64.StdEncoding.EncodeToString([]byte(containerJSON))
 
 	req := &Request{
@@ -275,15 +287,79 @@ func TestBuildIAPaymentRequest_CardToken_WithMetadataExtFields(t *testing.T) {
 	}
 }
 
-func TestBuildIAPaymentRequest_CardPAN_IsNotSupported(t *testing.T) {
-	merchant := &Merchant{
-		MerchantKey: "CLIENT_KEY",
-		SecretKey:   "CLIENT_PASS",
-		TermsURL:    ref("https://example.com/3ds"),
+func TestFollowUpOperations_ExtFields(t *testing.T) {
+	metadata := func() map[string]string {
+		return map[string]string{"ext1": " merchant-core ", "ext2": "   ", "ext4": "wallet-topup"}
+	}
+	followUp := func() *Request {
+		return &Request{
+			Merchant:     &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+			PersonalData: &PersonalData{Email: ref("payer@example.com")},
+			PaymentData:  &PaymentData{PlatonTransID: ref("632508054"), Amount: 100, Metadata: metadata()},
+		}
+	}
+	credit := func() *Request {
+		req := newBatchCreditRequest("payout-1")
+		req.PaymentData.Metadata = metadata()
+		return req
 	}
 
-	req := &Request{
-		Merchant: merchant,
+	c := &client{}
+	tests := []struct {
+		name    string
+		request func() *Request
+		call    func(*Request, ...RunOption) (*platon.Response, error)
+	}{
+		{name: "capture", request: followUp, call: c.Capture},
+		{name: "refund", request: followUp, call: c.Refund},
+		{name: "credit", request: credit, call: c.Credit},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var sent *platon.Request
+				dryRun := DryRun(
+					func(_ string, payload any) {
+						sent, _ = payload.(*platon.Request)
+					},
+				)
+
+				if _, err := tt.call(tt.request(), dryRun); err != nil {
+					t.Fatalf("%s error: %v", tt.name, err)
+				}
+				if sent == nil {
+					t.Fatal("no request was built")
+				}
+				if sent.Ext1 == nil || *sent.Ext1 != "merchant-core" {
+					t.Fatalf("ext1 mismatch: got %#v", sent.Ext1)
+				}
+				if sent.Ext2 != nil {
+					t.Fatalf("ext2 must be nil for blank metadata value, got %#v", sent.Ext2)
+				}
+				if sent.Ext4 == nil || *sent.Ext4 != "wallet-topup" {
+					t.Fatalf("ext4 mismatch: got %#v", sent.Ext4)
+				}
+
+				tooLong := tt.request()
+				tooLong.PaymentData.Metadata["ext3"] = strings.Repeat("x", 1025)
+				_, err := tt.call(tooLong, dryRun)
+				var validationErr *platon.ValidationError
+				if !errors.As(err, &validationErr) || validationErr.Field != "ext3" {
+					t.Fatalf("expected ext3 validation error, got %v", err)
+				}
+			},
+		)
+	}
+}
+
+func newCardPANPaymentRequest() *Request {
+	return &Request{
+		Merchant: &Merchant{
+			MerchantKey: "CLIENT_KEY",
+			SecretKey:   "CLIENT_PASS",
+			TermsURL:    ref("https://example.com/3ds"),
+		},
 		PaymentMethod: &PaymentMethod{
 			Card: &Card{
 				Pan:             ref("4111111111111111"),
@@ -303,14 +379,109 @@ func TestBuildIAPaymentRequest_CardPAN_IsNotSupported(t *testing.T) {
 			Phone: ref("380631234567"),
 		},
 	}
+}
+
+// testClock is pinned before the 01/2026 expiry of newCardPANPaymentRequest.
+var testClock = platon.ClockFunc(func() time.Time { return time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC) })
+
+func TestBuildIAPaymentRequest_CardPAN(t *testing.T) {
+	for _, hold := range []bool{false, true} {
+		c := &client{}
+		apiReq, apiURL, err := c.buildIAPaymentRequest(newCardPANPaymentRequest(), hold)
+		if err != nil {
+			t.Fatalf("buildIAPaymentRequest(hold=%v) error: %v", hold, err)
+		}
+
+		if apiURL != consts.ApiPostUnqURL {
+			t.Fatalf("apiURL mismatch: want %q, got %q", consts.ApiPostUnqURL, apiURL)
+		}
+		if apiReq.Action != platon.ActionCodeSALE.String() {
+			t.Fatalf("action mismatch: want %q, got %q", platon.ActionCodeSALE.String(), apiReq.Action)
+		}
+		if apiReq.HashType != platon.HashTypeCardPayment {
+			t.Fatalf("hash type mismatch: want %q, got %q", platon.HashTypeCardPayment, apiReq.HashType)
+		}
+		if apiReq.CardNumber == nil || *apiReq.CardNumber != "4111111111111111" {
+			t.Fatalf("card_number mismatch: got %v", apiReq.CardNumber)
+		}
+		if apiReq.ReqToken == nil || *apiReq.ReqToken != "N" || apiReq.RecurringInit == nil || *apiReq.RecurringInit != "N" {
+			t.Fatalf("req_token/recurring_init must default to N for card PAN payment")
+		}
+		if hold && (apiReq.AuthFlag == nil || *apiReq.AuthFlag != "Y") {
+			t.Fatalf("hold mode must set auth=Y, got %v", apiReq.AuthFlag)
+		}
+		if !hold && apiReq.AuthFlag != nil {
+			t.Fatalf("payment mode must not set auth, got %q", *apiReq.AuthFlag)
+		}
+
+		if _, err := apiReq.WithClock(testClock).SignAndPrepare(); err != nil {
+			t.Fatalf("SignAndPrepare(hold=%v) error: %v", hold, err)
+		}
+	}
+}
+
+func TestBuildIAPaymentRequest_CardPAN_WithSplitRules(t *testing.T) {
+	req := newCardPANPaymentRequest()
+	req.PaymentData.Amount = 10000
+	req.PaymentData.SplitRules = []SplitRule{
+		{SubmerchantIdentification: "submerchant_01", Amount: 2500},
+		{SubmerchantIdentification: "submerchant_02", Amount: 7500},
+	}
 
 	c := &client{}
-	if _, _, err := c.buildIAPaymentRequest(req, false); err == nil {
-		t.Fatalf("buildIAPaymentRequest() expected error for PAN payment, got nil")
+	apiReq, _, err := c.buildIAPaymentRequest(req, false)
+	if err != nil {
+		t.Fatalf("buildIAPaymentRequest() error: %v", err)
+	}
+	if len(apiReq.SplitRules) != 2 {
+		t.Fatalf("split rules mismatch: got %v", apiReq.SplitRules)
+	}
+	if _, err := apiReq.WithClock(testClock).SignAndPrepare(); err != nil {
+		t.Fatalf("SignAndPrepare() error: %v", err)
+	}
+}
+
+func TestBuildIAPaymentRequest_CardPAN_MissingCardData(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(card *Card)
+		wantErr string
+	}{
+		{name: "month", mutate: func(card *Card) { card.ExpirationMonth = nil }, wantErr: "payment: card_exp_month is required"},
+		{name: "year", mutate: func(card *Card) { card.ExpirationYear = ref("") }, wantErr: "payment: card_exp_year is required"},
+		{name: "cvv2", mutate: func(card *Card) { card.Cvv2 = nil }, wantErr: "payment: card_cvv2 is required"},
+	}
+
+	for _, tt := range tests {
+		req := newCardPANPaymentRequest()
+		tt.mutate(req.PaymentMethod.Card)
+
+		c := &client{}
+		_, _, err := c.buildIAPaymentRequest(req, false)
+		if !errors.Is(err, platon.ErrValidation) || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Fatalf("%s: expected a validation error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
 	}
 }
 
-func TestBuildIAPaymentRequest_CardPAN_WithSplitRules_IsNotSupported(t *testing.T) {
+func TestBuildIAPaymentRequest_CardTokenPreferredOverPAN(t *testing.T) {
+	req := newCardPANPaymentRequest()
+	req.PaymentMethod.Card.Token = ref("CARD_TOKEN")
+
+	c := &client{}
+	apiReq, _, err := c.buildIAPaymentRequest(req, false)
+	if err != nil {
+		t.Fatalf("buildIAPaymentRequest() error: %v", err)
+	}
+	if apiReq.HashType != platon.HashTypeCardTokenPayment {
+		t.Fatalf("hash type mismatch: want %q, got %q", platon.HashTypeCardTokenPayment, apiReq.HashType)
+	}
+	if apiReq.CardNumber != nil {
+		t.Fatalf("card_number must not be sent when a token is present")
+	}
+}
+
+func TestBuildIAPaymentRequest_CardPAN_SplitRulesTotalExceedsAmount(t *testing.T) {
 	merchant := &Merchant{
 		MerchantKey: "CLIENT_KEY",
 		SecretKey:   "CLIENT_PASS",
@@ -329,12 +500,12 @@ func TestBuildIAPaymentRequest_CardPAN_WithSplitRules_IsNotSupported(t *testing.
 		},
 		PaymentData: &PaymentData{
 			PaymentID:   ref("order-1"),
-			Amount:      10000,
+			Amount:      1000,
 			Currency:    currency.UAH,
 			Description: "desc",
 			SplitRules: []SplitRule{
-				{SubmerchantIdentification: "submerchant_01", Amount: 2500},
-				{SubmerchantIdentification: "submerchant_02", Amount: 7500},
+				{SubmerchantIdentification: "submerchant_01", Amount: 600},
+				{SubmerchantIdentification: "submerchant_02", Amount: 500},
 			},
 		},
 		PersonalData: &PersonalData{
@@ -345,45 +516,586 @@ func TestBuildIAPaymentRequest_CardPAN_WithSplitRules_IsNotSupported(t *testing.
 
 	c := &client{}
 	if _, _, err := c.buildIAPaymentRequest(req, false); err == nil {
-		t.Fatalf("buildIAPaymentRequest() expected error for PAN payment, got nil")
+		t.Fatalf("expected split rules validation error, got nil")
 	}
 }
 
-func TestBuildIAPaymentRequest_CardPAN_SplitRulesTotalExceedsAmount(t *testing.T) {
+func TestPaymentByCard_DryRun(t *testing.T) {
+	var capturedEndpoint string
+	var capturedRequest *platon.Request
+
+	req := newCardPANPaymentRequest()
+	req.PaymentMethod.Card.Token = ref("CARD_TOKEN")
+	req.PaymentData.Metadata = map[string]string{"req_token": "Y", "recurring_init": "true"}
+
+	c := &client{}
+	_, err := c.PaymentByCard(
+		req, DryRun(
+			func(endpoint string, payload any) {
+				capturedEndpoint = endpoint
+				capturedRequest, _ = payload.(*platon.Request)
+			},
+		),
+	)
+	if err != nil {
+		t.Fatalf("PaymentByCard() unexpected error: %v", err)
+	}
+	if capturedEndpoint != consts.ApiPostUnqURL {
+		t.Fatalf("PaymentByCard() endpoint mismatch: want %q, got %q", consts.ApiPostUnqURL, capturedEndpoint)
+	}
+	if capturedRequest == nil {
+		t.Fatal("PaymentByCard() captured request is nil")
+	}
+	if capturedRequest.Action != platon.ActionCodeSALE.String() {
+		t.Fatalf("PaymentByCard() action mismatch: got %q", capturedRequest.Action)
+	}
+	if capturedRequest.HashType != platon.HashTypeCardPayment {
+		t.Fatalf("PaymentByCard() hash type mismatch: got %q", capturedRequest.HashType)
+	}
+	if capturedRequest.CardToken != nil {
+		t.Fatalf("PaymentByCard() must not send card_token, got %q", *capturedRequest.CardToken)
+	}
+	if capturedRequest.CardNumber == nil || *capturedRequest.CardNumber != "4111111111111111" {
+		t.Fatalf("PaymentByCard() card_number mismatch: got %v", capturedRequest.CardNumber)
+	}
+	if capturedRequest.ReqToken == nil || *capturedRequest.ReqToken != "Y" {
+		t.Fatalf("PaymentByCard() req_token mismatch: got %v", capturedRequest.ReqToken)
+	}
+	if capturedRequest.RecurringInit == nil || *capturedRequest.RecurringInit != "Y" {
+		t.Fatalf("PaymentByCard() recurring_init mismatch: got %v", capturedRequest.RecurringInit)
+	}
+
+	if _, err := capturedRequest.WithClock(testClock).SignAndPrepare(); err != nil {
+		t.Fatalf("SignAndPrepare() error: %v", err)
+	}
+}
+
+func TestPaymentByCard_RequiresCardData(t *testing.T) {
+	c := &client{}
+
+	cases := map[string]struct {
+		mutate func(*Request)
+		want   string
+	}{
+		"pan":    {func(r *Request) { r.PaymentMethod.Card.Pan = nil }, "payment by card: card_number is required"},
+		"month":  {func(r *Request) { r.PaymentMethod.Card.ExpirationMonth = nil }, "payment by card: card_exp_month is required"},
+		"year":   {func(r *Request) { r.PaymentMethod.Card.ExpirationYear = ref(" ") }, "payment by card: card_exp_year is required"},
+		"cvv2":   {func(r *Request) { r.PaymentMethod.Card.Cvv2 = nil }, "payment by card: card_cvv2 is required"},
+		"method": {func(r *Request) { r.PaymentMethod = nil }, "payment by card: card_number is required"},
+	}
+	for name, tc := range cases {
+		req := newCardPANPaymentRequest()
+		tc.mutate(req)
+
+		_, err := c.PaymentByCard(req, DryRun(func(string, any) {}))
+		if !errors.Is(err, platon.ErrValidation) || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected %q validation error, got %v", name, tc.want, err)
+		}
+	}
+}
+
+func TestRefundByOrder_ResolvesTransIDThenRefunds(t *testing.T) {
+	var actions []string
+	var refundTransID string
+
+	cl := NewClient(
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(r *http.Request) (*http.Response, error) {
+						if err := r.ParseForm(); err != nil {
+							return nil, err
+						}
+						action := r.PostForm.Get("action")
+						actions = append(actions, action)
+
+						body := `{"action":"GET_TRANS_STATUS_BY_ORDER","result":"SUCCESS","status":"SETTLED","order_id":"order-1","trans_id":"trans-42"}`
+						if action == platon.ActionCodeCREDITVOID.String() {
+							refundTransID = r.PostForm.Get("trans_id")
+							body = `{"action":"CREDITVOID","result":"ACCEPTED","order_id":"order-1","trans_id":"trans-42"}`
+						}
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(body)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
+	resp, err := cl.RefundByOrder(
+		&Request{
+			Merchant:     &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+			PersonalData: &PersonalData{Email: ref("payer@example.com")},
+			PaymentData: &PaymentData{
+				PaymentID: ref("order-1"),
+				Amount:    100,
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("RefundByOrder() error: %v", err)
+	}
+	if resp == nil || resp.Action == nil || *resp.Action != "CREDITVOID" {
+		t.Fatalf("RefundByOrder() unexpected response: %+v", resp)
+	}
+	want := []string{platon.ActionCodeGetTransStatusByOrder.String(), platon.ActionCodeCREDITVOID.String()}
+	if strings.Join(actions, ",") != strings.Join(want, ",") {
+		t.Fatalf("RefundByOrder() call sequence = %v, want %v", actions, want)
+	}
+	if refundTransID != "trans-42" {
+		t.Fatalf("RefundByOrder() refund trans_id = %q, want %q", refundTransID, "trans-42")
+	}
+}
+
+func TestRefundByOrder_NoTransIDFound(t *testing.T) {
+	calls := 0
+	cl := NewClient(
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(*http.Request) (*http.Response, error) {
+						calls++
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(`{"action":"GET_TRANS_STATUS_BY_ORDER","result":"SUCCESS","order_id":"order-1"}`)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
+	_, err := cl.RefundByOrder(
+		&Request{
+			Merchant:    &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+			PaymentData: &PaymentData{PaymentID: ref("order-1"), Amount: 100},
+		},
+	)
+	if err == nil || !strings.Contains(err.Error(), `refund by order: no trans_id found for order_id "order-1"`) {
+		t.Fatalf("expected missing trans_id error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("refund must not be sent without trans_id, got %d calls", calls)
+	}
+}
+
+func TestDetails_SendsGetTransDetails(t *testing.T) {
+	var path string
+	var form url.Values
+	cl := NewClient(
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(r *http.Request) (*http.Response, error) {
+						if err := r.ParseForm(); err != nil {
+							return nil, err
+						}
+						path, form = r.URL.Path, r.PostForm
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body: io.NopCloser(
+								strings.NewReader(`{"action":"GET_TRANS_DETAILS","result":"SUCCESS","status":"SETTLED","trans_id":"632508054","card":"411111****1111","payer_name":"Ivan Petrenko","fee":"2.75"}`),
+							),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
+	resp, err := cl.Details(
+		&Request{
+			Merchant:    &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+			PaymentData: &PaymentData{PlatonTransID: ref("632508054")},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Details() error: %v", err)
+	}
+	if path != "/post-unq/" || form.Get("action") != "GET_TRANS_DETAILS" || form.Get("trans_id") != "632508054" {
+		t.Fatalf("unexpected request: path=%q form=%v", path, form)
+	}
+	if resp.Card != "411111****1111" || resp.PayerName != "Ivan Petrenko" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	if _, err := cl.Details(&Request{Merchant: &Merchant{MerchantKey: "CLIENT_KEY"}, PaymentData: &PaymentData{}}); err == nil ||
+		!strings.Contains(err.Error(), "trans_id is required for GET_TRANS_DETAILS") {
+		t.Fatalf("expected trans_id error, got %v", err)
+	}
+}
+
+func TestPayment_RequiresRealPayerIP(t *testing.T) {
+	calls := 0
+	cl := NewClient(
+		WithClock(testClock),
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(*http.Request) (*http.Response, error) {
+						calls++
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(`{"result":"ACCEPTED"}`)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
+	for _, ip := range []*string{nil, ref("127.0.0.1"), ref("::1"), ref("0.0.0.0"), ref("not-an-ip")} {
+		req := newCardPANPaymentRequest()
+		req.Merchant.ClientIP = ip
+
+		_, err := cl.Payment(req)
+		if err == nil || !strings.Contains(err.Error(), "set Merchant.ClientIP or use Merchant.SetClientIPFromHTTP") {
+			t.Fatalf("ClientIP %v: expected payer IP error, got %v", ip, err)
+		}
+		if _, err := cl.Hold(req); !errors.Is(err, platon.ErrValidation) || !strings.HasPrefix(err.Error(), "hold: payer_ip") {
+			t.Fatalf("ClientIP %v: expected hold payer IP error, got %v", ip, err)
+		}
+
+		// Dry runs keep accepting the loopback fallback.
+		if _, err := cl.Payment(req, DryRun(func(string, any) {})); err != nil {
+			t.Fatalf("ClientIP %v: DryRun unexpected error: %v", ip, err)
+		}
+	}
+	if calls != 0 {
+		t.Fatalf("requests without a real payer IP must not be sent, got %d calls", calls)
+	}
+
+	req := newCardPANPaymentRequest()
+	req.Merchant.ClientIP = ref("198.51.100.20")
+	if _, err := cl.Payment(req); err != nil {
+		t.Fatalf("Payment() with real IP error: %v", err)
+	}
+}
+
+type tagRecorder struct {
+	recorder.Recorder
+
+	mu        sync.Mutex
+	requests  []map[string]string
+	responses []map[string]string
+}
+
+func (r *tagRecorder) RecordRequest(_ context.Context, _ *string, _ string, _ []byte, tags map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, tags)
+	return nil
+}
+
+func (r *tagRecorder) RecordResponse(_ context.Context, _ *string, _ string, _ []byte, tags map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses = append(r.responses, tags)
+	return nil
+}
+
+func (r *tagRecorder) RecordError(context.Context, *string, string, error, map[string]string) error {
+	return nil
+}
+
+func TestPayment_PerRequestPayerIPAndTermsURL(t *testing.T) {
+	var forms []url.Values
+	cl := NewClient(
+		WithClock(testClock),
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(r *http.Request) (*http.Response, error) {
+						if err := r.ParseForm(); err != nil {
+							return nil, err
+						}
+						forms = append(forms, r.PostForm)
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(`{"result":"ACCEPTED"}`)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
 	merchant := &Merchant{
 		MerchantKey: "CLIENT_KEY",
 		SecretKey:   "CLIENT_PASS",
-		TermsURL:    ref("https://example.com/3ds"),
+		ClientIP:    ref("203.0.113.10"),
+		TermsURL:    ref("https://merchant.example/terms"),
 	}
 
-	req := &Request{
-		Merchant: merchant,
-		PaymentMethod: &PaymentMethod{
-			Card: &Card{
-				Pan:             ref("4111111111111111"),
-				ExpirationMonth: ref("01"),
-				ExpirationYear:  ref("2026"),
-				Cvv2:            ref("123"),
+	perRequest := newCardPANPaymentRequest()
+	perRequest.Merchant = merchant
+	perRequest.PaymentData.PayerIP = ref("198.51.100.7")
+	perRequest.PaymentData.TermsURL = ref("https://merchant.example/order-terms")
+	if _, err := cl.Payment(perRequest); err != nil {
+		t.Fatalf("Payment() error: %v", err)
+	}
+
+	fallback := newCardPANPaymentRequest()
+	fallback.Merchant = merchant
+	if _, err := cl.Payment(fallback); err != nil {
+		t.Fatalf("Payment() error: %v", err)
+	}
+
+	if len(forms) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(forms))
+	}
+	if got := forms[0].Get("payer_ip"); got != "198.51.100.7" {
+		t.Fatalf("payer_ip = %q, want per-request IP", got)
+	}
+	if got := forms[0].Get("term_url_3ds"); got != "https://merchant.example/order-terms" {
+		t.Fatalf("term_url_3ds = %q, want per-request URL", got)
+	}
+	if got := forms[1].Get("payer_ip"); got != "203.0.113.10" {
+		t.Fatalf("payer_ip = %q, want merchant IP", got)
+	}
+	if got := forms[1].Get("term_url_3ds"); got != "https://merchant.example/terms" {
+		t.Fatalf("term_url_3ds = %q, want merchant URL", got)
+	}
+	if merchant.ClientIP == nil || *merchant.ClientIP != "203.0.113.10" {
+		t.Fatalf("shared merchant was modified: %v", merchant.ClientIP)
+	}
+}
+
+func TestRecorder_ReceivesCorrelationTags(t *testing.T) {
+	rec := &tagRecorder{}
+	cl := NewClient(
+		WithClock(testClock),
+		WithRecorder(rec),
+		WithRecorderAmountTag(),
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(r *http.Request) (*http.Response, error) {
+						if err := r.ParseForm(); err != nil {
+							return nil, err
+						}
+						body := `{"action":"` + r.PostForm.Get("action") + `","result":"SUCCESS","status":"SETTLED","order_id":"order-1","trans_id":"trans-1"}`
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(body)),
+						}, nil
+					},
+				),
 			},
+		),
+	)
+
+	payment := newCardPANPaymentRequest()
+	payment.Merchant.ClientIP = ref("203.0.113.10")
+	if _, err := cl.Payment(payment); err != nil {
+		t.Fatalf("Payment() error: %v", err)
+	}
+	if _, err := cl.Capture(
+		&Request{
+			Merchant:     &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+			PersonalData: &PersonalData{Email: ref("payer@example.com")},
+			PaymentData:  &PaymentData{PlatonTransID: ref("trans-1"), Amount: 100},
 		},
+	); err != nil {
+		t.Fatalf("Capture() error: %v", err)
+	}
+	if _, err := cl.Status(
+		&Request{
+			Merchant:    &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+			PaymentData: &PaymentData{PaymentID: ref("order-1")},
+		},
+	); err != nil {
+		t.Fatalf("Status() error: %v", err)
+	}
+
+	wantRequests := []map[string]string{
+		{"action": "SALE", "hash_type": "card_payment", "order_id": "order-1", "currency": "UAH", "amount": "1.00"},
+		{"action": "CAPTURE", "hash_type": "capture", "trans_id": "trans-1", "amount": "1.00"},
+		{"action": "GET_TRANS_STATUS_BY_ORDER", "hash_type": "get_trans_status_by_order", "order_id": "order-1"},
+	}
+	if len(rec.requests) != len(wantRequests) || len(rec.responses) != len(wantRequests) {
+		t.Fatalf("recorded %d requests and %d responses, want %d each", len(rec.requests), len(rec.responses), len(wantRequests))
+	}
+
+	for i, want := range wantRequests {
+		request, response := rec.requests[i], rec.responses[i]
+		want["client_key"] = "CLIENT_KEY"
+		want["dry_run"] = "false"
+		for key, value := range want {
+			if request[key] != value {
+				t.Fatalf("request %d tag %s = %q, want %q (tags %v)", i, key, request[key], value, request)
+			}
+			if response[key] != value {
+				t.Fatalf("response %d tag %s = %q, want %q (tags %v)", i, key, response[key], value, response)
+			}
+		}
+		if request["request_id"] == "" || response["request_id"] != request["request_id"] {
+			t.Fatalf("request %d: request_id tags %q / %q must match", i, request["request_id"], response["request_id"])
+		}
+		if response["http_status"] != "200" || response["duration_ms"] == "" || response["attempt"] != "1" {
+			t.Fatalf("response %d tags %v, want http_status, duration_ms and attempt", i, response)
+		}
+		for _, tags := range []map[string]string{request, response} {
+			for key, value := range tags {
+				if strings.Contains(value, "CLIENT_PASS") || strings.Contains(value, "4111111111111111") {
+					t.Fatalf("tag %s leaks a secret: %q", key, value)
+				}
+			}
+		}
+	}
+}
+
+func TestRecorder_TagsCaptureBusinessContext(t *testing.T) {
+	rec := &tagRecorder{}
+	cl := NewClient(
+		WithRecorder(rec),
+		WithRecorderAmountTag(),
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(*http.Request) (*http.Response, error) {
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(`{"action":"CAPTURE","result":"SUCCESS","trans_id":"trans-7"}`)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
+	_, err := cl.Capture(
+		&Request{
+			Merchant:     &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+			PersonalData: &PersonalData{Email: ref("payer@example.com")},
+			PaymentData: &PaymentData{
+				PlatonTransID: ref("trans-7"),
+				Amount:        12345,
+				SplitRules: []SplitRule{
+					{SubmerchantIdentification: "sub-b", Amount: 2345},
+					{SubmerchantIdentification: "sub-a", Amount: 10000},
+				},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Capture() error: %v", err)
+	}
+
+	if len(rec.requests) != 1 || len(rec.responses) != 1 {
+		t.Fatalf("recorded %d requests and %d responses, want 1 each", len(rec.requests), len(rec.responses))
+	}
+	want := map[string]string{
+		"action":         "CAPTURE",
+		"trans_id":       "trans-7",
+		"client_key":     "CLIENT_KEY",
+		"hash_type":      "capture",
+		"submerchant_id": "sub-a,sub-b",
+		"amount":         "123.45",
+		"dry_run":        "false",
+	}
+	for _, tags := range []map[string]string{rec.requests[0], rec.responses[0]} {
+		for key, value := range want {
+			if tags[key] != value {
+				t.Fatalf("tag %s = %q, want %q (tags %v)", key, tags[key], value, tags)
+			}
+		}
+		if tags["request_id"] == "" || tags["request_id"] != rec.requests[0]["request_id"] {
+			t.Fatalf("request_id tag = %q, want the request's id", tags["request_id"])
+		}
+	}
+}
+
+func TestRecorder_TagsDryRuns(t *testing.T) {
+	rec := &tagRecorder{}
+	cl := NewClient(WithClock(testClock), WithRecorder(rec))
+
+	payment := newCardPANPaymentRequest()
+	payment.Merchant.ClientIP = ref("203.0.113.10")
+	var payload *platon.Request
+	if _, err := cl.Payment(payment, DryRun(func(_ string, p any) { payload, _ = p.(*platon.Request) })); err != nil {
+		t.Fatalf("Payment() dry run error: %v", err)
+	}
+
+	if len(rec.requests) != 1 || len(rec.responses) != 0 {
+		t.Fatalf("recorded %d requests and %d responses, want 1 request", len(rec.requests), len(rec.responses))
+	}
+	if tags := rec.requests[0]; tags["dry_run"] != "true" || tags["action"] != "SALE" || tags["request_id"] == "" {
+		t.Fatalf("dry run tags = %v", tags)
+	}
+	if amount, ok := rec.requests[0]["amount"]; ok {
+		t.Fatalf("amount tag = %q, want none without WithRecorderAmountTag", amount)
+	}
+	if payload == nil || payload.Hash != "" {
+		t.Fatalf("dry-run handler must get the unsigned request, got %+v", payload)
+	}
+}
+
+func TestPayment_UnsupportedCurrencyFailsBeforeSending(t *testing.T) {
+	var calls int
+	cl := NewClient(
+		WithClient(
+			&http.Client{
+				Transport: roundTripperFunc(
+					func(*http.Request) (*http.Response, error) {
+						calls++
+						return nil, io.ErrUnexpectedEOF
+					},
+				),
+			},
+		),
+	)
+
+	payment := newCardPANPaymentRequest()
+	payment.Merchant.ClientIP = ref("203.0.113.10")
+	payment.PaymentData.Currency = "UAG"
+
+	_, err := cl.Payment(payment)
+	if err == nil || !strings.Contains(err.Error(), `unsupported currency "UAG"`) {
+		t.Fatalf("Payment() error = %v, want an unsupported currency error", err)
+	}
+	if calls != 0 {
+		t.Fatalf("HTTP calls = %d, want 0 for a pre-flight error", calls)
+	}
+}
+
+func TestRecorder_TagsGooglePayCardNetwork(t *testing.T) {
+	rec := &tagRecorder{}
+	cl := NewClient(WithRecorder(rec))
+
+	googlePay := base64.StdEncoding.EncodeToString(
+		[]byte(`{"paymentMethodData":{"info":{"cardNetwork":"MASTERCARD","cardDetails":"4444"},"tokenizationData":{"token":"{}"}}}`),
+	)
+	payment := &Request{
+		Merchant: &Merchant{
+			MerchantKey: "CLIENT_KEY",
+			SecretKey:   "CLIENT_PASS",
+			TermsURL:    ref("https://example.com/3ds"),
+			ClientIP:    ref("203.0.113.10"),
+		},
+		PaymentMethod: &PaymentMethod{GoogleToken: &googlePay},
 		PaymentData: &PaymentData{
 			PaymentID:   ref("order-1"),
-			Amount:      1000,
+			Amount:      100,
 			Currency:    currency.UAH,
 			Description: "desc",
-			SplitRules: []SplitRule{
-				{SubmerchantIdentification: "submerchant_01", Amount: 600},
-				{SubmerchantIdentification: "submerchant_02", Amount: 500},
-			},
-		},
-		PersonalData: &PersonalData{
-			Email: ref("payer@example.com"),
-			Phone: ref("380631234567"),
 		},
+		PersonalData: &PersonalData{Email: ref("payer@example.com"), Phone: ref("380631234567")},
+	}
+	if _, err := cl.Payment(payment, DryRun(func(string, any) {})); err != nil {
+		t.Fatalf("Payment() dry run error: %v", err)
 	}
 
-	c := &client{}
-	if _, _, err := c.buildIAPaymentRequest(req, false); err == nil {
-		t.Fatalf("expected split rules validation error, got nil")
+	if len(rec.requests) != 1 || rec.requests[0]["card_network"] != "MASTERCARD" {
+		t.Fatalf("recorded tags = %v, want card_network=MASTERCARD", rec.requests)
 	}
 }
@@ -0,0 +1,107 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stremovskyy/go-platon/consts"
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+func newVoidRequest() *Request {
+	return &Request{
+		Merchant: &Merchant{
+			MerchantKey: "CLIENT_KEY",
+			SecretKey:   "CLIENT_PASS",
+		},
+		PersonalData: &PersonalData{
+			Email: ref("payer@example.com"),
+		},
+		PaymentData: &PaymentData{
+			PlatonTransID: ref("632508054"),
+		},
+	}
+}
+
+func TestVoid_DryRun_BuildsCreditVoidWithoutAmount(t *testing.T) {
+	var capturedEndpoint string
+	var capturedRequest *platon.Request
+
+	c := &client{}
+	_, err := c.Void(
+		newVoidRequest(), DryRun(
+			func(endpoint string, payload any) {
+				capturedEndpoint = endpoint
+				capturedRequest, _ = payload.(*platon.Request)
+			},
+		),
+	)
+	if err != nil {
+		t.Fatalf("Void() unexpected error: %v", err)
+	}
+
+	if capturedEndpoint != consts.ApiPostUnqURL {
+		t.Fatalf("Void() endpoint mismatch: want %q, got %q", consts.ApiPostUnqURL, capturedEndpoint)
+	}
+	if capturedRequest == nil {
+		t.Fatal("Void() captured request is nil")
+	}
+	if capturedRequest.Action != platon.ActionCodeCREDITVOID.String() {
+		t.Fatalf("Void() action mismatch: want %q, got %q", platon.ActionCodeCREDITVOID.String(), capturedRequest.Action)
+	}
+	if capturedRequest.HashType != platon.HashTypeVoid {
+		t.Fatalf("Void() hash type mismatch: want %q, got %q", platon.HashTypeVoid, capturedRequest.HashType)
+	}
+	if capturedRequest.Amount != "" {
+		t.Fatalf("Void() amount should be empty, got %q", capturedRequest.Amount)
+	}
+	if capturedRequest.TransId == nil || *capturedRequest.TransId != "632508054" {
+		t.Fatalf("Void() trans_id mismatch: got %v", capturedRequest.TransId)
+	}
+}
+
+func TestVoid_RejectsAmountSplitRulesAndMissingTransID(t *testing.T) {
+	c := &client{}
+
+	withAmount := newVoidRequest()
+	withAmount.PaymentData.Amount = 100
+	if _, err := c.Void(withAmount, DryRun(func(string, any) {})); err == nil || !strings.Contains(err.Error(), "use Refund") {
+		t.Fatalf("expected amount error, got %v", err)
+	}
+
+	withSplit := newVoidRequest()
+	withSplit.PaymentData.SplitRules = []SplitRule{{SubmerchantIdentification: "1", Amount: 100}}
+	if _, err := c.Void(withSplit, DryRun(func(string, any) {})); err == nil || !strings.Contains(err.Error(), "split_rules are not supported") {
+		t.Fatalf("expected split rules error, got %v", err)
+	}
+
+	noTransID := newVoidRequest()
+	noTransID.PaymentData = &PaymentData{PaymentID: ref("order-1")}
+	if _, err := c.Void(noTransID, DryRun(func(string, any) {})); err == nil || !strings.Contains(err.Error(), "void: trans_id is required") {
+		t.Fatalf("expected trans_id error, got %v", err)
+	}
+}
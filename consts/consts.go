@@ -28,24 +28,33 @@ const (
 	Version    = "1.0.0"
 	ApiVersion = "1.28"
 
-	baseUrl = "https://secure.platononline.com"
+	// BaseURL is the production gateway. Every Api*URL below is BaseURL
+	// followed by the matching Api*Path.
+	BaseURL = "https://secure.platononline.com"
 
-	ApiPaymentURL = baseUrl + "/payment"
+	ApiPaymentPath       = "/payment"
+	ApiPaymentAuthPath   = "/payment/auth"
+	ApiConfigurationPath = "/configuration/"
+	ApiPostPath          = "/post/"
+	ApiPostUnqPath       = "/post-unq/"
+	ApiP2PUnqPath        = "/p2p-unq/"
+
+	ApiPaymentURL = BaseURL + ApiPaymentPath
 	// ApiPaymentAuthURL is the Client-Server browser endpoint used by card verification.
-	ApiPaymentAuthURL = baseUrl + "/payment/auth"
+	ApiPaymentAuthURL = BaseURL + ApiPaymentAuthPath
 
 	// ApiConfigurationURL is the IA configuration endpoint (e.g. GET_SUBMERCHANT).
-	ApiConfigurationURL = baseUrl + "/configuration/"
+	ApiConfigurationURL = BaseURL + ApiConfigurationPath
 
 	// ApiPostURL is the IA endpoint for Apple Pay and Google Pay.
-	ApiPostURL = baseUrl + "/post/"
+	ApiPostURL = BaseURL + ApiPostPath
 
 	// ApiPostUnqURL is the IA Server-Server endpoint for card payments, verification, one-click,
 	// recurring by token, capture/refund, and status.
-	ApiPostUnqURL = baseUrl + "/post-unq/"
+	ApiPostUnqURL = BaseURL + ApiPostUnqPath
 
 	// ApiP2PUnqURL is the A2C Server-Server endpoint for payouts (CREDIT2CARD) and A2C status checks.
-	ApiP2PUnqURL = baseUrl + "/p2p-unq/"
+	ApiP2PUnqURL = BaseURL + ApiP2PUnqPath
 
 	// ApiVerifyURL is the legacy name for the IA Server-Server endpoint (`/post-unq/`).
 	// It is used both for card verification and card/token payments.
@@ -0,0 +1,166 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownMerchant is returned (wrapped) when a CredentialStore has no entry
+// for the requested merchant key.
+var ErrUnknownMerchant = errors.New("unknown merchant")
+
+// CredentialStore resolves merchant credentials by client_key, so requests can
+// carry only Merchant.MerchantKey. Implementations must be safe for concurrent
+// use and should return an error wrapping ErrUnknownMerchant for missing keys.
+type CredentialStore interface {
+	Resolve(ctx context.Context, merchantKey string) (*Merchant, error)
+}
+
+// MemoryCredentialStore is a map-backed CredentialStore.
+type MemoryCredentialStore struct {
+	mu        sync.RWMutex
+	merchants map[string]Merchant
+}
+
+var _ CredentialStore = (*MemoryCredentialStore)(nil)
+
+// NewMemoryCredentialStore returns a store holding copies of merchants keyed by
+// their MerchantKey.
+func NewMemoryCredentialStore(merchants ...*Merchant) *MemoryCredentialStore {
+	store := &MemoryCredentialStore{merchants: make(map[string]Merchant, len(merchants))}
+	for _, merchant := range merchants {
+		store.Add(merchant)
+	}
+
+	return store
+}
+
+// Add stores a copy of merchant, replacing any entry with the same MerchantKey.
+// Merchants without a MerchantKey are ignored.
+func (s *MemoryCredentialStore) Add(merchant *Merchant) {
+	if s == nil || merchant == nil {
+		return
+	}
+
+	key := strings.TrimSpace(merchant.MerchantKey)
+	if key == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.merchants == nil {
+		s.merchants = make(map[string]Merchant)
+	}
+	s.merchants[key] = *merchant
+}
+
+// Remove deletes the entry for merchantKey.
+func (s *MemoryCredentialStore) Remove(merchantKey string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.merchants, strings.TrimSpace(merchantKey))
+}
+
+// Resolve returns a copy of the merchant stored under merchantKey.
+func (s *MemoryCredentialStore) Resolve(ctx context.Context, merchantKey string) (*Merchant, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMerchant, merchantKey)
+	}
+
+	s.mu.RLock()
+	merchant, ok := s.merchants[strings.TrimSpace(merchantKey)]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMerchant, merchantKey)
+	}
+
+	return &merchant, nil
+}
+
+// resolveMerchant fills the request's merchant from the configured credential
+// store. Values set explicitly on the request win over stored ones; ClientIP is
+// per payer and is never taken from the store. The caller's request is never
+// modified.
+func (c *client) resolveMerchant(ctx context.Context, request *Request) (*Request, error) {
+	if c == nil || c.credentialStore == nil || request == nil || request.Merchant == nil {
+		return request, nil
+	}
+
+	merchantKey := strings.TrimSpace(request.Merchant.MerchantKey)
+	if merchantKey == "" {
+		return request, nil
+	}
+
+	stored, err := c.credentialStore.Resolve(ctx, merchantKey)
+	if err == nil && stored == nil {
+		err = fmt.Errorf("%w: %q", ErrUnknownMerchant, merchantKey)
+	}
+	if err != nil {
+		if errors.Is(err, ErrUnknownMerchant) && strings.TrimSpace(request.Merchant.SecretKey) != "" {
+			// Fully specified merchants do not need a store entry.
+			return request, nil
+		}
+		return nil, fmt.Errorf("credential store: %w", err)
+	}
+
+	merchant := *request.Merchant
+	fillString(&merchant.Name, stored.Name)
+	fillString(&merchant.MerchantID, stored.MerchantID)
+	fillString(&merchant.SecretKey, stored.SecretKey)
+	fillString(&merchant.Login, stored.Login)
+	fillString(&merchant.SuccessRedirect, stored.SuccessRedirect)
+	fillString(&merchant.FailRedirect, stored.FailRedirect)
+	if (merchant.TermsURL == nil || strings.TrimSpace(*merchant.TermsURL) == "") && stored.TermsURL != nil {
+		termsURL := *stored.TermsURL
+		merchant.TermsURL = &termsURL
+	}
+
+	resolved := *request
+	resolved.Merchant = &merchant
+
+	return &resolved, nil
+}
+
+func fillString(dst *string, value string) {
+	if strings.TrimSpace(*dst) == "" {
+		*dst = value
+	}
+}
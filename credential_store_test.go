@@ -0,0 +1,188 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stremovskyy/go-platon/currency"
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+func newStoreTestPayment(merchant *Merchant) *Request {
+	return &Request{
+		Merchant: merchant,
+		PaymentData: &PaymentData{
+			PaymentID:   ref("order-1"),
+			Amount:      100,
+			Currency:    currency.UAH,
+			Description: "credential store",
+		},
+		PaymentMethod: &PaymentMethod{Card: &Card{Token: ref("CARD_TOKEN")}},
+		PersonalData:  &PersonalData{Email: ref("payer@example.com")},
+	}
+}
+
+func dryRunPayment(t *testing.T, cl Platon, request *Request) *platon.Request {
+	t.Helper()
+
+	var captured *platon.Request
+	_, err := cl.Payment(
+		request, DryRun(
+			func(_ string, payload any) {
+				captured = payload.(*platon.Request)
+			},
+		),
+	)
+	if err != nil {
+		t.Fatalf("Payment() dry run error: %v", err)
+	}
+
+	return captured
+}
+
+func TestCredentialStore_ResolvesMerchant(t *testing.T) {
+	store := NewMemoryCredentialStore(
+		&Merchant{
+			MerchantKey: "tenant-a",
+			SecretKey:   "secret-a",
+			TermsURL:    ref("https://a.example/3ds"),
+		},
+	)
+	cl := NewClient(WithCredentialStore(store))
+
+	request := newStoreTestPayment(&Merchant{MerchantKey: "tenant-a"})
+	got := dryRunPayment(t, cl, request)
+
+	want := dryRunPayment(
+		t, NewDefaultClient(), newStoreTestPayment(
+			&Merchant{
+				MerchantKey: "tenant-a",
+				SecretKey:   "secret-a",
+				TermsURL:    ref("https://a.example/3ds"),
+			},
+		),
+	)
+	if got.Hash != want.Hash {
+		t.Fatalf("hash = %q, want %q (signed with the stored secret)", got.Hash, want.Hash)
+	}
+	if got.TermUrl3ds == nil || *got.TermUrl3ds != "https://a.example/3ds" {
+		t.Fatalf("term_url_3ds = %v, want stored URL", got.TermUrl3ds)
+	}
+	if request.Merchant.SecretKey != "" || request.Merchant.TermsURL != nil {
+		t.Fatal("caller's merchant was modified")
+	}
+}
+
+func TestCredentialStore_ExplicitValuesWin(t *testing.T) {
+	store := NewMemoryCredentialStore(
+		&Merchant{
+			MerchantKey: "tenant-a",
+			SecretKey:   "stored-secret",
+			TermsURL:    ref("https://stored.example/3ds"),
+		},
+	)
+	cl := NewClient(WithCredentialStore(store))
+
+	explicit := &Merchant{
+		MerchantKey: "tenant-a",
+		SecretKey:   "explicit-secret",
+		TermsURL:    ref("https://explicit.example/3ds"),
+	}
+	got := dryRunPayment(t, cl, newStoreTestPayment(explicit))
+	want := dryRunPayment(t, NewDefaultClient(), newStoreTestPayment(explicit))
+
+	if got.Hash != want.Hash {
+		t.Fatalf("hash = %q, want %q (signed with the explicit secret)", got.Hash, want.Hash)
+	}
+	if *got.TermUrl3ds != "https://explicit.example/3ds" {
+		t.Fatalf("term_url_3ds = %q, want explicit URL", *got.TermUrl3ds)
+	}
+
+	// A fully specified merchant does not need a store entry.
+	other := &Merchant{MerchantKey: "tenant-b", SecretKey: "secret-b", TermsURL: ref("https://b.example/3ds")}
+	dryRunPayment(t, cl, newStoreTestPayment(other))
+}
+
+func TestCredentialStore_UnknownMerchant(t *testing.T) {
+	cl := NewClient(WithCredentialStore(NewMemoryCredentialStore()))
+
+	_, err := cl.Payment(newStoreTestPayment(&Merchant{MerchantKey: "missing"}), DryRun())
+	if !errors.Is(err, ErrUnknownMerchant) {
+		t.Fatalf("Payment() error = %v, want ErrUnknownMerchant", err)
+	}
+
+	_, err = cl.Capture(
+		&Request{
+			Merchant:    &Merchant{MerchantKey: "missing"},
+			PaymentData: &PaymentData{PlatonTransID: ref("1"), Amount: 100},
+		},
+	)
+	if !errors.Is(err, ErrUnknownMerchant) {
+		t.Fatalf("Capture() error = %v, want ErrUnknownMerchant", err)
+	}
+}
+
+type failingCredentialStore struct{ err error }
+
+func (s failingCredentialStore) Resolve(context.Context, string) (*Merchant, error) {
+	return nil, s.err
+}
+
+func TestCredentialStore_ResolveError(t *testing.T) {
+	storeErr := errors.New("vault unavailable")
+	cl := NewClient(WithCredentialStore(failingCredentialStore{err: storeErr}))
+
+	// Store failures other than ErrUnknownMerchant fail even with an explicit secret.
+	_, err := cl.Payment(
+		newStoreTestPayment(&Merchant{MerchantKey: "tenant-a", SecretKey: "secret"}),
+		DryRun(),
+	)
+	if !errors.Is(err, storeErr) {
+		t.Fatalf("Payment() error = %v, want store error", err)
+	}
+}
+
+func TestMemoryCredentialStore_AddRemove(t *testing.T) {
+	store := NewMemoryCredentialStore()
+	store.Add(&Merchant{MerchantKey: " tenant-a ", SecretKey: "secret"})
+
+	merchant, err := store.Resolve(context.Background(), "tenant-a")
+	if err != nil || merchant.SecretKey != "secret" {
+		t.Fatalf("Resolve() = %+v, %v", merchant, err)
+	}
+
+	merchant.SecretKey = "changed"
+	if again, _ := store.Resolve(context.Background(), "tenant-a"); again.SecretKey != "secret" {
+		t.Fatal("Resolve() returned a shared merchant")
+	}
+
+	store.Remove("tenant-a")
+	if _, err := store.Resolve(context.Background(), "tenant-a"); !errors.Is(err, ErrUnknownMerchant) {
+		t.Fatalf("Resolve() after Remove error = %v", err)
+	}
+}
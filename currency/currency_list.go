@@ -24,6 +24,11 @@
 
 package currency
 
+import (
+	"sort"
+	"strings"
+)
+
 type Code string
 
 // Currency codes
@@ -31,8 +36,37 @@ const (
 	UAH Code = "UAH"
 	USD Code = "USD"
 	EUR Code = "EUR"
+	GBP Code = "GBP"
+	PLN Code = "PLN"
+	JPY Code = "JPY"
+	KRW Code = "KRW"
+	CHF Code = "CHF"
+	CZK Code = "CZK"
+	CAD Code = "CAD"
 )
 
 func (c Code) String() string {
 	return string(c)
 }
+
+// IsSupported reports whether code is one of the currencies the SDK knows
+// (case-insensitive). Requests in other currencies are rejected before signing.
+func IsSupported(code Code) bool {
+	_, ok := fractionDigits[normalize(code)]
+	return ok
+}
+
+// Supported returns the supported currency codes in alphabetical order.
+func Supported() []Code {
+	codes := make([]Code, 0, len(fractionDigits))
+	for code := range fractionDigits {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	return codes
+}
+
+func normalize(code Code) Code {
+	return Code(strings.ToUpper(strings.TrimSpace(string(code))))
+}
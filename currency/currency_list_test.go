@@ -0,0 +1,50 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package currency
+
+import "testing"
+
+func TestIsSupported(t *testing.T) {
+	for _, code := range []Code{UAH, USD, EUR, GBP, PLN, JPY, "uah", " eur "} {
+		if !IsSupported(code) {
+			t.Fatalf("IsSupported(%q) = false, want true", code)
+		}
+	}
+	for _, code := range []Code{"", "UAG", "XYZ", "US"} {
+		if IsSupported(code) {
+			t.Fatalf("IsSupported(%q) = true, want false", code)
+		}
+	}
+
+	supported := Supported()
+	if len(supported) != len(fractionDigits) {
+		t.Fatalf("Supported() has %d codes, want %d", len(supported), len(fractionDigits))
+	}
+	for i := 1; i < len(supported); i++ {
+		if supported[i-1] >= supported[i] {
+			t.Fatalf("Supported() is not sorted: %v", supported)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package currency
+
+import (
+	"sync"
+
+	"github.com/stremovskyy/go-platon/log"
+)
+
+// DefaultFractionDigits is used for currencies missing from the table.
+const DefaultFractionDigits = 2
+
+// fractionDigits is the number of minor-unit digits (ISO 4217 exponent) of
+// each known currency.
+var fractionDigits = map[Code]int{
+	UAH: 2,
+	USD: 2,
+	EUR: 2,
+	GBP: 2,
+	PLN: 2,
+	JPY: 0,
+	KRW: 0,
+	CHF: 2,
+	CZK: 2,
+	CAD: 2,
+}
+
+// FractionDigits returns the number of decimal digits amounts in code are
+// written with (2 for UAH, 0 for JPY). Unknown currencies report
+// DefaultFractionDigits and false.
+func FractionDigits(code Code) (int, bool) {
+	digits, ok := fractionDigits[normalize(code)]
+	if !ok {
+		return DefaultFractionDigits, false
+	}
+
+	return digits, true
+}
+
+var (
+	logger        = log.NewLogger("Platon Currency: ")
+	warnedUnknown sync.Map
+)
+
+// Exponent returns the ISO 4217 exponent of code, i.e. how many decimal
+// digits its amounts have (2 for UAH and USD, 0 for JPY). Unknown currencies
+// use DefaultFractionDigits and log a warning once per code; an empty code
+// silently uses DefaultFractionDigits.
+func Exponent(code Code) int {
+	digits, ok := FractionDigits(code)
+	if !ok {
+		normalized := normalize(code)
+		if _, warned := warnedUnknown.LoadOrStore(normalized, true); normalized != "" && !warned {
+			logger.Warning("unknown currency %q, assuming %d fraction digits", normalized, DefaultFractionDigits)
+		}
+	}
+
+	return digits
+}
+
+// FractionDigitsTable returns a copy of the fraction-digit table.
+func FractionDigitsTable() map[Code]int {
+	table := make(map[Code]int, len(fractionDigits))
+	for code, digits := range fractionDigits {
+		table[code] = digits
+	}
+
+	return table
+}
@@ -0,0 +1,72 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package currency
+
+import "testing"
+
+func TestFractionDigits(t *testing.T) {
+	tests := []struct {
+		code   Code
+		want   int
+		wantOK bool
+	}{
+		{code: UAH, want: 2, wantOK: true},
+		{code: USD, want: 2, wantOK: true},
+		{code: EUR, want: 2, wantOK: true},
+		{code: " jpy ", want: 0, wantOK: true},
+		{code: "XTS", want: DefaultFractionDigits},
+		{code: "", want: DefaultFractionDigits},
+	}
+
+	for _, tt := range tests {
+		got, ok := FractionDigits(tt.code)
+		if got != tt.want || ok != tt.wantOK {
+			t.Fatalf("FractionDigits(%q) = %d, %v; want %d, %v", tt.code, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	table := FractionDigitsTable()
+	table[UAH] = 0
+	if got, _ := FractionDigits(UAH); got != 2 {
+		t.Fatalf("FractionDigitsTable() must return a copy, UAH digits = %d", got)
+	}
+}
+
+func TestExponent(t *testing.T) {
+	tests := map[Code]int{
+		UAH:   2,
+		USD:   2,
+		JPY:   0,
+		"krw": 0,
+		"XTS": DefaultFractionDigits,
+		"":    DefaultFractionDigits,
+	}
+
+	for code, want := range tests {
+		if got := Exponent(code); got != want {
+			t.Fatalf("Exponent(%q) = %d, want %d", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/stremovskyy/go-platon/consts"
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+// Endpoints lists the gateway URLs used by the client. Point them at a
+// staging or mock gateway with WithEndpoints or WithBaseURL; empty fields fall
+// back to DefaultEndpoints.
+type Endpoints struct {
+	// PostURL is the IA endpoint for Apple Pay and Google Pay (/post/).
+	PostURL string
+	// PostUnqURL is the IA Server-Server endpoint for card and token payments,
+	// capture, refund and void (/post-unq/).
+	PostUnqURL string
+	// P2PUnqURL is the A2C endpoint for payouts and A2C status (/p2p-unq/).
+	P2PUnqURL string
+	// GetTransStatus is the endpoint for GET_TRANS_STATUS,
+	// GET_TRANS_STATUS_BY_ORDER and GET_TRANS_DETAILS (/post-unq/).
+	GetTransStatus string
+	// GetSubmerchant is the configuration endpoint for GET_SUBMERCHANT
+	// (/configuration/).
+	GetSubmerchant string
+	// PaymentAuthURL is the Client-Server card verification endpoint
+	// (/payment/auth).
+	PaymentAuthURL string
+}
+
+// DefaultEndpoints returns the production Platon endpoints.
+func DefaultEndpoints() Endpoints {
+	return endpointsAt(consts.BaseURL)
+}
+
+// EndpointsForBaseURL returns the Platon endpoints served under base (scheme,
+// host and optional path prefix), e.g. a sandbox or a local mock gateway. A
+// path in base is prepended, so "http://localhost:8080/platon" maps /post-unq/
+// to http://localhost:8080/platon/post-unq/. WithBaseURL uses it.
+func EndpointsForBaseURL(base string) (Endpoints, error) {
+	parsed, err := parseBaseURL(base)
+	if err != nil {
+		return DefaultEndpoints(), err
+	}
+
+	root := url.URL{Scheme: parsed.Scheme, User: parsed.User, Host: parsed.Host, Path: strings.TrimRight(parsed.Path, "/")}
+	return endpointsAt(root.String()), nil
+}
+
+// endpointsAt appends the Platon paths to base, which has no trailing slash.
+func endpointsAt(base string) Endpoints {
+	return Endpoints{
+		PostURL:        base + consts.ApiPostPath,
+		PostUnqURL:     base + consts.ApiPostUnqPath,
+		P2PUnqURL:      base + consts.ApiP2PUnqPath,
+		GetTransStatus: base + consts.ApiPostUnqPath,
+		GetSubmerchant: base + consts.ApiConfigurationPath,
+		PaymentAuthURL: base + consts.ApiPaymentAuthPath,
+	}
+}
+
+func parseBaseURL(base string) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimSpace(base))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("endpoint: invalid base URL %q", base)
+	}
+
+	return parsed, nil
+}
+
+// withDefaults fills empty fields from DefaultEndpoints.
+func (e Endpoints) withDefaults() Endpoints {
+	defaults := DefaultEndpoints()
+	fill := func(field *string, fallback string) {
+		if strings.TrimSpace(*field) == "" {
+			*field = fallback
+		}
+	}
+	fill(&e.PostURL, defaults.PostURL)
+	fill(&e.PostUnqURL, defaults.PostUnqURL)
+	fill(&e.P2PUnqURL, defaults.P2PUnqURL)
+	fill(&e.GetTransStatus, defaults.GetTransStatus)
+	fill(&e.GetSubmerchant, defaults.GetSubmerchant)
+	fill(&e.PaymentAuthURL, defaults.PaymentAuthURL)
+
+	return e
+}
+
+// resolve maps the production endpoint chosen for hashType to the configured one.
+func (e Endpoints) resolve(hashType platon.HashType, endpoint string) string {
+	switch hashType {
+	case platon.HashTypeGetTransStatus, platon.HashTypeGetTransStatusByOrder, platon.HashTypeGetTransDetails:
+		return e.GetTransStatus
+	case platon.HashTypeGetSubmerchant:
+		return e.GetSubmerchant
+	}
+
+	switch endpoint {
+	case consts.ApiPostURL:
+		return e.PostURL
+	case consts.ApiPostUnqURL:
+		return e.PostUnqURL
+	case consts.ApiP2PUnqURL:
+		return e.P2PUnqURL
+	default:
+		return endpoint
+	}
+}
+
+// endpointFor resolves the configured endpoint for a prepared request.
+func (c *client) endpointFor(apiRequest *platon.Request) (string, error) {
+	endpoint, err := endpointFor(apiRequest)
+	if err != nil {
+		return "", err
+	}
+	if c.endpointsErr != nil {
+		return "", c.endpointsErr
+	}
+	if c.endpoints == nil {
+		return endpoint, nil
+	}
+
+	return c.endpoints.resolve(apiRequest.HashType, endpoint), nil
+}
+
+// paymentAuthURL returns the configured Client-Server verification endpoint.
+func (c *client) paymentAuthURL() (string, error) {
+	if c.endpointsErr != nil {
+		return "", c.endpointsErr
+	}
+	if c.endpoints == nil {
+		return consts.ApiPaymentAuthURL, nil
+	}
+
+	return c.endpoints.PaymentAuthURL, nil
+}
+
+// endpointsByHashType is the routing table from signature type to IA endpoint.
+var endpointsByHashType = map[platon.HashType]string{
+	platon.HashTypeApplePay:                 consts.ApiPostURL,
+	platon.HashTypeGooglePay:                consts.ApiPostURL,
+	platon.HashTypeVerification:             consts.ApiPostUnqURL,
+	platon.HashTypeCardPayment:              consts.ApiPostUnqURL,
+	platon.HashTypeCardTokenPayment:         consts.ApiPostUnqURL,
+	platon.HashTypeRecurring:                consts.ApiPostUnqURL,
+	platon.HashTypeGetTransStatus:           consts.ApiPostUnqURL,
+	platon.HashTypeGetTransStatusByOrder:    consts.ApiPostUnqURL,
+	platon.HashTypeGetTransDetails:          consts.ApiPostUnqURL,
+	platon.HashTypeCapture:                  consts.ApiPostUnqURL,
+	platon.HashTypeCreditVoid:               consts.ApiPostUnqURL,
+	platon.HashTypeVoid:                     consts.ApiPostUnqURL,
+	platon.HashTypeGetTransStatusA2C:        consts.ApiP2PUnqURL,
+	platon.HashTypeGetTransStatusByOrderA2C: consts.ApiP2PUnqURL,
+	platon.HashTypeCredit2Card:              consts.ApiP2PUnqURL,
+	platon.HashTypeCredit2CardToken:         consts.ApiP2PUnqURL,
+	platon.HashTypeGetSubmerchant:           consts.ApiConfigurationURL,
+}
+
+// endpointFor resolves the endpoint for a prepared request and verifies that
+// the action is allowed on it.
+func endpointFor(apiRequest *platon.Request) (string, error) {
+	if apiRequest == nil {
+		return "", platon.ErrRequestIsNil
+	}
+
+	endpoint, ok := endpointsByHashType[apiRequest.HashType]
+	if !ok {
+		return "", fmt.Errorf("endpoint: no endpoint for hash type %q", apiRequest.HashType)
+	}
+	if err := checkEndpoint(platon.ActionCode(apiRequest.Action), endpoint); err != nil {
+		return "", err
+	}
+
+	return endpoint, nil
+}
+
+// checkEndpoint guards the action/endpoint coupling: wallet actions are only
+// accepted by the mobile endpoint, and the mobile endpoint accepts nothing else.
+// The gateway declines misrouted requests without a clear reason.
+func checkEndpoint(action platon.ActionCode, endpoint string) error {
+	isWalletAction := action == platon.ActionCodeAPPLEPAY || action == platon.ActionCodeGOOGLEPAY
+	isMobileEndpoint := endpoint == consts.ApiPostURL
+
+	if isWalletAction && !isMobileEndpoint {
+		return fmt.Errorf("endpoint: %s must be sent to %s (got %s)", action, consts.ApiPostURL, endpoint)
+	}
+	if !isWalletAction && isMobileEndpoint {
+		return fmt.Errorf("endpoint: %s must not be sent to the mobile endpoint %s", action, endpoint)
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stremovskyy/go-platon/consts"
+	"github.com/stremovskyy/go-platon/currency"
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+func TestEndpointFor_RoutesByHashType(t *testing.T) {
+	tests := []struct {
+		action   platon.ActionCode
+		hashType platon.HashType
+		want     string
+	}{
+		{action: platon.ActionCodeAPPLEPAY, hashType: platon.HashTypeApplePay, want: consts.ApiPostURL},
+		{action: platon.ActionCodeGOOGLEPAY, hashType: platon.HashTypeGooglePay, want: consts.ApiPostURL},
+		{action: platon.ActionCodeSALE, hashType: platon.HashTypeCardTokenPayment, want: consts.ApiPostUnqURL},
+		{action: platon.ActionCodeCAPTURE, hashType: platon.HashTypeCapture, want: consts.ApiPostUnqURL},
+		{action: platon.ActionCodeCREDIT2CARD, hashType: platon.HashTypeCredit2CardToken, want: consts.ApiP2PUnqURL},
+		{action: platon.ActionCodeGetSubmerchant, hashType: platon.HashTypeGetSubmerchant, want: consts.ApiGetSubmerchant},
+	}
+
+	for _, tt := range tests {
+		got, err := endpointFor(platon.NewRequest(tt.action).SignForAction(tt.hashType))
+		if err != nil {
+			t.Fatalf("%s: endpointFor() error: %v", tt.hashType, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%s: endpoint mismatch: want %q, got %q", tt.hashType, tt.want, got)
+		}
+	}
+}
+
+func TestEndpointFor_WalletActionCannotUseTokenEndpoint(t *testing.T) {
+	// A wallet action signed as a token payment would resolve to /post-unq/.
+	req := platon.NewRequest(platon.ActionCodeAPPLEPAY).SignForAction(platon.HashTypeCardTokenPayment)
+
+	_, err := endpointFor(req)
+	if err == nil || !strings.Contains(err.Error(), "APPLEPAY must be sent to "+consts.ApiPostURL) {
+		t.Fatalf("expected wallet routing error, got %v", err)
+	}
+
+	if err := checkEndpoint(platon.ActionCodeGOOGLEPAY, consts.ApiPostUnqURL); err == nil {
+		t.Fatalf("expected GOOGLEPAY on %s to be rejected", consts.ApiPostUnqURL)
+	}
+	if err := checkEndpoint(platon.ActionCodeSALE, consts.ApiPostURL); err == nil {
+		t.Fatalf("expected SALE on mobile endpoint to be rejected")
+	}
+}
+
+func TestEndpointsForBaseURL(t *testing.T) {
+	got, err := EndpointsForBaseURL("http://127.0.0.1:8080/mock/")
+	if err != nil {
+		t.Fatalf("EndpointsForBaseURL() error: %v", err)
+	}
+	want := Endpoints{
+		PostURL:        "http://127.0.0.1:8080/mock/post/",
+		PostUnqURL:     "http://127.0.0.1:8080/mock/post-unq/",
+		P2PUnqURL:      "http://127.0.0.1:8080/mock/p2p-unq/",
+		GetTransStatus: "http://127.0.0.1:8080/mock/post-unq/",
+		GetSubmerchant: "http://127.0.0.1:8080/mock/configuration/",
+		PaymentAuthURL: "http://127.0.0.1:8080/mock/payment/auth",
+	}
+	if got != want {
+		t.Fatalf("EndpointsForBaseURL() = %+v, want %+v", got, want)
+	}
+
+	if got, _ := EndpointsForBaseURL(consts.BaseURL); got != DefaultEndpoints() {
+		t.Fatalf("EndpointsForBaseURL(BaseURL) = %+v, want the production endpoints", got)
+	}
+	if DefaultEndpoints().PostUnqURL != consts.ApiPostUnqURL || DefaultEndpoints().GetSubmerchant != consts.ApiGetSubmerchant {
+		t.Fatalf("DefaultEndpoints() must match the consts URLs, got %+v", DefaultEndpoints())
+	}
+
+	for _, base := range []string{"", "not a url", "/relative"} {
+		if _, err := EndpointsForBaseURL(base); err == nil {
+			t.Fatalf("EndpointsForBaseURL(%q) expected an error", base)
+		}
+	}
+}
+
+func TestWithBaseURL_EveryMethodTargetsOverriddenHost(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				action := r.PostForm.Get("action")
+
+				mu.Lock()
+				calls = append(calls, r.URL.Path+" "+action)
+				mu.Unlock()
+
+				if r.URL.Path == "/payment/auth" {
+					w.Header().Set("Location", "https://secure.platononline.com/payment/purchase?token=abc")
+					w.WriteHeader(http.StatusFound)
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				if action == platon.ActionCodeGetSubmerchant.String() {
+					_, _ = w.Write([]byte(`{"status":"SUCCESS","action":"GET_SUBMERCHANT","submerchant_id":"123","submerchant_id_status":"ENABLED"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"action":"` + action + `","result":"SUCCESS","status":"SETTLED","order_id":"order-1","trans_id":"trans-1"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	cl := NewClient(WithClock(testClock), WithBaseURL(srv.URL))
+	merchant := func() *Merchant {
+		return &Merchant{
+			MerchantKey:     "CLIENT_KEY",
+			SecretKey:       "CLIENT_PASS",
+			SuccessRedirect: "https://merchant.example/success",
+		}
+	}
+	byTransID := func() *Request {
+		return &Request{
+			Merchant:     merchant(),
+			PersonalData: &PersonalData{Email: ref("payer@example.com")},
+			PaymentData:  &PaymentData{PlatonTransID: ref("trans-1"), Amount: 100},
+		}
+	}
+
+	payment := newCardPANPaymentRequest()
+	payment.Merchant.ClientIP = ref("203.0.113.10")
+	if _, err := cl.Payment(payment); err != nil {
+		t.Fatalf("Payment() error: %v", err)
+	}
+	if _, err := cl.Capture(byTransID()); err != nil {
+		t.Fatalf("Capture() error: %v", err)
+	}
+	if _, err := cl.Refund(byTransID()); err != nil {
+		t.Fatalf("Refund() error: %v", err)
+	}
+	if _, err := cl.Credit(
+		&Request{
+			Merchant:      merchant(),
+			PaymentData:   &PaymentData{PaymentID: ref("order-1"), Amount: 100, Currency: currency.UAH, Description: "payout"},
+			PaymentMethod: &PaymentMethod{Card: &Card{Token: ref("CARD_TOKEN")}},
+		},
+	); err != nil {
+		t.Fatalf("Credit() error: %v", err)
+	}
+	if _, err := cl.Status(&Request{Merchant: merchant(), PaymentData: &PaymentData{PaymentID: ref("order-1")}}); err != nil {
+		t.Fatalf("Status() error: %v", err)
+	}
+	if _, err := cl.SubmerchantAvailableForSplit(&Request{Merchant: merchant(), PaymentData: &PaymentData{SubmerchantID: ref("123")}}); err != nil {
+		t.Fatalf("SubmerchantAvailableForSplit() error: %v", err)
+	}
+	if _, err := cl.Verification(
+		&Request{
+			Merchant:    merchant(),
+			PaymentData: &PaymentData{PaymentID: ref("order-1"), Currency: currency.UAH, Description: "Verify card"},
+		},
+	); err != nil {
+		t.Fatalf("Verification() error: %v", err)
+	}
+
+	want := []string{
+		"/post-unq/ SALE",
+		"/post-unq/ CAPTURE",
+		"/post-unq/ CREDITVOID",
+		"/p2p-unq/ CREDIT2CARD",
+		"/post-unq/ GET_TRANS_STATUS_BY_ORDER",
+		"/configuration/ GET_SUBMERCHANT",
+		"/payment/auth ",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("calls to the overridden host:\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestWithBaseURL_InvalidURLFailsCalls(t *testing.T) {
+	cl := NewClient(WithBaseURL("::not a url"))
+
+	payment := newCardPANPaymentRequest()
+	payment.Merchant.ClientIP = ref("203.0.113.10")
+	if _, err := cl.Payment(payment); err == nil || !strings.Contains(err.Error(), "invalid base URL") {
+		t.Fatalf("Payment() error = %v, want an invalid base URL error", err)
+	}
+}
+
+func TestWithEndpoints_KeepsDefaultsForEmptyFields(t *testing.T) {
+	var gotEndpoint string
+	cl := NewClient(WithEndpoints(Endpoints{PostUnqURL: "https://staging.example/post-unq/"}))
+
+	payment := newCardPANPaymentRequest()
+	payment.Merchant.ClientIP = ref("203.0.113.10")
+	if _, err := cl.Payment(payment, DryRun(func(endpoint string, _ any) { gotEndpoint = endpoint })); err != nil {
+		t.Fatalf("Payment() dry run error: %v", err)
+	}
+	if gotEndpoint != "https://staging.example/post-unq/" {
+		t.Fatalf("endpoint = %q, want the staging PostUnqURL", gotEndpoint)
+	}
+
+	var statusEndpoint string
+	if _, err := cl.Status(
+		&Request{Merchant: &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"}, PaymentData: &PaymentData{PaymentID: ref("order-1")}},
+		DryRun(func(endpoint string, _ any) { statusEndpoint = endpoint }),
+	); err != nil {
+		t.Fatalf("Status() dry run error: %v", err)
+	}
+	if statusEndpoint != consts.ApiGetTransStatus {
+		t.Fatalf("status endpoint = %q, want the production default", statusEndpoint)
+	}
+}
@@ -0,0 +1,521 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+const (
+	// DefaultIdempotencyTTL is how long a recorded response is replayed when
+	// IdempotencyConfig.TTL is not set.
+	DefaultIdempotencyTTL = 24 * time.Hour
+	// DefaultIdempotencyExtField is the ext field that carries the idempotency
+	// key when IdempotencyConfig.ExtField is not set.
+	DefaultIdempotencyExtField = "ext9"
+	// IdempotencyExtFieldNone disables sending the key in an ext field.
+	IdempotencyExtFieldNone = "-"
+)
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is reused for a
+// request whose fields differ from the one that first used it.
+var ErrIdempotencyKeyReused = errors.New("idempotency key reused with a different request")
+
+// memoryGuardSweepInterval is how often MemoryIdempotencyGuard drops expired
+// entries.
+const memoryGuardSweepInterval = time.Minute
+
+// IdempotencyGuard records responses by idempotency key so that a repeated
+// call is answered locally instead of being sent to Platon again.
+// Implementations must be safe for concurrent use; back it with Redis or a DB
+// to share the guard between processes.
+type IdempotencyGuard interface {
+	// Acquire reserves key for the caller. It returns the recorded response if
+	// the key already completed within its TTL. It returns (nil, nil) when the
+	// caller now owns the key and must call Store or Release. While another
+	// caller owns the key, Acquire waits for it or for ctx to end.
+	// fingerprint identifies the request; when key is held, recorded or
+	// unresolved for a different fingerprint, Acquire returns
+	// ErrIdempotencyKeyReused.
+	Acquire(ctx context.Context, key, fingerprint string, ttl time.Duration) (*platon.Response, error)
+	// Store records response for key for ttl and releases waiting callers.
+	Store(ctx context.Context, key string, response *platon.Response, ttl time.Duration) error
+	// Release drops the reservation without recording a response, so the
+	// operation can be sent again.
+	Release(ctx context.Context, key string)
+	// MarkUnresolved releases the reservation like Release and remembers for
+	// ttl that the last attempt got no answer, until Store is called for key.
+	MarkUnresolved(ctx context.Context, key string, ttl time.Duration) error
+	// Unresolved reports whether key was marked by MarkUnresolved and has not
+	// been stored or expired since.
+	Unresolved(ctx context.Context, key string) (bool, error)
+}
+
+// IdempotencyConfig configures WithIdempotency.
+type IdempotencyConfig struct {
+	// Guard stores responses; an in-memory guard is used when nil.
+	Guard IdempotencyGuard
+	// TTL is how long a response is replayed, DefaultIdempotencyTTL when zero.
+	TTL time.Duration
+	// ExtField is the ext field ("ext1".."ext9") that carries the key, so it
+	// is echoed back in callbacks and status responses. A different value for
+	// it in Metadata fails the call. ext10 is reserved for PaymentData.Reference.
+	// DefaultIdempotencyExtField (ext9) when empty; IdempotencyExtFieldNone
+	// disables it.
+	ExtField string
+
+	// extFieldErr is set by WithIdempotency for an unusable ExtField and
+	// fails every idempotent call.
+	extFieldErr error
+}
+
+// MemoryIdempotencyGuard is an in-process IdempotencyGuard. Expired entries
+// are dropped at most once a minute, when a response is stored.
+type MemoryIdempotencyGuard struct {
+	mu         sync.Mutex
+	entries    map[string]*idempotencyEntry
+	unresolved map[string]unresolvedMarker
+	nextSweep  time.Time
+	now        func() time.Time
+}
+
+type idempotencyEntry struct {
+	done        chan struct{}
+	fingerprint string
+	response    *platon.Response
+	expires     time.Time
+}
+
+type unresolvedMarker struct {
+	fingerprint string
+	expires     time.Time
+}
+
+var _ IdempotencyGuard = (*MemoryIdempotencyGuard)(nil)
+
+// NewMemoryIdempotencyGuard returns an empty in-process guard.
+func NewMemoryIdempotencyGuard() *MemoryIdempotencyGuard {
+	return &MemoryIdempotencyGuard{
+		entries:    make(map[string]*idempotencyEntry),
+		unresolved: make(map[string]unresolvedMarker),
+		now:        time.Now,
+	}
+}
+
+// Acquire implements IdempotencyGuard.
+func (g *MemoryIdempotencyGuard) Acquire(ctx context.Context, key, fingerprint string, ttl time.Duration) (*platon.Response, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		g.mu.Lock()
+		entry, ok := g.entries[key]
+		if ok && entry.response != nil && g.now().After(entry.expires) {
+			delete(g.entries, key)
+			ok = false
+		}
+		if ok && entry.fingerprint != fingerprint {
+			g.mu.Unlock()
+			return nil, ErrIdempotencyKeyReused
+		}
+		if !ok {
+			if marker, unresolved := g.unresolved[key]; unresolved && !g.now().After(marker.expires) && marker.fingerprint != fingerprint {
+				g.mu.Unlock()
+				return nil, ErrIdempotencyKeyReused
+			}
+			g.entries[key] = &idempotencyEntry{done: make(chan struct{}), fingerprint: fingerprint}
+			g.mu.Unlock()
+			return nil, nil
+		}
+		if entry.response != nil {
+			response := *entry.response
+			g.mu.Unlock()
+			return &response, nil
+		}
+		g.mu.Unlock()
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Store implements IdempotencyGuard.
+func (g *MemoryIdempotencyGuard) Store(_ context.Context, key string, response *platon.Response, ttl time.Duration) error {
+	if response == nil {
+		g.Release(context.Background(), key)
+		return nil
+	}
+
+	stored := *response
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.sweep()
+	delete(g.unresolved, key)
+	entry, ok := g.entries[key]
+	if !ok {
+		entry = &idempotencyEntry{done: make(chan struct{})}
+		g.entries[key] = entry
+	} else if entry.response != nil {
+		return nil
+	}
+	entry.response = &stored
+	entry.expires = g.now().Add(ttl)
+	close(entry.done)
+
+	return nil
+}
+
+// Release implements IdempotencyGuard.
+func (g *MemoryIdempotencyGuard) Release(_ context.Context, key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.release(key)
+}
+
+func (g *MemoryIdempotencyGuard) release(key string) {
+	entry, ok := g.entries[key]
+	if !ok || entry.response != nil {
+		return
+	}
+	delete(g.entries, key)
+	close(entry.done)
+}
+
+// MarkUnresolved implements IdempotencyGuard.
+func (g *MemoryIdempotencyGuard) MarkUnresolved(_ context.Context, key string, ttl time.Duration) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	marker := unresolvedMarker{expires: g.now().Add(ttl)}
+	if entry, ok := g.entries[key]; ok {
+		marker.fingerprint = entry.fingerprint
+	}
+	g.unresolved[key] = marker
+	g.release(key)
+	return nil
+}
+
+// Unresolved implements IdempotencyGuard.
+func (g *MemoryIdempotencyGuard) Unresolved(_ context.Context, key string) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	marker, ok := g.unresolved[key]
+	if ok && g.now().After(marker.expires) {
+		delete(g.unresolved, key)
+		return false, nil
+	}
+
+	return ok, nil
+}
+
+// sweep drops expired responses and unresolved markers. Callers hold g.mu.
+func (g *MemoryIdempotencyGuard) sweep() {
+	now := g.now()
+	if now.Before(g.nextSweep) {
+		return
+	}
+	g.nextSweep = now.Add(memoryGuardSweepInterval)
+
+	for key, entry := range g.entries {
+		if entry.response != nil && now.After(entry.expires) {
+			delete(g.entries, key)
+		}
+	}
+	for key, marker := range g.unresolved {
+		if now.After(marker.expires) {
+			delete(g.unresolved, key)
+		}
+	}
+}
+
+// idempotentAPI sends apiRequest at most once per merchant, action and
+// idempotency key while the recorded response is within the TTL.
+func (c *client) idempotentAPI(ctx context.Context, apiRequest *platon.Request, apiURL string, opts *runOptions) (*platon.Response, error) {
+	cfg := c.idempotency
+	if cfg.extFieldErr != nil {
+		return nil, cfg.extFieldErr
+	}
+	if cfg.ExtField != "" && cfg.ExtField != IdempotencyExtFieldNone {
+		ext := extFieldPointer(apiRequest, cfg.ExtField)
+		key := apiRequest.IdempotencyKey
+		if *ext != nil && **ext != key {
+			return nil, platon.NewValidationError("idempotency", cfg.ExtField, "is already set by Metadata and carries the idempotency key (move the key with IdempotencyConfig.ExtField)")
+		}
+		*ext = &key
+	}
+
+	guardKey := strings.Join([]string{apiRequest.ClientKey, apiRequest.Action, apiRequest.IdempotencyKey}, ":")
+	recorded, err := cfg.Guard.Acquire(ctx, guardKey, requestFingerprint(apiRequest), cfg.TTL)
+	if err != nil {
+		return nil, fmt.Errorf("idempotency: %w", err)
+	}
+	if recorded != nil {
+		return recorded, recorded.GetError()
+	}
+
+	unresolved, err := cfg.Guard.Unresolved(ctx, guardKey)
+	if err != nil {
+		cfg.Guard.Release(context.WithoutCancel(ctx), guardKey)
+		return nil, fmt.Errorf("idempotency: %w", err)
+	}
+	if unresolved {
+		// The previous attempt may have reached Platon; look the order up
+		// before posting the operation again.
+		response, found, err := c.checkIdempotentOutcome(ctx, apiRequest, opts)
+		if found {
+			return c.storeIdempotent(ctx, guardKey, response, err)
+		}
+		if err != nil {
+			cfg.Guard.Release(context.WithoutCancel(ctx), guardKey)
+			return nil, err
+		}
+	}
+
+	response, err := c.platonClient.ApiWithContext(ctx, apiRequest, apiURL, opts.callOptions()...)
+	if response == nil {
+		// Nothing reached the gateway or its answer was lost; allow a retry,
+		// which checks the order status first.
+		if markErr := cfg.Guard.MarkUnresolved(context.WithoutCancel(ctx), guardKey, cfg.TTL); markErr != nil {
+			return nil, errors.Join(err, fmt.Errorf("idempotency: %w", markErr))
+		}
+		return response, err
+	}
+
+	return c.storeIdempotent(ctx, guardKey, response, err)
+}
+
+func (c *client) storeIdempotent(ctx context.Context, guardKey string, response *platon.Response, err error) (*platon.Response, error) {
+	cfg := c.idempotency
+	if storeErr := cfg.Guard.Store(context.WithoutCancel(ctx), guardKey, response, cfg.TTL); storeErr != nil && err == nil {
+		return response, fmt.Errorf("idempotency: %w", storeErr)
+	}
+
+	return response, err
+}
+
+// checkIdempotentOutcome asks Platon whether an operation whose answer was
+// lost reached it. Payments and payouts are looked up with
+// GET_TRANS_STATUS_BY_ORDER, CAPTURE and CREDITVOID with GET_TRANS_STATUS (see
+// checkTransOutcome). found reports that the operation went through; the status
+// response is then the outcome. Only an "Order not found" answer means a
+// payment is safe to post again. When the status cannot be fetched or is
+// rejected for another reason, err is set and the operation must not be posted.
+func (c *client) checkIdempotentOutcome(ctx context.Context, apiRequest *platon.Request, opts *runOptions) (*platon.Response, bool, error) {
+	hashType := platon.HashTypeGetTransStatusByOrder
+	switch platon.ActionCode(apiRequest.Action) {
+	case platon.ActionCodeSALE, platon.ActionCodeAPPLEPAY, platon.ActionCodeGOOGLEPAY:
+	case platon.ActionCodeCREDIT2CARD:
+		hashType = platon.HashTypeGetTransStatusByOrderA2C
+	case platon.ActionCodeCAPTURE, platon.ActionCodeCREDITVOID:
+		return c.checkTransOutcome(ctx, apiRequest, opts)
+	default:
+		return nil, false, nil
+	}
+	if apiRequest.OrderID == nil || strings.TrimSpace(*apiRequest.OrderID) == "" {
+		return nil, false, nil
+	}
+
+	statusRequest := platon.NewRequest(platon.ActionCodeGetTransStatusByOrder).
+		WithAuth(apiRequest.Auth).
+		WithClientKey(apiRequest.ClientKey).
+		WithOrderID(apiRequest.OrderID).
+		SignForAction(hashType)
+
+	response, err := c.idempotentStatus(ctx, statusRequest, opts)
+	if response == nil {
+		return nil, false, err
+	}
+
+	if response.IsOrderNotFound() {
+		return nil, false, nil
+	}
+	var apiErr *platon.APIError
+	if errors.As(err, &apiErr) && apiErr.Kind == platon.APIErrorKindError {
+		return nil, false, fmt.Errorf("idempotency: outcome of the previous attempt is unknown and the status check was rejected: %w", err)
+	}
+
+	return response, true, err
+}
+
+// checkTransOutcome looks up the transaction of a CAPTURE or CREDITVOID whose
+// answer was lost with GET_TRANS_STATUS. An untouched hold (status PENDING
+// with no CAPTURE or CREDITVOID in its history) and, for a refund, a SETTLED
+// transaction with no refund in its history mean the operation did not go
+// through. SETTLED means a CAPTURE did, and REFUND or REVERSAL means the hold
+// or payment was already closed. Anything else leaves the outcome unknown
+// and is reported as an error, so the operation is not posted twice.
+func (c *client) checkTransOutcome(ctx context.Context, apiRequest *platon.Request, opts *runOptions) (*platon.Response, bool, error) {
+	if apiRequest.TransId == nil || strings.TrimSpace(*apiRequest.TransId) == "" {
+		return nil, false, errors.New("idempotency: outcome of the previous attempt is unknown and the request has no trans_id to check")
+	}
+
+	statusRequest := platon.NewRequest(platon.ActionCodeGetTransStatus).
+		WithAuth(apiRequest.Auth).
+		WithClientKey(apiRequest.ClientKey).
+		WithTransID(apiRequest.TransId).
+		WithHashEmail(apiRequest.HashEmail).
+		SignForAction(platon.HashTypeGetTransStatus)
+
+	response, err := c.idempotentStatus(ctx, statusRequest, opts)
+	if response == nil {
+		return nil, false, err
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("idempotency: outcome of the previous attempt is unknown and the status check was rejected: %w", err)
+	}
+
+	status := ""
+	if response.Status != nil {
+		status = strings.ToUpper(strings.TrimSpace(*response.Status))
+	}
+	touched := hasTransactionOfType(response, platon.ActionCodeCAPTURE.String(), platon.ActionCodeCREDITVOID.String())
+	refunded := hasTransactionOfType(response, platon.ActionCodeCREDITVOID.String(), "REFUND", "REVERSAL")
+
+	switch {
+	case status == "PENDING" && !touched:
+		return nil, false, nil
+	case platon.ActionCode(apiRequest.Action) == platon.ActionCodeCAPTURE && status == "SETTLED":
+		return response, true, nil
+	case status == "REFUND" || status == "REVERSAL":
+		return response, true, nil
+	case platon.ActionCode(apiRequest.Action) == platon.ActionCodeCREDITVOID && status == "SETTLED" && len(response.Transactions) > 0 && !refunded:
+		return nil, false, nil
+	}
+
+	return nil, false, fmt.Errorf("idempotency: outcome of the previous attempt is unknown (transaction status %q)", status)
+}
+
+// idempotentStatus sends a status lookup for checkIdempotentOutcome. A nil
+// response comes with an error saying the outcome is unknown.
+func (c *client) idempotentStatus(ctx context.Context, statusRequest *platon.Request, opts *runOptions) (*platon.Response, error) {
+	statusURL, err := c.endpointFor(statusRequest)
+	if err != nil {
+		return nil, fmt.Errorf("idempotency: status check: %w", err)
+	}
+
+	response, err := c.platonClient.ApiWithContext(ctx, statusRequest, statusURL, opts.callOptions()...)
+	if response == nil {
+		return nil, fmt.Errorf("idempotency: outcome of the previous attempt is unknown and the status check failed: %w", err)
+	}
+
+	return response, err
+}
+
+// hasTransactionOfType reports whether the transaction history of response
+// lists an entry of one of types.
+func hasTransactionOfType(response *platon.Response, types ...string) bool {
+	for _, transaction := range response.Transactions {
+		for _, kind := range types {
+			if strings.EqualFold(strings.TrimSpace(transaction.Type), kind) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// requestFingerprint identifies the fields of apiRequest that are sent to
+// Platon. It is an HMAC keyed by the merchant secret, so card numbers cannot
+// be recovered from a guard by hashing candidates.
+func requestFingerprint(apiRequest *platon.Request) string {
+	var secret string
+	if apiRequest.Auth != nil {
+		secret = apiRequest.Auth.Secret
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	for _, field := range apiRequest.ToOrderedForm() {
+		if field.Key == "hash" {
+			continue
+		}
+		mac.Write([]byte(field.Key))
+		mac.Write([]byte{'='})
+		mac.Write([]byte(field.Value))
+		mac.Write([]byte{'\n'})
+	}
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// checkIdempotencyExtField reports why name cannot carry the idempotency key.
+func checkIdempotencyExtField(name string) error {
+	if name == IdempotencyExtFieldNone {
+		return nil
+	}
+	if name == platon.ReferenceExtField {
+		return platon.NewValidationError("idempotency", "ExtField", "must not be ext10, which carries PaymentData.Reference")
+	}
+	if extFieldPointer(&platon.Request{}, name) == nil {
+		return platon.NewValidationError("idempotency", "ExtField", fmt.Sprintf("must be ext1..ext9 (got %q)", name))
+	}
+
+	return nil
+}
+
+func extFieldPointer(apiRequest *platon.Request, name string) **string {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "ext1":
+		return &apiRequest.Ext1
+	case "ext2":
+		return &apiRequest.Ext2
+	case "ext3":
+		return &apiRequest.Ext3
+	case "ext4":
+		return &apiRequest.Ext4
+	case "ext5":
+		return &apiRequest.Ext5
+	case "ext6":
+		return &apiRequest.Ext6
+	case "ext7":
+		return &apiRequest.Ext7
+	case "ext8":
+		return &apiRequest.Ext8
+	case "ext9":
+		return &apiRequest.Ext9
+	case "ext10":
+		return &apiRequest.Ext10
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,610 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+type idempotencyTestTransport struct {
+	calls      atomic.Int32
+	mu         sync.Mutex
+	requestIDs []string
+	ext9       []string
+	delay      time.Duration
+	fail       atomic.Bool
+
+	// statusCalls counts GET_TRANS_STATUS_BY_ORDER and GET_TRANS_STATUS
+	// lookups, answered with
+	// statusBody (an unknown order by default) or a connection error while
+	// statusFail is set. They are not counted in calls.
+	statusCalls atomic.Int32
+	statusBody  string
+	statusFail  atomic.Bool
+}
+
+func (tr *idempotencyTestTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	switch r.PostForm.Get("action") {
+	case platon.ActionCodeGetTransStatusByOrder.String(), platon.ActionCodeGetTransStatus.String():
+		tr.statusCalls.Add(1)
+		if tr.statusFail.Load() {
+			return nil, errors.New("connection reset")
+		}
+		body := tr.statusBody
+		if body == "" {
+			body = `{"result":"ERROR","error_message":"Order not found"}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+
+	n := tr.calls.Add(1)
+
+	tr.mu.Lock()
+	tr.requestIDs = append(tr.requestIDs, r.Header.Get("X-Request-ID"))
+	tr.ext9 = append(tr.ext9, r.PostForm.Get("ext9"))
+	tr.mu.Unlock()
+
+	time.Sleep(tr.delay)
+	if tr.fail.Load() {
+		return nil, errors.New("connection reset")
+	}
+
+	body := fmt.Sprintf(`{"action":"SALE","result":"SUCCESS","status":"SETTLED","order_id":"order-1","trans_id":"trans-%d"}`, n)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}, nil
+}
+
+func newIdempotentPayment(key string) *Request {
+	request := newCardPANPaymentRequest()
+	request.Merchant.ClientIP = ref("203.0.113.10")
+	request.PaymentData.IdempotencyKey = key
+	return request
+}
+
+func TestIdempotency_ConcurrentCallsSendOnce(t *testing.T) {
+	transport := &idempotencyTestTransport{delay: 20 * time.Millisecond}
+	cl := NewClient(
+		WithClock(testClock),
+		WithClient(&http.Client{Transport: transport}),
+		WithIdempotency(IdempotencyConfig{}),
+	)
+
+	const callers = 10
+	transIDs := make([]string, callers)
+	errs := make([]error, callers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := cl.Payment(newIdempotentPayment("order-1-attempt"))
+			errs[i] = err
+			if resp != nil && resp.TransId != nil {
+				transIDs[i] = *resp.TransId
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := transport.calls.Load(); got != 1 {
+		t.Fatalf("HTTP calls = %d, want 1", got)
+	}
+	for i := range transIDs {
+		if errs[i] != nil || transIDs[i] != "trans-1" {
+			t.Fatalf("caller %d got %q, %v; want the recorded trans-1", i, transIDs[i], errs[i])
+		}
+	}
+
+	if _, err := cl.Payment(newIdempotentPayment("order-2-attempt")); err != nil {
+		t.Fatalf("Payment() with a new key error: %v", err)
+	}
+	if got := transport.calls.Load(); got != 2 {
+		t.Fatalf("HTTP calls after a new key = %d, want 2", got)
+	}
+}
+
+func TestIdempotency_TransportErrorAllowsRetry(t *testing.T) {
+	transport := &idempotencyTestTransport{}
+	transport.fail.Store(true)
+	cl := NewClient(
+		WithClock(testClock),
+		WithClient(&http.Client{Transport: transport}),
+		WithIdempotency(IdempotencyConfig{ExtField: "ext9"}),
+	)
+
+	if _, err := cl.Payment(newIdempotentPayment("retry-key")); err == nil {
+		t.Fatal("Payment() expected a transport error")
+	}
+
+	transport.fail.Store(false)
+	if _, err := cl.Payment(newIdempotentPayment("retry-key")); err != nil {
+		t.Fatalf("Payment() retry error: %v", err)
+	}
+	if got := transport.calls.Load(); got != 2 {
+		t.Fatalf("HTTP calls = %d, want 2 (failed call is not recorded)", got)
+	}
+
+	if transport.requestIDs[0] != transport.requestIDs[1] || transport.requestIDs[0] == "" {
+		t.Fatalf("X-Request-ID must be stable for the same key, got %v", transport.requestIDs)
+	}
+	if transport.ext9[1] != "retry-key" {
+		t.Fatalf("ext9 = %q, want the idempotency key", transport.ext9[1])
+	}
+	if got := transport.statusCalls.Load(); got != 1 {
+		t.Fatalf("status checks = %d, want 1 before re-posting", got)
+	}
+}
+
+func TestIdempotency_LostAnswerChecksStatusBeforeRetry(t *testing.T) {
+	transport := &idempotencyTestTransport{
+		statusBody: `{"action":"GET_TRANS_STATUS_BY_ORDER","result":"SUCCESS","status":"SETTLED","order_id":"order-1","trans_id":"trans-1"}`,
+	}
+	transport.fail.Store(true)
+	cl := NewClient(
+		WithClock(testClock),
+		WithClient(&http.Client{Transport: transport}),
+		WithIdempotency(IdempotencyConfig{}),
+	)
+
+	if _, err := cl.Payment(newIdempotentPayment("lost-answer")); err == nil {
+		t.Fatal("Payment() expected a transport error")
+	}
+	if got := transport.ext9[0]; got != "lost-answer" {
+		t.Fatalf("ext9 = %q, want the idempotency key by default", got)
+	}
+
+	transport.fail.Store(false)
+	resp, err := cl.Payment(newIdempotentPayment("lost-answer"))
+	if err != nil {
+		t.Fatalf("Payment() retry error: %v", err)
+	}
+	if resp == nil || resp.TransId == nil || *resp.TransId != "trans-1" {
+		t.Fatalf("Payment() retry = %+v, want the status of the first attempt", resp)
+	}
+	if _, err := cl.Payment(newIdempotentPayment("lost-answer")); err != nil {
+		t.Fatalf("Payment() replay error: %v", err)
+	}
+
+	if got := transport.calls.Load(); got != 1 {
+		t.Fatalf("SALE calls = %d, want at most 1", got)
+	}
+	if got := transport.statusCalls.Load(); got != 1 {
+		t.Fatalf("status checks = %d, want 1", got)
+	}
+}
+
+func TestIdempotency_FailedStatusCheckDoesNotRepost(t *testing.T) {
+	transport := &idempotencyTestTransport{}
+	transport.fail.Store(true)
+	transport.statusFail.Store(true)
+	cl := NewClient(
+		WithClock(testClock),
+		WithClient(&http.Client{Transport: transport}),
+		WithIdempotency(IdempotencyConfig{ExtField: IdempotencyExtFieldNone}),
+	)
+
+	if _, err := cl.Payment(newIdempotentPayment("unknown-outcome")); err == nil {
+		t.Fatal("Payment() expected a transport error")
+	}
+	if got := transport.ext9[0]; got != "" {
+		t.Fatalf("ext9 = %q, want nothing with IdempotencyExtFieldNone", got)
+	}
+
+	transport.fail.Store(false)
+	_, err := cl.Payment(newIdempotentPayment("unknown-outcome"))
+	if err == nil || !strings.Contains(err.Error(), "status check failed") {
+		t.Fatalf("Payment() error = %v, want a failed status check", err)
+	}
+	if got := transport.calls.Load(); got != 1 {
+		t.Fatalf("SALE calls = %d, want 1 while the outcome is unknown", got)
+	}
+
+	transport.statusFail.Store(false)
+	if _, err := cl.Payment(newIdempotentPayment("unknown-outcome")); err != nil {
+		t.Fatalf("Payment() after an unknown order error: %v", err)
+	}
+	if got := transport.calls.Load(); got != 2 {
+		t.Fatalf("SALE calls = %d, want 2 once the order is known to be missing", got)
+	}
+}
+
+func TestIdempotency_RejectedStatusCheckDoesNotRepost(t *testing.T) {
+	transport := &idempotencyTestTransport{statusBody: `{"result":"ERROR","error_message":"Invalid hash"}`}
+	transport.fail.Store(true)
+	cl := NewClient(
+		WithClock(testClock),
+		WithClient(&http.Client{Transport: transport}),
+		WithIdempotency(IdempotencyConfig{}),
+	)
+
+	if _, err := cl.Payment(newIdempotentPayment("rejected-status")); err == nil {
+		t.Fatal("Payment() expected a transport error")
+	}
+
+	transport.fail.Store(false)
+	_, err := cl.Payment(newIdempotentPayment("rejected-status"))
+	if err == nil || !strings.Contains(err.Error(), "status check was rejected") {
+		t.Fatalf("Payment() error = %v, want a rejected status check", err)
+	}
+	if got := transport.calls.Load(); got != 1 {
+		t.Fatalf("SALE calls = %d, want 1 unless the order is reported as not found", got)
+	}
+}
+
+func TestIdempotency_SharedGuardKeepsUnresolvedMarker(t *testing.T) {
+	transport := &idempotencyTestTransport{
+		statusBody: `{"action":"GET_TRANS_STATUS_BY_ORDER","result":"SUCCESS","status":"SETTLED","order_id":"order-1","trans_id":"trans-1"}`,
+	}
+	transport.fail.Store(true)
+	guard := NewMemoryIdempotencyGuard()
+	newClient := func() Platon {
+		return NewClient(
+			WithClock(testClock),
+			WithClient(&http.Client{Transport: transport}),
+			WithIdempotency(IdempotencyConfig{Guard: guard}),
+		)
+	}
+
+	if _, err := newClient().Payment(newIdempotentPayment("shared")); err == nil {
+		t.Fatal("Payment() expected a transport error")
+	}
+
+	transport.fail.Store(false)
+	resp, err := newClient().Payment(newIdempotentPayment("shared"))
+	if err != nil {
+		t.Fatalf("Payment() on another client error: %v", err)
+	}
+	if resp == nil || resp.TransId == nil || *resp.TransId != "trans-1" {
+		t.Fatalf("Payment() = %+v, want the status of the first attempt", resp)
+	}
+	if got := transport.calls.Load(); got != 1 {
+		t.Fatalf("SALE calls = %d, want 1", got)
+	}
+}
+
+func TestIdempotency_LostTransOperationAnswerChecksTransStatus(t *testing.T) {
+	newTransRequest := func(key string) *Request {
+		return &Request{
+			Merchant:     &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+			PersonalData: &PersonalData{Email: ref("payer@example.com")},
+			PaymentData:  &PaymentData{PlatonTransID: ref("trans-1"), Amount: 100, IdempotencyKey: key},
+		}
+	}
+	capture := func(cl Platon, request *Request) (*platon.Response, error) { return cl.Capture(request) }
+	refund := func(cl Platon, request *Request) (*platon.Response, error) { return cl.Refund(request) }
+
+	tests := []struct {
+		name       string
+		send       func(Platon, *Request) (*platon.Response, error)
+		statusBody string
+		wantCalls  int32
+		wantErr    string
+	}{
+		{
+			name:       "captured hold is not captured again",
+			send:       capture,
+			statusBody: `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"SETTLED","trans_id":"trans-1"}`,
+			wantCalls:  1,
+		},
+		{
+			name:       "untouched hold is captured on retry",
+			send:       capture,
+			statusBody: `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"PENDING","trans_id":"trans-1"}`,
+			wantCalls:  2,
+		},
+		{
+			name:       "refunded payment is not refunded again",
+			send:       refund,
+			statusBody: `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"REFUND","trans_id":"trans-1"}`,
+			wantCalls:  1,
+		},
+		{
+			name:       "payment without refunds is refunded on retry",
+			send:       refund,
+			statusBody: `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"SETTLED","trans_id":"trans-1","transactions":[{"type":"SALE","status":"SUCCESS","amount":"1.00"}]}`,
+			wantCalls:  2,
+		},
+		{
+			name:       "partially refunded payment blocks the retry",
+			send:       refund,
+			statusBody: `{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"SETTLED","trans_id":"trans-1","transactions":[{"type":"SALE","status":"SUCCESS","amount":"2.00"},{"type":"CREDITVOID","status":"SUCCESS","amount":"1.00"}]}`,
+			wantCalls:  1,
+			wantErr:    "outcome of the previous attempt is unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				transport := &idempotencyTestTransport{statusBody: tt.statusBody}
+				transport.fail.Store(true)
+				cl := NewClient(
+					WithClient(&http.Client{Transport: transport}),
+					WithIdempotency(IdempotencyConfig{}),
+				)
+
+				if _, err := tt.send(cl, newTransRequest("lost-trans-answer")); err == nil {
+					t.Fatal("first call expected a transport error")
+				}
+
+				transport.fail.Store(false)
+				_, err := tt.send(cl, newTransRequest("lost-trans-answer"))
+				if tt.wantErr == "" && err != nil {
+					t.Fatalf("retry error: %v", err)
+				}
+				if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+					t.Fatalf("retry error = %v, want %q", err, tt.wantErr)
+				}
+				if got := transport.statusCalls.Load(); got != 1 {
+					t.Fatalf("status checks = %d, want 1", got)
+				}
+				if got := transport.calls.Load(); got != tt.wantCalls {
+					t.Fatalf("operation calls = %d, want %d", got, tt.wantCalls)
+				}
+			},
+		)
+	}
+}
+
+func TestIdempotency_RejectsTakenExtField(t *testing.T) {
+	tests := []struct {
+		name     string
+		extField string
+		metadata map[string]string
+		want     string
+	}{
+		{name: "metadata in the key slot", metadata: map[string]string{"ext9": "wallet-topup"}, want: "idempotency: ext9 is already set by Metadata"},
+		{name: "reference slot", extField: "ext10", want: "idempotency: ExtField must not be ext10"},
+		{name: "unknown slot", extField: "ext11", want: `idempotency: ExtField must be ext1..ext9 (got "ext11")`},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				transport := &idempotencyTestTransport{}
+				cl := NewClient(
+					WithClock(testClock),
+					WithClient(&http.Client{Transport: transport}),
+					WithIdempotency(IdempotencyConfig{ExtField: tt.extField}),
+				)
+
+				request := newIdempotentPayment("taken-slot")
+				request.PaymentData.Metadata = tt.metadata
+				_, err := cl.Payment(request)
+				if !errors.Is(err, platon.ErrValidation) || !strings.Contains(err.Error(), tt.want) {
+					t.Fatalf("Payment() error = %v, want a validation error containing %q", err, tt.want)
+				}
+				if got := transport.calls.Load(); got != 0 {
+					t.Fatalf("HTTP calls = %d, want 0", got)
+				}
+			},
+		)
+	}
+}
+
+func TestIdempotency_ReusedKeyWithDifferentRequest(t *testing.T) {
+	transport := &idempotencyTestTransport{}
+	cl := NewClient(
+		WithClock(testClock),
+		WithClient(&http.Client{Transport: transport}),
+		WithIdempotency(IdempotencyConfig{}),
+	)
+
+	if _, err := cl.Payment(newIdempotentPayment("reused")); err != nil {
+		t.Fatalf("Payment() error: %v", err)
+	}
+
+	changed := newIdempotentPayment("reused")
+	changed.PaymentData.Amount++
+	_, err := cl.Payment(changed)
+	if !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("Payment() with a changed amount error = %v, want ErrIdempotencyKeyReused", err)
+	}
+	if got := transport.calls.Load(); got != 1 {
+		t.Fatalf("HTTP calls = %d, want 1", got)
+	}
+}
+
+func TestIdempotency_RequestIDWithoutGuard(t *testing.T) {
+	transport := &idempotencyTestTransport{}
+	cl := NewClient(WithClock(testClock), WithClient(&http.Client{Transport: transport}))
+
+	for i := 0; i < 2; i++ {
+		if _, err := cl.Payment(newIdempotentPayment("stable")); err != nil {
+			t.Fatalf("Payment() error: %v", err)
+		}
+	}
+	if _, err := cl.Payment(newIdempotentPayment("")); err != nil {
+		t.Fatalf("Payment() error: %v", err)
+	}
+
+	ids := transport.requestIDs
+	if ids[0] != ids[1] || ids[1] == ids[2] {
+		t.Fatalf("X-Request-IDs = %v, want the first two equal and the third random", ids)
+	}
+	if transport.ext9[0] != "" {
+		t.Fatalf("ext9 = %q, want nothing without WithIdempotency", transport.ext9[0])
+	}
+}
+
+func TestMemoryIdempotencyGuard_TTL(t *testing.T) {
+	now := time.Unix(0, 0)
+	guard := NewMemoryIdempotencyGuard()
+	guard.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if resp, err := guard.Acquire(ctx, "k", "fp", time.Minute); resp != nil || err != nil {
+		t.Fatalf("first Acquire() = %v, %v", resp, err)
+	}
+	if err := guard.Store(ctx, "k", &platon.Response{TransId: ref("t-1")}, time.Minute); err != nil {
+		t.Fatalf("Store() error: %v", err)
+	}
+
+	resp, err := guard.Acquire(ctx, "k", "fp", time.Minute)
+	if err != nil || resp == nil || *resp.TransId != "t-1" {
+		t.Fatalf("Acquire() within TTL = %v, %v", resp, err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if resp, err := guard.Acquire(ctx, "k", "fp", time.Minute); resp != nil || err != nil {
+		t.Fatalf("Acquire() after TTL = %v, %v; want a fresh reservation", resp, err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := guard.Acquire(waitCtx, "k", "fp", time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire() on a held key error = %v, want deadline exceeded", err)
+	}
+
+	guard.Release(ctx, "k")
+	if resp, err := guard.Acquire(ctx, "k", "fp", time.Minute); resp != nil || err != nil {
+		t.Fatalf("Acquire() after Release = %v, %v", resp, err)
+	}
+}
+
+func TestMemoryIdempotencyGuard_UnresolvedTTL(t *testing.T) {
+	now := time.Unix(0, 0)
+	guard := NewMemoryIdempotencyGuard()
+	guard.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := guard.Acquire(ctx, "k", "fp", time.Minute); err != nil {
+		t.Fatalf("Acquire() error: %v", err)
+	}
+	if err := guard.MarkUnresolved(ctx, "k", time.Minute); err != nil {
+		t.Fatalf("MarkUnresolved() error: %v", err)
+	}
+	if resp, err := guard.Acquire(ctx, "k", "fp", time.Minute); resp != nil || err != nil {
+		t.Fatalf("Acquire() after MarkUnresolved = %v, %v; want a fresh reservation", resp, err)
+	}
+	if unresolved, err := guard.Unresolved(ctx, "k"); !unresolved || err != nil {
+		t.Fatalf("Unresolved() = %v, %v; want true", unresolved, err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if unresolved, _ := guard.Unresolved(ctx, "k"); unresolved {
+		t.Fatal("Unresolved() after TTL = true, want false")
+	}
+	if len(guard.unresolved) != 0 {
+		t.Fatalf("expired marker was not dropped: %v", guard.unresolved)
+	}
+
+	if err := guard.MarkUnresolved(ctx, "k", time.Minute); err != nil {
+		t.Fatalf("MarkUnresolved() error: %v", err)
+	}
+	if err := guard.Store(ctx, "k", &platon.Response{TransId: ref("t-1")}, time.Minute); err != nil {
+		t.Fatalf("Store() error: %v", err)
+	}
+	if unresolved, _ := guard.Unresolved(ctx, "k"); unresolved {
+		t.Fatal("Unresolved() after Store = true, want false")
+	}
+}
+
+func TestMemoryIdempotencyGuard_SweepsExpiredEntries(t *testing.T) {
+	now := time.Unix(0, 0)
+	guard := NewMemoryIdempotencyGuard()
+	guard.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b"} {
+		if _, err := guard.Acquire(ctx, key, "fp", time.Minute); err != nil {
+			t.Fatalf("Acquire(%s) error: %v", key, err)
+		}
+		if err := guard.Store(ctx, key, &platon.Response{}, time.Minute); err != nil {
+			t.Fatalf("Store(%s) error: %v", key, err)
+		}
+	}
+	if _, err := guard.Acquire(ctx, "lost", "fp", time.Minute); err != nil {
+		t.Fatalf("Acquire(lost) error: %v", err)
+	}
+	if err := guard.MarkUnresolved(ctx, "lost", time.Minute); err != nil {
+		t.Fatalf("MarkUnresolved() error: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := guard.Acquire(ctx, "c", "fp", time.Minute); err != nil {
+		t.Fatalf("Acquire(c) error: %v", err)
+	}
+	if err := guard.Store(ctx, "c", &platon.Response{}, time.Minute); err != nil {
+		t.Fatalf("Store(c) error: %v", err)
+	}
+
+	if len(guard.entries) != 1 || guard.entries["c"] == nil {
+		t.Fatalf("entries after sweep = %v, want only c", guard.entries)
+	}
+	if len(guard.unresolved) != 0 {
+		t.Fatalf("unresolved after sweep = %v, want none", guard.unresolved)
+	}
+}
+
+func TestMemoryIdempotencyGuard_FingerprintMismatch(t *testing.T) {
+	guard := NewMemoryIdempotencyGuard()
+	ctx := context.Background()
+
+	if _, err := guard.Acquire(ctx, "k", "fp-1", time.Minute); err != nil {
+		t.Fatalf("Acquire() error: %v", err)
+	}
+	if _, err := guard.Acquire(ctx, "k", "fp-2", time.Minute); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("Acquire() on a held key with another fingerprint error = %v, want ErrIdempotencyKeyReused", err)
+	}
+
+	if err := guard.MarkUnresolved(ctx, "k", time.Minute); err != nil {
+		t.Fatalf("MarkUnresolved() error: %v", err)
+	}
+	if _, err := guard.Acquire(ctx, "k", "fp-2", time.Minute); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("Acquire() on an unresolved key with another fingerprint error = %v, want ErrIdempotencyKeyReused", err)
+	}
+	if _, err := guard.Acquire(ctx, "k", "fp-1", time.Minute); err != nil {
+		t.Fatalf("Acquire() with the same fingerprint error: %v", err)
+	}
+	if err := guard.Store(ctx, "k", &platon.Response{}, time.Minute); err != nil {
+		t.Fatalf("Store() error: %v", err)
+	}
+	if _, err := guard.Acquire(ctx, "k", "fp-2", time.Minute); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("Acquire() on a recorded key with another fingerprint error = %v, want ErrIdempotencyKeyReused", err)
+	}
+}
@@ -25,7 +25,9 @@
 package go_platon
 
 import (
+	"context"
 	"net/url"
+	"time"
 
 	"github.com/stremovskyy/go-platon/log"
 	"github.com/stremovskyy/go-platon/platon"
@@ -39,14 +41,77 @@ type Platon interface {
 	Verification(request *Request, opts ...RunOption) (*url.URL, error)
 	VerificationLink(request *Request, opts ...RunOption) (*url.URL, error)
 	Status(request *Request, opts ...RunOption) (*platon.Response, error)
+	// StatusByTransID queries GET_TRANS_STATUS by PaymentData.PlatonTransID only.
+	StatusByTransID(request *Request, opts ...RunOption) (*platon.Response, error)
+	// Details queries GET_TRANS_DETAILS by PaymentData.PlatonTransID: the status
+	// plus card mask, payer name and fee.
+	Details(request *Request, opts ...RunOption) (*platon.Response, error)
 	Payment(request *Request, opts ...RunOption) (*platon.Response, error)
+	// PaymentByCard charges a card by PAN, expiry and CVV2 even when a token is
+	// also set. Metadata flags req_token/recurring_init request tokenization.
+	PaymentByCard(request *Request, opts ...RunOption) (*platon.Response, error)
 	Hold(request *Request, opts ...RunOption) (*platon.Response, error)
+	// Recurring charges a stored CARD_TOKEN as a merchant-initiated recurring payment.
+	Recurring(request *Request, opts ...RunOption) (*platon.Response, error)
+	// Submerchant returns the GET_SUBMERCHANT details of PaymentData.SubmerchantID.
+	Submerchant(request *Request, opts ...RunOption) (*SubmerchantInfo, error)
 	SubmerchantAvailableForSplit(request *Request, opts ...RunOption) (bool, error)
 	Capture(request *Request, opts ...RunOption) (*platon.Response, error)
+	// CaptureFull captures what is left of a pending hold, read with GET_TRANS_STATUS first.
+	CaptureFull(request *Request, opts ...RunOption) (*platon.Response, error)
 	Refund(request *Request, opts ...RunOption) (*platon.Response, error)
+	// RefundByOrder refunds by merchant order_id, resolving trans_id with
+	// GET_TRANS_STATUS_BY_ORDER first.
+	RefundByOrder(request *Request, opts ...RunOption) (*platon.Response, error)
+	// Void cancels an uncaptured HOLD (CREDITVOID without amount).
+	Void(request *Request, opts ...RunOption) (*platon.Response, error)
 	Credit(request *Request, opts ...RunOption) (*platon.Response, error)
+	// CreditBatch sends payouts with bounded concurrency and per-request results.
+	CreditBatch(ctx context.Context, requests []*Request, concurrency int, opts ...RunOption) ([]CreditResult, error)
+	// StatusBatch checks many orders by order_id with a worker pool; outputs
+	// are indexed like requests.
+	StatusBatch(requests []*Request, opts ...BatchOption) ([]*platon.Response, []error)
+	// PaymentAsync sends a SALE with async=Y and returns as soon as Platon
+	// accepts it; the response carries order_id and trans_id.
+	PaymentAsync(request *Request, opts ...RunOption) (*platon.Response, error)
+	// PollStatus repeats Status every interval until the transaction is no
+	// longer pending or timeout elapses.
+	PollStatus(ctx context.Context, request *Request, interval, timeout time.Duration) (*platon.Response, error)
+	// WaitForFinalStatus repeats Status with exponential backoff until the
+	// response is final, see WaitOption.
+	WaitForFinalStatus(ctx context.Context, request *Request, opts ...WaitOption) (*platon.Response, error)
+
+	// WithContext variants bind the call to ctx: cancelling it or hitting its
+	// deadline aborts the HTTP request and the error wraps ctx.Err().
+	VerificationWithContext(ctx context.Context, request *Request, opts ...RunOption) (*url.URL, error)
+	VerificationLinkWithContext(ctx context.Context, request *Request, opts ...RunOption) (*url.URL, error)
+	StatusWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+	StatusByTransIDWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+	DetailsWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+	PaymentWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+	PaymentByCardWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+	HoldWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+	RecurringWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+	SubmerchantWithContext(ctx context.Context, request *Request, opts ...RunOption) (*SubmerchantInfo, error)
+	SubmerchantAvailableForSplitWithContext(ctx context.Context, request *Request, opts ...RunOption) (bool, error)
+	CaptureWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+	CaptureFullWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+	RefundWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+	RefundByOrderWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+	VoidWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+	CreditWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+	StatusBatchWithContext(ctx context.Context, requests []*Request, opts ...BatchOption) ([]*platon.Response, []error)
+	PaymentAsyncWithContext(ctx context.Context, request *Request, opts ...RunOption) (*platon.Response, error)
+
+	// MeasureClockSkew returns receivedAt minus the callback date, warning
+	// above the threshold set with WithClockSkewWarnThreshold.
+	MeasureClockSkew(form *platon.WebhookForm, receivedAt time.Time) time.Duration
+
 	// Deprecated: Platon production callbacks use application/x-www-form-urlencoded.
 	// Use go_platon.ParseWebhookForm for callback parsing and signature verification.
 	ParseWebhookXML(data []byte) (*platon.Payment, error)
 	SetLogLevel(levelDebug log.Level)
+	// Stats reports the rate limiters set by WithRateLimit and
+	// WithActionRateLimit.
+	Stats() ClientStats
 }
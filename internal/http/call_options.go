@@ -0,0 +1,65 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package http
+
+import (
+	"time"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+// CallOption configures a single Api call.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	onSigned func(*platon.Request)
+	retry    *retryPolicy
+}
+
+// OnSigned registers a hook invoked with the signed request right before it is sent.
+func OnSigned(hook func(*platon.Request)) CallOption {
+	return func(o *callOptions) {
+		o.onSigned = hook
+	}
+}
+
+// WithRetry overrides the client retry policy for a single call. Zero
+// maxRetries disables retries. Only read-only actions are ever retried.
+func WithRetry(maxRetries int, backoff time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.retry = &retryPolicy{maxRetries: maxRetries, backoff: backoff}
+	}
+}
+
+func collectCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+
+	return o
+}
@@ -26,12 +26,13 @@ package http
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -48,13 +49,48 @@ type Client struct {
 	options  *Options
 	logger   *log.Logger
 	recorder recorder.Recorder
+
+	recorderErrorHandler RecorderErrorHandler
+	responseHook         ResponseHook
+	observer             Observer
+	logSink              log.Sink
+	sensitiveKeys        map[string]Mask
+	rateLimiters         *rateLimiters
 }
 
-const maxResponseBodyBytes = 4 << 20 // 4 MiB
+// ResponseHook is called with every successfully parsed response before it is
+// returned. A non-nil error is returned to the caller instead of the response.
+type ResponseHook func(ctx context.Context, resp *platon.Response) error
+
+const defaultMaxResponseBytes = 4 << 20 // 4 MiB
+
+// maxResponseBytes returns Options.MaxResponseBytes, or the 4 MiB default.
+func (c *Client) maxResponseBytes() int {
+	if c.options == nil || c.options.MaxResponseBytes <= 0 {
+		return defaultMaxResponseBytes
+	}
+
+	return c.options.MaxResponseBytes
+}
 
 // Api handles Platon API request.
-func (c *Client) Api(apiRequest *platon.Request, apiURL string) (*platon.Response, error) {
-	return c.sendURLEncodedRequest(apiURL, apiRequest, c.logger)
+func (c *Client) Api(apiRequest *platon.Request, apiURL string, opts ...CallOption) (*platon.Response, error) {
+	return c.ApiWithContext(context.Background(), apiRequest, apiURL, opts...)
+}
+
+// ApiWithContext handles Platon API request bound to ctx. Cancelling ctx aborts
+// the HTTP call and the returned error wraps ctx.Err(). Values carried by ctx
+// (e.g. trace IDs) are passed on to the recorder.
+func (c *Client) ApiWithContext(ctx context.Context, apiRequest *platon.Request, apiURL string, opts ...CallOption) (*platon.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// Do not sign, record or send a request whose context is already done.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return c.sendURLEncodedRequest(ctx, apiURL, apiRequest, c.logger, collectCallOptions(opts))
 }
 
 // WithRecorder attaches a recorder to the client.
@@ -69,175 +105,329 @@ func (c *Client) SetClient(cl *http.Client) {
 	c.client = cl
 }
 
+// SetResponseHook sets a hook invoked with every parsed response.
+func (c *Client) SetResponseHook(hook ResponseHook) {
+	c.responseHook = hook
+}
+
+// SetLogSink routes the client's logs, and the signature logs of the requests
+// it sends, to sink.
+func (c *Client) SetLogSink(sink log.Sink) {
+	c.logSink = sink
+	c.logger = c.logger.WithSink(sink)
+}
+
 // SetRecorder allows setting a recorder explicitly.
 func (c *Client) SetRecorder(r recorder.Recorder) {
 	c.recorder = r
 }
 
-func (c *Client) sendURLEncodedRequest(apiURL string, unsignedRequest *platon.Request, logger *log.Logger) (*platon.Response, error) {
-	requestID := uuid.New().String()
+func (c *Client) sendURLEncodedRequest(
+	ctx context.Context,
+	apiURL string,
+	unsignedRequest *platon.Request,
+	logger *log.Logger,
+	callOpts *callOptions,
+) (response *platon.Response, err error) {
+	requestID := requestIDFor(unsignedRequest)
 	logger.Debug("API URL: %v", apiURL)
 	logger.Debug("Request ID: %v", requestID)
 
+	status := 0
+	if c.observer != nil {
+		event := RequestEvent{Endpoint: apiURL, RequestID: requestID}
+		if unsignedRequest != nil {
+			event.Action = unsignedRequest.Action
+		}
+		start := time.Now()
+		c.observer.OnRequestStart(event)
+		defer func() {
+			c.observer.OnRequestEnd(event, time.Since(start), status, err)
+		}()
+	}
+
 	if unsignedRequest == nil {
-		return nil, c.logAndReturnError("request is nil", platon.ErrRequestIsNil, logger, requestID, nil)
+		return nil, c.logAndReturnError(ctx, "request is nil", platon.ErrRequestIsNil, logger, requestID, nil)
 	}
 
+	if c.logSink != nil {
+		unsignedRequest.WithLogSink(c.logSink)
+	}
 	signedRequest, err := unsignedRequest.SignAndPrepare()
 	if err != nil {
-		return nil, c.logAndReturnError("cannot sign request", err, logger, requestID, nil)
+		return nil, c.logAndReturnError(ctx, "cannot sign request", err, logger, requestID, nil)
 	}
 
-	encodedForm, err := encodeRequestMap(signedRequest.ToMap())
-	if err != nil {
-		return nil, c.logAndReturnError("cannot encode request", err, logger, requestID, nil)
+	if callOpts != nil && callOpts.onSigned != nil {
+		callOpts.onSigned(signedRequest)
 	}
-	logger.Debug("Request (%s):\n%s", FormURLEncodedContentType, PrettyPrintFormURLEncodedBody(encodedForm))
 
-	ctx := context.Background()
-	if c.options != nil && c.options.Timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, c.options.Timeout)
-		defer cancel()
-	}
-	ctx = context.WithValue(ctx, CtxKeyRequestID, requestID)
+	encodedForm := encodeRequestForm(signedRequest.ToOrderedForm())
+	logger.Debug("Request (%s):\n%s", FormURLEncodedContentType, prettyPrintFormURLEncodedBody(encodedForm, c.logKeys()))
 
-	tags := tagsRetriever(signedRequest)
+	ctx = context.WithValue(ctx, CtxKeyRequestID, requestID)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(encodedForm))
-	if err != nil {
-		return nil, c.logAndReturnError("cannot create request", err, logger, requestID, tags)
-	}
-	c.setHeaders(req, requestID)
+	tags := withRequestID(ctx, tagsRetriever(signedRequest, c.recordAmountTag()))
+	tags["dry_run"] = "false"
 
-	if c.recorder != nil {
-		if err := c.recorder.RecordRequest(ctx, nil, requestID, []byte(encodedForm), tags); err != nil {
-			logger.Error("cannot record request: %v", err)
-		}
-	}
+	c.recordRequest(ctx, requestID, []byte(encodedForm), tags)
 
 	if c.client == nil {
-		return nil, c.logAndReturnError("http client is nil", fmt.Errorf("http client is nil"), logger, requestID, tags)
+		return nil, c.logAndReturnError(ctx, "http client is nil", fmt.Errorf("http client is nil"), logger, requestID, tags)
 	}
 
-	tStart := time.Now()
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, c.logAndReturnError("cannot send request", err, logger, requestID, tags)
-	}
-	if resp == nil {
-		return nil, c.logAndReturnError(
-			"invalid response",
-			fmt.Errorf("http response is nil"),
-			logger,
-			requestID,
-			tags,
-		)
-	}
-	if resp.Body == nil {
-		return nil, c.logAndReturnError(
-			"invalid response",
-			fmt.Errorf("http response body is nil"),
-			logger,
-			requestID,
-			tags,
-		)
-	}
-	logger.Debug("Request time: %v", time.Since(tStart))
+	policy := c.retryPolicyFor(signedRequest, callOpts)
 
-	defer c.safeClose(resp.Body, logger)
+	var result *attemptResult
+	sendStart := time.Now()
+	for attempt := 0; ; attempt++ {
+		if err := c.waitRateLimit(ctx, signedRequest.Action); err != nil {
+			return nil, c.logAndReturnError(ctx, "rate limit wait aborted", err, logger, requestID, tags)
+		}
 
-	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodyBytes+1))
-	if err != nil {
-		return nil, c.logAndReturnError("cannot read response", err, logger, requestID, tags)
+		var attemptErr *attemptError
+		result, attemptErr = c.doAttempt(ctx, apiURL, encodedForm, requestID, logger)
+		tags = withAttempt(tags, attempt+1)
+		if result != nil {
+			status = result.statusCode
+		}
+
+		canRetry := attempt < policy.maxRetries && ctx.Err() == nil
+		if attemptErr != nil && !(canRetry && attemptErr.retryable) {
+			return nil, c.logAndReturnError(ctx, attemptErr.msg, attemptErr.err, logger, requestID, tags)
+		}
+		if attemptErr == nil && !(canRetry && policy.retryableStatus(result.statusCode)) {
+			break
+		}
+
+		delay := policy.delay(attempt)
+		logger.Debug("Retrying request %s in %v (retry %d of %d)", requestID, delay, attempt+1, policy.maxRetries)
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, c.logAndReturnError(ctx, "cannot send request", err, logger, requestID, tags)
+		}
 	}
+	raw := result.raw
+	tags = withResponseMeta(tags, result.statusCode, time.Since(sendStart))
 
-	logger.Debug("Response: %v", FormatBodyForDebug(resp.Header.Get("Content-Type"), raw))
-	logger.Debug("Response status: %v", resp.StatusCode)
+	logger.Debug("Response: %v", formatBodyForDebug(result.contentType, raw, c.logKeys()))
+	logger.Debug("Response status: %v", result.statusCode)
 
 	if len(raw) == 0 {
-		return nil, c.logAndReturnError("no response bytes", fmt.Errorf("empty response"), logger, requestID, tags)
+		return nil, c.logAndReturnError(ctx, "no response bytes", fmt.Errorf("empty response"), logger, requestID, tags)
 	}
-	if len(raw) > maxResponseBodyBytes {
+	if limit := c.maxResponseBytes(); len(raw) > limit {
 		return nil, c.logAndReturnError(
+			ctx,
 			"response too large",
-			fmt.Errorf("response exceeds %d bytes", maxResponseBodyBytes),
+			fmt.Errorf("response exceeds %d bytes", limit),
 			logger,
 			requestID,
 			tags,
 		)
 	}
 
-	if c.recorder != nil {
-		if err := c.recorder.RecordResponse(ctx, nil, requestID, raw, tags); err != nil {
-			logger.Error("cannot record response: %v", err)
-		}
-	}
+	c.recordResponse(ctx, requestID, raw, tags)
 
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+	if result.statusCode < http.StatusOK || result.statusCode >= http.StatusMultipleChoices {
 		return nil, c.logAndReturnError(
+			ctx,
 			"unexpected response status",
-			fmt.Errorf("status=%d body=%s", resp.StatusCode, truncateBodyForError(raw)),
+			&platon.GatewayError{StatusCode: result.statusCode, Body: truncateBodyForError(raw)},
 			logger,
 			requestID,
 			tags,
 		)
 	}
 
-	response, err := platon.UnmarshalJSONResponse(raw)
+	response, err = platon.UnmarshalJSONResponse(raw)
 	if err != nil {
-		return nil, c.logAndReturnError("cannot unmarshal response", err, logger, requestID, tags)
+		return nil, c.logAndReturnError(ctx, "cannot unmarshal response", err, logger, requestID, tags)
+	}
+
+	if c.responseHook != nil {
+		if err := c.responseHook(ctx, response); err != nil {
+			return nil, c.logAndReturnError(ctx, "response hook failed", fmt.Errorf("response hook: %w", err), logger, requestID, tags)
+		}
 	}
 
 	return response, response.GetError()
 }
 
-func encodeRequestMap(requestMap map[string]interface{}) (string, error) {
-	formValues := url.Values{}
+type attemptResult struct {
+	statusCode  int
+	contentType string
+	raw         []byte
+}
 
-	for key, value := range requestMap {
-		if value == nil {
-			continue
+type attemptError struct {
+	msg       string
+	err       error
+	retryable bool
+}
+
+// doAttempt performs a single HTTP round trip. Options.Timeout applies to each
+// attempt separately.
+func (c *Client) doAttempt(
+	ctx context.Context,
+	apiURL string,
+	encodedForm string,
+	requestID string,
+	logger *log.Logger,
+) (*attemptResult, *attemptError) {
+	if c.options != nil && c.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.options.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(encodedForm))
+	if err != nil {
+		return nil, &attemptError{msg: "cannot create request", err: err}
+	}
+	c.setHeaders(req, requestID)
+
+	tStart := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = fmt.Errorf("%w: %w", ctxErr, err)
 		}
+		return nil, &attemptError{msg: "cannot send request", err: err, retryable: true}
+	}
+	if resp == nil {
+		return nil, &attemptError{msg: "invalid response", err: fmt.Errorf("http response is nil")}
+	}
+	if resp.Body == nil {
+		return nil, &attemptError{msg: "invalid response", err: fmt.Errorf("http response body is nil")}
+	}
+	logger.Debug("Request time: %v", time.Since(tStart))
+
+	defer c.safeClose(resp.Body, logger)
 
-		switch typed := value.(type) {
-		case string:
-			formValues.Set(key, typed)
-		case []byte:
-			formValues.Set(key, string(typed))
-		default:
-			rawValue, err := json.Marshal(value)
-			if err != nil {
-				return "", fmt.Errorf("cannot marshal field %q: %w", key, err)
-			}
-			formValues.Set(key, string(rawValue))
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, int64(c.maxResponseBytes())+1))
+	if err != nil {
+		return nil, &attemptError{msg: "cannot read response", err: err, retryable: true}
+	}
+
+	return &attemptResult{
+		statusCode:  resp.StatusCode,
+		contentType: resp.Header.Get("Content-Type"),
+		raw:         raw,
+	}, nil
+}
+
+// encodeRequestForm form-encodes fields in their given order, so the same
+// request always produces the same body.
+func encodeRequestForm(fields []platon.FormField) string {
+	var buf strings.Builder
+	for idx, field := range fields {
+		if idx > 0 {
+			buf.WriteByte('&')
 		}
+		buf.WriteString(url.QueryEscape(field.Key))
+		buf.WriteByte('=')
+		buf.WriteString(url.QueryEscape(field.Value))
 	}
 
-	return formValues.Encode(), nil
+	return buf.String()
+}
+
+// FormatBodyForDebug is FormatBodyForDebug with the keys configured by
+// Options.SensitiveLogKeys and Options.UnsafeLogging.
+func (c *Client) FormatBodyForDebug(contentType string, raw []byte) string {
+	return formatBodyForDebug(contentType, raw, c.logKeys())
+}
+
+// NoRedirectHTTPClient returns a copy of the net/http client used for API
+// calls (the one set by SetClient, or the one built from Options with its
+// proxy and TLS settings) that returns redirects instead of following them.
+// It returns nil when no client is configured.
+func (c *Client) NoRedirectHTTPClient() *http.Client {
+	if c == nil || c.client == nil {
+		return nil
+	}
+
+	cl := *c.client
+	cl.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &cl
+}
+
+func (c *Client) logKeys() map[string]Mask {
+	if c == nil || c.sensitiveKeys == nil {
+		return defaultSensitiveKeys
+	}
+
+	return c.sensitiveKeys
 }
 
 // logAndReturnError logs an error and optionally records it.
-func (c *Client) logAndReturnError(msg string, err error, logger *log.Logger, requestID string, tags map[string]string) error {
+func (c *Client) logAndReturnError(
+	ctx context.Context,
+	msg string,
+	err error,
+	logger *log.Logger,
+	requestID string,
+	tags map[string]string,
+) error {
 	logger.Error("%s: %v", msg, err)
 
-	if c.recorder != nil {
-		ctx := context.WithValue(context.Background(), CtxKeyRequestID, requestID)
-		if err := c.recorder.RecordError(ctx, nil, requestID, err, tags); err != nil {
-			logger.Error("cannot record error: %v", err)
-		}
-	}
+	// Record the error even when the caller's context is already cancelled.
+	ctx = context.WithValue(context.WithoutCancel(ctx), CtxKeyRequestID, requestID)
+	c.recordError(ctx, requestID, err, tags)
 
 	return err
 }
 
+// requestIDFor returns a random request id, or one derived from the action and
+// idempotency key so that retries of the same operation share X-Request-ID.
+func requestIDFor(request *platon.Request) string {
+	if request == nil || request.IdempotencyKey == "" {
+		return uuid.New().String()
+	}
+
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(request.Action+":"+request.IdempotencyKey)).String()
+}
+
+// reservedHeaders are set by setHeaders and cannot be overridden by
+// Options.Headers.
+var reservedHeaders = map[string]bool{
+	"Accept":       true,
+	"Api-Version":  true,
+	"Content-Type": true,
+	"User-Agent":   true,
+	"X-Request-Id": true,
+}
+
 // setHeaders sets common headers for all requests.
 func (c *Client) setHeaders(req *http.Request, requestID string) {
-	req.Header.Set("Content-Type", FormURLEncodedContentType)
+	var extra http.Header
+	userAgent := "GO PLATON/" + consts.Version
+	if c.options != nil {
+		extra = c.options.Headers
+		if suffix := strings.TrimSpace(c.options.UserAgentSuffix); suffix != "" {
+			userAgent += " " + suffix
+		}
+	}
+
+	for key, values := range extra {
+		key = http.CanonicalHeaderKey(key)
+		if reservedHeaders[key] {
+			continue
+		}
+		req.Header.Del(key)
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "GO PLATON/"+consts.Version)
-	req.Header.Set("X-Request-ID", requestID)
 	req.Header.Set("Api-Version", consts.ApiVersion)
+	req.Header.Set("Content-Type", FormURLEncodedContentType)
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-Request-ID", requestID)
 }
 
 // safeClose ensures the body is closed properly and logs any error.
@@ -247,7 +437,14 @@ func (c *Client) safeClose(body io.ReadCloser, logger *log.Logger) {
 	}
 }
 
-func tagsRetriever(request *platon.Request) map[string]string {
+// recordAmountTag reports whether Options.RecordAmountTag is set.
+func (c *Client) recordAmountTag() bool {
+	return c.options != nil && c.options.RecordAmountTag
+}
+
+// tagsRetriever returns the recorder tags of request. The amount is only
+// included with withAmount, since exact amounts can identify a payer.
+func tagsRetriever(request *platon.Request, withAmount bool) map[string]string {
 	tags := make(map[string]string)
 	if request == nil {
 		return tags
@@ -262,10 +459,93 @@ func tagsRetriever(request *platon.Request) map[string]string {
 	if request.TransId != nil {
 		tags["trans_id"] = *request.TransId
 	}
+	if request.ClientKey != "" {
+		tags["client_key"] = request.ClientKey
+	}
+	if request.HashType != "" {
+		tags["hash_type"] = request.HashType.String()
+	}
+	if request.ChannelId != "" {
+		tags["channel_id"] = request.ChannelId
+	}
+	if request.CardNetwork != "" {
+		tags["card_network"] = request.CardNetwork
+	}
+	if request.SubmerchantID != nil && *request.SubmerchantID != "" {
+		tags["submerchant_id"] = *request.SubmerchantID
+	} else if len(request.SplitRules) > 0 {
+		submerchants := make([]string, 0, len(request.SplitRules))
+		for submerchant := range request.SplitRules {
+			submerchants = append(submerchants, submerchant)
+		}
+		sort.Strings(submerchants)
+		tags["submerchant_id"] = strings.Join(submerchants, ",")
+	}
+	if request.OrderCurrency != "" {
+		tags["currency"] = request.OrderCurrency
+	}
+	if !withAmount {
+		return tags
+	}
+	if request.OrderAmount != "" {
+		tags["amount"] = request.OrderAmount
+	} else if request.Amount != "" {
+		tags["amount"] = request.Amount
+	}
+
+	return tags
+}
+
+// withRequestID adds the request ID stored in ctx (CtxKeyRequestID) so
+// recorder backends keyed on tags can deduplicate.
+func withRequestID(ctx context.Context, tags map[string]string) map[string]string {
+	if requestID, ok := ctx.Value(CtxKeyRequestID).(string); ok && requestID != "" {
+		tags["request_id"] = requestID
+	}
 
 	return tags
 }
 
+// withResponseMeta returns a copy of tags with the HTTP status code and the
+// time from the first attempt to the last response, in milliseconds.
+func withResponseMeta(tags map[string]string, statusCode int, duration time.Duration) map[string]string {
+	out := make(map[string]string, len(tags)+2)
+	for k, v := range tags {
+		out[k] = v
+	}
+	out["http_status"] = strconv.Itoa(statusCode)
+	out["duration_ms"] = strconv.FormatInt(duration.Milliseconds(), 10)
+
+	return out
+}
+
+// RecordDryRun signs request and hands it to the recorder with the dry_run tag
+// set, without sending it.
+func (c *Client) RecordDryRun(ctx context.Context, apiURL string, request *platon.Request) {
+	if c == nil || c.recorder == nil || request == nil {
+		return
+	}
+
+	requestID := requestIDFor(request)
+	// Sign a copy: the dry-run handler gets the request as it was built.
+	unsigned := *request
+	if c.logSink != nil {
+		unsigned.WithLogSink(c.logSink)
+	}
+	signedRequest, err := unsigned.SignAndPrepare()
+	if err != nil {
+		c.logger.Debug("Dry run for %s not recorded: %v", apiURL, err)
+		return
+	}
+	encodedForm := encodeRequestForm(signedRequest.ToOrderedForm())
+
+	ctx = context.WithValue(ctx, CtxKeyRequestID, requestID)
+	tags := withRequestID(ctx, tagsRetriever(signedRequest, c.recordAmountTag()))
+	tags["dry_run"] = "true"
+
+	c.recordRequest(ctx, requestID, []byte(encodedForm), tags)
+}
+
 func truncateBodyForError(raw []byte) string {
 	const max = 512
 	if len(raw) <= max {
@@ -283,8 +563,13 @@ func NewClient(options *Options) *Client {
 		KeepAlive: options.KeepAlive,
 	}
 
+	proxy := options.Proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+
 	tr := &http.Transport{
-		Proxy:                 http.ProxyFromEnvironment,
+		Proxy:                 proxy,
 		DialContext:           dialer.DialContext,
 		ForceAttemptHTTP2:     true,
 		MaxIdleConns:          options.MaxIdleConns,
@@ -295,6 +580,7 @@ func NewClient(options *Options) *Client {
 		ResponseHeaderTimeout: options.ResponseHeaderTimeout,
 		ExpectContinueTimeout: options.ExpectContinueTimeout,
 		DisableCompression:    true,
+		TLSClientConfig:       options.TLSConfig,
 	}
 
 	cl := &http.Client{
@@ -306,8 +592,10 @@ func NewClient(options *Options) *Client {
 	}
 
 	return &Client{
-		client:  cl,
-		options: options,
-		logger:  log.NewLogger("Platon HTTP: "),
+		client:        cl,
+		options:       options,
+		logger:        log.NewLogger("Platon HTTP: "),
+		sensitiveKeys: logSensitiveKeys(options.SensitiveLogKeys, options.UnsafeLogging),
+		rateLimiters:  newRateLimiters(options),
 	}
 }
@@ -25,6 +25,8 @@
 package http
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"net/http"
 	"testing"
 )
@@ -67,6 +69,7 @@ func TestNewClient_NormalizesInvalidOptions(t *testing.T) {
 			MaxIdleConnsPerHost:   0,
 			MaxConnsPerHost:       -1,
 			IdleConnTimeout:       0,
+			MaxResponseBytes:      -1,
 		},
 	)
 
@@ -77,6 +80,9 @@ func TestNewClient_NormalizesInvalidOptions(t *testing.T) {
 	if c.options.MaxConnsPerHost != defaults.MaxConnsPerHost {
 		t.Fatalf("max conns per host mismatch: want %d, got %d", defaults.MaxConnsPerHost, c.options.MaxConnsPerHost)
 	}
+	if c.options.MaxResponseBytes != defaults.MaxResponseBytes {
+		t.Fatalf("max response bytes mismatch: want %d, got %d", defaults.MaxResponseBytes, c.options.MaxResponseBytes)
+	}
 	if c.options.ResponseHeaderTimeout != defaults.ResponseHeaderTimeout {
 		t.Fatalf(
 			"response header timeout mismatch: want %v, got %v",
@@ -102,3 +108,33 @@ func TestNewClient_TransportIsHardenedByDefault(t *testing.T) {
 		t.Fatalf("expected check redirect function to be configured")
 	}
 }
+
+func TestNewClient_AppliesTLSConfig(t *testing.T) {
+	pool := x509.NewCertPool()
+	cert := tls.Certificate{Certificate: [][]byte{{0x30}}}
+	config := &tls.Config{RootCAs: pool, Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS13}
+
+	c := NewClient(&Options{TLSConfig: config})
+	transport, ok := c.client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport type mismatch: got %T", c.client.Transport)
+	}
+
+	applied := transport.TLSClientConfig
+	if applied == nil {
+		t.Fatal("expected TLSClientConfig to be set")
+	}
+	if applied == config {
+		t.Fatal("expected TLSClientConfig to be a clone of the option")
+	}
+	if applied.RootCAs != pool || len(applied.Certificates) != 1 || applied.MinVersion != tls.VersionTLS13 {
+		t.Fatalf("TLSClientConfig not applied: %+v", applied)
+	}
+	if !transport.ForceAttemptHTTP2 || transport.Proxy == nil {
+		t.Fatal("expected the hardened transport settings to be kept")
+	}
+
+	if NewClient(nil).client.Transport.(*http.Transport).TLSClientConfig != nil {
+		t.Fatal("expected no TLSClientConfig by default")
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -149,7 +150,7 @@ func TestApi_ReturnsErrorOnNon2xxStatus(t *testing.T) {
 }
 
 func TestApi_ReturnsErrorWhenResponseIsTooLarge(t *testing.T) {
-	tooLarge := bytes.Repeat([]byte("x"), maxResponseBodyBytes+16)
+	tooLarge := bytes.Repeat([]byte("x"), defaultMaxResponseBytes+16)
 
 	srv := httptest.NewServer(
 		http.HandlerFunc(
@@ -194,6 +195,46 @@ func TestApi_ReturnsErrorWhenResponseIsTooLarge(t *testing.T) {
 	}
 }
 
+func TestApi_MaxResponseBytesOption(t *testing.T) {
+	body := `{"result":"ACCEPTED","status":"SALE","trans_id":"trans-1","order_id":"order-123"}`
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(body))
+			},
+		),
+	)
+	defer srv.Close()
+
+	newRequest := func() *platon.Request {
+		orderID := "order-123"
+		transID := "trans-1"
+		email := "payer@example.com"
+
+		return platon.NewRequest(platon.ActionCodeGetTransStatus).
+			WithAuth(&platon.Auth{Key: "k", Secret: "secret123"}).
+			WithClientKey("clientKey").
+			WithOrderID(&orderID).
+			WithTransID(&transID).
+			WithHashEmail(&email).
+			SignForAction(platon.HashTypeGetTransStatus)
+	}
+
+	options := DefaultOptions()
+	options.MaxResponseBytes = 32
+	_, err := NewClient(options).Api(newRequest(), srv.URL)
+	if err == nil || !strings.Contains(err.Error(), "response exceeds 32 bytes") {
+		t.Fatalf("expected response size error, got %v", err)
+	}
+
+	options.MaxResponseBytes = len(body)
+	if _, err := NewClient(options).Api(newRequest(), srv.URL); err != nil {
+		t.Fatalf("response at the limit: unexpected error: %v", err)
+	}
+}
+
 func TestApi_ReturnsErrorOnNilResponseBody(t *testing.T) {
 	auth := &platon.Auth{Key: "k", Secret: "secret123"}
 	orderID := "order-123"
@@ -291,3 +332,50 @@ func TestApi_ReturnsDeclinedErrorFromReason(t *testing.T) {
 		t.Fatalf("unexpected decline reason: %q", resp.DeclineReason)
 	}
 }
+
+func TestEncodeRequestForm_SplitRulesAreSerializedDeterministically(t *testing.T) {
+	request := &platon.Request{
+		Action: "CAPTURE",
+		SplitRules: platon.SplitRules{
+			"1002":  "30.00",
+			"1001":  "60.00",
+			"A-100": "10.00",
+		},
+	}
+
+	const want = `{"1001":"60.00","1002":"30.00","A-100":"10.00"}`
+	for i := 0; i < 20; i++ {
+		encoded := encodeRequestForm(request.ToOrderedForm())
+		values, err := url.ParseQuery(encoded)
+		if err != nil {
+			t.Fatalf("ParseQuery() error: %v", err)
+		}
+		if got := values.Get("split_rules"); got != want {
+			t.Fatalf("split_rules mismatch: want %s, got %s", want, got)
+		}
+	}
+}
+
+func TestEncodeRequestForm_IsByteIdenticalAcrossRuns(t *testing.T) {
+	orderID, email, ext := "order-1", "payer@example.com", "a b&c"
+	request := &platon.Request{
+		Action:        "SALE",
+		ClientKey:     "client",
+		Hash:          "0123456789abcdef0123456789abcdef",
+		OrderID:       &orderID,
+		OrderAmount:   "10.00",
+		OrderCurrency: "UAH",
+		PayerEmail:    &email,
+		Ext1:          &ext,
+		SplitRules:    platon.SplitRules{"s2": "4.00", "s1": "6.00"},
+	}
+
+	const want = "action=SALE&client_key=client&hash=0123456789abcdef0123456789abcdef&order_id=order-1" +
+		"&order_amount=10.00&order_currency=UAH&payer_email=payer%40example.com&ext1=a+b%26c" +
+		"&split_rules=%7B%22s1%22%3A%226.00%22%2C%22s2%22%3A%224.00%22%7D"
+	for i := 0; i < 20; i++ {
+		if got := encodeRequestForm(request.ToOrderedForm()); got != want {
+			t.Fatalf("run %d: encoded body = %q, want %q", i, got, want)
+		}
+	}
+}
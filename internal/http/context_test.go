@@ -0,0 +1,189 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stremovskyy/recorder"
+)
+
+func TestApiWithContext_CancelAbortsRequest(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"result":"ACCEPTED"}`))
+			},
+		),
+	)
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	c := NewClient(DefaultOptions())
+	start := time.Now()
+	_, err := c.ApiWithContext(ctx, testTokenSaleRequest(), srv.URL)
+	if err == nil {
+		t.Fatal("expected error after cancellation")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Fatalf("expected the transport *url.Error to stay reachable, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("request was not aborted promptly: %v", elapsed)
+	}
+}
+
+func TestApiWithContext_DeadlineExceeded(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			},
+		),
+	)
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	c := NewClient(DefaultOptions())
+	_, err := c.ApiWithContext(ctx, testTokenSaleRequest(), srv.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Fatalf("unexpected error text: %v", err)
+	}
+}
+
+type contextRecorder struct {
+	recorder.Recorder
+
+	mu     sync.Mutex
+	values []any
+}
+
+func (r *contextRecorder) RecordRequest(ctx context.Context, _ *string, _ string, _ []byte, _ map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values = append(r.values, ctx.Value(traceIDKey{}))
+	return nil
+}
+
+func (r *contextRecorder) RecordResponse(ctx context.Context, _ *string, _ string, _ []byte, _ map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values = append(r.values, ctx.Value(traceIDKey{}))
+	return nil
+}
+
+func (r *contextRecorder) RecordError(context.Context, *string, string, error, map[string]string) error {
+	return nil
+}
+
+type traceIDKey struct{}
+
+func TestApiWithContext_PropagatesContextValuesToRecorder(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"result":"ACCEPTED"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	rec := &contextRecorder{}
+	c := NewClient(DefaultOptions())
+	c.SetRecorder(rec)
+
+	ctx := context.WithValue(context.Background(), traceIDKey{}, "trace-1")
+	if _, err := c.ApiWithContext(ctx, testTokenSaleRequest(), srv.URL); err != nil {
+		t.Fatalf("ApiWithContext() error: %v", err)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.values) != 2 {
+		t.Fatalf("expected request and response to be recorded, got %d", len(rec.values))
+	}
+	for _, value := range rec.values {
+		if value != "trace-1" {
+			t.Fatalf("recorder context lost trace id: got %v", value)
+		}
+	}
+}
+
+func TestApiWithContext_AlreadyCancelledSkipsRequest(t *testing.T) {
+	called := false
+	c := NewClient(DefaultOptions())
+	c.SetClient(
+		&http.Client{
+			Transport: roundTripFunc(
+				func(*http.Request) (*http.Response, error) {
+					called = true
+					return nil, errors.New("unexpected call")
+				},
+			),
+		},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.ApiWithContext(ctx, testTokenSaleRequest(), "https://example.com"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if called {
+		t.Fatal("request must not be sent with a cancelled context")
+	}
+}
@@ -25,6 +25,8 @@
 package http
 
 import (
+	"bytes"
+	"encoding/json"
 	"mime"
 	"net/url"
 	"sort"
@@ -47,10 +49,18 @@ func IsFormURLEncodedContentType(contentType string) bool {
 	return strings.EqualFold(mediaType, FormURLEncodedContentType)
 }
 
-// PrettyPrintFormURLEncodedBody formats a URL-encoded body
+// PrettyPrintFormURLEncodedBody formats a URL-encoded body. Card numbers, CVV2
+// and tokens (see DefaultSensitiveKeys) are masked.
 func PrettyPrintFormURLEncodedBody(raw string) string {
+	return prettyPrintFormURLEncodedBody(raw, defaultSensitiveKeys)
+}
+
+func prettyPrintFormURLEncodedBody(raw string, sensitive map[string]Mask) string {
 	values, err := url.ParseQuery(raw)
 	if err != nil {
+		if len(sensitive) > 0 {
+			return "<unparseable form body>"
+		}
 		return raw
 	}
 	if len(values) == 0 {
@@ -90,7 +100,7 @@ func PrettyPrintFormURLEncodedBody(raw string) string {
 				b.WriteString("<empty>")
 				continue
 			}
-			b.WriteString(value)
+			b.WriteString(redactValue(key, value, sensitive))
 		}
 	}
 
@@ -101,17 +111,71 @@ func PrettyPrintFormURLEncodedBody(raw string) string {
 	return b.String()
 }
 
-// FormatBodyForDebug pretty-prints URL-encoded bodies and returns
-// raw payload for all other content types.
+// FormatBodyForDebug pretty-prints URL-encoded bodies and masks sensitive
+// fields in them and in JSON bodies. Other payloads are returned as is.
 func FormatBodyForDebug(contentType string, raw []byte) string {
+	return formatBodyForDebug(contentType, raw, defaultSensitiveKeys)
+}
+
+func formatBodyForDebug(contentType string, raw []byte, keys map[string]Mask) string {
 	if len(raw) == 0 {
 		return "<empty>"
 	}
 
 	text := string(raw)
 	if IsFormURLEncodedContentType(contentType) {
-		return PrettyPrintFormURLEncodedBody(text)
+		return prettyPrintFormURLEncodedBody(text, keys)
+	}
+	if masked, ok := redactJSONBody(raw, keys); ok {
+		return masked
 	}
 
 	return text
 }
+
+// redactJSONBody masks sensitive keys at any depth of a JSON object or array.
+// It reports false when raw is not JSON, so the caller can log it unchanged.
+func redactJSONBody(raw []byte, keys map[string]Mask) (string, bool) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(keys) == 0 || len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return "", false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+	var body interface{}
+	if err := decoder.Decode(&body); err != nil {
+		return "", false
+	}
+
+	masked, err := json.Marshal(redactJSONValue(body, keys))
+	if err != nil {
+		return "", false
+	}
+
+	return string(masked), true
+}
+
+func redactJSONValue(value interface{}, keys map[string]Mask) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			switch field := item.(type) {
+			case string:
+				v[key] = redactValue(key, field, keys)
+			case json.Number:
+				if redacted := redactValue(key, field.String(), keys); redacted != field.String() {
+					v[key] = redacted
+				}
+			default:
+				v[key] = redactJSONValue(item, keys)
+			}
+		}
+	case []interface{}:
+		for idx, item := range v {
+			v[idx] = redactJSONValue(item, keys)
+		}
+	}
+
+	return value
+}
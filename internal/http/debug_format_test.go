@@ -25,8 +25,16 @@
 package http
 
 import (
+	"fmt"
+	"io"
+	"net/http"
 	"strings"
+	"sync"
 	"testing"
+
+	"github.com/stremovskyy/go-platon/currency"
+	"github.com/stremovskyy/go-platon/log"
+	"github.com/stremovskyy/go-platon/platon"
 )
 
 func TestIsFormURLEncodedContentType(t *testing.T) {
@@ -128,3 +136,181 @@ func TestFormatBodyForDebug(t *testing.T) {
 		},
 	)
 }
+
+func TestPrettyPrintFormURLEncodedBody_MasksSensitiveFields(t *testing.T) {
+	t.Parallel()
+
+	raw := "card_number=4111111111111111&card_cvv2=123&payment_token=TOKEN-SECRET&order_id=o-1"
+	got := PrettyPrintFormURLEncodedBody(raw)
+
+	for _, leaked := range []string{"4111111111111111", "card_cvv2=123", "TOKEN-SECRET"} {
+		if strings.Contains(got, leaked) {
+			t.Fatalf("debug output leaks %q: %q", leaked, got)
+		}
+	}
+	if !strings.Contains(got, "card_number=411111******1111") {
+		t.Fatalf("card_number should keep first6/last4, got %q", got)
+	}
+	if !strings.Contains(got, "card_cvv2=***") {
+		t.Fatalf("card_cvv2 should be masked, got %q", got)
+	}
+	if !strings.Contains(got, "payment_token=sha256:") {
+		t.Fatalf("payment_token should be hashed, got %q", got)
+	}
+	if !strings.Contains(got, "order_id=o-1") {
+		t.Fatalf("non-sensitive fields must be kept, got %q", got)
+	}
+}
+
+func TestFormatBodyForDebug_ExtraSensitiveKeys(t *testing.T) {
+	t.Parallel()
+
+	keys := sensitiveKeys([]string{"Payer_Phone"})
+	got := formatBodyForDebug(FormURLEncodedContentType, []byte("payer_phone=380631234567&card_number=4111111111111111"), keys)
+
+	want := "card_number=411111******1111\npayer_phone=***"
+	if got != want {
+		t.Fatalf("formatBodyForDebug() = %q, want %q", got, want)
+	}
+	if _, ok := DefaultSensitiveKeys()["payer_phone"]; ok {
+		t.Fatal("extra keys must not change the defaults")
+	}
+}
+
+func TestFormatBodyForDebug_MasksJSONResponse(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"result":"SUCCESS","card_token":"CARD-TOKEN-SECRET","transactions":[{"id":"t-1","rc_token":"RC-TOKEN-SECRET"}]}`)
+	got := FormatBodyForDebug("application/json", body)
+
+	for _, leaked := range []string{"CARD-TOKEN-SECRET", "RC-TOKEN-SECRET"} {
+		if strings.Contains(got, leaked) {
+			t.Fatalf("debug output leaks %q: %q", leaked, got)
+		}
+	}
+	for _, kept := range []string{`"card_token":"sha256:`, `"rc_token":"sha256:`, `"result":"SUCCESS"`, `"id":"t-1"`} {
+		if !strings.Contains(got, kept) {
+			t.Fatalf("debug output = %q, want it to contain %q", got, kept)
+		}
+	}
+}
+
+func TestRedactFormBody(t *testing.T) {
+	t.Parallel()
+
+	raw := "order_id=o+1&card_number=4111111111111111&card_cvv2=123&hash=0123456789abcdef0123456789abcdef&payment_token=TOKEN-SECRET&sign=ABCDEF"
+	got := RedactFormBody(raw)
+
+	for _, leaked := range []string{"4111111111111111", "card_cvv2=123", "0123456789abcdef", "TOKEN-SECRET", "ABCDEF"} {
+		if strings.Contains(got, leaked) {
+			t.Fatalf("RedactFormBody() leaks %q: %q", leaked, got)
+		}
+	}
+	if !strings.HasPrefix(got, "card_cvv2=***&card_number=411111******1111&hash=***&order_id=o+1&payment_token=sha256:") ||
+		!strings.HasSuffix(got, "&sign=***") {
+		t.Fatalf("RedactFormBody() = %q", got)
+	}
+
+	if got := RedactFormBody("card_number=4111111111111111&bad=%zz"); strings.Contains(got, "4111111111111111") {
+		t.Fatalf("unparseable bodies must not be logged verbatim, got %q", got)
+	}
+}
+
+type logCapture struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (c *logCapture) Log(level log.Level, prefix, msg string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lines = append(c.lines, prefix+fmt.Sprintf(msg, args...))
+}
+
+func (c *logCapture) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return strings.Join(c.lines, "\n")
+}
+
+func cardPaymentRequest(secret, pan, cvv string) *platon.Request {
+	orderID := "order-redact"
+	ip := "203.0.113.10"
+	term := "https://example.com/3ds"
+	email := "payer@example.com"
+	phone := "380631234567"
+	month, year := "01", "2030"
+
+	return platon.NewRequest(platon.ActionCodeSALE).
+		WithAuth(&platon.Auth{Key: "k", Secret: secret}).
+		WithClientKey("clientKey").
+		WithOrderID(&orderID).
+		WithOrderAmount("1.00").
+		ForCurrency(currency.UAH).
+		WithDescription("redaction").
+		WithPayerIP(&ip).
+		WithTermsURL(&term).
+		WithCardNumber(&pan).
+		WithCardExpMonth(&month).
+		WithCardExpYear(&year).
+		WithCardCvv2(&cvv).
+		WithPayerEmail(&email).
+		WithPayerPhone(&phone).
+		SignForAction(platon.HashTypeCardPayment)
+}
+
+func TestApi_DebugLogsAreRedacted(t *testing.T) {
+	previousLevel := log.GetLevel()
+	log.SetLevel(log.LevelAll)
+	t.Cleanup(func() { log.SetLevel(previousLevel) })
+
+	const (
+		secret = "merchant-secret-123"
+		pan    = "4111111111111111"
+		cvv    = "987"
+	)
+
+	run := func(options *Options) (string, string) {
+		var sentHash string
+		capture := &logCapture{}
+		c := NewClient(options)
+		c.SetLogSink(capture)
+		c.SetClient(
+			&http.Client{
+				Transport: roundTripFunc(
+					func(req *http.Request) (*http.Response, error) {
+						_ = req.ParseForm()
+						sentHash = req.PostForm.Get("hash")
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(`{"result":"ACCEPTED","card":"411111****1111"}`)),
+						}, nil
+					},
+				),
+			},
+		)
+		if _, err := c.Api(cardPaymentRequest(secret, pan, cvv), "https://example.com/post-unq/"); err != nil {
+			t.Fatalf("Api() error: %v", err)
+		}
+		return capture.String(), sentHash
+	}
+
+	output, hash := run(DefaultOptions())
+	if !strings.Contains(output, "card_number=411111******1111") {
+		t.Fatalf("request form was not logged with a masked card number:\n%s", output)
+	}
+	upper := strings.ToUpper(output)
+	for _, leaked := range []string{secret, pan, "card_cvv2=" + cvv, hash} {
+		if leaked != "" && strings.Contains(upper, strings.ToUpper(leaked)) {
+			t.Fatalf("debug logs leak %q:\n%s", leaked, output)
+		}
+	}
+
+	unsafe := DefaultOptions()
+	unsafe.UnsafeLogging = true
+	output, _ = run(unsafe)
+	if !strings.Contains(output, "card_number="+pan) || !strings.Contains(output, "card_cvv2="+cvv) {
+		t.Fatalf("UnsafeLogging must log the form verbatim:\n%s", output)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package http
+
+import "time"
+
+// RequestEvent describes a single API call reported to an Observer.
+type RequestEvent struct {
+	// Action is the Platon action, e.g. "SALE" or "GET_TRANS_STATUS".
+	Action string
+	// Endpoint is the URL the request is posted to.
+	Endpoint string
+	// RequestID is the X-Request-ID of the call.
+	RequestID string
+}
+
+// Observer receives lightweight notifications around API calls, e.g. to feed
+// metrics. OnRequestEnd gets the total duration including retries, the HTTP
+// status of the last attempt (0 when no response was received) and the error
+// returned to the caller. Observers must be safe for concurrent use.
+type Observer interface {
+	OnRequestStart(event RequestEvent)
+	OnRequestEnd(event RequestEvent, duration time.Duration, status int, err error)
+}
+
+// SetObserver sets the observer notified around every API call.
+func (c *Client) SetObserver(observer Observer) {
+	c.observer = observer
+}
@@ -0,0 +1,138 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+type countingObserver struct {
+	mu     sync.Mutex
+	starts []RequestEvent
+	ends   []observedEnd
+}
+
+type observedEnd struct {
+	event    RequestEvent
+	duration time.Duration
+	status   int
+	err      error
+}
+
+func (o *countingObserver) OnRequestStart(event RequestEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.starts = append(o.starts, event)
+}
+
+func (o *countingObserver) OnRequestEnd(event RequestEvent, duration time.Duration, status int, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.ends = append(o.ends, observedEnd{event: event, duration: duration, status: status, err: err})
+}
+
+func TestObserver_CalledOncePerRequest(t *testing.T) {
+	var attempts atomic.Int32
+	observer := &countingObserver{}
+
+	c := NewClient(&Options{MaxRetries: 3, RetryBackoff: time.Millisecond})
+	c.SetClient(&http.Client{Transport: flakyTransport(2, &attempts, nil)})
+	c.SetObserver(observer)
+
+	if _, err := c.Api(testStatusRequest(), "https://example.com/post-unq/"); err != nil {
+		t.Fatalf("Api() error: %v", err)
+	}
+
+	if len(observer.starts) != 1 || len(observer.ends) != 1 {
+		t.Fatalf("observer calls: %d starts, %d ends; want 1 each (attempts=%d)", len(observer.starts), len(observer.ends), attempts.Load())
+	}
+
+	end := observer.ends[0]
+	if end.event != observer.starts[0] {
+		t.Fatalf("end event %+v differs from start event %+v", end.event, observer.starts[0])
+	}
+	if end.event.Action != platon.ActionCodeGetTransStatus.String() || end.event.Endpoint != "https://example.com/post-unq/" || end.event.RequestID == "" {
+		t.Fatalf("unexpected event: %+v", end.event)
+	}
+	if end.status != http.StatusOK || end.err != nil || end.duration <= 0 {
+		t.Fatalf("unexpected end: status=%d err=%v duration=%v", end.status, end.err, end.duration)
+	}
+}
+
+func TestObserver_ReportsFailures(t *testing.T) {
+	observer := &countingObserver{}
+	c := NewClient(nil)
+	c.SetObserver(observer)
+
+	c.SetClient(
+		&http.Client{
+			Transport: roundTripFunc(
+				func(*http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusBadGateway,
+						Body:       io.NopCloser(strings.NewReader("bad gateway")),
+					}, nil
+				},
+			),
+		},
+	)
+	_, err := c.Api(testStatusRequest(), "https://example.com")
+	if err == nil {
+		t.Fatal("Api() expected an error for 502")
+	}
+
+	refused := errors.New("connection refused")
+	c.SetClient(
+		&http.Client{
+			Transport: roundTripFunc(
+				func(*http.Request) (*http.Response, error) {
+					return nil, refused
+				},
+			),
+		},
+	)
+	if _, err := c.Api(testStatusRequest(), "https://example.com"); err == nil {
+		t.Fatal("Api() expected a transport error")
+	}
+
+	if len(observer.starts) != 2 || len(observer.ends) != 2 {
+		t.Fatalf("observer calls: %d starts, %d ends; want 2 each", len(observer.starts), len(observer.ends))
+	}
+	if end := observer.ends[0]; end.status != http.StatusBadGateway || end.err == nil {
+		t.Fatalf("502 end: status=%d err=%v", end.status, end.err)
+	}
+	if end := observer.ends[1]; end.status != 0 || !errors.Is(end.err, refused) {
+		t.Fatalf("transport error end: status=%d err=%v", end.status, end.err)
+	}
+}
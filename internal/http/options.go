@@ -24,7 +24,12 @@
 
 package http
 
-import "time"
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+)
 
 // Options for http client
 type Options struct {
@@ -39,6 +44,56 @@ type Options struct {
 	MaxConnsPerHost       int
 	IdleConnTimeout       time.Duration
 	IsDebug               bool
+
+	// MaxRetries is the number of retries for transient failures (connection
+	// errors, 502/503/504) of read-only actions. Zero disables retries.
+	MaxRetries int
+	// RetryBackoff is the base delay between retries; it doubles on every
+	// retry and is jittered.
+	RetryBackoff time.Duration
+	// RetryableStatusCodes overrides the HTTP statuses that are retried
+	// (502, 503 and 504 by default).
+	RetryableStatusCodes []int
+	// RetryUnsafeActions also retries actions that move money (SALE, CAPTURE,
+	// CREDITVOID, ...). Only enable it when replays are deduplicated upstream.
+	RetryUnsafeActions bool
+
+	// SensitiveLogKeys are extra form fields masked in debug logs, on top of
+	// card_number, card_cvv2, payment_token and card_token.
+	SensitiveLogKeys []string
+	// UnsafeLogging disables the masking of card data, signatures and secrets
+	// in debug logs. It is meant for local debugging against the sandbox only.
+	UnsafeLogging bool
+	// RecordAmountTag adds the exact order amount as the "amount" recorder
+	// tag. Amounts are left out of tags by default.
+	RecordAmountTag bool
+
+	// Proxy selects the proxy for each outbound request. It defaults to
+	// http.ProxyFromEnvironment.
+	Proxy func(*http.Request) (*url.URL, error)
+
+	// TLSConfig is used by the transport for custom root CAs or client
+	// certificates (mTLS). It is cloned; nil keeps Go's defaults.
+	TLSConfig *tls.Config
+
+	// UserAgentSuffix is appended to the default User-Agent, e.g. a service
+	// name that Platon support can trace.
+	UserAgentSuffix string
+	// Headers are sent with every request on top of the default headers.
+	// Content-Type, User-Agent and X-Request-ID are managed by the client and
+	// are never overridden.
+	Headers http.Header
+
+	// MaxResponseBytes caps the size of a response body; a larger response
+	// fails with "response exceeds N bytes". It defaults to 4 MiB.
+	MaxResponseBytes int
+
+	// RateLimit smooths requests with a token bucket shared by all actions of
+	// the client. Nil disables rate limiting.
+	RateLimit *RateLimit
+	// ActionRateLimits override RateLimit for single actions, keyed by action
+	// code. A RateLimit with RPS of zero or less makes the action unlimited.
+	ActionRateLimits map[string]RateLimit
 }
 
 func DefaultOptions() *Options {
@@ -54,6 +109,7 @@ func DefaultOptions() *Options {
 		MaxConnsPerHost:       100,
 		IdleConnTimeout:       90 * time.Second,
 		IsDebug:               false,
+		MaxResponseBytes:      defaultMaxResponseBytes,
 	}
 }
 
@@ -95,6 +151,29 @@ func normalizeOptions(options *Options) *Options {
 	if normalized.IdleConnTimeout <= 0 {
 		normalized.IdleConnTimeout = defaults.IdleConnTimeout
 	}
+	if normalized.MaxResponseBytes <= 0 {
+		normalized.MaxResponseBytes = defaults.MaxResponseBytes
+	}
+	if normalized.MaxRetries < 0 {
+		normalized.MaxRetries = 0
+	}
+	if normalized.TLSConfig != nil {
+		normalized.TLSConfig = normalized.TLSConfig.Clone()
+	}
+	if normalized.RateLimit != nil {
+		limit := *normalized.RateLimit
+		normalized.RateLimit = &limit
+	}
+	if normalized.ActionRateLimits != nil {
+		actions := make(map[string]RateLimit, len(normalized.ActionRateLimits))
+		for action, limit := range normalized.ActionRateLimits {
+			actions[action] = limit
+		}
+		normalized.ActionRateLimits = actions
+	}
+	if normalized.RetryableStatusCodes != nil {
+		normalized.RetryableStatusCodes = append([]int(nil), normalized.RetryableStatusCodes...)
+	}
 
 	return &normalized
 }
@@ -0,0 +1,238 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit is a token-bucket limit of RPS requests per second with bursts of
+// up to Burst requests. An RPS of zero or less means unlimited.
+type RateLimit struct {
+	RPS   float64
+	Burst int
+}
+
+// RateLimitStats is a snapshot of one rate limiter.
+type RateLimitStats struct {
+	RPS   float64
+	Burst int
+	// Tokens is the number of requests that can be sent right now without
+	// waiting; it is negative while callers are queued.
+	Tokens float64
+	// Allowed counts requests sent without waiting.
+	Allowed uint64
+	// Delayed counts requests sent after waiting for a token.
+	Delayed uint64
+	// Cancelled counts requests whose context ended while waiting.
+	Cancelled uint64
+	// Waited is the total time spent waiting for tokens.
+	Waited time.Duration
+}
+
+// Stats reports the client's rate limiters. Limiters that are not configured
+// are left out.
+type Stats struct {
+	// RateLimit is the limiter shared by all actions, nil when disabled.
+	RateLimit *RateLimitStats
+	// ActionRateLimits are the per-action limiters keyed by action, e.g.
+	// "GET_TRANS_STATUS". Unlimited actions are not listed.
+	ActionRateLimits map[string]RateLimitStats
+}
+
+// rateLimiter is a token bucket. Waiting callers reserve a token up front, so
+// they are served in arrival order, and give it back when their context ends.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rps    float64
+	burst  int
+	tokens float64
+	last   time.Time
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+
+	allowed   uint64
+	delayed   uint64
+	cancelled uint64
+	waited    time.Duration
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.RPS <= 0 {
+		return nil
+	}
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+
+	return &rateLimiter{
+		rps:    limit.RPS,
+		burst:  limit.Burst,
+		tokens: float64(limit.Burst),
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+}
+
+// refill adds the tokens earned since the last call. It must be called with
+// mu held.
+func (l *rateLimiter) refill() {
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = math.Min(float64(l.burst), l.tokens+now.Sub(l.last).Seconds()*l.rps)
+	}
+	l.last = now
+}
+
+// wait blocks until a token is available or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	l.refill()
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(math.Ceil(-l.tokens / l.rps * float64(time.Second)))
+	}
+	if delay == 0 {
+		l.allowed++
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	if err := l.sleep(ctx, delay); err != nil {
+		l.mu.Lock()
+		l.refill()
+		l.tokens = math.Min(float64(l.burst), l.tokens+1)
+		l.cancelled++
+		l.mu.Unlock()
+
+		return fmt.Errorf("rate limit: %w", err)
+	}
+
+	l.mu.Lock()
+	l.delayed++
+	l.waited += delay
+	l.mu.Unlock()
+
+	return nil
+}
+
+func (l *rateLimiter) stats() RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+
+	return RateLimitStats{
+		RPS:       l.rps,
+		Burst:     l.burst,
+		Tokens:    l.tokens,
+		Allowed:   l.allowed,
+		Delayed:   l.delayed,
+		Cancelled: l.cancelled,
+		Waited:    l.waited,
+	}
+}
+
+// rateLimiters holds the shared limiter and the per-action overrides. An
+// override with no limiter makes its action unlimited.
+type rateLimiters struct {
+	shared  *rateLimiter
+	actions map[string]*rateLimiter
+}
+
+func newRateLimiters(options *Options) *rateLimiters {
+	if options == nil {
+		return nil
+	}
+
+	limiters := &rateLimiters{}
+	if options.RateLimit != nil {
+		limiters.shared = newRateLimiter(*options.RateLimit)
+	}
+	if len(options.ActionRateLimits) > 0 {
+		limiters.actions = make(map[string]*rateLimiter, len(options.ActionRateLimits))
+		for action, limit := range options.ActionRateLimits {
+			limiters.actions[action] = newRateLimiter(limit)
+		}
+	}
+	if limiters.shared == nil && limiters.actions == nil {
+		return nil
+	}
+
+	return limiters
+}
+
+func (r *rateLimiters) forAction(action string) *rateLimiter {
+	if r == nil {
+		return nil
+	}
+	if limiter, ok := r.actions[action]; ok {
+		return limiter
+	}
+
+	return r.shared
+}
+
+// waitRateLimit blocks until the limiter of action lets the request through.
+func (c *Client) waitRateLimit(ctx context.Context, action string) error {
+	return c.rateLimiters.forAction(action).wait(ctx)
+}
+
+// Stats returns a snapshot of the client's rate limiters.
+func (c *Client) Stats() Stats {
+	var stats Stats
+	if c == nil || c.rateLimiters == nil {
+		return stats
+	}
+
+	if c.rateLimiters.shared != nil {
+		shared := c.rateLimiters.shared.stats()
+		stats.RateLimit = &shared
+	}
+	for action, limiter := range c.rateLimiters.actions {
+		if limiter == nil {
+			continue
+		}
+		if stats.ActionRateLimits == nil {
+			stats.ActionRateLimits = make(map[string]RateLimitStats)
+		}
+		stats.ActionRateLimits[action] = limiter.stats()
+	}
+
+	return stats
+}
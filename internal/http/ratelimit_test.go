@@ -0,0 +1,194 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+// fakeClock is a manual clock whose sleep advances time instead of blocking.
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func (f *fakeClock) Now() time.Time { return f.now }
+
+func (f *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.slept = append(f.slept, d)
+	f.now = f.now.Add(d)
+
+	return nil
+}
+
+func newFakeLimiter(limit RateLimit) (*rateLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	limiter := newRateLimiter(limit)
+	limiter.now = clock.Now
+	limiter.sleep = clock.Sleep
+
+	return limiter, clock
+}
+
+func TestRateLimiter_BurstThenSteadyRate(t *testing.T) {
+	limiter, clock := newFakeLimiter(RateLimit{RPS: 2, Burst: 3})
+
+	for i := 0; i < 5; i++ {
+		if err := limiter.wait(context.Background()); err != nil {
+			t.Fatalf("wait() #%d error: %v", i, err)
+		}
+	}
+
+	want := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}
+	if len(clock.slept) != len(want) {
+		t.Fatalf("expected sleeps %v, got %v", want, clock.slept)
+	}
+	for i := range want {
+		if clock.slept[i] != want[i] {
+			t.Fatalf("expected sleeps %v, got %v", want, clock.slept)
+		}
+	}
+
+	stats := limiter.stats()
+	if stats.Allowed != 3 || stats.Delayed != 2 || stats.Waited != time.Second {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	clock.now = clock.now.Add(10 * time.Second)
+	if got := limiter.stats().Tokens; got != 3 {
+		t.Fatalf("tokens should refill up to the burst, got %v", got)
+	}
+}
+
+func TestRateLimiter_CancelledWaitReturnsToken(t *testing.T) {
+	limiter, clock := newFakeLimiter(RateLimit{RPS: 1, Burst: 1})
+
+	if err := limiter.wait(context.Background()); err != nil {
+		t.Fatalf("wait() error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := limiter.wait(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(clock.slept) != 0 {
+		t.Fatalf("cancelled wait must not sleep, got %v", clock.slept)
+	}
+
+	stats := limiter.stats()
+	if stats.Cancelled != 1 || stats.Tokens != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestApi_RateLimitSharedWithActionOverride(t *testing.T) {
+	var calls atomic.Int32
+
+	c := NewClient(
+		&Options{
+			RateLimit: &RateLimit{RPS: 1, Burst: 1},
+			ActionRateLimits: map[string]RateLimit{
+				platon.ActionCodeGetTransStatus.String(): {},
+			},
+		},
+	)
+	c.SetClient(
+		&http.Client{
+			Transport: roundTripFunc(
+				func(*http.Request) (*http.Response, error) {
+					calls.Add(1)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     http.Header{"Content-Type": []string{"application/json"}},
+						Body:       io.NopCloser(strings.NewReader(`{"result":"ACCEPTED"}`)),
+					}, nil
+				},
+			),
+		},
+	)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := c.Api(testStatusRequest(), "https://example.com"); err != nil {
+			t.Fatalf("Api() error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("unlimited action should not wait, took %v", elapsed)
+	}
+
+	stats := c.Stats()
+	if stats.RateLimit == nil || stats.RateLimit.RPS != 1 || stats.RateLimit.Allowed != 0 {
+		t.Fatalf("shared limiter should be untouched, got %+v", stats.RateLimit)
+	}
+	if len(stats.ActionRateLimits) != 0 {
+		t.Fatalf("unlimited actions should not be listed, got %+v", stats.ActionRateLimits)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	transID := "trans-1"
+	email := "payer@example.com"
+	capture := platon.NewRequest(platon.ActionCodeCAPTURE).
+		WithAuth(&platon.Auth{Key: "k", Secret: "secret123"}).
+		WithClientKey("clientKey").
+		WithTransID(&transID).
+		WithHashEmail(&email).
+		WithAmount("1.00").
+		SignForAction(platon.HashTypeCapture)
+
+	if _, err := c.ApiWithContext(context.Background(), capture, "https://example.com"); err != nil {
+		t.Fatalf("first CAPTURE error: %v", err)
+	}
+
+	start = time.Now()
+	_, err := c.ApiWithContext(ctx, capture, "https://example.com")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("cancelled wait should return promptly, took %v", elapsed)
+	}
+	if got := calls.Load(); got != 4 {
+		t.Fatalf("expected 4 round trips, got %d", got)
+	}
+	if stats := c.Stats(); stats.RateLimit.Allowed != 1 || stats.RateLimit.Cancelled != 1 {
+		t.Fatalf("unexpected shared stats: %+v", stats.RateLimit)
+	}
+}
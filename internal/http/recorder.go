@@ -0,0 +1,95 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package http
+
+import (
+	"context"
+	"fmt"
+)
+
+// RecorderErrorHandler is called when a recorder call returns an error or panics.
+// op is one of "request", "response" or "error".
+type RecorderErrorHandler func(op string, requestID string, err error)
+
+// SetRecorderErrorHandler sets a handler for recorder failures.
+func (c *Client) SetRecorderErrorHandler(handler RecorderErrorHandler) {
+	c.recorderErrorHandler = handler
+}
+
+func (c *Client) recordRequest(ctx context.Context, requestID string, body []byte, tags map[string]string) {
+	if c.recorder == nil {
+		return
+	}
+	c.safeRecord(
+		"request", requestID, func() error {
+			return c.recorder.RecordRequest(ctx, nil, requestID, body, tags)
+		},
+	)
+}
+
+func (c *Client) recordResponse(ctx context.Context, requestID string, body []byte, tags map[string]string) {
+	if c.recorder == nil {
+		return
+	}
+	c.safeRecord(
+		"response", requestID, func() error {
+			return c.recorder.RecordResponse(ctx, nil, requestID, body, tags)
+		},
+	)
+}
+
+func (c *Client) recordError(ctx context.Context, requestID string, recordedErr error, tags map[string]string) {
+	if c.recorder == nil {
+		return
+	}
+	c.safeRecord(
+		"error", requestID, func() error {
+			return c.recorder.RecordError(ctx, nil, requestID, recordedErr, tags)
+		},
+	)
+}
+
+// safeRecord runs a recorder call so that a failing or panicking recorder
+// never breaks the payment path.
+func (c *Client) safeRecord(op string, requestID string, call func() error) {
+	err := func() (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = fmt.Errorf("recorder panicked: %v", recovered)
+			}
+		}()
+		return call()
+	}()
+	if err == nil {
+		return
+	}
+
+	if c.logger != nil {
+		c.logger.Error("cannot record %s: %v", op, err)
+	}
+	if c.recorderErrorHandler != nil {
+		c.recorderErrorHandler(op, requestID, err)
+	}
+}
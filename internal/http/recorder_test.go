@@ -0,0 +1,141 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stremovskyy/go-platon/currency"
+	"github.com/stremovskyy/go-platon/platon"
+	"github.com/stremovskyy/recorder"
+)
+
+type panickingRecorder struct {
+	recorder.Recorder
+}
+
+func (panickingRecorder) RecordRequest(context.Context, *string, string, []byte, map[string]string) error {
+	panic("boom request")
+}
+
+func (panickingRecorder) RecordResponse(context.Context, *string, string, []byte, map[string]string) error {
+	panic("boom response")
+}
+
+func (panickingRecorder) RecordError(context.Context, *string, string, error, map[string]string) error {
+	panic("boom error")
+}
+
+func TestApi_RecorderPanic_DoesNotBreakPayment(t *testing.T) {
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"result":"ACCEPTED"}`))
+			},
+		),
+	)
+	defer srv.Close()
+
+	var mu sync.Mutex
+	captured := map[string]error{}
+
+	c := NewClient(DefaultOptions())
+	c.SetRecorder(panickingRecorder{})
+	c.SetRecorderErrorHandler(
+		func(op string, requestID string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if requestID == "" {
+				t.Errorf("expected request id for %s", op)
+			}
+			captured[op] = err
+		},
+	)
+
+	resp, err := c.Api(testTokenSaleRequest(), srv.URL)
+	if err != nil {
+		t.Fatalf("Api() error: %v", err)
+	}
+	if resp == nil || resp.Result == nil || *resp.Result != platon.ResultAccepted {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	for _, op := range []string{"request", "response"} {
+		if captured[op] == nil || !strings.Contains(captured[op].Error(), "recorder panicked: boom "+op) {
+			t.Fatalf("expected captured %s panic, got %v", op, captured[op])
+		}
+	}
+}
+
+func TestApi_RecorderPanicOnError_ReturnsOriginalError(t *testing.T) {
+	var captured error
+
+	c := NewClient(DefaultOptions())
+	c.SetRecorder(panickingRecorder{})
+	c.SetRecorderErrorHandler(
+		func(op string, _ string, err error) {
+			if op == "error" {
+				captured = err
+			}
+		},
+	)
+
+	_, err := c.Api(nil, "http://127.0.0.1:0")
+	if err != platon.ErrRequestIsNil {
+		t.Fatalf("expected ErrRequestIsNil, got %v", err)
+	}
+	if captured == nil || !strings.Contains(captured.Error(), "boom error") {
+		t.Fatalf("expected captured error panic, got %v", captured)
+	}
+}
+
+func testTokenSaleRequest() *platon.Request {
+	orderID := "order-123"
+	ip := "127.0.0.1"
+	term := "https://example.com/3ds"
+	email := "payer@example.com"
+	phone := "380631234567"
+	token := "TOKEN123"
+
+	return platon.NewRequest(platon.ActionCodeSALE).
+		WithAuth(&platon.Auth{Key: "k", Secret: "secret123"}).
+		WithClientKey("clientKey").
+		WithCardToken(&token).
+		WithOrderID(&orderID).
+		WithOrderAmount("1.00").
+		ForCurrency(currency.UAH).
+		WithDescription("one-click").
+		WithPayerIP(&ip).
+		WithTermsURL(&term).
+		WithPayerEmail(&email).
+		WithPayerPhone(&phone).
+		SignForAction(platon.HashTypeCardTokenPayment)
+}
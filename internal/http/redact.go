@@ -0,0 +1,168 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Mask selects how a sensitive form field is shown in debug logs.
+type Mask int
+
+const (
+	// MaskFull replaces the whole value with "***".
+	MaskFull Mask = iota
+	// MaskPAN keeps the first 6 and last 4 digits of a card number.
+	MaskPAN
+	// MaskToken replaces the value with a truncated SHA-256 of it, so the same
+	// token can still be correlated across log lines.
+	MaskToken
+)
+
+// defaultSensitiveKeys lists the form fields masked in debug logs by default.
+var defaultSensitiveKeys = map[string]Mask{
+	"card_number":   MaskPAN,
+	"card_cvv2":     MaskFull,
+	"payment_token": MaskToken,
+	"card_token":    MaskToken,
+	"rc_token":      MaskToken,
+	"hash":          MaskFull,
+	"sign":          MaskFull,
+	"pass":          MaskFull,
+	"client_pass":   MaskFull,
+	"secret":        MaskFull,
+}
+
+// DefaultSensitiveKeys returns a copy of the fields masked by default.
+func DefaultSensitiveKeys() map[string]Mask {
+	return sensitiveKeys(nil)
+}
+
+// sensitiveKeys returns the default sensitive keys plus extra keys, which are
+// masked fully unless they already have a mask.
+func sensitiveKeys(extra []string) map[string]Mask {
+	return logSensitiveKeys(extra, false)
+}
+
+// logSensitiveKeys is sensitiveKeys, except that unsafe disables masking
+// entirely (Options.UnsafeLogging).
+func logSensitiveKeys(extra []string, unsafe bool) map[string]Mask {
+	if unsafe {
+		return map[string]Mask{}
+	}
+
+	keys := make(map[string]Mask, len(defaultSensitiveKeys)+len(extra))
+	for key, mask := range defaultSensitiveKeys {
+		keys[key] = mask
+	}
+	for _, key := range extra {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		if _, ok := keys[key]; !ok {
+			keys[key] = MaskFull
+		}
+	}
+
+	return keys
+}
+
+// RedactFormBody returns a URL-encoded body with card numbers reduced to their
+// first 6 / last 4 digits, CVV2, signatures and secrets replaced by "***" and
+// tokens truncated to a SHA-256 prefix. Keys are sorted. A body that cannot be
+// parsed is replaced entirely, so it never reaches the log unmasked.
+func RedactFormBody(raw string) string {
+	return redactFormBody(raw, defaultSensitiveKeys)
+}
+
+func redactFormBody(raw string, keys map[string]Mask) string {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return "<unparseable form body>"
+	}
+
+	names := make([]string, 0, len(values))
+	for key := range values {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, key := range names {
+		for _, value := range values[key] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(key))
+			b.WriteByte('=')
+			if redacted := redactValue(key, value, keys); redacted != value {
+				// Masks only contain digits, '*' and hex, so they stay readable.
+				b.WriteString(redacted)
+			} else {
+				b.WriteString(url.QueryEscape(value))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// RedactValue masks value when key is one of the default sensitive fields.
+func RedactValue(key, value string) string {
+	return redactValue(key, value, defaultSensitiveKeys)
+}
+
+func redactValue(key, value string, keys map[string]Mask) string {
+	mask, ok := keys[strings.ToLower(key)]
+	if !ok || value == "" {
+		return value
+	}
+
+	switch mask {
+	case MaskPAN:
+		return maskPAN(value)
+	case MaskToken:
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:])[:12]
+	default:
+		return "***"
+	}
+}
+
+// maskPAN keeps the first 6 and last 4 characters of a card number and masks
+// the middle. Values too short to be a PAN are masked fully.
+func maskPAN(pan string) string {
+	pan = strings.TrimSpace(pan)
+	if len(pan) < 13 {
+		return "***"
+	}
+
+	return pan[:6] + strings.Repeat("*", len(pan)-10) + pan[len(pan)-4:]
+}
@@ -0,0 +1,146 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package http
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+const (
+	defaultRetryBackoff = 200 * time.Millisecond
+	maxRetryDelay       = 10 * time.Second
+)
+
+// retryPolicy controls how many times a transient failure is retried.
+type retryPolicy struct {
+	maxRetries  int
+	backoff     time.Duration
+	statusCodes []int
+}
+
+// retryableActions lists read-only actions that are safe to replay. Payment
+// actions (SALE, CAPTURE, CREDITVOID, ...) are only retried when
+// Options.RetryUnsafeActions is set.
+var retryableActions = map[string]struct{}{
+	platon.ActionCodeGetTransStatus.String():        {},
+	platon.ActionCodeGetTransStatusByOrder.String(): {},
+	platon.ActionCodeGetTransDetails.String():       {},
+	platon.ActionCodeGetSubmerchant.String():        {},
+}
+
+func (c *Client) retryPolicyFor(request *platon.Request, callOpts *callOptions) retryPolicy {
+	if request == nil {
+		return retryPolicy{}
+	}
+	if _, ok := retryableActions[request.Action]; !ok && (c.options == nil || !c.options.RetryUnsafeActions) {
+		return retryPolicy{}
+	}
+
+	var policy retryPolicy
+	if c.options != nil {
+		policy = retryPolicy{
+			maxRetries:  c.options.MaxRetries,
+			backoff:     c.options.RetryBackoff,
+			statusCodes: c.options.RetryableStatusCodes,
+		}
+	}
+	if callOpts != nil && callOpts.retry != nil {
+		policy.maxRetries = callOpts.retry.maxRetries
+		policy.backoff = callOpts.retry.backoff
+	}
+
+	if policy.maxRetries < 0 {
+		policy.maxRetries = 0
+	}
+	if policy.backoff <= 0 {
+		policy.backoff = defaultRetryBackoff
+	}
+
+	return policy
+}
+
+// delay returns the wait before retry number attempt (0-based): exponential
+// backoff with jitter in [d/2, d].
+func (p retryPolicy) delay(attempt int) time.Duration {
+	d := p.backoff
+	for i := 0; i < attempt && d < maxRetryDelay; i++ {
+		d *= 2
+	}
+	if d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+
+	half := d / 2
+
+	return half + time.Duration(rand.Int64N(int64(d-half)+1))
+}
+
+func (p retryPolicy) retryableStatus(status int) bool {
+	if p.statusCodes != nil {
+		for _, code := range p.statusCodes {
+			if code == status {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// withAttempt returns a copy of tags carrying the 1-based attempt number, so
+// maps already handed to the recorder are never mutated.
+func withAttempt(tags map[string]string, attempt int) map[string]string {
+	out := make(map[string]string, len(tags)+1)
+	for k, v := range tags {
+		out[k] = v
+	}
+	out["attempt"] = strconv.Itoa(attempt)
+
+	return out
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,261 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stremovskyy/go-platon/platon"
+	"github.com/stremovskyy/recorder"
+)
+
+func testStatusRequest() *platon.Request {
+	transID := "trans-1"
+	email := "payer@example.com"
+
+	return platon.NewRequest(platon.ActionCodeGetTransStatus).
+		WithAuth(&platon.Auth{Key: "k", Secret: "secret123"}).
+		WithClientKey("clientKey").
+		WithTransID(&transID).
+		WithHashEmail(&email).
+		SignForAction(platon.HashTypeGetTransStatus)
+}
+
+// flakyTransport fails the first failures round trips with a connection error
+// and answers ACCEPTED afterwards.
+func flakyTransport(failures int32, attempts *atomic.Int32, requestIDs *[]string) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		n := attempts.Add(1)
+		if requestIDs != nil {
+			*requestIDs = append(*requestIDs, req.Header.Get("X-Request-ID"))
+		}
+		if n <= failures {
+			return nil, errors.New("connection reset by peer")
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"result":"ACCEPTED"}`)),
+		}, nil
+	}
+}
+
+func TestApi_RetriesReadOnlyActions(t *testing.T) {
+	var attempts atomic.Int32
+	var requestIDs []string
+
+	c := NewClient(&Options{MaxRetries: 3, RetryBackoff: time.Millisecond})
+	c.SetClient(&http.Client{Transport: flakyTransport(2, &attempts, &requestIDs)})
+
+	resp, err := c.Api(testStatusRequest(), "https://example.com")
+	if err != nil {
+		t.Fatalf("Api() error: %v", err)
+	}
+	if resp == nil || resp.Result == nil || *resp.Result != platon.ResultAccepted {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+	for _, id := range requestIDs {
+		if id != requestIDs[0] {
+			t.Fatalf("retries must reuse X-Request-ID, got %v", requestIDs)
+		}
+	}
+}
+
+func TestApi_RetriesOn503(t *testing.T) {
+	var attempts atomic.Int32
+
+	c := NewClient(&Options{MaxRetries: 2, RetryBackoff: time.Millisecond})
+	c.SetClient(
+		&http.Client{
+			Transport: roundTripFunc(
+				func(*http.Request) (*http.Response, error) {
+					status := http.StatusOK
+					if attempts.Add(1) == 1 {
+						status = http.StatusServiceUnavailable
+					}
+					return &http.Response{
+						StatusCode: status,
+						Header:     http.Header{"Content-Type": []string{"application/json"}},
+						Body:       io.NopCloser(strings.NewReader(`{"result":"ACCEPTED"}`)),
+					}, nil
+				},
+			),
+		},
+	)
+
+	if _, err := c.Api(testStatusRequest(), "https://example.com"); err != nil {
+		t.Fatalf("Api() error: %v", err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestApi_DoesNotRetrySale(t *testing.T) {
+	var attempts atomic.Int32
+
+	c := NewClient(&Options{MaxRetries: 3, RetryBackoff: time.Millisecond})
+	c.SetClient(&http.Client{Transport: flakyTransport(2, &attempts, nil)})
+
+	if _, err := c.Api(testTokenSaleRequest(), "https://example.com"); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Fatalf("SALE must not be retried, got %d attempts", got)
+	}
+}
+
+func TestApi_WithRetryOverridesClientPolicy(t *testing.T) {
+	var attempts atomic.Int32
+
+	c := NewClient(&Options{MaxRetries: 3, RetryBackoff: time.Millisecond})
+	c.SetClient(&http.Client{Transport: flakyTransport(2, &attempts, nil)})
+
+	if _, err := c.Api(testStatusRequest(), "https://example.com", WithRetry(0, 0)); err == nil {
+		t.Fatal("expected error with retries disabled")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Fatalf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestRetryPolicy_DelayIsBounded(t *testing.T) {
+	p := retryPolicy{maxRetries: 10, backoff: 100 * time.Millisecond}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		want := p.backoff << attempt
+		if want > maxRetryDelay {
+			want = maxRetryDelay
+		}
+		got := p.delay(attempt)
+		if got < want/2 || got > want {
+			t.Fatalf("delay(%d) = %v, want within [%v, %v]", attempt, got, want/2, want)
+		}
+	}
+}
+
+type attemptTagRecorder struct {
+	recorder.Recorder
+
+	mu       sync.Mutex
+	attempts []string
+}
+
+func (r *attemptTagRecorder) RecordRequest(context.Context, *string, string, []byte, map[string]string) error {
+	return nil
+}
+
+func (r *attemptTagRecorder) RecordResponse(_ context.Context, _ *string, _ string, _ []byte, tags map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, tags["attempt"])
+	return nil
+}
+
+func (r *attemptTagRecorder) RecordError(context.Context, *string, string, error, map[string]string) error {
+	return nil
+}
+
+func flakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var attempts atomic.Int32
+	srv := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				if attempts.Add(1) <= failures {
+					w.WriteHeader(status)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"result":"ACCEPTED"}`))
+			},
+		),
+	)
+	t.Cleanup(srv.Close)
+
+	return srv, &attempts
+}
+
+func TestApi_RetryableStatusCodes(t *testing.T) {
+	srv, attempts := flakyServer(t, 1, http.StatusTooManyRequests)
+
+	c := NewClient(&Options{MaxRetries: 2, RetryBackoff: time.Millisecond})
+	if _, err := c.Api(testStatusRequest(), srv.URL); err == nil {
+		t.Fatal("429 is not retried by default")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Fatalf("expected 1 attempt, got %d", got)
+	}
+
+	srv, attempts = flakyServer(t, 1, http.StatusTooManyRequests)
+	c = NewClient(
+		&Options{
+			MaxRetries:           2,
+			RetryBackoff:         time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusTooManyRequests},
+		},
+	)
+	if _, err := c.Api(testStatusRequest(), srv.URL); err != nil {
+		t.Fatalf("Api() error: %v", err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestApi_RetryUnsafeActionsOptIn(t *testing.T) {
+	srv, attempts := flakyServer(t, 2, http.StatusBadGateway)
+
+	rec := &attemptTagRecorder{}
+	c := NewClient(&Options{MaxRetries: 2, RetryBackoff: time.Millisecond, RetryUnsafeActions: true})
+	c.SetRecorder(rec)
+
+	if _, err := c.Api(testTokenSaleRequest(), srv.URL); err != nil {
+		t.Fatalf("Api() error: %v", err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.attempts) != 1 || rec.attempts[0] != "3" {
+		t.Fatalf("expected response recorded with attempt=3, got %v", rec.attempts)
+	}
+}
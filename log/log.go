@@ -55,20 +55,73 @@ var (
 	}
 )
 
+// Sink receives log entries, e.g. to forward them to slog or zap. msg is a
+// fmt format string for args. Entries below the level set with SetLevel never
+// reach the sink. Implementations must be safe for concurrent use.
+type Sink interface {
+	Log(level Level, prefix, msg string, args ...any)
+}
+
+// stderrSink is the default sink, writing one line per entry to stderr.
+type stderrSink struct{}
+
+func (stderrSink) Log(level Level, prefix, msg string, args ...any) {
+	line := fmt.Sprintf("%s %s %s", time.Now().Format(time.RFC3339), labels[level], prefix)
+	line += fmt.Sprintf(msg, args...)
+	fmt.Fprintln(os.Stderr, line)
+}
+
+var globalSink Sink = stderrSink{}
+
+// SetSink routes every logger without its own sink to sink. A nil sink
+// restores the default stderr output.
+func SetSink(sink Sink) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	if sink == nil {
+		sink = stderrSink{}
+	}
+	globalSink = sink
+}
+
+func getSink() Sink {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	return globalSink
+}
+
 type Logger struct {
 	prefix string
+	sink   Sink
 }
 
 func NewLogger(prefix string) *Logger {
 	return &Logger{prefix: prefix}
 }
 
+// WithSink returns a copy of the logger that writes to sink instead of the
+// global one. A nil sink keeps the global sink.
+func (l *Logger) WithSink(sink Sink) *Logger {
+	if l == nil {
+		return &Logger{sink: sink}
+	}
+
+	return &Logger{prefix: l.prefix, sink: sink}
+}
+
 func SetLevel(level Level) {
 	logMutex.Lock()
 	defer logMutex.Unlock()
 	globalLogLevel = level
 }
 
+// GetLevel returns the level set with SetLevel.
+func GetLevel() Level {
+	return getLogLevel()
+}
+
 func (l *Logger) log(level Level, format string, a ...interface{}) {
 	if level > getLogLevel() {
 		return
@@ -79,9 +132,11 @@ func (l *Logger) log(level Level, format string, a ...interface{}) {
 		prefix = l.prefix
 	}
 
-	msg := fmt.Sprintf("%s %s %s", time.Now().Format(time.RFC3339), labels[level], prefix)
-	msg += fmt.Sprintf(format, a...)
-	fmt.Fprintln(os.Stderr, msg)
+	sink := getSink()
+	if l != nil && l.sink != nil {
+		sink = l.sink
+	}
+	sink.Log(level, prefix, format, a...)
 }
 
 func getLogLevel() Level {
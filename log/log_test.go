@@ -26,6 +26,7 @@ package log
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -63,6 +64,59 @@ func TestAll_UsesDebugThreshold(t *testing.T) {
 	}
 }
 
+type capturingSink struct {
+	entries []string
+}
+
+func (s *capturingSink) Log(level Level, prefix, msg string, args ...any) {
+	s.entries = append(s.entries, fmt.Sprintf("%s|%s%s", labels[level], prefix, fmt.Sprintf(msg, args...)))
+}
+
+func TestSink_Routing(t *testing.T) {
+	previousLevel := getLogLevel()
+	t.Cleanup(
+		func() {
+			SetLevel(previousLevel)
+			SetSink(nil)
+		},
+	)
+	SetLevel(LevelInfo)
+
+	global := &capturingSink{}
+	SetSink(global)
+
+	own := &capturingSink{}
+	logger := NewLogger("test: ")
+	scoped := logger.WithSink(own)
+
+	stderr := captureStderr(
+		t, func() {
+			logger.Info("to global %d", 1)
+			logger.Debug("filtered by level")
+			scoped.Warning("to own %s", "sink")
+		},
+	)
+	if stderr != "" {
+		t.Fatalf("custom sinks must replace stderr output, got %q", stderr)
+	}
+	if len(global.entries) != 1 || global.entries[0] != "[info ]|test: to global 1" {
+		t.Fatalf("global sink entries = %q", global.entries)
+	}
+	if len(own.entries) != 1 || own.entries[0] != "[warn ]|test: to own sink" {
+		t.Fatalf("scoped sink entries = %q", own.entries)
+	}
+
+	SetSink(nil)
+	stderr = captureStderr(
+		t, func() {
+			logger.Info("back to stderr")
+		},
+	)
+	if !strings.Contains(stderr, "[info ] test: back to stderr") {
+		t.Fatalf("SetSink(nil) must restore stderr output, got %q", stderr)
+	}
+}
+
 func captureStderr(t *testing.T, fn func()) string {
 	t.Helper()
 
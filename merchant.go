@@ -25,6 +25,8 @@
 package go_platon
 
 import (
+	"net"
+	"net/http"
 	"strconv"
 	"strings"
 )
@@ -51,6 +53,34 @@ type Merchant struct {
 	TermsURL     *string
 }
 
+// SetClientIPFromHTTP sets ClientIP from an incoming payer request: the first
+// valid address in X-Forwarded-For, then X-Real-IP, then RemoteAddr. The
+// headers are trusted as is, so only use it behind a proxy that sets them.
+// It reports whether an address was found.
+func (m *Merchant) SetClientIPFromHTTP(r *http.Request) bool {
+	if m == nil || r == nil {
+		return false
+	}
+
+	candidates := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	candidates = append(candidates, r.Header.Get("X-Real-IP"))
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		candidates = append(candidates, host)
+	} else {
+		candidates = append(candidates, r.RemoteAddr)
+	}
+
+	for _, candidate := range candidates {
+		if ip := net.ParseIP(strings.TrimSpace(candidate)); ip != nil {
+			value := ip.String()
+			m.ClientIP = &value
+			return true
+		}
+	}
+
+	return false
+}
+
 func (m *Merchant) GetMerchantID() *int64 {
 	if m == nil {
 		return nil
@@ -24,7 +24,11 @@
 
 package go_platon
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
 
 func TestMerchant_NilReceiverMethods(t *testing.T) {
 	var merchant *Merchant
@@ -36,3 +40,37 @@ func TestMerchant_NilReceiverMethods(t *testing.T) {
 		t.Fatalf("GetMobileLogin() mismatch: want nil, got %q", *got)
 	}
 }
+
+func TestMerchant_SetClientIPFromHTTP(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		remote  string
+		want    string
+	}{
+		{name: "forwarded for", headers: map[string]string{"X-Forwarded-For": "203.0.113.7, 10.0.0.1"}, remote: "10.0.0.2:1234", want: "203.0.113.7"},
+		{name: "real ip", headers: map[string]string{"X-Forwarded-For": "garbage", "X-Real-IP": "198.51.100.4"}, remote: "10.0.0.2:1234", want: "198.51.100.4"},
+		{name: "remote addr", remote: "[2001:db8::1]:443", want: "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/checkout", nil)
+		r.RemoteAddr = tt.remote
+		for k, v := range tt.headers {
+			r.Header.Set(k, v)
+		}
+
+		m := &Merchant{}
+		if !m.SetClientIPFromHTTP(r) {
+			t.Fatalf("%s: SetClientIPFromHTTP() = false", tt.name)
+		}
+		if m.ClientIP == nil || *m.ClientIP != tt.want {
+			t.Fatalf("%s: ClientIP = %v, want %q", tt.name, m.ClientIP, tt.want)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/checkout", nil)
+	r.RemoteAddr = "unknown"
+	if (&Merchant{}).SetClientIPFromHTTP(r) {
+		t.Fatal("SetClientIPFromHTTP() = true without any address")
+	}
+}
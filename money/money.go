@@ -0,0 +1,71 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+// Package money pairs a platon.Money amount with its currency. Use
+// platon.Money and platon.ParseMoney for arithmetic and parsing.
+package money
+
+import (
+	"github.com/stremovskyy/go-platon/currency"
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+// Amount is a monetary amount stored in minor units (e.g. kopecks).
+type Amount struct {
+	// Minor is the amount in the smallest unit of the currency.
+	Minor int64
+	// Currency is the currency of the amount.
+	Currency currency.Code
+}
+
+// New returns an Amount for the given minor units and currency.
+func New(minor int64, code currency.Code) Amount {
+	return Amount{Minor: minor, Currency: code}
+}
+
+// IsZero reports whether the amount is zero.
+func (a Amount) IsZero() bool {
+	return a.Minor == 0
+}
+
+// Money returns the amount as platon.Money, dropping the currency.
+func (a Amount) Money() platon.Money {
+	return platon.Money(a.Minor)
+}
+
+// Major formats the amount in major units with the exponent of Currency, e.g.
+// "123.45" for UAH and "12345" for JPY (see platon.Money.Format). Without a
+// currency it uses two decimals.
+func (a Amount) Major() string {
+	return a.Money().Format(a.Currency)
+}
+
+// String formats the amount with its currency (e.g. "123.45 UAH", "1000 JPY").
+func (a Amount) String() string {
+	if a.Currency == "" {
+		return a.Major()
+	}
+
+	return a.Major() + " " + a.Currency.String()
+}
@@ -0,0 +1,53 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package money
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stremovskyy/go-platon/currency"
+)
+
+func TestAmount_String(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{amount: New(0, currency.UAH), want: "0.00 UAH"},
+		{amount: New(5, currency.UAH), want: "0.05 UAH"},
+		{amount: New(12345, currency.USD), want: "123.45 USD"},
+		{amount: New(-101, currency.EUR), want: "-1.01 EUR"},
+		{amount: New(9007199254740993, ""), want: "90071992547409.93"},
+		{amount: New(1000, currency.JPY), want: "1000 JPY"},
+		{amount: New(math.MinInt64, currency.UAH), want: "-92233720368547758.08 UAH"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.String(); got != tt.want {
+			t.Fatalf("String() mismatch: want %q, got %q", tt.want, got)
+		}
+	}
+}
@@ -24,9 +24,27 @@
 
 package go_platon
 
-import "github.com/stremovskyy/go-platon/currency"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stremovskyy/go-platon/currency"
+	"github.com/stremovskyy/go-platon/platon"
+)
 
 // PaymentData represents the data related to a payment transaction.
+//
+// Three identifiers live here and are easy to mix up:
+//   - PaymentID is the merchant order id (Platon `order_id`). It is chosen by
+//     the merchant and is required to create a payment (SALE, APPLEPAY,
+//     GOOGLEPAY, CREDIT2CARD) and for status lookup by order.
+//   - PlatonTransID is the gateway transaction id (Platon `trans_id`). It is
+//     returned by Platon and is required for CAPTURE, CREDITVOID and
+//     GET_TRANS_STATUS.
+//   - PlatonPaymentID is a deprecated numeric form of PlatonTransID.
+//
+// Use IDs to see which of them is set.
 type PaymentData struct {
 	// PlatonPaymentID is the unique identifier for the Platon payment.
 	//
@@ -34,10 +52,13 @@ type PaymentData struct {
 	PlatonPaymentID *int64
 	// PlatonTransID is the Platon transaction identifier (trans_id) used for GET_TRANS_STATUS/CAPTURE/CREDITVOID.
 	PlatonTransID *string
-	// PaymentID is the unique identifier for the payment.
+	// PaymentID is the merchant order identifier (Platon order_id).
 	PaymentID *string
 	// Amount is the amount of the payment in the smallest unit of the currency.
 	Amount int
+	// OriginalAmount is the held amount in minor units. It is optional and only
+	// used by Capture to reject capturing more than was held.
+	OriginalAmount *int
 	// Currency is the currency code of the payment.
 	Currency currency.Code
 	// Description is a brief description of the payment.
@@ -47,15 +68,39 @@ type PaymentData struct {
 	// SplitRules defines optional split payouts to sub-merchants.
 	// Amount is specified in minor units.
 	SplitRules []SplitRule
+	// RecurringFirstTransID is the trans_id of the initial payment of a
+	// recurring series. It is required by Recurring.
+	RecurringFirstTransID *string
+	// PayerIP is the payer IP of this payment (payer_ip). It overrides
+	// Merchant.ClientIP, which suits a Merchant shared between requests.
+	PayerIP *string
+	// TermsURL is the 3DS terms URL of this payment (term_url_3ds). It
+	// overrides Merchant.TermsURL.
+	TermsURL *string
 	// SubmerchantID is used by GET_SUBMERCHANT request.
 	SubmerchantID *string
+	// Reference is a merchant reference echoed back in callbacks
+	// (WebhookForm.Reference). It is sent in ext10, so Metadata["ext10"] is
+	// ignored when Reference is set.
+	Reference *string
+	// IdempotencyKey identifies retries of the same operation. It is sent as a
+	// stable X-Request-ID and, with WithIdempotency, guards against sending the
+	// same SALE/CAPTURE/CREDITVOID/CREDIT2CARD twice.
+	IdempotencyKey string
 	// RelatedIds is a list of related payment IDs.
 	RelatedIds []int64
 	// Metadata is a map of additional data.
 	// Supported integration keys:
-	// - ext1..ext10: passed to Platon request fields with the same names.
-	// - immediately: for Refund, "Y"/"true"/"1" enables fast refund mode.
+	// - ext1..ext10: passed to Platon request fields with the same names
+	//   (ext10 is reserved for Reference when it is set).
+	// - immediately: for Refund, "Y"/"true"/"1" enables fast refund mode
+	//   (prefer the Immediately run option).
 	// - platon_flow: for Status, value "a2c" switches to A2C status endpoint.
+	// - recurring_first_trans_id: for Recurring, fallback for RecurringFirstTransID.
+	// - req_token, recurring_init: for card PAN payments, "Y"/"true"/"1" asks Platon
+	//   to return a card token / to mark the payment as the first of a recurring series.
+	// - payer_first_name, payer_last_name, payer_address, payer_country, payer_state,
+	//   payer_city, payer_zip: payer identity for Credit. Required when paying out by PAN.
 	Metadata map[string]string
 }
 
@@ -64,3 +109,57 @@ type SplitRule struct {
 	SubmerchantIdentification string
 	Amount                    int
 }
+
+// PaymentIDs is a summary of the identifiers carried by PaymentData.
+type PaymentIDs struct {
+	// MerchantOrderID is the merchant order id (Platon `order_id`).
+	MerchantOrderID string
+	// PlatonTransID is the gateway transaction id (Platon `trans_id`).
+	PlatonTransID string
+}
+
+// IDs returns the merchant order id and Platon trans_id set on the payment.
+// The deprecated PlatonPaymentID is used as trans_id when PlatonTransID is empty.
+func (d *PaymentData) IDs() PaymentIDs {
+	if d == nil {
+		return PaymentIDs{}
+	}
+
+	ids := PaymentIDs{}
+	if d.PaymentID != nil {
+		ids.MerchantOrderID = strings.TrimSpace(*d.PaymentID)
+	}
+	if d.PlatonTransID != nil && strings.TrimSpace(*d.PlatonTransID) != "" {
+		ids.PlatonTransID = strings.TrimSpace(*d.PlatonTransID)
+	} else if d.PlatonPaymentID != nil {
+		ids.PlatonTransID = strconv.FormatInt(*d.PlatonPaymentID, 10)
+	}
+
+	return ids
+}
+
+// RequireIDs checks that the identifier needed by the given action is set.
+//
+// CAPTURE, CREDITVOID and GET_TRANS_STATUS need PlatonTransID (trans_id).
+// SALE, APPLEPAY, GOOGLEPAY, CREDIT2CARD and GET_TRANS_STATUS_BY_ORDER need
+// PaymentID (order_id). Other actions do not need either.
+func (d *PaymentData) RequireIDs(action platon.ActionCode) error {
+	ids := d.IDs()
+
+	switch action {
+	case platon.ActionCodeCAPTURE, platon.ActionCodeCREDITVOID, platon.ActionCodeGetTransStatus, platon.ActionCodeGetTransDetails:
+		if ids.PlatonTransID == "" {
+			return fmt.Errorf("trans_id is required for %s (set PaymentData.PlatonTransID)", action)
+		}
+	case platon.ActionCodeSALE,
+		platon.ActionCodeAPPLEPAY,
+		platon.ActionCodeGOOGLEPAY,
+		platon.ActionCodeCREDIT2CARD,
+		platon.ActionCodeGetTransStatusByOrder:
+		if ids.MerchantOrderID == "" {
+			return fmt.Errorf("order_id is required for %s (set PaymentData.PaymentID)", action)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+func TestPaymentData_IDs(t *testing.T) {
+	legacyID := int64(42)
+	data := &PaymentData{
+		PaymentID:       ref(" order-1 "),
+		PlatonPaymentID: &legacyID,
+	}
+
+	ids := data.IDs()
+	if ids.MerchantOrderID != "order-1" {
+		t.Fatalf("MerchantOrderID mismatch: want %q, got %q", "order-1", ids.MerchantOrderID)
+	}
+	if ids.PlatonTransID != "42" {
+		t.Fatalf("PlatonTransID mismatch: want %q, got %q", "42", ids.PlatonTransID)
+	}
+
+	data.PlatonTransID = ref("trans-1")
+	if got := data.IDs().PlatonTransID; got != "trans-1" {
+		t.Fatalf("PlatonTransID mismatch: want %q, got %q", "trans-1", got)
+	}
+
+	var nilData *PaymentData
+	if got := nilData.IDs(); got != (PaymentIDs{}) {
+		t.Fatalf("expected empty IDs for nil PaymentData, got %#v", got)
+	}
+}
+
+func TestPaymentData_RequireIDs_ByOperation(t *testing.T) {
+	orderOnly := &PaymentData{PaymentID: ref("order-1")}
+	transOnly := &PaymentData{PlatonTransID: ref("trans-1")}
+
+	tests := []struct {
+		action    platon.ActionCode
+		data      *PaymentData
+		wantError string
+	}{
+		{action: platon.ActionCodeSALE, data: orderOnly},
+		{action: platon.ActionCodeSALE, data: transOnly, wantError: "order_id is required"},
+		{action: platon.ActionCodeAPPLEPAY, data: transOnly, wantError: "order_id is required"},
+		{action: platon.ActionCodeGOOGLEPAY, data: transOnly, wantError: "order_id is required"},
+		{action: platon.ActionCodeCREDIT2CARD, data: transOnly, wantError: "order_id is required"},
+		{action: platon.ActionCodeGetTransStatusByOrder, data: orderOnly},
+		{action: platon.ActionCodeCAPTURE, data: transOnly},
+		{action: platon.ActionCodeCAPTURE, data: orderOnly, wantError: "trans_id is required"},
+		{action: platon.ActionCodeCREDITVOID, data: orderOnly, wantError: "trans_id is required"},
+		{action: platon.ActionCodeGetTransStatus, data: orderOnly, wantError: "trans_id is required"},
+		{action: platon.ActionCodeGetSubmerchant, data: nil},
+	}
+
+	for _, tt := range tests {
+		err := tt.data.RequireIDs(tt.action)
+		if tt.wantError == "" {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.action, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+			t.Fatalf("%s: expected error containing %q, got %v", tt.action, tt.wantError, err)
+		}
+	}
+}
+
+func TestCapture_RequiresTransIDNotOrderID(t *testing.T) {
+	c := NewDefaultClient()
+	req := &Request{
+		Merchant: &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "SECRET"},
+		PaymentData: &PaymentData{
+			PaymentID: ref("order-1"),
+			Amount:    100,
+		},
+	}
+
+	_, err := c.Capture(req, DryRun())
+	if err == nil || !strings.Contains(err.Error(), "capture: trans_id is required") {
+		t.Fatalf("expected trans_id error, got %v", err)
+	}
+}
@@ -45,3 +45,34 @@ type Card struct {
 	ExpirationYear  *string
 	Cvv2            *string
 }
+
+// PaymentMethodKind identifies which payment method a Request carries.
+type PaymentMethodKind int
+
+const (
+	// PaymentMethodUnknown means no usable payment method is set.
+	PaymentMethodUnknown PaymentMethodKind = iota
+	// PaymentMethodApplePay is an Apple Pay container.
+	PaymentMethodApplePay
+	// PaymentMethodGooglePay is a Google Pay token.
+	PaymentMethodGooglePay
+	// PaymentMethodCardToken is a CARD_TOKEN from an earlier payment.
+	PaymentMethodCardToken
+	// PaymentMethodCardPAN is a full card number.
+	PaymentMethodCardPAN
+)
+
+func (k PaymentMethodKind) String() string {
+	switch k {
+	case PaymentMethodApplePay:
+		return "apple_pay"
+	case PaymentMethodGooglePay:
+		return "google_pay"
+	case PaymentMethodCardToken:
+		return "card_token"
+	case PaymentMethodCardPAN:
+		return "card_pan"
+	default:
+		return "unknown"
+	}
+}
@@ -41,4 +41,21 @@ type PersonalData struct {
 	// Email is the email address of the user.
 	Email *string
 	Phone *string
+	// BillingAddress is the payer's billing address. CREDIT2CARD prefers it
+	// over the payer_* metadata keys and the built-in placeholders.
+	BillingAddress *BillingAddress
+}
+
+// BillingAddress is the payer's billing address. Nil or empty fields are unset.
+type BillingAddress struct {
+	// Address is the street address (payer_address).
+	Address *string
+	// Country is the ISO 3166-1 alpha-2 country code (payer_country).
+	Country *string
+	// State is the two-letter state or region code (payer_state).
+	State *string
+	// City is the city name (payer_city).
+	City *string
+	// Zip is the postal code (payer_zip).
+	Zip *string
 }
@@ -0,0 +1,141 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// APIErrorKind classifies an APIError.
+type APIErrorKind string
+
+const (
+	// APIErrorKindDeclined means the gateway declined the operation (decline_reason / DECLINED).
+	APIErrorKindDeclined APIErrorKind = "declined"
+	// APIErrorKindError means the gateway rejected the request (error_message / ERROR).
+	APIErrorKindError APIErrorKind = "error"
+	// APIErrorKindUnknown is used for errors that cannot be classified.
+	APIErrorKindUnknown APIErrorKind = "unknown"
+)
+
+var apiErrorCodeRe = regexp.MustCompile(`^\s*(\d+)\s*[:\-]\s*(.*)$`)
+
+// APIError is an error reported by Platon in a response body.
+//
+// Messages such as "102: Token is not active" are split into Code (102) and
+// Reason ("Token is not active"). Without a numeric prefix Code is 0 and
+// Reason holds the whole message.
+type APIError struct {
+	Code   int
+	Reason string
+	Kind   APIErrorKind
+	// Message is the raw error_message or decline_reason value.
+	Message string
+}
+
+// NewAPIError builds an APIError from a raw gateway message.
+func NewAPIError(kind APIErrorKind, message string) *APIError {
+	message = strings.TrimSpace(message)
+	apiErr := &APIError{
+		Kind:    kind,
+		Reason:  message,
+		Message: message,
+	}
+
+	if match := apiErrorCodeRe.FindStringSubmatch(message); match != nil {
+		if code, err := strconv.Atoi(match[1]); err == nil {
+			apiErr.Code = code
+			apiErr.Reason = strings.TrimSpace(match[2])
+		}
+	}
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	switch e.Kind {
+	case APIErrorKindDeclined:
+		if e.Message == "" {
+			return "unknown platon api decline"
+		}
+		return "platon api declined: " + e.Message
+	case APIErrorKindError:
+		if e.Message == "" {
+			return "unknown platon api error"
+		}
+		return "platon api error: " + e.Message
+	default:
+		if e.Message == "" {
+			return "unknown platon api error"
+		}
+		return "platon api error: " + e.Message
+	}
+}
+
+// Category is ErrorCategoryDecline for declines and ErrorCategoryGateway
+// otherwise.
+func (e *APIError) Category() ErrorCategory {
+	if e != nil && e.Kind == APIErrorKindDeclined {
+		return ErrorCategoryDecline
+	}
+
+	return ErrorCategoryGateway
+}
+
+// Is reports whether target is the sentinel of the error category.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrDeclined:
+		return e.Category() == ErrorCategoryDecline
+	case ErrGateway:
+		return e.Category() == ErrorCategoryGateway
+	default:
+		return false
+	}
+}
+
+// As lets errors.As extract a *DeclineError from a declined APIError.
+func (e *APIError) As(target any) bool {
+	declineErr, ok := target.(**DeclineError)
+	if !ok || e == nil || e.Kind != APIErrorKindDeclined {
+		return false
+	}
+
+	*declineErr = &DeclineError{Code: e.Code, Reason: e.Reason, Message: e.Message}
+	return true
+}
+
+// IsDeclined reports whether err wraps an APIError of kind declined.
+func IsDeclined(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Kind == APIErrorKindDeclined
+}
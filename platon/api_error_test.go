@@ -0,0 +1,114 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestResponse_GetError_ReturnsAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		payload    string
+		wantKind   APIErrorKind
+		wantCode   int
+		wantReason string
+		wantError  string
+	}{
+		{
+			name:       "declined with code",
+			payload:    `{"result":"DECLINED","decline_reason":"102: Token is not active"}`,
+			wantKind:   APIErrorKindDeclined,
+			wantCode:   102,
+			wantReason: "Token is not active",
+			wantError:  "platon api declined: 102: Token is not active",
+		},
+		{
+			name:       "declined without code",
+			payload:    `{"result":"DECLINED","decline_reason":"Insufficient funds"}`,
+			wantKind:   APIErrorKindDeclined,
+			wantReason: "Insufficient funds",
+			wantError:  "platon api declined: Insufficient funds",
+		},
+		{
+			name:       "error with code",
+			payload:    `{"result":"ERROR","error_message":"204 - Duplicate request"}`,
+			wantKind:   APIErrorKindError,
+			wantCode:   204,
+			wantReason: "Duplicate request",
+			wantError:  "platon api error: 204 - Duplicate request",
+		},
+		{
+			name:      "error without message",
+			payload:   `{"result":"ERROR"}`,
+			wantKind:  APIErrorKindError,
+			wantError: "unknown platon api error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				resp, err := UnmarshalJSONResponse([]byte(tt.payload))
+				if err != nil {
+					t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+				}
+
+				wrapped := fmt.Errorf("payment API call: %w", resp.GetError())
+
+				var apiErr *APIError
+				if !errors.As(wrapped, &apiErr) {
+					t.Fatalf("expected *APIError, got %T", resp.GetError())
+				}
+				if apiErr.Kind != tt.wantKind {
+					t.Fatalf("kind mismatch: want %q, got %q", tt.wantKind, apiErr.Kind)
+				}
+				if apiErr.Code != tt.wantCode {
+					t.Fatalf("code mismatch: want %d, got %d", tt.wantCode, apiErr.Code)
+				}
+				if apiErr.Reason != tt.wantReason {
+					t.Fatalf("reason mismatch: want %q, got %q", tt.wantReason, apiErr.Reason)
+				}
+				if apiErr.Error() != tt.wantError {
+					t.Fatalf("message mismatch: want %q, got %q", tt.wantError, apiErr.Error())
+				}
+				if IsDeclined(wrapped) != (tt.wantKind == APIErrorKindDeclined) {
+					t.Fatalf("IsDeclined() mismatch for kind %q", tt.wantKind)
+				}
+			},
+		)
+	}
+}
+
+func TestIsDeclined_NonAPIError(t *testing.T) {
+	if IsDeclined(nil) {
+		t.Fatalf("IsDeclined(nil) should be false")
+	}
+	if IsDeclined(errors.New("platon api declined: fake")) {
+		t.Fatalf("IsDeclined() should be false for plain errors")
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidApplePayContainer is wrapped by every ParseApplePayContainer error.
+var ErrInvalidApplePayContainer = errors.New("invalid Apple Pay container")
+
+// ApplePayContainer is the PKPaymentToken paymentData object that Platon
+// expects in payment_token.
+type ApplePayContainer struct {
+	Version   string         `json:"version"`
+	Data      string         `json:"data"`
+	Signature string         `json:"signature"`
+	Header    ApplePayHeader `json:"header"`
+	raw       json.RawMessage
+}
+
+// ApplePayHeader is the header of an Apple Pay container. EC_v1 containers
+// carry EphemeralPublicKey, RSA_v1 containers carry WrappedKey.
+type ApplePayHeader struct {
+	EphemeralPublicKey string `json:"ephemeralPublicKey,omitempty"`
+	WrappedKey         string `json:"wrappedKey,omitempty"`
+	PublicKeyHash      string `json:"publicKeyHash"`
+	TransactionID      string `json:"transactionId"`
+}
+
+// ParseApplePayContainer decodes a base64 Apple Pay container and checks that
+// every field Platon needs is present. The container may be bare or wrapped
+// in the shapes Apple Pay JS produces: {"token":{...}}, a PKPaymentToken
+// {"paymentData":{...},"paymentMethod":{...}}, or that token nested under
+// "token" or "payment". Each missing field is reported in the returned error.
+func ParseApplePayContainer(b64 string) (*ApplePayContainer, error) {
+	decoded, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot decode base64: %w", ErrInvalidApplePayContainer, err)
+	}
+
+	raw, err := unwrapApplePayContainer(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	var container ApplePayContainer
+	if err := json.Unmarshal(raw, &container); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidApplePayContainer, err)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidApplePayContainer, err)
+	}
+	container.raw = compact.Bytes()
+
+	if err := container.validate(); err != nil {
+		return nil, err
+	}
+
+	return &container, nil
+}
+
+// applePayWrapperKeys are the keys under which Apple Pay JS payloads nest the
+// container, in lookup order.
+var applePayWrapperKeys = []string{"paymentData", "token", "payment"}
+
+// unwrapApplePayContainer descends through the wrapper keys until it reaches
+// an object without any of them, which is taken as the container. A
+// PKPaymentToken (recognized by paymentMethod or transactionIdentifier)
+// without paymentData is reported by the path of the missing key.
+func unwrapApplePayContainer(decoded []byte) (json.RawMessage, error) {
+	raw := json.RawMessage(decoded)
+	path := ""
+
+	for depth := 0; depth <= len(applePayWrapperKeys); depth++ {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			if path == "" {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidApplePayContainer, err)
+			}
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidApplePayContainer, strings.TrimSuffix(path, "."), err)
+		}
+
+		key := ""
+		for _, candidate := range applePayWrapperKeys {
+			if _, ok := fields[candidate]; ok {
+				key = candidate
+				break
+			}
+		}
+		if key == "" {
+			_, hasMethod := fields["paymentMethod"]
+			_, hasTransaction := fields["transactionIdentifier"]
+			if hasMethod || hasTransaction {
+				return nil, fmt.Errorf("%w: %spaymentData is missing", ErrInvalidApplePayContainer, path)
+			}
+			return raw, nil
+		}
+
+		raw = fields[key]
+		path += key + "."
+	}
+
+	return nil, fmt.Errorf("%w: container is nested too deep (%s)", ErrInvalidApplePayContainer, strings.TrimSuffix(path, "."))
+}
+
+func (c *ApplePayContainer) validate() error {
+	missing := func(field string) error {
+		return fmt.Errorf("%w: %s is missing", ErrInvalidApplePayContainer, field)
+	}
+
+	var errs []error
+	if c.Version == "" {
+		errs = append(errs, missing("version"))
+	}
+	if c.Data == "" {
+		errs = append(errs, missing("data"))
+	}
+	if c.Signature == "" {
+		errs = append(errs, missing("signature"))
+	}
+	if c.Version == "RSA_v1" {
+		if c.Header.WrappedKey == "" {
+			errs = append(errs, missing("header.wrappedKey"))
+		}
+	} else if c.Header.EphemeralPublicKey == "" {
+		errs = append(errs, missing("header.ephemeralPublicKey"))
+	}
+	if c.Header.PublicKeyHash == "" {
+		errs = append(errs, missing("header.publicKeyHash"))
+	}
+	if c.Header.TransactionID == "" {
+		errs = append(errs, missing("header.transactionId"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// TransactionID returns the Apple Pay transaction ID from the header, for
+// reconciliation logs.
+func (c *ApplePayContainer) TransactionID() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.Header.TransactionID
+}
+
+// Base64 returns the container JSON, unwrapped and with unknown fields kept,
+// encoded for payment_token.
+func (c *ApplePayContainer) Base64() string {
+	if c == nil {
+		return ""
+	}
+	if len(c.raw) > 0 {
+		return base64.StdEncoding.EncodeToString(c.raw)
+	}
+
+	encoded, _ := json.Marshal(c)
+	return base64.StdEncoding.EncodeToString(encoded)
+}
@@ -0,0 +1,135 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testApplePayContainer = `{"version":"EC_v1","data":"ZGF0YQ==","signature":"c2ln",` +
+	`"header":{"ephemeralPublicKey":"a2V5","publicKeyHash":"aGFzaA==","transactionId":"abc123"}}`
+
+func encodeApplePay(raw string) string {
+	return base64.StdEncoding.EncodeToString([]byte(raw))
+}
+
+func TestParseApplePayContainer_Valid(t *testing.T) {
+	for name, raw := range map[string]string{
+		"bare":    testApplePayContainer,
+		"wrapped": `{"token": ` + testApplePayContainer + `}`,
+		"payment token": `{"paymentData":` + testApplePayContainer +
+			`,"paymentMethod":{"network":"Visa","type":"debit"},"transactionIdentifier":"abc123"}`,
+		"nested payment token": `{"token":{"paymentData":` + testApplePayContainer +
+			`,"paymentMethod":{"displayName":"Visa 1234"}}}`,
+		"payment event": `{"payment":{"token":{"paymentData":` + testApplePayContainer + `}}}`,
+	} {
+		t.Run(
+			name, func(t *testing.T) {
+				container, err := ParseApplePayContainer(encodeApplePay(raw))
+				if err != nil {
+					t.Fatalf("ParseApplePayContainer() error: %v", err)
+				}
+				if container.Version != "EC_v1" {
+					t.Fatalf("Version = %q, want EC_v1", container.Version)
+				}
+				if got := container.TransactionID(); got != "abc123" {
+					t.Fatalf("TransactionID() = %q, want abc123", got)
+				}
+				if got := container.Base64(); got != encodeApplePay(testApplePayContainer) {
+					t.Fatalf("Base64() = %q, want the unwrapped container", got)
+				}
+			},
+		)
+	}
+}
+
+func TestParseApplePayContainer_RSAUsesWrappedKey(t *testing.T) {
+	raw := `{"version":"RSA_v1","data":"ZA==","signature":"cw==",` +
+		`"header":{"wrappedKey":"dw==","publicKeyHash":"aA==","transactionId":"t1"}}`
+	if _, err := ParseApplePayContainer(encodeApplePay(raw)); err != nil {
+		t.Fatalf("ParseApplePayContainer() error: %v", err)
+	}
+}
+
+func TestParseApplePayContainer_MissingSignature(t *testing.T) {
+	raw := strings.Replace(testApplePayContainer, `"signature":"c2ln",`, "", 1)
+
+	_, err := ParseApplePayContainer(encodeApplePay(raw))
+	if !errors.Is(err, ErrInvalidApplePayContainer) {
+		t.Fatalf("ParseApplePayContainer() error = %v, want ErrInvalidApplePayContainer", err)
+	}
+	if !strings.Contains(err.Error(), "signature is missing") {
+		t.Fatalf("ParseApplePayContainer() error = %v, want signature reported", err)
+	}
+	if strings.Contains(err.Error(), "version") {
+		t.Fatalf("ParseApplePayContainer() error = %v, reports a present field", err)
+	}
+}
+
+func TestParseApplePayContainer_ReportsEveryMissingField(t *testing.T) {
+	_, err := ParseApplePayContainer(encodeApplePay(`{"token":{}}`))
+	if err == nil {
+		t.Fatal("ParseApplePayContainer() expected error")
+	}
+	for _, field := range []string{
+		"version", "data", "signature", "header.ephemeralPublicKey", "header.publicKeyHash", "header.transactionId",
+	} {
+		if !strings.Contains(err.Error(), field+" is missing") {
+			t.Fatalf("ParseApplePayContainer() error = %v, want %s reported", err, field)
+		}
+	}
+}
+
+func TestParseApplePayContainer_NamesMissingPaymentData(t *testing.T) {
+	for raw, want := range map[string]string{
+		`{"paymentMethod":{"network":"Visa"}}`:                       "paymentData is missing",
+		`{"token":{"paymentMethod":{},"transactionIdentifier":"t"}}`: "token.paymentData is missing",
+		`{"token":{"paymentData":"oops"}}`:                           "token.paymentData:",
+	} {
+		_, err := ParseApplePayContainer(encodeApplePay(raw))
+		if !errors.Is(err, ErrInvalidApplePayContainer) || !strings.Contains(err.Error(), want) {
+			t.Fatalf("ParseApplePayContainer(%s) error = %v, want %q", raw, err, want)
+		}
+	}
+}
+
+func TestParseApplePayContainer_InvalidPayload(t *testing.T) {
+	for name, input := range map[string]string{
+		"not base64": "%%%",
+		"not json":   encodeApplePay("apple"),
+		"json array": encodeApplePay(`[1,2]`),
+	} {
+		t.Run(
+			name, func(t *testing.T) {
+				if _, err := ParseApplePayContainer(input); !errors.Is(err, ErrInvalidApplePayContainer) {
+					t.Fatalf("ParseApplePayContainer() error = %v, want ErrInvalidApplePayContainer", err)
+				}
+			},
+		)
+	}
+}
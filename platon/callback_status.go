@@ -0,0 +1,68 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import "strings"
+
+// CallbackStatus is the status of a Platon callback (WebhookForm.Status).
+type CallbackStatus string
+
+func (s CallbackStatus) String() string {
+	return string(s)
+}
+
+const (
+	CallbackStatusSale       CallbackStatus = "SALE"
+	CallbackStatusCapture    CallbackStatus = "CAPTURE"
+	CallbackStatusCreditVoid CallbackStatus = "CREDITVOID"
+	CallbackStatusRefund     CallbackStatus = "REFUND"
+	CallbackStatusReversal   CallbackStatus = "REVERSAL"
+	CallbackStatusChargeback CallbackStatus = "CHARGEBACK"
+	CallbackStatus3DS        CallbackStatus = "3DS"
+	// CallbackStatusUnknown is returned for empty statuses and statuses this
+	// package does not know yet.
+	CallbackStatusUnknown CallbackStatus = "UNKNOWN"
+)
+
+// ParseCallbackStatus maps a callback status to a CallbackStatus, ignoring
+// case and surrounding whitespace.
+func ParseCallbackStatus(status string) CallbackStatus {
+	switch parsed := CallbackStatus(strings.ToUpper(strings.TrimSpace(status))); parsed {
+	case CallbackStatusSale, CallbackStatusCapture, CallbackStatusCreditVoid, CallbackStatusRefund,
+		CallbackStatusReversal, CallbackStatusChargeback, CallbackStatus3DS:
+		return parsed
+	default:
+		return CallbackStatusUnknown
+	}
+}
+
+// ParsedStatus returns Status as a CallbackStatus.
+func (f *WebhookForm) ParsedStatus() CallbackStatus {
+	if f == nil {
+		return CallbackStatusUnknown
+	}
+
+	return ParseCallbackStatus(f.Status)
+}
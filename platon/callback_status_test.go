@@ -0,0 +1,58 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import "testing"
+
+func TestWebhookForm_ParsedStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   CallbackStatus
+	}{
+		{status: "SALE", want: CallbackStatusSale},
+		{status: "sale", want: CallbackStatusSale},
+		{status: " Capture ", want: CallbackStatusCapture},
+		{status: "CreditVoid", want: CallbackStatusCreditVoid},
+		{status: "refund", want: CallbackStatusRefund},
+		{status: "Reversal", want: CallbackStatusReversal},
+		{status: "chargeBack", want: CallbackStatusChargeback},
+		{status: "3ds", want: CallbackStatus3DS},
+		{status: "", want: CallbackStatusUnknown},
+		{status: "SETTLED", want: CallbackStatusUnknown},
+		{status: "SALE2", want: CallbackStatusUnknown},
+	}
+
+	for _, tt := range tests {
+		form := &WebhookForm{Status: tt.status}
+		if got := form.ParsedStatus(); got != tt.want {
+			t.Fatalf("ParsedStatus(%q) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+
+	var nilForm *WebhookForm
+	if got := nilForm.ParsedStatus(); got != CallbackStatusUnknown {
+		t.Fatalf("nil ParsedStatus() = %q, want %q", got, CallbackStatusUnknown)
+	}
+}
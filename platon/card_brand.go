@@ -0,0 +1,84 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CardBrand is a card network detected from the PAN.
+type CardBrand string
+
+const (
+	CardBrandVisa       CardBrand = "VISA"
+	CardBrandMastercard CardBrand = "MASTERCARD"
+	CardBrandProstir    CardBrand = "PROSTIR"
+	CardBrandUnknown    CardBrand = "UNKNOWN"
+)
+
+// DetectBrand returns the card brand from the IIN (leading digits) of pan:
+// 4 is VISA, 51-55 and 2221-2720 are MASTERCARD and 9804 is PROSTIR. Spaces
+// and hyphens are ignored and only the leading digits are read, so masked
+// numbers such as the callback "card" value ("411111****1111") work too.
+func DetectBrand(pan string) CardBrand {
+	digits := leadingDigits(pan, 6)
+
+	switch {
+	case strings.HasPrefix(digits, "4"):
+		return CardBrandVisa
+	case strings.HasPrefix(digits, "9804"):
+		return CardBrandProstir
+	case len(digits) >= 2 && digits[0] == '5' && digits[1] >= '1' && digits[1] <= '5':
+		return CardBrandMastercard
+	case len(digits) >= 4:
+		if prefix, err := strconv.Atoi(digits[:4]); err == nil && prefix >= 2221 && prefix <= 2720 {
+			return CardBrandMastercard
+		}
+	}
+
+	return CardBrandUnknown
+}
+
+// leadingDigits returns up to limit digits from the start of pan, skipping
+// spaces and hyphens and stopping at any other character.
+func leadingDigits(pan string, limit int) string {
+	var digits strings.Builder
+	for _, c := range strings.TrimSpace(pan) {
+		if digits.Len() == limit {
+			break
+		}
+		switch {
+		case c >= '0' && c <= '9':
+			digits.WriteRune(c)
+		case c == ' ' || c == '-':
+			continue
+		default:
+			return digits.String()
+		}
+	}
+
+	return digits.String()
+}
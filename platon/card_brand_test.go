@@ -0,0 +1,56 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import "testing"
+
+func TestDetectBrand(t *testing.T) {
+	tests := []struct {
+		pan  string
+		want CardBrand
+	}{
+		{"4111111111111111", CardBrandVisa},
+		{"4242 4242 4242 4242", CardBrandVisa},
+		{"411111****1111", CardBrandVisa},
+		{"5555555555554444", CardBrandMastercard},
+		{"5105-1051-0510-5100", CardBrandMastercard},
+		{"2223003122003222", CardBrandMastercard},
+		{"2720990000000007", CardBrandMastercard},
+		{"2721000000000000", CardBrandUnknown},
+		{"2220990000000000", CardBrandUnknown},
+		{"5000000000000000", CardBrandUnknown},
+		{"9804000000000009", CardBrandProstir},
+		{"980400****0009", CardBrandProstir},
+		{"378282246310005", CardBrandUnknown},
+		{"", CardBrandUnknown},
+		{"card", CardBrandUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := DetectBrand(tt.pan); got != tt.want {
+			t.Fatalf("DetectBrand(%q) = %s, want %s", tt.pan, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NormalizeCardExpiry trims month and year, pads a one-digit month to two
+// digits and expands a two-digit year to 20xx, so "1"/"27" becomes "01"/"2027".
+// Other values are returned trimmed and left to the expiry validation.
+func NormalizeCardExpiry(month, year string) (string, string) {
+	month = strings.TrimSpace(month)
+	year = strings.TrimSpace(year)
+
+	if len(month) == 1 && month[0] >= '0' && month[0] <= '9' {
+		month = "0" + month
+	}
+	if len(year) == 2 && cardExpYearRe.MatchString("20"+year) {
+		year = "20" + year
+	}
+
+	return month, year
+}
+
+// validateCardExpiry checks card_exp_month (01..12) and card_exp_year (four
+// digits) and rejects a card whose expiry month lies before the current month
+// of the request clock. A card stays valid through its expiry month. Missing
+// values are left to the per-action required checks.
+func (r *Request) validateCardExpiry(op string) error {
+	if r.CardExpMonth == nil || r.CardExpYear == nil {
+		return nil
+	}
+	if !cardExpMonthRe.MatchString(*r.CardExpMonth) {
+		return NewValidationError(op, "card_exp_month", fmt.Sprintf("must be 01..12 (got %q)", *r.CardExpMonth))
+	}
+	if !cardExpYearRe.MatchString(*r.CardExpYear) {
+		return NewValidationError(op, "card_exp_year", fmt.Sprintf("must be 4 digits (got %q)", *r.CardExpYear))
+	}
+
+	month, _ := strconv.Atoi(*r.CardExpMonth)
+	year, _ := strconv.Atoi(*r.CardExpYear)
+
+	current := r.now()
+	if year < current.Year() || (year == current.Year() && month < int(current.Month())) {
+		return NewValidationError(op, "card_exp_year", fmt.Sprintf("is expired (%s/%s)", *r.CardExpMonth, *r.CardExpYear))
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeCardExpiry(t *testing.T) {
+	tests := []struct{ month, year, wantMonth, wantYear string }{
+		{"01", "2027", "01", "2027"},
+		{"1", "27", "01", "2027"},
+		{" 12 ", " 30 ", "12", "2030"},
+		{"13", "2x", "13", "2x"},
+		{"", "", "", ""},
+	}
+	for _, tt := range tests {
+		month, year := NormalizeCardExpiry(tt.month, tt.year)
+		if month != tt.wantMonth || year != tt.wantYear {
+			t.Fatalf("NormalizeCardExpiry(%q, %q) = %q, %q, want %q, %q", tt.month, tt.year, month, year, tt.wantMonth, tt.wantYear)
+		}
+	}
+}
+
+func TestValidateByHashType_CardExpiry(t *testing.T) {
+	clock := ClockFunc(func() time.Time { return time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC) })
+
+	newRequest := func(hashType HashType, month, year string) *Request {
+		name := "Name"
+
+		req := newSignableCardPayment().
+			WithCardExpiry(month, year).
+			WithClock(clock).
+			SignForAction(hashType)
+
+		switch hashType {
+		case HashTypeVerification:
+			req.WithChannelNoAmountVerification().WithOrderAmount(VerifyNoAmount.String())
+		case HashTypeCredit2Card:
+			country, state, city, zip, address := "US", "NY", "New York", "10001", "5th Ave"
+			req.Action = ActionCodeCREDIT2CARD.String()
+			req.WithAmount("1.00").WithPayerFirstName(&name).WithPayerLastName(&name)
+			req.PayerAddress, req.PayerCountry, req.PayerState, req.PayerCity, req.PayerZip = &address, &country, &state, &city, &zip
+		}
+
+		return req
+	}
+
+	for _, hashType := range []HashType{HashTypeVerification, HashTypeCardPayment, HashTypeCredit2Card} {
+		for _, exp := range [][2]string{{"10", "2026"}, {"10", "26"}, {"1", "27"}, {"12", "2099"}} {
+			if _, err := newRequest(hashType, exp[0], exp[1]).SignAndPrepare(); err != nil {
+				t.Fatalf("%s %s/%s: unexpected error: %v", hashType, exp[0], exp[1], err)
+			}
+		}
+
+		for _, tt := range []struct{ month, year, field, reason string }{
+			{"09", "2026", "card_exp_year", "is expired (09/2026)"},
+			{"12", "2025", "card_exp_year", "is expired"},
+			{"13", "2027", "card_exp_month", "must be 01..12"},
+			{"00", "2027", "card_exp_month", "must be 01..12"},
+			{"ab", "2027", "card_exp_month", "must be 01..12"},
+			{"01", "202", "card_exp_year", "must be 4 digits"},
+			{"01", "20x7", "card_exp_year", "must be 4 digits"},
+		} {
+			_, err := newRequest(hashType, tt.month, tt.year).SignAndPrepare()
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field || !strings.Contains(validationErr.Reason, tt.reason) {
+				t.Fatalf("%s %s/%s: error = %v, want %s %s", hashType, tt.month, tt.year, err, tt.field, tt.reason)
+			}
+		}
+	}
+}
@@ -0,0 +1,211 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// CheckoutStepType tells a frontend what to do next in a redirect-based flow.
+type CheckoutStepType string
+
+const (
+	// CheckoutStepRedirect navigates the browser to URL (GET).
+	CheckoutStepRedirect CheckoutStepType = "redirect"
+	// CheckoutStepFormPost submits Fields to URL using Method.
+	CheckoutStepFormPost CheckoutStepType = "form_post"
+	// CheckoutStepThreeDS submits Fields to the ACS page at URL using Method.
+	CheckoutStepThreeDS CheckoutStepType = "3ds"
+	// CheckoutStepSuccess means the payment is accepted and no browser action is needed.
+	CheckoutStepSuccess CheckoutStepType = "success"
+	// CheckoutStepPending means the result is not final yet; poll the status.
+	CheckoutStepPending CheckoutStepType = "pending"
+	// CheckoutStepDeclined means the payment was declined.
+	CheckoutStepDeclined CheckoutStepType = "declined"
+	// CheckoutStepError means the gateway rejected the request.
+	CheckoutStepError CheckoutStepType = "error"
+)
+
+// CheckoutStep is a JSON-serializable description of the next checkout step
+// shared between the backend and a single-page frontend.
+type CheckoutStep struct {
+	Type    CheckoutStepType  `json:"type"`
+	URL     string            `json:"url,omitempty"`
+	Method  string            `json:"method,omitempty"`
+	Fields  map[string]string `json:"fields,omitempty"`
+	OrderID string            `json:"order_id,omitempty"`
+	TransID string            `json:"trans_id,omitempty"`
+	Message string            `json:"message,omitempty"`
+}
+
+// ThreeDSRedirect contains the ACS endpoint and form fields the payer must be
+// sent to when a payment requires 3DS.
+type ThreeDSRedirect struct {
+	Method   string
+	Endpoint string
+	Fields   map[string]string
+}
+
+// ThreeDSRedirect returns the ACS redirect described by the response, if any.
+func (p *Response) ThreeDSRedirect() (*ThreeDSRedirect, bool) {
+	if p == nil || p.RedirectURL == "" {
+		return nil, false
+	}
+
+	method := strings.ToUpper(p.RedirectMethod)
+	if method == "" {
+		method = clientServerVerificationMethod
+	}
+
+	return &ThreeDSRedirect{
+		Method:   method,
+		Endpoint: p.RedirectURL,
+		Fields:   copyFields(p.RedirectParams),
+	}, true
+}
+
+// Requires3DS reports whether the payer must pass 3DS on the ACS page before
+// the payment completes: the response has a redirect_url and its result is
+// neither DECLINED nor ERROR.
+func (p *Response) Requires3DS() bool {
+	if p == nil || p.RedirectURL == "" {
+		return false
+	}
+	if p.Result == nil {
+		return true
+	}
+
+	switch strings.ToUpper(strings.TrimSpace(p.Result.String())) {
+	case ResultDeclined.String(), ResultError.String():
+		return false
+	}
+
+	return true
+}
+
+// Build3DSForm returns the ACS form the payer's browser must submit; render it
+// with RenderAutoSubmitHTML. It fails when the response does not require 3DS.
+func (p *Response) Build3DSForm() (*ClientServerVerificationForm, error) {
+	if !p.Requires3DS() {
+		return nil, fmt.Errorf("3ds form: response does not require 3DS")
+	}
+
+	redirect, _ := p.ThreeDSRedirect()
+	return &ClientServerVerificationForm{
+		Method:   redirect.Method,
+		Endpoint: redirect.Endpoint,
+		Fields:   redirect.Fields,
+	}, nil
+}
+
+// CheckoutStep maps an API response to the next checkout step.
+func (p *Response) CheckoutStep() (*CheckoutStep, error) {
+	if p == nil {
+		return nil, fmt.Errorf("checkout step: response is nil")
+	}
+
+	var step *CheckoutStep
+	if redirect, ok := p.ThreeDSRedirect(); ok {
+		step = redirect.CheckoutStep()
+	} else if err := p.GetError(); err != nil {
+		step = &CheckoutStep{Type: CheckoutStepError, Message: err.Error()}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Kind == APIErrorKindDeclined {
+			step.Type = CheckoutStepDeclined
+		}
+	} else if p.Result != nil && strings.EqualFold(p.Result.String(), ResultAccepted.String()) {
+		step = &CheckoutStep{Type: CheckoutStepSuccess}
+	} else {
+		step = &CheckoutStep{Type: CheckoutStepPending}
+	}
+
+	if p.OrderId != nil {
+		step.OrderID = *p.OrderId
+	}
+	if p.TransId != nil {
+		step.TransID = *p.TransId
+	}
+
+	return step, nil
+}
+
+// CheckoutStep returns the 3DS step for the ACS redirect.
+func (r *ThreeDSRedirect) CheckoutStep() *CheckoutStep {
+	if r == nil {
+		return nil
+	}
+
+	return &CheckoutStep{
+		Type:   CheckoutStepThreeDS,
+		URL:    r.Endpoint,
+		Method: r.Method,
+		Fields: copyFields(r.Fields),
+	}
+}
+
+// CheckoutStep returns a form-post step for the Client-Server verification form.
+func (f *ClientServerVerificationForm) CheckoutStep() *CheckoutStep {
+	if f == nil {
+		return nil
+	}
+
+	return &CheckoutStep{
+		Type:   CheckoutStepFormPost,
+		URL:    f.Endpoint,
+		Method: f.Method,
+		Fields: copyFields(f.Fields),
+	}
+}
+
+// CheckoutStepFromURL returns a redirect step for a resolved payment or
+// verification URL, such as the one returned by Verification.
+func CheckoutStepFromURL(u *url.URL) (*CheckoutStep, error) {
+	if u == nil || u.String() == "" {
+		return nil, fmt.Errorf("checkout step: url is empty")
+	}
+
+	return &CheckoutStep{
+		Type:   CheckoutStepRedirect,
+		URL:    u.String(),
+		Method: "GET",
+	}, nil
+}
+
+func copyFields(fields map[string]string) map[string]string {
+	if fields == nil {
+		return nil
+	}
+
+	copied := make(map[string]string, len(fields))
+	for key, value := range fields {
+		copied[key] = value
+	}
+
+	return copied
+}
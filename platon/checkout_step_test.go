@@ -0,0 +1,234 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestResponse_CheckoutStep(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     string
+		wantType    CheckoutStepType
+		wantURL     string
+		wantMethod  string
+		wantFields  map[string]string
+		wantMessage string
+	}{
+		{
+			name:     "accepted",
+			payload:  `{"action":"SALE","result":"ACCEPTED","status":"SETTLED","order_id":"o-1","trans_id":"t-1"}`,
+			wantType: CheckoutStepSuccess,
+		},
+		{
+			name:       "3ds redirect",
+			payload:    `{"action":"SALE","result":"REDIRECT","status":"3DS","order_id":"o-1","trans_id":"t-1","redirect_url":"https://acs.example.com/pareq","redirect_method":"post","redirect_params":{"PaReq":"abc","MD":"md-1","TermUrl":"https://merchant.example.com/3ds"}}`,
+			wantType:   CheckoutStepThreeDS,
+			wantURL:    "https://acs.example.com/pareq",
+			wantMethod: "POST",
+			wantFields: map[string]string{"PaReq": "abc", "MD": "md-1", "TermUrl": "https://merchant.example.com/3ds"},
+		},
+		{
+			name:        "declined",
+			payload:     `{"action":"SALE","result":"DECLINED","status":"DECLINED","order_id":"o-1","trans_id":"t-1","decline_reason":"Insufficient funds"}`,
+			wantType:    CheckoutStepDeclined,
+			wantMessage: "platon api declined: Insufficient funds",
+		},
+		{
+			name:        "error",
+			payload:     `{"result":"ERROR","order_id":"o-1","trans_id":"t-1","error_message":"Invalid hash"}`,
+			wantType:    CheckoutStepError,
+			wantMessage: "platon api error: Invalid hash",
+		},
+		{
+			name:     "pending",
+			payload:  `{"action":"SALE","result":"SUCCESS","status":"PENDING","order_id":"o-1","trans_id":"t-1"}`,
+			wantType: CheckoutStepPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				resp, err := UnmarshalJSONResponse([]byte(tt.payload))
+				if err != nil {
+					t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+				}
+
+				step, err := resp.CheckoutStep()
+				if err != nil {
+					t.Fatalf("CheckoutStep() error: %v", err)
+				}
+				if step.Type != tt.wantType {
+					t.Fatalf("type mismatch: want %q, got %q", tt.wantType, step.Type)
+				}
+				if step.URL != tt.wantURL || step.Method != tt.wantMethod {
+					t.Fatalf("target mismatch: want %s %s, got %s %s", tt.wantMethod, tt.wantURL, step.Method, step.URL)
+				}
+				if len(step.Fields) != len(tt.wantFields) {
+					t.Fatalf("fields mismatch: want %v, got %v", tt.wantFields, step.Fields)
+				}
+				for key, want := range tt.wantFields {
+					if step.Fields[key] != want {
+						t.Fatalf("field %s mismatch: want %q, got %q", key, want, step.Fields[key])
+					}
+				}
+				if step.Message != tt.wantMessage {
+					t.Fatalf("message mismatch: want %q, got %q", tt.wantMessage, step.Message)
+				}
+				if step.OrderID != "o-1" || step.TransID != "t-1" {
+					t.Fatalf("ids mismatch: got order_id=%q trans_id=%q", step.OrderID, step.TransID)
+				}
+			},
+		)
+	}
+}
+
+func TestResponse_CheckoutStep_Nil(t *testing.T) {
+	var resp *Response
+	if _, err := resp.CheckoutStep(); err == nil {
+		t.Fatalf("expected error for nil response")
+	}
+}
+
+func TestClientServerVerificationForm_CheckoutStep(t *testing.T) {
+	form := &ClientServerVerificationForm{
+		Method:   "POST",
+		Endpoint: "https://secure.platononline.com/payment/auth",
+		Fields:   map[string]string{"key": "client", "sign": "abc"},
+	}
+
+	step := form.CheckoutStep()
+	if step.Type != CheckoutStepFormPost || step.URL != form.Endpoint || step.Method != "POST" {
+		t.Fatalf("unexpected step: %+v", step)
+	}
+
+	form.Fields["sign"] = "changed"
+	if step.Fields["sign"] != "abc" {
+		t.Fatalf("step fields must not alias the form fields")
+	}
+
+	raw, err := json.Marshal(step)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	const want = `{"type":"form_post","url":"https://secure.platononline.com/payment/auth","method":"POST","fields":{"key":"client","sign":"abc"}}`
+	if string(raw) != want {
+		t.Fatalf("json mismatch:\nwant %s\ngot  %s", want, raw)
+	}
+}
+
+func TestCheckoutStepFromURL(t *testing.T) {
+	u, _ := url.Parse("https://secure.platononline.com/payment/purchase?token=abc")
+
+	step, err := CheckoutStepFromURL(u)
+	if err != nil {
+		t.Fatalf("CheckoutStepFromURL() error: %v", err)
+	}
+	if step.Type != CheckoutStepRedirect || step.URL != u.String() || step.Method != "GET" {
+		t.Fatalf("unexpected step: %+v", step)
+	}
+
+	if _, err := CheckoutStepFromURL(nil); err == nil {
+		t.Fatalf("expected error for nil url")
+	}
+}
+
+func TestResponse_Requires3DS_RedirectParamsEncodings(t *testing.T) {
+	wantFields := map[string]string{"PaReq": "abc+/=", "MD": "12345", "TermUrl": "https://merchant.example.com/3ds"}
+	payloads := map[string]string{
+		"object":        `{"result":"REDIRECT","status":"3DS","redirect_url":"https://acs.example.com/pareq","redirect_method":"POST","redirect_params":{"PaReq":"abc+/=","MD":12345,"TermUrl":"https://merchant.example.com/3ds"}}`,
+		"urlencoded":    `{"result":"REDIRECT","status":"3DS","redirect_url":"https://acs.example.com/pareq","redirect_method":"POST","redirect_params":"PaReq=abc%2B%2F%3D&MD=12345&TermUrl=https%3A%2F%2Fmerchant.example.com%2F3ds"}`,
+		"json string":   `{"result":"REDIRECT","status":"3DS","redirect_url":"https://acs.example.com/pareq","redirect_method":"POST","redirect_params":"{\"PaReq\":\"abc+/=\",\"MD\":\"12345\",\"TermUrl\":\"https://merchant.example.com/3ds\"}"}`,
+		"no method set": `{"result":"REDIRECT","redirect_url":"https://acs.example.com/pareq","redirect_params":{"PaReq":"abc+/=","MD":"12345","TermUrl":"https://merchant.example.com/3ds"}}`,
+	}
+
+	for name, payload := range payloads {
+		t.Run(
+			name, func(t *testing.T) {
+				resp, err := UnmarshalJSONResponse([]byte(payload))
+				if err != nil {
+					t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+				}
+				if !resp.Requires3DS() {
+					t.Fatalf("Requires3DS() = false, want true")
+				}
+				if err := resp.GetError(); err != nil {
+					t.Fatalf("GetError() = %v, want nil for a 3DS response", err)
+				}
+
+				form, err := resp.Build3DSForm()
+				if err != nil {
+					t.Fatalf("Build3DSForm() error: %v", err)
+				}
+				if form.Method != "POST" || form.Endpoint != "https://acs.example.com/pareq" {
+					t.Fatalf("unexpected form target: %s %s", form.Method, form.Endpoint)
+				}
+				if !reflect.DeepEqual(form.Fields, wantFields) {
+					t.Fatalf("form fields = %v, want %v", form.Fields, wantFields)
+				}
+				if _, err := form.RenderAutoSubmitHTML(); err != nil {
+					t.Fatalf("RenderAutoSubmitHTML() error: %v", err)
+				}
+			},
+		)
+	}
+}
+
+func TestResponse_Requires3DS_NotRequired(t *testing.T) {
+	payloads := []string{
+		`{"result":"ACCEPTED","status":"SETTLED"}`,
+		`{"result":"DECLINED","decline_reason":"3DS failed","redirect_url":"https://acs.example.com/pareq"}`,
+	}
+
+	for _, payload := range payloads {
+		resp, err := UnmarshalJSONResponse([]byte(payload))
+		if err != nil {
+			t.Fatalf("UnmarshalJSONResponse(%s) error: %v", payload, err)
+		}
+		if resp.Requires3DS() {
+			t.Fatalf("Requires3DS(%s) = true, want false", payload)
+		}
+		if _, err := resp.Build3DSForm(); err == nil {
+			t.Fatalf("Build3DSForm(%s) expected error", payload)
+		}
+	}
+
+	if _, err := UnmarshalJSONResponse([]byte(`{"result":"REDIRECT","redirect_params":["PaReq"]}`)); err == nil {
+		t.Fatalf("expected error for redirect_params array")
+	}
+
+	resp, err := UnmarshalJSONResponse([]byte(`{"result":"REDIRECT","redirect_url":"https://acs.example.com/pareq","redirect_params":{"PaReq":" abc ","Challenge":true}}`))
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() non-string params error: %v", err)
+	}
+	if resp.RedirectParams["PaReq"] != "abc" || resp.RedirectParams["Challenge"] != "true" {
+		t.Fatalf("RedirectParams = %v, want PaReq abc and Challenge true", resp.RedirectParams)
+	}
+}
@@ -30,6 +30,9 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"html"
+	"net/url"
+	"sort"
 	"strings"
 )
 
@@ -171,6 +174,47 @@ func BuildClientServerVerificationForm(params ClientServerVerificationParams, en
 	return form, nil
 }
 
+// RenderAutoSubmitHTML returns a minimal HTML page that posts the form to
+// Endpoint as soon as it loads. Serve it to the payer's browser to start the
+// verification (3DS) redirect. Every value is HTML-escaped; a submit button is
+// kept inside <noscript> for browsers without JavaScript.
+func (f *ClientServerVerificationForm) RenderAutoSubmitHTML() (string, error) {
+	if f == nil {
+		return "", fmt.Errorf("verification form is nil")
+	}
+
+	endpoint, err := url.Parse(strings.TrimSpace(f.Endpoint))
+	if err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+		return "", fmt.Errorf("verification form: invalid endpoint %q", f.Endpoint)
+	}
+
+	method := strings.ToUpper(strings.TrimSpace(f.Method))
+	if method == "" {
+		method = clientServerVerificationMethod
+	}
+
+	keys := make([]string, 0, len(f.Fields))
+	for key := range f.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>Redirecting…</title></head>\n")
+	b.WriteString("<body onload=\"document.forms[0].submit()\">\n")
+	fmt.Fprintf(&b, "<form method=\"%s\" action=\"%s\">\n", html.EscapeString(method), html.EscapeString(endpoint.String()))
+	for _, key := range keys {
+		fmt.Fprintf(
+			&b, "<input type=\"hidden\" name=\"%s\" value=\"%s\">\n",
+			html.EscapeString(key), html.EscapeString(f.Fields[key]),
+		)
+	}
+	b.WriteString("<noscript><button type=\"submit\">Continue</button></noscript>\n")
+	b.WriteString("</form>\n</body>\n</html>\n")
+
+	return b.String(), nil
+}
+
 func setNonEmptyFormField(fields map[string]string, key string, value string) {
 	if fields == nil {
 		return
@@ -0,0 +1,82 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"html"
+	"strings"
+	"testing"
+)
+
+func TestClientServerVerificationForm_RenderAutoSubmitHTML(t *testing.T) {
+	form := &ClientServerVerificationForm{
+		Method:   "POST",
+		Endpoint: "https://secure.platononline.com/payment/auth?lang=uk&x=1",
+		Fields: map[string]string{
+			"key":   "client-key",
+			"url":   "https://merchant.example/return?a=1&b=\"2\"",
+			"data":  "eyJhbW91bnQiOiIxLjAwIn0=",
+			"ext1":  `<script>alert('x')</script>`,
+			"sign":  "0123456789abcdef0123456789abcdef",
+			"order": "O'Reilly & Sons",
+		},
+	}
+
+	page, err := form.RenderAutoSubmitHTML()
+	if err != nil {
+		t.Fatalf("RenderAutoSubmitHTML() error: %v", err)
+	}
+
+	action := `action="` + html.EscapeString(form.Endpoint) + `"`
+	if !strings.Contains(page, `<form method="POST" `+action+`>`) {
+		t.Fatalf("form action must equal the endpoint, got:\n%s", page)
+	}
+	if !strings.Contains(page, `onload="document.forms[0].submit()"`) {
+		t.Fatalf("page must submit itself on load, got:\n%s", page)
+	}
+	for key, value := range form.Fields {
+		input := `<input type="hidden" name="` + html.EscapeString(key) + `" value="` + html.EscapeString(value) + `">`
+		if !strings.Contains(page, input) {
+			t.Fatalf("missing hidden input %s, got:\n%s", input, page)
+		}
+	}
+	if strings.Contains(page, "<script>") || strings.Contains(page, `"2"`) {
+		t.Fatalf("field values must be HTML-escaped, got:\n%s", page)
+	}
+}
+
+func TestClientServerVerificationForm_RenderAutoSubmitHTML_Errors(t *testing.T) {
+	var nilForm *ClientServerVerificationForm
+	if _, err := nilForm.RenderAutoSubmitHTML(); err == nil {
+		t.Fatal("expected error for nil form")
+	}
+
+	for _, endpoint := range []string{"", "javascript:alert(1)", "/relative/path"} {
+		form := &ClientServerVerificationForm{Endpoint: endpoint}
+		if _, err := form.RenderAutoSubmitHTML(); err == nil {
+			t.Fatalf("expected error for endpoint %q", endpoint)
+		}
+	}
+}
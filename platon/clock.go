@@ -0,0 +1,57 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import "time"
+
+// Clock supplies the current time to request validation. Tests inject a fixed
+// clock to make time-dependent checks, such as card expiry, deterministic.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function such as time.Now to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now returns f(), or time.Now() when f is nil.
+func (f ClockFunc) Now() time.Time {
+	if f == nil {
+		return time.Now()
+	}
+
+	return f()
+}
+
+// SystemClock is the wall clock used when a request has no Clock.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// now returns the current time from the request clock, falling back to SystemClock.
+func (r *Request) now() time.Time {
+	if r == nil || r.Clock == nil {
+		return SystemClock.Now()
+	}
+
+	return r.Clock.Now()
+}
@@ -0,0 +1,69 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// testClock is pinned before the 01/2026 expiry used by the card fixtures.
+var testClock = ClockFunc(func() time.Time { return time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC) })
+
+func TestRequest_CardExpiryWithFixedClock(t *testing.T) {
+	clock := ClockFunc(func() time.Time { return time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC) })
+
+	newPayment := func(month, year string) *Request {
+		return newSignableCardPayment().WithCardExpMonth(&month).WithCardExpYear(&year).WithClock(clock)
+	}
+
+	for _, exp := range [][2]string{{"10", "2026"}, {"01", "2027"}} {
+		if _, err := newPayment(exp[0], exp[1]).SignAndPrepare(); err != nil {
+			t.Fatalf("%s/%s: unexpected error: %v", exp[0], exp[1], err)
+		}
+	}
+
+	for _, exp := range [][2]string{{"09", "2026"}, {"12", "2025"}} {
+		_, err := newPayment(exp[0], exp[1]).SignAndPrepare()
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "card_exp_year" {
+			t.Fatalf("%s/%s: expected card_exp_year validation error, got %v", exp[0], exp[1], err)
+		}
+		if !errors.Is(err, ErrValidation) {
+			t.Fatalf("%s/%s: expected ErrValidation, got %v", exp[0], exp[1], err)
+		}
+	}
+}
+
+func TestClockFunc_NilUsesWallClock(t *testing.T) {
+	before := time.Now()
+	if got := ClockFunc(nil).Now(); got.Before(before) {
+		t.Fatalf("ClockFunc(nil).Now() = %v, want >= %v", got, before)
+	}
+	if got := (*Request)(nil).now(); got.IsZero() {
+		t.Fatalf("nil request now() returned zero time")
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/stremovskyy/go-platon/currency"
+)
+
+// currencyFractionDigits returns the exponent of the request currency (2 when
+// it is empty or unknown, see currency.Exponent).
+func currencyFractionDigits(code string) int {
+	return currency.Exponent(currency.Code(code))
+}
+
+// formatAmountForCurrency formats minor units with the exponent of code.
+// Negative amounts keep the two-decimal form and are rejected by validation.
+func formatAmountForCurrency(minorUnits Money, code string) string {
+	if minorUnits < 0 {
+		return minorUnits.String()
+	}
+
+	return formatAmountMinorUnits(minorUnits, currencyFractionDigits(code))
+}
+
+// validateCurrencyAmount checks that amount is a positive decimal written with
+// exactly the fraction digits of code, e.g. "10.00" for UAH and "10" for JPY.
+func validateCurrencyAmount(context, field, amount, code string) error {
+	digits := currencyFractionDigits(code)
+
+	minor, err := parseAmountMinorUnits(amount, digits)
+	if err != nil {
+		return NewValidationError(context, field, fmt.Sprintf("must have %s (got %q)", describeFractionDigits(code, digits), amount))
+	}
+	if minor <= 0 {
+		return NewValidationError(context, field, fmt.Sprintf("must be > 0 (got %q)", amount))
+	}
+
+	return nil
+}
+
+func describeFractionDigits(code string, digits int) string {
+	label := strings.ToUpper(strings.TrimSpace(code))
+	if label == "" {
+		label = "the currency"
+	}
+	example := formatAmountMinorUnits(Money(1000*pow10(digits)), digits)
+	if digits == 0 {
+		return fmt.Sprintf("no decimals for %s, e.g. %q", label, example)
+	}
+
+	return fmt.Sprintf("%d decimals for %s, e.g. %q", digits, label, example)
+}
+
+// parseAmountMinorUnits parses a non-negative decimal amount with exactly
+// digits fraction digits (no decimal point when digits is 0) into minor units.
+func parseAmountMinorUnits(amount string, digits int) (Money, error) {
+	major, fraction, hasFraction := strings.Cut(amount, ".")
+	if hasFraction != (digits > 0) || len(fraction) != digits {
+		return 0, fmt.Errorf("invalid amount format")
+	}
+	if !isDigits(major) || (digits > 0 && !isDigits(fraction)) {
+		return 0, fmt.Errorf("invalid amount format")
+	}
+
+	var minorUnits int64
+	var err error
+	if digits > 0 {
+		if minorUnits, err = strconv.ParseInt(fraction, 10, 64); err != nil {
+			return 0, fmt.Errorf("invalid minor amount")
+		}
+	}
+
+	scale := pow10(digits)
+	majorUnits, err := strconv.ParseInt(major, 10, 64)
+	if err != nil || majorUnits > (math.MaxInt64-minorUnits)/scale {
+		return 0, fmt.Errorf("amount is out of range")
+	}
+
+	return Money(majorUnits*scale + minorUnits), nil
+}
+
+// formatAmountMinorUnits formats non-negative minor units with digits
+// fraction digits.
+func formatAmountMinorUnits(minorUnits Money, digits int) string {
+	if digits == 0 {
+		return strconv.FormatInt(minorUnits.MinorUnits(), 10)
+	}
+
+	scale := pow10(digits)
+	return fmt.Sprintf("%d.%0*d", minorUnits.MinorUnits()/scale, digits, minorUnits.MinorUnits()%scale)
+}
+
+func pow10(n int) int64 {
+	result := int64(1)
+	for i := 0; i < n; i++ {
+		result *= 10
+	}
+
+	return result
+}
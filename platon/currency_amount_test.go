@@ -0,0 +1,131 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stremovskyy/go-platon/currency"
+)
+
+func TestValidateCurrencyAmount(t *testing.T) {
+	tests := []struct {
+		amount  string
+		code    string
+		wantErr string
+	}{
+		{amount: "10.00", code: "UAH"},
+		{amount: "0.01", code: "USD"},
+		{amount: "10.00", code: ""},
+		{amount: "10.00", code: "XTS"},
+		{amount: "1000", code: "JPY"},
+		{amount: "10.5", code: "UAH", wantErr: `card_payment: order_amount must have 2 decimals for UAH, e.g. "1000.00" (got "10.5")`},
+		{amount: "10", code: "EUR", wantErr: `card_payment: order_amount must have 2 decimals for EUR, e.g. "1000.00" (got "10")`},
+		{amount: "10.00", code: "JPY", wantErr: `card_payment: order_amount must have no decimals for JPY, e.g. "1000" (got "10.00")`},
+		{amount: "1,00", code: "", wantErr: `must have 2 decimals for the currency`},
+		{amount: "0.00", code: "UAH", wantErr: `card_payment: order_amount must be > 0 (got "0.00")`},
+		{amount: "0", code: "KRW", wantErr: `must be > 0`},
+	}
+
+	for _, tt := range tests {
+		err := validateCurrencyAmount("card_payment", "order_amount", tt.amount, tt.code)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Fatalf("validateCurrencyAmount(%q, %q) error: %v", tt.amount, tt.code, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Fatalf("validateCurrencyAmount(%q, %q) error = %v, want %q", tt.amount, tt.code, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSignAndPrepare_ZeroDecimalCurrency(t *testing.T) {
+	newSale := func(amount string, splitRules SplitRules) *Request {
+		return newSignableTokenPayment().
+			ForCurrency(currency.JPY).
+			WithOrderAmount(amount).
+			WithSplitRules(splitRules)
+	}
+
+	if _, err := newSale("1500", SplitRules{"sub_1": "1000", "sub_2": "500"}).SignAndPrepare(); err != nil {
+		t.Fatalf("SignAndPrepare() JPY error: %v", err)
+	}
+
+	_, err := newSale("1500.00", nil).SignAndPrepare()
+	if err == nil || !strings.Contains(err.Error(), "no decimals for JPY") {
+		t.Fatalf("SignAndPrepare() expected JPY format error, got %v", err)
+	}
+
+	_, err = newSale("1500", SplitRules{"sub_1": "1000", "sub_2": "400"}).SignAndPrepare()
+	if err == nil || !strings.Contains(err.Error(), "split rules total is 100 short of amount (1400 != 1500)") {
+		t.Fatalf("SignAndPrepare() expected JPY split shortfall, got %v", err)
+	}
+
+	_, err = newSale("1500", SplitRules{"sub_1": "1000.00", "sub_2": "500"}).SignAndPrepare()
+	if err == nil || !strings.Contains(err.Error(), `split_rules["sub_1"] amount must have no decimals for JPY`) {
+		t.Fatalf("SignAndPrepare() expected JPY split format error, got %v", err)
+	}
+}
+
+func TestMinorUnitBuilders_UseCurrencyExponent(t *testing.T) {
+	tests := []struct {
+		code currency.Code
+		want string
+	}{
+		{code: currency.UAH, want: "1500.00"},
+		{code: currency.USD, want: "1500.00"},
+		{code: currency.JPY, want: "150000"},
+		{code: "XTS", want: "1500.00"},
+	}
+
+	for _, tt := range tests {
+		currencyFirst := NewRequest(ActionCodeSALE).
+			ForCurrency(tt.code).
+			WithOrderAmountMinorUnits(150000).
+			WithAmountMinorUnits(150000)
+		amountFirst := NewRequest(ActionCodeSALE).
+			WithOrderAmountMinorUnits(150000).
+			WithAmountMinorUnits(150000).
+			ForCurrency(tt.code)
+
+		for _, req := range []*Request{currencyFirst, amountFirst} {
+			if req.OrderAmount != tt.want || req.Amount != tt.want {
+				t.Fatalf("%s amounts = %q/%q, want %q", tt.code, req.OrderAmount, req.Amount, tt.want)
+			}
+		}
+	}
+
+	// An amount set as a string afterwards is not reformatted.
+	req := NewRequest(ActionCodeSALE).
+		WithOrderAmountMinorUnits(1500).
+		WithOrderAmount("15.00").
+		ForCurrency(currency.JPY)
+	if req.OrderAmount != "15.00" {
+		t.Fatalf("OrderAmount = %q, want the explicit string", req.OrderAmount)
+	}
+}
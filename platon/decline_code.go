@@ -0,0 +1,61 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import "strings"
+
+// DeclineCode maps Platon decline codes to descriptions that can be shown to
+// payers. It holds the codes the SDK has seen in Platon responses; add or
+// replace entries, e.g. with localized texts, during initialization:
+//
+//	platon.DeclineCode[102] = "Збережена картка більше не активна"
+//
+// The map is read without locking, so do not modify it while requests run.
+var DeclineCode = map[int]string{
+	102: "The saved card is no longer active. Please pay with another card.",
+}
+
+// DescribeDecline splits a decline_reason such as "102: Token is not active"
+// into its code and the DeclineCode description. When the reason has no code
+// or the code is not in DeclineCode, the description is the trimmed raw reason.
+func DescribeDecline(reason string) (code int, description string) {
+	parsed := NewAPIError(APIErrorKindDeclined, reason)
+	if text, ok := DeclineCode[parsed.Code]; ok && parsed.Code != 0 {
+		return parsed.Code, text
+	}
+
+	return parsed.Code, strings.TrimSpace(reason)
+}
+
+// Description returns the DeclineCode description of the decline, or the raw
+// message when its code is unknown.
+func (e *DeclineError) Description() string {
+	if e == nil {
+		return ""
+	}
+
+	_, description := DescribeDecline(e.Message)
+	return description
+}
@@ -0,0 +1,64 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDescribeDecline(t *testing.T) {
+	tests := []struct {
+		reason   string
+		wantCode int
+		wantDesc string
+	}{
+		{"102: Token is not active", 102, DeclineCode[102]},
+		{" 999: Something new ", 999, "999: Something new"},
+		{"Insufficient funds", 0, "Insufficient funds"},
+		{"", 0, ""},
+	}
+	for _, tt := range tests {
+		code, desc := DescribeDecline(tt.reason)
+		if code != tt.wantCode || desc != tt.wantDesc {
+			t.Fatalf("DescribeDecline(%q) = %d, %q, want %d, %q", tt.reason, code, desc, tt.wantCode, tt.wantDesc)
+		}
+	}
+}
+
+func TestDeclineCode_IsExtensible(t *testing.T) {
+	DeclineCode[999] = "Спробуйте іншу картку"
+	defer delete(DeclineCode, 999)
+
+	if code, desc := DescribeDecline("999: Something new"); code != 999 || desc != "Спробуйте іншу картку" {
+		t.Fatalf("DescribeDecline() = %d, %q, want the registered description", code, desc)
+	}
+
+	var declined *DeclineError
+	err := error(NewAPIError(APIErrorKindDeclined, "999: Something new"))
+	if !errors.As(err, &declined) || declined.Description() != "Спробуйте іншу картку" {
+		t.Fatalf("DeclineError.Description() = %q, want the registered description", declined.Description())
+	}
+}
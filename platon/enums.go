@@ -86,6 +86,7 @@ const (
 	ActionCodeSALE                  ActionCode = "SALE"
 	ActionCodeGetTransStatus        ActionCode = "GET_TRANS_STATUS"
 	ActionCodeGetTransStatusByOrder ActionCode = "GET_TRANS_STATUS_BY_ORDER"
+	ActionCodeGetTransDetails       ActionCode = "GET_TRANS_DETAILS"
 	ActionCodeAPPLEPAY              ActionCode = "APPLEPAY"
 	ActionCodeGOOGLEPAY             ActionCode = "GOOGLEPAY"
 	ActionCodeCAPTURE               ActionCode = "CAPTURE"
@@ -122,18 +123,28 @@ const (
 	// HashTypeGetTransStatus is used for the GET_TRANS_STATUS request.
 	HashTypeGetTransStatus HashType = "get_trans_status"
 
+	// HashTypeGetTransStatusA2C is used for A2C GET_TRANS_STATUS requests over /p2p-unq/.
+	HashTypeGetTransStatusA2C HashType = "get_trans_status_a2c"
+
 	// HashTypeGetTransStatusByOrder is used for the GET_TRANS_STATUS_BY_ORDER request.
 	HashTypeGetTransStatusByOrder HashType = "get_trans_status_by_order"
 
 	// HashTypeGetTransStatusByOrderA2C is used for A2C GET_TRANS_STATUS_BY_ORDER requests over /p2p-unq/.
 	HashTypeGetTransStatusByOrderA2C HashType = "get_trans_status_by_order_a2c"
 
+	// HashTypeGetTransDetails is used for the GET_TRANS_DETAILS request. It is
+	// signed like GET_TRANS_STATUS.
+	HashTypeGetTransDetails HashType = "get_trans_details"
+
 	// HashTypeCapture is used for CAPTURE (confirm HOLD).
 	HashTypeCapture HashType = "capture"
 
 	// HashTypeCreditVoid is used for CREDITVOID (refund).
 	HashTypeCreditVoid HashType = "creditvoid"
 
+	// HashTypeVoid is used for CREDITVOID without amount (cancel an uncaptured HOLD).
+	HashTypeVoid HashType = "void"
+
 	// HashTypeGetSubmerchant is used for GET_SUBMERCHANT requests.
 	HashTypeGetSubmerchant HashType = "get_submerchant"
 
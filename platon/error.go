@@ -26,15 +26,16 @@ package platon
 
 import "fmt"
 
-var ErrRequestIsNil = Error{Code: 1, Message: "Request is nil", Details: "Request is nil"}
-var ErrNotImplemented = Error{Code: 2, Message: "Not implemented", Details: "This operation is not implemented yet"}
+var ErrRequestIsNil = CodedError{Code: 1, Message: "Request is nil", Details: "Request is nil"}
+var ErrNotImplemented = CodedError{Code: 2, Message: "Not implemented", Details: "This operation is not implemented yet"}
 
-type Error struct {
+// CodedError is a numbered SDK error such as ErrRequestIsNil.
+type CodedError struct {
 	Code    int
 	Message string
 	Details string
 }
 
-func (e Error) Error() string {
+func (e CodedError) Error() string {
 	return fmt.Sprintf("Error %d: %s. Details: %s", e.Code, e.Message, e.Details)
 }
@@ -0,0 +1,109 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+// FormField is one form-encoded request field.
+type FormField struct {
+	Key   string
+	Value string
+}
+
+// ToOrderedForm returns the request fields that are sent to Platon, in the
+// order of the Request struct, which follows the Platon docs: action and
+// credentials, order, payer, card, operation flags, ext1..ext10 and
+// split_rules. As in ToMap, empty strings and nil pointers are omitted, while
+// a pointer to "" is sent as an empty value. Unlike ToMap it does not
+// use reflection and its order is stable, so the same request always encodes
+// to the same body. split_rules is encoded with sorted submerchant IDs.
+func (r *Request) ToOrderedForm() []FormField {
+	if r == nil {
+		return nil
+	}
+
+	fields := make([]FormField, 0, 24)
+	add := func(key, value string) {
+		if value != "" {
+			fields = append(fields, FormField{Key: key, Value: value})
+		}
+	}
+	addRef := func(key string, value *string) {
+		if value != nil {
+			fields = append(fields, FormField{Key: key, Value: *value})
+		}
+	}
+
+	add("action", r.Action)
+	add("client_key", r.ClientKey)
+	add("hash", r.Hash)
+	add("channel_id", r.ChannelId)
+	addRef("payer_ip", r.PayerIp)
+	addRef("term_url_3ds", r.TermUrl3ds)
+	addRef("order_id", r.OrderID)
+	add("order_amount", r.OrderAmount)
+	add("order_currency", r.OrderCurrency)
+	addRef("submerchant_id", r.SubmerchantID)
+	addRef("order_description", r.OrderDescription)
+	addRef("payment_token", r.PaymentToken)
+	addRef("payer_email", r.PayerEmail)
+	addRef("payer_phone", r.PayerPhone)
+	addRef("payer_first_name", r.PayerFirstName)
+	addRef("payer_last_name", r.PayerLastName)
+	addRef("payer_address", r.PayerAddress)
+	addRef("payer_country", r.PayerCountry)
+	addRef("payer_state", r.PayerState)
+	addRef("payer_city", r.PayerCity)
+	addRef("payer_zip", r.PayerZip)
+	addRef("customer_wallet", r.CustomerWallet)
+	addRef("card_number", r.CardNumber)
+	addRef("card_exp_month", r.CardExpMonth)
+	addRef("card_exp_year", r.CardExpYear)
+	addRef("card_cvv2", r.CardCvv2)
+	addRef("card_token", r.CardToken)
+	addRef("auth", r.AuthFlag)
+	addRef("recurring_first_trans_id", r.RecurringFirstTransID)
+	addRef("trans_id", r.TransId)
+	add("amount", r.Amount)
+	addRef("immediately", r.Immediately)
+	addRef("req_token", r.ReqToken)
+	addRef("recurring_init", r.RecurringInit)
+	addRef("async", r.Async)
+	addRef("ext1", r.Ext1)
+	addRef("ext2", r.Ext2)
+	addRef("ext3", r.Ext3)
+	addRef("ext4", r.Ext4)
+	addRef("ext5", r.Ext5)
+	addRef("ext6", r.Ext6)
+	addRef("ext7", r.Ext7)
+	addRef("ext8", r.Ext8)
+	addRef("ext9", r.Ext9)
+	addRef("ext10", r.Ext10)
+	if len(r.SplitRules) > 0 {
+		// SplitRules holds only strings, so encoding cannot fail.
+		encoded, _ := r.SplitRules.Encode()
+		add("split_rules", encoded)
+	}
+
+	return fields
+}
@@ -0,0 +1,110 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// fullRequest sets every field that is sent to Platon to a distinct value.
+func fullRequest() *Request {
+	req := &Request{}
+	v := reflect.ValueOf(req).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		value := fmt.Sprintf("v%d", i)
+		switch fv := v.Field(i); fv.Kind() {
+		case reflect.String:
+			fv.SetString(value)
+		case reflect.Ptr:
+			fv.Set(reflect.ValueOf(&value))
+		}
+	}
+	req.SplitRules = SplitRules{"b": "1.00", "a": "2.00"}
+
+	return req
+}
+
+func TestRequest_ToOrderedFormMatchesToMap(t *testing.T) {
+	req := fullRequest()
+
+	form := req.ToOrderedForm()
+	fields := req.ToMap()
+	if len(form) != len(fields) {
+		t.Fatalf("ToOrderedForm() has %d fields, ToMap() has %d", len(form), len(fields))
+	}
+
+	for _, field := range form {
+		want, ok := fields[field.Key]
+		if !ok {
+			t.Fatalf("ToOrderedForm() field %q is not in ToMap()", field.Key)
+		}
+		if rules, isRules := want.(SplitRules); isRules {
+			want, _ = rules.Encode()
+		}
+		if field.Value != want {
+			t.Fatalf("%s = %q, want %q", field.Key, field.Value, want)
+		}
+	}
+
+	if form[0].Key != "action" || form[len(form)-1].Key != "split_rules" {
+		t.Fatalf("unexpected field order: first %q, last %q", form[0].Key, form[len(form)-1].Key)
+	}
+}
+
+func TestRequest_ToOrderedFormKeepsEmptyPointers(t *testing.T) {
+	empty := ""
+	form := (&Request{Action: "SALE", CardCvv2: &empty}).ToOrderedForm()
+
+	want := []FormField{{Key: "action", Value: "SALE"}, {Key: "card_cvv2", Value: ""}}
+	if !reflect.DeepEqual(form, want) {
+		t.Fatalf("ToOrderedForm() = %v, want %v", form, want)
+	}
+	if (*Request)(nil).ToOrderedForm() != nil {
+		t.Fatal("nil request ToOrderedForm() expected nil")
+	}
+}
+
+func BenchmarkRequest_ToMap(b *testing.B) {
+	req := fullRequest()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = req.ToMap()
+	}
+}
+
+func BenchmarkRequest_ToOrderedForm(b *testing.B) {
+	req := fullRequest()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = req.ToOrderedForm()
+	}
+}
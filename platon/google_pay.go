@@ -0,0 +1,79 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidGooglePayToken is wrapped by every ParseGooglePayToken error.
+var ErrInvalidGooglePayToken = errors.New("invalid Google Pay token")
+
+// GooglePayInfo describes the card behind a Google Pay token, from
+// paymentMethodData.info.
+type GooglePayInfo struct {
+	// CardNetwork is the card network, e.g. "VISA" or "MASTERCARD".
+	CardNetwork string `json:"cardNetwork"`
+	// CardDetails is usually the last four digits of the card.
+	CardDetails string `json:"cardDetails"`
+}
+
+// ParseGooglePayToken decodes a base64 Google Pay PaymentData object and
+// returns paymentMethodData.tokenizationData.token with the card info. A
+// missing or empty token is an error.
+func ParseGooglePayToken(b64 string) (string, *GooglePayInfo, error) {
+	decoded, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: cannot decode base64: %w", ErrInvalidGooglePayToken, err)
+	}
+
+	var data struct {
+		PaymentMethodData *struct {
+			Info             GooglePayInfo `json:"info"`
+			TokenizationData *struct {
+				Token string `json:"token"`
+			} `json:"tokenizationData"`
+		} `json:"paymentMethodData"`
+	}
+	if err := json.Unmarshal(decoded, &data); err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrInvalidGooglePayToken, err)
+	}
+
+	switch {
+	case data.PaymentMethodData == nil:
+		return "", nil, fmt.Errorf("%w: paymentMethodData is missing", ErrInvalidGooglePayToken)
+	case data.PaymentMethodData.TokenizationData == nil:
+		return "", nil, fmt.Errorf("%w: paymentMethodData.tokenizationData is missing", ErrInvalidGooglePayToken)
+	case strings.TrimSpace(data.PaymentMethodData.TokenizationData.Token) == "":
+		return "", nil, fmt.Errorf("%w: paymentMethodData.tokenizationData.token is empty", ErrInvalidGooglePayToken)
+	}
+
+	info := data.PaymentMethodData.Info
+	return data.PaymentMethodData.TokenizationData.Token, &info, nil
+}
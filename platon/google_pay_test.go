@@ -0,0 +1,105 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func encodeGooglePay(raw string) string {
+	return base64.StdEncoding.EncodeToString([]byte(raw))
+}
+
+func TestParseGooglePayToken_RealisticPayload(t *testing.T) {
+	raw := `{
+		"apiVersion": 2,
+		"apiVersionMinor": 0,
+		"paymentMethodData": {
+			"type": "CARD",
+			"description": "Visa •••• 1111",
+			"info": {"cardNetwork": "VISA", "cardDetails": "1111"},
+			"tokenizationData": {
+				"type": "PAYMENT_GATEWAY",
+				"token": "{\"signature\":\"MEUCIQ==\",\"protocolVersion\":\"ECv2\",\"signedMessage\":\"{}\"}"
+			}
+		}
+	}`
+
+	token, info, err := ParseGooglePayToken(encodeGooglePay(raw))
+	if err != nil {
+		t.Fatalf("ParseGooglePayToken() error: %v", err)
+	}
+	if want := `{"signature":"MEUCIQ==","protocolVersion":"ECv2","signedMessage":"{}"}`; token != want {
+		t.Fatalf("token = %q, want %q", token, want)
+	}
+	if info.CardNetwork != "VISA" || info.CardDetails != "1111" {
+		t.Fatalf("info = %+v, want VISA 1111", info)
+	}
+}
+
+func TestParseGooglePayToken_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "not base64", input: "%%%", want: "cannot decode base64"},
+		{name: "not json", input: encodeGooglePay("google"), want: "invalid Google Pay token"},
+		{name: "missing paymentMethodData", input: encodeGooglePay(`{}`), want: "paymentMethodData is missing"},
+		{
+			name:  "missing tokenizationData",
+			input: encodeGooglePay(`{"paymentMethodData":{"info":{"cardNetwork":"VISA"}}}`),
+			want:  "tokenizationData is missing",
+		},
+		{
+			name:  "empty token",
+			input: encodeGooglePay(`{"paymentMethodData":{"tokenizationData":{"token":""}}}`),
+			want:  "tokenizationData.token is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				_, _, err := ParseGooglePayToken(tt.input)
+				if !errors.Is(err, ErrInvalidGooglePayToken) {
+					t.Fatalf("ParseGooglePayToken() error = %v, want ErrInvalidGooglePayToken", err)
+				}
+				if !strings.Contains(err.Error(), tt.want) {
+					t.Fatalf("ParseGooglePayToken() error = %v, want %q", err, tt.want)
+				}
+			},
+		)
+	}
+
+	_, _, err := ParseGooglePayToken("%%%")
+	var corrupt base64.CorruptInputError
+	if !errors.As(err, &corrupt) {
+		t.Fatalf("ParseGooglePayToken() error = %v, want the base64 cause to stay reachable", err)
+	}
+}
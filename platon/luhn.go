@@ -0,0 +1,94 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+// validateLuhn reports whether pan is a digit string with a valid Luhn check digit.
+func validateLuhn(pan string) bool {
+	if len(pan) < 2 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(pan) - 1; i >= 0; i-- {
+		c := pan[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+
+		digit := int(c - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// ValidatePAN reports whether pan is a plausible card number: 13 to 19 digits
+// with a valid Luhn check digit. The error is a *ValidationError for card_number.
+func ValidatePAN(pan string) error {
+	if reason := panReason(pan, false); reason != "" {
+		return NewValidationError("", "card_number", reason)
+	}
+
+	return nil
+}
+
+// panReason returns why pan is not a valid card number, or "" when it is.
+// skipLuhn keeps the length check but accepts any check digit.
+func panReason(pan string, skipLuhn bool) string {
+	if len(pan) < 13 || len(pan) > 19 {
+		return "must be 13..19 digits"
+	}
+	for i := 0; i < len(pan); i++ {
+		if pan[i] < '0' || pan[i] > '9' {
+			return "must be 13..19 digits"
+		}
+	}
+	if !skipLuhn && !validateLuhn(pan) {
+		return "fails Luhn check"
+	}
+
+	return ""
+}
+
+// checkPAN returns an error prefixed with op when card_number is not 13 to 19
+// digits or fails the Luhn check. SkipLuhn disables only the Luhn check.
+func (r *Request) checkPAN(op string) error {
+	if r.CardNumber == nil {
+		return nil
+	}
+	if reason := panReason(*r.CardNumber, r.SkipLuhn); reason != "" {
+		return NewValidationError(op, "card_number", reason)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateLuhn(t *testing.T) {
+	tests := []struct {
+		pan  string
+		want bool
+	}{
+		{pan: "4111111111111111", want: true},
+		{pan: "4242424242424242", want: true},
+		{pan: "4111111111111112", want: false},
+		{pan: "4111 1111 1111 1111", want: false},
+		{pan: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := validateLuhn(tt.pan); got != tt.want {
+			t.Fatalf("validateLuhn(%q) = %v, want %v", tt.pan, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePAN(t *testing.T) {
+	tests := []struct {
+		pan    string
+		reason string
+	}{
+		{pan: "4111111111111111"},
+		{pan: "5555555555554444"},
+		{pan: "2223003122003222"},
+		{pan: "9804000000000009"},
+		{pan: "4222222222222"},
+		{pan: "4111111111111111110"},
+		{pan: "4111111111111112", reason: "fails Luhn check"},
+		{pan: "411111111111", reason: "must be 13..19 digits"},
+		{pan: "41111111111111111111", reason: "must be 13..19 digits"},
+		{pan: "4111 1111 1111 1111", reason: "must be 13..19 digits"},
+		{pan: "", reason: "must be 13..19 digits"},
+	}
+
+	for _, tt := range tests {
+		err := ValidatePAN(tt.pan)
+		if tt.reason == "" {
+			if err != nil {
+				t.Fatalf("ValidatePAN(%q) error: %v", tt.pan, err)
+			}
+			continue
+		}
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "card_number" || validationErr.Reason != tt.reason {
+			t.Fatalf("ValidatePAN(%q) error = %v, want card_number %s", tt.pan, err, tt.reason)
+		}
+	}
+}
+
+func TestSignAndPrepare_CardPaymentLuhn(t *testing.T) {
+	newCardPayment := func(pan string) *Request {
+		return newSignableCardPayment().WithCardNumber(&pan)
+	}
+
+	if _, err := newCardPayment("4111111111111111").SignAndPrepare(); err != nil {
+		t.Fatalf("valid PAN: unexpected error: %v", err)
+	}
+
+	_, err := newCardPayment("4111111111111112").SignAndPrepare()
+	if err == nil || !strings.Contains(err.Error(), "card_payment: card_number fails Luhn check") {
+		t.Fatalf("corrupted PAN: expected Luhn error, got %v", err)
+	}
+
+	if _, err := newCardPayment("4111111111111112").WithSkipLuhn(true).SignAndPrepare(); err != nil {
+		t.Fatalf("SkipLuhn: unexpected error: %v", err)
+	}
+
+	if _, err := newCardPayment("4111111111111111110").SignAndPrepare(); err != nil {
+		t.Fatalf("19-digit PAN: unexpected error: %v", err)
+	}
+	_, err = newCardPayment("411111111111").WithSkipLuhn(true).SignAndPrepare()
+	if !errors.Is(err, ErrValidation) || errors.Is(err, ErrSignature) {
+		t.Fatalf("12-digit PAN: expected a validation error that is not a signature error, got %v", err)
+	}
+}
+
+func TestSignAndPrepare_Credit2CardRejectsInvalidPAN(t *testing.T) {
+	pan := "4111111111111112"
+	_, err := NewRequest(ActionCodeCREDIT2CARD).
+		WithAuth(&Auth{Key: "k", Secret: "secret123"}).
+		WithClientKey("clientKey").
+		WithCardNumber(&pan).
+		SignForAction(HashTypeCredit2Card).
+		SignAndPrepare()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Op != "credit2card" || validationErr.Field != "card_number" {
+		t.Fatalf("expected credit2card card_number validation error, got %v", err)
+	}
+	if errors.Is(err, ErrSignature) {
+		t.Fatalf("invalid PAN must not match ErrSignature: %v", err)
+	}
+}
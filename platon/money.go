@@ -0,0 +1,130 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stremovskyy/go-platon/currency"
+)
+
+// Money is an exact amount in minor units (e.g. kopecks). It formats to the
+// "0.00" decimal string Platon expects without going through float64, so large
+// amounts never pick up rounding errors.
+type Money int64
+
+// Add returns m + other, or an error when the sum overflows int64.
+func (m Money) Add(other Money) (Money, error) {
+	sum := m + other
+	if (other > 0 && sum < m) || (other < 0 && sum > m) {
+		return 0, fmt.Errorf("amount: %s + %s overflows", m, other)
+	}
+
+	return sum, nil
+}
+
+// Sub returns m - other, or an error when the difference overflows int64.
+func (m Money) Sub(other Money) (Money, error) {
+	diff := m - other
+	if (other > 0 && diff > m) || (other < 0 && diff < m) {
+		return 0, fmt.Errorf("amount: %s - %s overflows", m, other)
+	}
+
+	return diff, nil
+}
+
+// MoneyFromMinorUnits returns Money for the given minor units.
+func MoneyFromMinorUnits(minor int64) Money {
+	return Money(minor)
+}
+
+// MinorUnits returns the amount in minor units.
+func (m Money) MinorUnits() int64 {
+	return int64(m)
+}
+
+// String formats the amount in major units with exactly two decimals
+// (e.g. 12345 -> "123.45").
+func (m Money) String() string {
+	minor := int64(m)
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+	}
+
+	// Work on uint64 so that math.MinInt64 does not overflow on negation.
+	abs := uint64(minor)
+	if minor < 0 {
+		abs = -abs
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, abs/100, abs%100)
+}
+
+// Format formats the amount with the exponent of code, e.g. "123.45" for UAH
+// and "12345" for JPY. Negative amounts keep the two-decimal form.
+func (m Money) Format(code currency.Code) string {
+	return formatAmountForCurrency(m, code.String())
+}
+
+// ParseMoney parses a decimal amount such as "123.45", "123.4" or "123" into
+// Money. It is the inverse of String.
+func ParseMoney(s string) (Money, error) {
+	value := strings.TrimSpace(s)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	major, fraction, hasFraction := strings.Cut(value, ".")
+	if major == "" || !isDigits(major) {
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+	if hasFraction && (fraction == "" || len(fraction) > 2 || !isDigits(fraction)) {
+		return 0, fmt.Errorf("money: invalid amount %q (at most two decimals)", s)
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+
+	total, err := parseAmountMinorUnits(major+"."+fraction, 2)
+	if err != nil {
+		return 0, fmt.Errorf("money: amount %q is out of range", s)
+	}
+	if negative {
+		total = -total
+	}
+
+	return total, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return s != ""
+}
@@ -0,0 +1,213 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stremovskyy/go-platon/currency"
+)
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		minor int64
+		want  string
+	}{
+		{minor: 0, want: "0.00"},
+		{minor: 1, want: "0.01"},
+		{minor: 100, want: "1.00"},
+		{minor: 12345, want: "123.45"},
+		{minor: -101, want: "-1.01"},
+		{minor: 99999999, want: "999999.99"},
+		{minor: 9007199254740993, want: "90071992547409.93"},
+		{minor: math.MaxInt64, want: "92233720368547758.07"},
+		{minor: math.MinInt64, want: "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		if got := MoneyFromMinorUnits(tt.minor).String(); got != tt.want {
+			t.Fatalf("Money(%d).String() = %q, want %q", tt.minor, got, tt.want)
+		}
+	}
+}
+
+func TestMoney_Format(t *testing.T) {
+	tests := []struct {
+		minor int64
+		code  currency.Code
+		want  string
+	}{
+		{minor: 12345, code: currency.UAH, want: "123.45"},
+		{minor: 12345, code: currency.JPY, want: "12345"},
+		{minor: 600, code: currency.JPY, want: "600"},
+		{minor: 0, code: currency.USD, want: "0.00"},
+	}
+
+	for _, tt := range tests {
+		if got := MoneyFromMinorUnits(tt.minor).Format(tt.code); got != tt.want {
+			t.Fatalf("Money(%d).Format(%s) = %q, want %q", tt.minor, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestMoney_StringHasNoFloatDrift(t *testing.T) {
+	// float64 cannot represent these values exactly, so "%.2f" of minor/100
+	// formats them with the wrong cents.
+	for _, minor := range []int64{9007199254740993, 1<<53 + 1, 123456789012345679} {
+		money := MoneyFromMinorUnits(minor)
+		if float := fmt.Sprintf("%.2f", float64(minor)/100); float == money.String() {
+			t.Fatalf("expected float formatting of %d to drift, got %q for both", minor, float)
+		}
+
+		parsed, err := ParseMoney(money.String())
+		if err != nil {
+			t.Fatalf("ParseMoney(%q) error: %v", money.String(), err)
+		}
+		if parsed != money {
+			t.Fatalf("round trip mismatch: want %d, got %d", minor, parsed)
+		}
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: "0.01", want: 1},
+		{in: "123.45", want: 12345},
+		{in: "123.4", want: 12340},
+		{in: "123", want: 12300},
+		{in: " 999999.99 ", want: 99999999},
+		{in: "-1.01", want: -101},
+		{in: "21474836.48", want: math.MaxInt32 + 1},
+		{in: "90071992547409.93", want: 9007199254740993},
+		{in: "92233720368547758.07", want: math.MaxInt64},
+		{in: "", wantErr: true},
+		{in: ".50", wantErr: true},
+		{in: "1.", wantErr: true},
+		{in: "1.234", wantErr: true},
+		{in: "1,00", wantErr: true},
+		{in: "+1.00", wantErr: true},
+		{in: "92233720368547758.08", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("ParseMoney(%q) expected error, got %d", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ParseMoney(%q) error: %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Fatalf("ParseMoney(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRequest_MinorUnitBuildersUseExactFormatting(t *testing.T) {
+	req := NewRequest(ActionCodeSALE).
+		WithOrderAmountMinorUnits(99999999).
+		WithAmountMinorUnits(2147483647)
+
+	if req.OrderAmount != "999999.99" {
+		t.Fatalf("OrderAmount = %q, want %q", req.OrderAmount, "999999.99")
+	}
+	if req.Amount != "21474836.47" {
+		t.Fatalf("Amount = %q, want %q", req.Amount, "21474836.47")
+	}
+}
+
+func TestMoney_RoundTripBoundaries(t *testing.T) {
+	values := []int64{0, 1, 99, 100, math.MaxInt32, math.MaxInt32 + 1, 1 << 40, 1<<53 + 1, math.MaxInt64}
+
+	// A deterministic spread of values above 2^31 minor units.
+	seed := uint64(0x9e3779b97f4a7c15)
+	for i := 0; i < 1000; i++ {
+		seed ^= seed << 13
+		seed ^= seed >> 7
+		seed ^= seed << 17
+		values = append(values, int64(seed>>1))
+	}
+
+	for _, minor := range values {
+		amount := MoneyFromMinorUnits(minor)
+
+		parsed, err := ParseMoney(amount.String())
+		if err != nil {
+			t.Fatalf("ParseMoney(%q) error: %v", amount.String(), err)
+		}
+		if parsed != amount {
+			t.Fatalf("round trip of %d: got %d", minor, parsed)
+		}
+		if req := NewRequest(ActionCodeSALE).WithOrderAmountMinorUnits(int(minor)); req.OrderAmount != amount.String() {
+			t.Fatalf("WithOrderAmountMinorUnits(%d) = %q, want %q", minor, req.OrderAmount, amount.String())
+		}
+	}
+}
+
+func TestAmount_AddSub(t *testing.T) {
+	sum, err := MoneyFromMinorUnits(math.MaxInt32).Add(1)
+	if err != nil || sum.String() != "21474836.48" {
+		t.Fatalf("Add() = %s, %v", sum, err)
+	}
+	if diff, err := sum.Sub(MoneyFromMinorUnits(1)); err != nil || diff != math.MaxInt32 {
+		t.Fatalf("Sub() = %d, %v", diff, err)
+	}
+
+	if _, err := MoneyFromMinorUnits(math.MaxInt64).Add(1); err == nil {
+		t.Fatal("Add() expected overflow error")
+	}
+	if _, err := MoneyFromMinorUnits(math.MinInt64).Sub(1); err == nil {
+		t.Fatal("Sub() expected overflow error")
+	}
+	if _, err := MoneyFromMinorUnits(0).Sub(math.MinInt64); err == nil {
+		t.Fatal("Sub() expected overflow error")
+	}
+}
+
+func TestSplitRules_SplitEquals(t *testing.T) {
+	rules := SplitRules{"a": "21474836.47", "b": "0.01"}
+	if err := rules.SplitEquals(MoneyFromMinorUnits(math.MaxInt32 + 1)); err != nil {
+		t.Fatalf("SplitEquals() error: %v", err)
+	}
+
+	err := rules.SplitEquals(MoneyFromMinorUnits(math.MaxInt32 + 2))
+	if err == nil || err.Error() != "split rules total is 0.01 short of amount (21474836.48 != 21474836.49)" {
+		t.Fatalf("SplitEquals() short error = %v", err)
+	}
+
+	overflow := SplitRules{"a": "92233720368547758.07", "b": "0.01"}
+	if err := overflow.SplitEquals(MoneyFromMinorUnits(1)); err == nil {
+		t.Fatal("SplitEquals() expected overflow error")
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"net"
+	"strings"
+)
+
+// DefaultPayerIP is the payer_ip set by WithPayerIP when it is given a nil IP.
+// Use WithPayerIPStrict to make a missing payer IP fail SignAndPrepare instead.
+const DefaultPayerIP = "127.0.0.1"
+
+// NormalizeIP returns ip in canonical form: surrounding spaces and an IPv6
+// zone identifier ("%eth0") are removed and IPv6 hex digits are lowercased
+// and compressed. A value that is not an IP address is returned trimmed, so
+// payer_ip validation can reject it.
+func NormalizeIP(ip string) string {
+	ip = strings.TrimSpace(ip)
+	host := ip
+	if idx := strings.IndexByte(host, '%'); idx >= 0 {
+		host = host[:idx]
+	}
+
+	parsed := net.ParseIP(host)
+	if parsed == nil {
+		return ip
+	}
+
+	return parsed.String()
+}
@@ -0,0 +1,85 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeIP(t *testing.T) {
+	tests := map[string]string{
+		" 203.0.113.10 ":       "203.0.113.10",
+		"2001:DB8:0:0:0:0:0:1": "2001:db8::1",
+		"fe80::1%eth0":         "fe80::1",
+		"::ffff:198.51.100.7":  "198.51.100.7",
+		"not-an-ip":            "not-an-ip",
+		"2001:db8::zz%ignored": "2001:db8::zz%ignored",
+		"":                     "",
+	}
+	for in, want := range tests {
+		if got := NormalizeIP(in); got != want {
+			t.Fatalf("NormalizeIP(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSignAndPrepare_PayerIP(t *testing.T) {
+	newTokenSale := func(ip *string) *Request {
+		return newSignableTokenPayment().WithPayerIP(ip)
+	}
+
+	for in, want := range map[string]string{
+		"203.0.113.10":   "203.0.113.10",
+		"2001:DB8::1":    "2001:db8::1",
+		"fe80::abcd%en0": "fe80::abcd",
+	} {
+		ip := in
+		req, err := newTokenSale(&ip).SignAndPrepare()
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", in, err)
+		}
+		if *req.PayerIp != want {
+			t.Fatalf("%q: payer_ip = %q, want %q", in, *req.PayerIp, want)
+		}
+	}
+
+	invalid := "999.1.1.1"
+	if _, err := newTokenSale(&invalid).SignAndPrepare(); err == nil || !strings.Contains(err.Error(), "PayerIp") {
+		t.Fatalf("invalid IP: expected validation error, got %v", err)
+	}
+
+	req, err := newTokenSale(nil).SignAndPrepare()
+	if err != nil {
+		t.Fatalf("nil IP with default: unexpected error: %v", err)
+	}
+	if *req.PayerIp != "127.0.0.1" {
+		t.Fatalf("nil IP: payer_ip = %q, want DefaultPayerIP", *req.PayerIp)
+	}
+
+	if _, err := newSignableTokenPayment().WithPayerIPStrict(nil).SignAndPrepare(); err == nil || !strings.Contains(err.Error(), "payer_ip is required") {
+		t.Fatalf("nil IP with WithPayerIPStrict: expected payer_ip error, got %v", err)
+	}
+}
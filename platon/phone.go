@@ -0,0 +1,60 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import "strings"
+
+// NormalizePhone strips a leading "+", spaces and hyphens from phone, so that
+// "+380 63-123-45-67" becomes "380631234567". Other characters are kept and
+// left to the payer_phone validation.
+func NormalizePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+	phone = strings.TrimPrefix(phone, "+")
+
+	return strings.Map(
+		func(r rune) rune {
+			if r == ' ' || r == '-' {
+				return -1
+			}
+			return r
+		}, phone,
+	)
+}
+
+// validatePayerPhonePrefix rejects a payer_phone that does not start with one
+// of PayerPhonePrefixes. Any prefix is accepted when the list is empty.
+func (r *Request) validatePayerPhonePrefix() error {
+	if len(r.PayerPhonePrefixes) == 0 || r.PayerPhone == nil || *r.PayerPhone == "" {
+		return nil
+	}
+
+	for _, prefix := range r.PayerPhonePrefixes {
+		if strings.HasPrefix(*r.PayerPhone, NormalizePhone(prefix)) {
+			return nil
+		}
+	}
+
+	return NewValidationError("request", "payer_phone", "must start with one of "+strings.Join(r.PayerPhonePrefixes, ", "))
+}
@@ -0,0 +1,77 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := map[string]string{
+		"+380631234567":     "380631234567",
+		"380 63 123 45 67":  "380631234567",
+		" +48-22-123-45-67": "48221234567",
+		"abc":               "abc",
+	}
+	for in, want := range tests {
+		if got := NormalizePhone(in); got != want {
+			t.Fatalf("NormalizePhone(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRequest_PayerPhoneValidation(t *testing.T) {
+	newPayment := func(phone string) *Request {
+		return newSignableCardPayment().WithPayerPhone(&phone)
+	}
+
+	for _, phone := range []string{"+380631234567", "380 63 123 45 67"} {
+		req, err := newPayment(phone).SignAndPrepare()
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", phone, err)
+		}
+		if *req.PayerPhone != "380631234567" {
+			t.Fatalf("%q: payer_phone = %q, want 380631234567", phone, *req.PayerPhone)
+		}
+	}
+
+	if _, err := newPayment("call-me").SignAndPrepare(); err == nil || !strings.Contains(err.Error(), "PayerPhone") {
+		t.Fatalf("alphabetic phone: expected validation error, got %v", err)
+	}
+
+	if _, err := newPayment("+48 22 123 45 67").SignAndPrepare(); err != nil {
+		t.Fatalf("non-380 phone without prefixes: unexpected error: %v", err)
+	}
+	_, err := newPayment("+48 22 123 45 67").WithPayerPhonePrefixes("380").SignAndPrepare()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "payer_phone" {
+		t.Fatalf("prefix allow-list: expected payer_phone validation error, got %v", err)
+	}
+	if _, err := newPayment("+48 22 123 45 67").WithPayerPhonePrefixes("380", "+48").SignAndPrepare(); err != nil {
+		t.Fatalf("prefix allow-list with 48: unexpected error: %v", err)
+	}
+}
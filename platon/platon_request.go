@@ -30,10 +30,8 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
-	"strconv"
 	"strings"
-
-	"github.com/go-playground/validator/v10"
+	"unicode/utf8"
 
 	"github.com/stremovskyy/go-platon/log"
 )
@@ -42,14 +40,14 @@ var orderAmountRe = regexp.MustCompile("^[0-9]+\\.[0-9]{2}$")
 
 // Request represents the main payment request structure
 type Request struct {
-	Action           string  `json:"action" validate:"omitempty,oneof=SALE GET_TRANS_STATUS GET_TRANS_STATUS_BY_ORDER APPLEPAY GOOGLEPAY CAPTURE CREDITVOID CREDIT2CARD GET_SUBMERCHANT"`
+	Action           string  `json:"action" validate:"omitempty,oneof=SALE GET_TRANS_STATUS GET_TRANS_STATUS_BY_ORDER GET_TRANS_DETAILS APPLEPAY GOOGLEPAY CAPTURE CREDITVOID CREDIT2CARD GET_SUBMERCHANT"`
 	ClientKey        string  `json:"client_key" validate:"required"`
 	Hash             string  `json:"hash,omitempty" validate:"omitempty,len=32"`
 	ChannelId        string  `json:"channel_id,omitempty" validate:"omitempty,max=255"`
-	PayerIp          *string `json:"payer_ip,omitempty" validate:"omitempty,ipv4"`
+	PayerIp          *string `json:"payer_ip,omitempty" validate:"omitempty,ip"`
 	TermUrl3ds       *string `json:"term_url_3ds,omitempty" validate:"omitempty,max=1024,url"`
 	OrderID          *string `json:"order_id,omitempty" validate:"omitempty,max=255"`
-	OrderAmount      string  `json:"order_amount,omitempty" validate:"omitempty"`
+	OrderAmount      string  `json:"order_amount,omitempty" validate:"omitempty,platon_amount"`
 	OrderCurrency    string  `json:"order_currency,omitempty" validate:"omitempty,alpha,len=3"`
 	SubmerchantID    *string `json:"submerchant_id,omitempty" validate:"omitempty,max=255"`
 	OrderDescription *string `json:"order_description,omitempty" validate:"omitempty,max=1024"`
@@ -58,7 +56,7 @@ type Request struct {
 	PaymentToken *string `json:"payment_token,omitempty" validate:"omitempty"`
 
 	PayerEmail     *string `json:"payer_email,omitempty" validate:"omitempty,email,max=256"`
-	PayerPhone     *string `json:"payer_phone,omitempty" validate:"omitempty,numeric,max=32"`
+	PayerPhone     *string `json:"payer_phone,omitempty" validate:"omitempty,platon_phone"`
 	PayerFirstName *string `json:"payer_first_name,omitempty" validate:"omitempty,max=32"`
 	PayerLastName  *string `json:"payer_last_name,omitempty" validate:"omitempty,max=32"`
 	PayerAddress   *string `json:"payer_address,omitempty" validate:"omitempty,max=256"`
@@ -67,9 +65,9 @@ type Request struct {
 	PayerCity      *string `json:"payer_city,omitempty" validate:"omitempty,max=32"`
 	PayerZip       *string `json:"payer_zip,omitempty" validate:"omitempty,max=32"`
 	CustomerWallet *string `json:"customer_wallet,omitempty" validate:"omitempty,max=255"`
-	CardNumber     *string `json:"card_number,omitempty" validate:"omitempty,numeric,len=16"`
-	CardExpMonth   *string `json:"card_exp_month,omitempty" validate:"omitempty,numeric,len=2"`
-	CardExpYear    *string `json:"card_exp_year,omitempty" validate:"omitempty,numeric,len=4"`
+	CardNumber     *string `json:"card_number,omitempty" validate:"omitempty,numeric,min=13,max=19"`
+	CardExpMonth   *string `json:"card_exp_month,omitempty" validate:"omitempty,card_exp=month"`
+	CardExpYear    *string `json:"card_exp_year,omitempty" validate:"omitempty,card_exp=year"`
 	CardCvv2       *string `json:"card_cvv2,omitempty" validate:"omitempty,numeric,len=3"`
 	CardToken      *string `json:"card_token,omitempty" validate:"omitempty"`
 
@@ -83,7 +81,7 @@ type Request struct {
 	TransId *string `json:"trans_id,omitempty" validate:"omitempty,max=32"`
 
 	// CAPTURE / CREDITVOID amount.
-	Amount string `json:"amount,omitempty" validate:"omitempty"`
+	Amount string `json:"amount,omitempty" validate:"omitempty,platon_amount"`
 
 	// CREDITVOID: fast refund flag.
 	Immediately *string `json:"immediately,omitempty" validate:"omitempty,oneof=Y"`
@@ -106,12 +104,50 @@ type Request struct {
 	// Optional split distribution rules for SALE/CAPTURE/CREDITVOID.
 	SplitRules SplitRules `json:"split_rules,omitempty" validate:"omitempty"`
 
+	// SplitRemainderTo is the submerchant that absorbs a small difference
+	// between the split rules total and the amount. It is not sent to Platon.
+	SplitRemainderTo string `json:"-"`
+
+	// IdempotencyKey identifies retries of the same operation. It is not sent
+	// as a form field; the HTTP client derives a stable X-Request-ID from it.
+	IdempotencyKey string `json:"-"`
+
 	// HashEmail is an internal helper for signature generation for CAPTURE/CREDITVOID/GET_TRANS_STATUS.
 	// Per IA docs, it is not sent to Platon and may be empty if not specified in the initial payment.
 	HashEmail *string `json:"-"`
 
+	// OriginalAmount is the held amount in minor units. It is not sent to Platon;
+	// when set, CAPTURE validation rejects an amount above it.
+	OriginalAmount *int `json:"-"`
+
+	// PayerPhonePrefixes, when set, restricts payer_phone to numbers starting
+	// with one of the prefixes (e.g. "380"). It is not sent to Platon.
+	PayerPhonePrefixes []string `json:"-"`
+
+	// Clock supplies the current time for the card expiry check. It is not
+	// sent to Platon; a nil Clock uses SystemClock.
+	Clock Clock `json:"-"`
+
+	// SkipLuhn disables the card_number Luhn check, for sandbox test PANs that do not pass it.
+	SkipLuhn bool `json:"-"`
+
+	// CardNetwork is the wallet card network (e.g. from Google Pay). It is not
+	// sent to Platon; the HTTP client records it as the card_network tag.
+	CardNetwork string `json:"-"`
+
 	Auth     *Auth    `json:"-"`
 	HashType HashType `json:"-"`
+
+	// orderAmountMinorUnits and amountMinorUnits remember amounts set in minor
+	// units, so that ForCurrency can reformat them with the currency exponent.
+	orderAmountMinorUnits *Money
+	amountMinorUnits      *Money
+
+	// logSink receives the signature generators' debug logs (see WithLogSink).
+	logSink log.Sink
+
+	// buildErr keeps the first error reported by a builder method; it is returned by SignAndPrepare.
+	buildErr error
 }
 
 // NewPaymentRequest creates a new validated payment request
@@ -119,6 +155,13 @@ func (r *Request) SignAndPrepare() (*Request, error) {
 	if r == nil {
 		return nil, fmt.Errorf("request is nil")
 	}
+	if r.buildErr != nil {
+		return nil, fmt.Errorf("request build failed: %w", r.buildErr)
+	}
+	if err := r.validateUTF8(); err != nil {
+		return nil, err
+	}
+	r.applySplitRemainder()
 
 	var sign string
 	var err error
@@ -127,57 +170,57 @@ func (r *Request) SignAndPrepare() (*Request, error) {
 	case HashTypeVerification:
 		sign, err = r.generateCardPanSignature()
 		if err != nil {
-			return nil, fmt.Errorf("signature generation failed: %w", err)
+			return nil, signatureError(r.HashType, err)
 		}
 	case HashTypeCardPayment:
 		sign, err = r.generateCardPanSignature()
 		if err != nil {
-			return nil, fmt.Errorf("signature generation failed: %w", err)
+			return nil, signatureError(r.HashType, err)
 		}
 	case HashTypeCardTokenPayment:
 		sign, err = r.generateCardTokenSignature()
 		if err != nil {
-			return nil, fmt.Errorf("signature generation failed: %w", err)
+			return nil, signatureError(r.HashType, err)
 		}
 	case HashTypeApplePay, HashTypeGooglePay:
 		sign, err = r.generatePaymentTokenSignature()
 		if err != nil {
-			return nil, fmt.Errorf("signature generation failed: %w", err)
+			return nil, signatureError(r.HashType, err)
 		}
 	case HashTypeRecurring:
 		sign, err = r.generateRecurringSignature()
 		if err != nil {
-			return nil, fmt.Errorf("signature generation failed: %w", err)
+			return nil, signatureError(r.HashType, err)
 		}
-	case HashTypeGetTransStatus, HashTypeCapture, HashTypeCreditVoid:
+	case HashTypeGetTransStatus, HashTypeGetTransStatusA2C, HashTypeGetTransDetails, HashTypeCapture, HashTypeCreditVoid, HashTypeVoid:
 		sign, err = r.generateTransIDSignature()
 		if err != nil {
-			return nil, fmt.Errorf("signature generation failed: %w", err)
+			return nil, signatureError(r.HashType, err)
 		}
 	case HashTypeGetTransStatusByOrder:
 		sign, err = r.generateGetTransStatusByOrderSignature()
 		if err != nil {
-			return nil, fmt.Errorf("signature generation failed: %w", err)
+			return nil, signatureError(r.HashType, err)
 		}
 	case HashTypeGetTransStatusByOrderA2C:
 		sign, err = r.generateGetTransStatusByOrderA2CSignature()
 		if err != nil {
-			return nil, fmt.Errorf("signature generation failed: %w", err)
+			return nil, signatureError(r.HashType, err)
 		}
 	case HashTypeGetSubmerchant:
 		sign, err = r.generateGetSubmerchantSignature()
 		if err != nil {
-			return nil, fmt.Errorf("signature generation failed: %w", err)
+			return nil, signatureError(r.HashType, err)
 		}
 	case HashTypeCredit2Card:
 		sign, err = r.generateCredit2CardSignature()
 		if err != nil {
-			return nil, fmt.Errorf("signature generation failed: %w", err)
+			return nil, signatureError(r.HashType, err)
 		}
 	case HashTypeCredit2CardToken:
 		sign, err = r.generateCredit2CardTokenSignature()
 		if err != nil {
-			return nil, fmt.Errorf("signature generation failed: %w", err)
+			return nil, signatureError(r.HashType, err)
 		}
 	default:
 		return nil, fmt.Errorf("unknown hash type: %s", r.HashType)
@@ -188,10 +231,13 @@ func (r *Request) SignAndPrepare() (*Request, error) {
 	if err := r.validateByHashType(); err != nil {
 		return nil, err
 	}
+	if err := r.validatePayerPhonePrefix(); err != nil {
+		return nil, err
+	}
 
 	// Validate request
-	if err := validator.New().Struct(r); err != nil {
-		return nil, fmt.Errorf("internal request validation failed: %w", err)
+	if err := requestValidator().Struct(r); err != nil {
+		return nil, wrapValidationError("internal request validation failed", "", err)
 	}
 
 	return r, nil
@@ -207,9 +253,25 @@ func (r *Request) SignForAction(t HashType) *Request {
 	return r
 }
 
+// signatureLogger returns the logger used by the signature generators. They
+// never log the merchant secret or the string it is concatenated into.
+func (r *Request) signatureLogger(prefix string) *log.Logger {
+	return log.NewLogger(prefix).WithSink(r.logSink)
+}
+
+// maskSignature keeps the first 4 characters of a signature for correlating
+// log lines; the rest is masked like the hash field of logged forms.
+func maskSignature(signature string) string {
+	if len(signature) <= 4 {
+		return "***"
+	}
+
+	return signature[:4] + strings.Repeat("*", len(signature)-4)
+}
+
 func (r *Request) generateSignature(signArray []string) (string, error) {
 	// Create a logger instance with a custom prefix.
-	logger := log.NewLogger("PlatonSignature")
+	logger := r.signatureLogger("PlatonSignature")
 
 	logger.All("Generating signature with property keys: %v", signArray)
 
@@ -236,32 +298,25 @@ func (r *Request) generateSignature(signArray []string) (string, error) {
 			value = fieldValue
 		}
 
-		// Reverse the string value.
-		reversed := reverseString(value)
-
-		logger.All("Key '%s': original='%s', reversed='%s'", key, value, reversed)
-
-		concatenated += reversed
+		// Reverse the string value. Values are not logged: they include the
+		// merchant secret and card data.
+		concatenated += reverseString(value)
 	}
 
-	// Log the concatenated reversed string.
-	logger.All("Concatenated reversed string: %s", concatenated)
-
 	// Convert to uppercase.
 	upperConcatenated := strings.ToUpper(concatenated)
-	logger.All("Uppercased string: %s", upperConcatenated)
 
 	// Compute the MD5 hash.
 	hash := md5.Sum([]byte(upperConcatenated))
 	signature := hex.EncodeToString(hash[:])
-	logger.All("Generated MD5 signature: %s", signature)
+	logger.All("Generated MD5 signature: %s", maskSignature(signature))
 
 	return signature, nil
 }
 
 func (r *Request) generateCardPanSignature() (string, error) {
 	// Create a logger instance with a custom prefix
-	logger := log.NewLogger("CardPanSignature")
+	logger := r.signatureLogger("CardPanSignature")
 	logger.All("Generating signature for payment request")
 
 	// Validate required fields for hash generation
@@ -275,6 +330,10 @@ func (r *Request) generateCardPanSignature() (string, error) {
 		return "", fmt.Errorf("card_number is required for signature generation")
 	}
 
+	if err := r.checkPAN(string(r.HashType)); err != nil {
+		return "", err
+	}
+
 	cardFragment, err := signatureCardFragment(*r.CardNumber)
 	if err != nil {
 		return "", fmt.Errorf("card_number: %w", err)
@@ -293,18 +352,17 @@ func (r *Request) generateCardPanSignature() (string, error) {
 
 	// Convert to uppercase
 	upperConcatenated := strings.ToUpper(concatenated)
-	logger.All("Uppercased concatenated string: %s", upperConcatenated)
 
 	// Compute the MD5 hash
 	hash := md5.Sum([]byte(upperConcatenated))
 	signature := hex.EncodeToString(hash[:])
-	logger.All("Generated MD5 signature: %s", signature)
+	logger.All("Generated MD5 signature: %s", maskSignature(signature))
 
 	return signature, nil
 }
 
 func (r *Request) generateCardTokenSignature() (string, error) {
-	logger := log.NewLogger("CardTokenSignature")
+	logger := r.signatureLogger("CardTokenSignature")
 	logger.All("Generating signature for card_token request")
 
 	if r.PayerEmail == nil {
@@ -324,13 +382,13 @@ func (r *Request) generateCardTokenSignature() (string, error) {
 	upperConcatenated := strings.ToUpper(concatenated)
 	hash := md5.Sum([]byte(upperConcatenated))
 	signature := hex.EncodeToString(hash[:])
-	logger.All("Generated MD5 signature: %s", signature)
+	logger.All("Generated MD5 signature: %s", maskSignature(signature))
 
 	return signature, nil
 }
 
 func (r *Request) generatePaymentTokenSignature() (string, error) {
-	logger := log.NewLogger("PaymentTokenSignature")
+	logger := r.signatureLogger("PaymentTokenSignature")
 	logger.All("Generating signature for payment_token request")
 
 	if r.PayerEmail == nil {
@@ -350,7 +408,7 @@ func (r *Request) generatePaymentTokenSignature() (string, error) {
 	upperConcatenated := strings.ToUpper(concatenated)
 	hash := md5.Sum([]byte(upperConcatenated))
 	signature := hex.EncodeToString(hash[:])
-	logger.All("Generated MD5 signature: %s", signature)
+	logger.All("Generated MD5 signature: %s", maskSignature(signature))
 
 	return signature, nil
 }
@@ -361,7 +419,7 @@ func (r *Request) generateRecurringSignature() (string, error) {
 }
 
 func (r *Request) generateTransIDSignature() (string, error) {
-	logger := log.NewLogger("TransIDSignature")
+	logger := r.signatureLogger("TransIDSignature")
 	logger.All("Generating signature for trans_id based request")
 
 	if r.Auth == nil || r.Auth.Secret == "" {
@@ -388,13 +446,13 @@ func (r *Request) generateTransIDSignature() (string, error) {
 	upperConcatenated := strings.ToUpper(concatenated)
 	hash := md5.Sum([]byte(upperConcatenated))
 	signature := hex.EncodeToString(hash[:])
-	logger.All("Generated MD5 signature: %s", signature)
+	logger.All("Generated MD5 signature: %s", maskSignature(signature))
 
 	return signature, nil
 }
 
 func (r *Request) generateGetTransStatusByOrderSignature() (string, error) {
-	logger := log.NewLogger("GetTransStatusByOrderSignature")
+	logger := r.signatureLogger("GetTransStatusByOrderSignature")
 	logger.All("Generating signature for GET_TRANS_STATUS_BY_ORDER request")
 
 	if r.Auth == nil || r.Auth.Secret == "" {
@@ -409,13 +467,13 @@ func (r *Request) generateGetTransStatusByOrderSignature() (string, error) {
 	upperConcatenated := strings.ToUpper(concatenated)
 	hash := md5.Sum([]byte(upperConcatenated))
 	signature := hex.EncodeToString(hash[:])
-	logger.All("Generated MD5 signature: %s", signature)
+	logger.All("Generated MD5 signature: %s", maskSignature(signature))
 
 	return signature, nil
 }
 
 func (r *Request) generateGetTransStatusByOrderA2CSignature() (string, error) {
-	logger := log.NewLogger("GetTransStatusByOrderA2CSignature")
+	logger := r.signatureLogger("GetTransStatusByOrderA2CSignature")
 	logger.All("Generating signature for A2C GET_TRANS_STATUS_BY_ORDER request")
 
 	if r.Auth == nil || r.Auth.Secret == "" {
@@ -430,13 +488,13 @@ func (r *Request) generateGetTransStatusByOrderA2CSignature() (string, error) {
 	upperConcatenated := strings.ToUpper(concatenated)
 	hash := md5.Sum([]byte(upperConcatenated))
 	signature := hex.EncodeToString(hash[:])
-	logger.All("Generated MD5 signature: %s", signature)
+	logger.All("Generated MD5 signature: %s", maskSignature(signature))
 
 	return signature, nil
 }
 
 func (r *Request) generateGetSubmerchantSignature() (string, error) {
-	logger := log.NewLogger("GetSubmerchantSignature")
+	logger := r.signatureLogger("GetSubmerchantSignature")
 	logger.All("Generating signature for GET_SUBMERCHANT request")
 
 	if r.Auth == nil || r.Auth.Secret == "" {
@@ -452,13 +510,13 @@ func (r *Request) generateGetSubmerchantSignature() (string, error) {
 	upperConcatenated := strings.ToUpper(concatenated)
 	hash := md5.Sum([]byte(upperConcatenated))
 	signature := hex.EncodeToString(hash[:])
-	logger.All("Generated MD5 signature: %s", signature)
+	logger.All("Generated MD5 signature: %s", maskSignature(signature))
 
 	return signature, nil
 }
 
 func (r *Request) generateCredit2CardSignature() (string, error) {
-	logger := log.NewLogger("Credit2CardSignature")
+	logger := r.signatureLogger("Credit2CardSignature")
 	logger.All("Generating signature for CREDIT2CARD request by PAN")
 
 	if r.Auth == nil || r.Auth.Secret == "" {
@@ -468,10 +526,11 @@ func (r *Request) generateCredit2CardSignature() (string, error) {
 		return "", fmt.Errorf("card_number is required for signature generation")
 	}
 
-	cardNumber := *r.CardNumber
-	if len(cardNumber) < 10 {
-		return "", fmt.Errorf("card_number is too short")
+	if err := r.checkPAN("credit2card"); err != nil {
+		return "", err
 	}
+
+	cardNumber := *r.CardNumber
 	cardHashPart := cardNumber[0:6] + cardNumber[len(cardNumber)-4:]
 
 	reversedCardHash := reverseString(cardHashPart)
@@ -479,13 +538,13 @@ func (r *Request) generateCredit2CardSignature() (string, error) {
 	upperConcatenated := strings.ToUpper(concatenated)
 	hash := md5.Sum([]byte(upperConcatenated))
 	signature := hex.EncodeToString(hash[:])
-	logger.All("Generated MD5 signature: %s", signature)
+	logger.All("Generated MD5 signature: %s", maskSignature(signature))
 
 	return signature, nil
 }
 
 func (r *Request) generateCredit2CardTokenSignature() (string, error) {
-	logger := log.NewLogger("Credit2CardTokenSignature")
+	logger := r.signatureLogger("Credit2CardTokenSignature")
 	logger.All("Generating signature for CREDIT2CARD request by card token")
 
 	if r.Auth == nil || r.Auth.Secret == "" {
@@ -500,7 +559,7 @@ func (r *Request) generateCredit2CardTokenSignature() (string, error) {
 	upperConcatenated := strings.ToUpper(concatenated)
 	hash := md5.Sum([]byte(upperConcatenated))
 	signature := hex.EncodeToString(hash[:])
-	logger.All("Generated MD5 signature: %s", signature)
+	logger.All("Generated MD5 signature: %s", maskSignature(signature))
 
 	return signature, nil
 }
@@ -554,6 +613,41 @@ func (r *Request) ToMap() map[string]interface{} {
 	return requestMap
 }
 
+// validateUTF8 rejects string fields containing invalid UTF-8. Signatures
+// uppercase and hash these values, so corrupted input (e.g. Latin-1 bytes)
+// would otherwise surface as an opaque signature mismatch at the gateway.
+func (r *Request) validateUTF8() error {
+	v := reflect.ValueOf(*r)
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fieldValue := v.Field(i)
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		if fieldValue.Kind() != reflect.String || utf8.ValidString(fieldValue.String()) {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			name = field.Name
+		}
+
+		return NewValidationError("", name, "contains invalid UTF-8")
+	}
+
+	return nil
+}
+
 func (r *Request) validateByHashType() error {
 	switch r.HashType {
 	case HashTypeVerification:
@@ -566,67 +660,73 @@ func (r *Request) validateByHashType() error {
 		}
 
 		if r.Action != ActionCodeSALE.String() {
-			return fmt.Errorf("verification: action must be %s", ActionCodeSALE.String())
+			return NewValidationError("verification", "action", fmt.Sprintf("must be %s", ActionCodeSALE.String()))
 		}
 		if r.ChannelId != "VERIFY_ZERO" {
-			return fmt.Errorf("verification: channel_id must be VERIFY_ZERO")
+			return NewValidationError("verification", "channel_id", "must be VERIFY_ZERO")
 		}
 		if r.OrderAmount != VerifyNoAmount.String() {
-			return fmt.Errorf("verification: order_amount must be %s", VerifyNoAmount.String())
+			return NewValidationError("verification", "order_amount", fmt.Sprintf("must be %s", VerifyNoAmount.String()))
 		}
 		if r.OrderID == nil || *r.OrderID == "" {
-			return fmt.Errorf("verification: order_id is required")
+			return NewValidationError("verification", "order_id", "is required")
 		}
 		// if len(*r.OrderID) > 32 {
-		// 	return fmt.Errorf("verification: order_id must be <= 32 characters")
+		// 	return NewValidationError("verification", "order_id", "must be <= 32 characters")
 		// }
 		if r.OrderCurrency == "" {
-			return fmt.Errorf("verification: order_currency is required")
+			return NewValidationError("verification", "order_currency", "is required")
 		}
 		if r.OrderDescription == nil || *r.OrderDescription == "" {
-			return fmt.Errorf("verification: order_description is required")
+			return NewValidationError("verification", "order_description", "is required")
 		}
 		if len(*r.OrderDescription) > 255 {
-			return fmt.Errorf("verification: order_description must be <= 255 characters")
+			return NewValidationError("verification", "order_description", "must be <= 255 characters")
 		}
 		if r.PayerIp == nil || *r.PayerIp == "" {
-			return fmt.Errorf("verification: payer_ip is required")
+			return NewValidationError("verification", "payer_ip", "is required")
 		}
 		if r.TermUrl3ds == nil || *r.TermUrl3ds == "" {
-			return fmt.Errorf("verification: term_url_3ds is required")
+			return NewValidationError("verification", "term_url_3ds", "is required")
 		}
 		if len(*r.TermUrl3ds) > 255 {
-			return fmt.Errorf("verification: term_url_3ds must be <= 255 characters")
+			return NewValidationError("verification", "term_url_3ds", "must be <= 255 characters")
 		}
 		if r.PayerEmail == nil || *r.PayerEmail == "" {
-			return fmt.Errorf("verification: payer_email is required")
+			return NewValidationError("verification", "payer_email", "is required")
 		}
 		if r.PayerPhone == nil || *r.PayerPhone == "" {
-			return fmt.Errorf("verification: payer_phone is required")
+			return NewValidationError("verification", "payer_phone", "is required")
 		}
 		if r.CardNumber == nil || *r.CardNumber == "" {
-			return fmt.Errorf("verification: card_number is required")
+			return NewValidationError("verification", "card_number", "is required")
+		}
+		if err := r.checkPAN("verification"); err != nil {
+			return err
 		}
 		if r.CardExpMonth == nil || *r.CardExpMonth == "" {
-			return fmt.Errorf("verification: card_exp_month is required")
+			return NewValidationError("verification", "card_exp_month", "is required")
 		}
 		if r.CardExpYear == nil || *r.CardExpYear == "" {
-			return fmt.Errorf("verification: card_exp_year is required")
+			return NewValidationError("verification", "card_exp_year", "is required")
+		}
+		if err := r.validateCardExpiry("verification"); err != nil {
+			return err
 		}
 		if r.CardCvv2 == nil || *r.CardCvv2 == "" {
-			return fmt.Errorf("verification: card_cvv2 is required")
+			return NewValidationError("verification", "card_cvv2", "is required")
 		}
 		if r.ReqToken == nil || *r.ReqToken == "" {
-			return fmt.Errorf("verification: req_token is required")
+			return NewValidationError("verification", "req_token", "is required")
 		}
 		if *r.ReqToken != "Y" {
-			return fmt.Errorf("verification: req_token must be Y")
+			return NewValidationError("verification", "req_token", "must be Y")
 		}
 		if r.RecurringInit == nil || *r.RecurringInit == "" {
-			return fmt.Errorf("verification: recurring_init is required")
+			return NewValidationError("verification", "recurring_init", "is required")
 		}
 		if *r.RecurringInit != "Y" {
-			return fmt.Errorf("verification: recurring_init must be Y")
+			return NewValidationError("verification", "recurring_init", "must be Y")
 		}
 
 	case HashTypeCardPayment:
@@ -639,433 +739,457 @@ func (r *Request) validateByHashType() error {
 		}
 
 		if r.Action != ActionCodeSALE.String() {
-			return fmt.Errorf("card_payment: action must be %s", ActionCodeSALE.String())
+			return NewValidationError("card_payment", "action", fmt.Sprintf("must be %s", ActionCodeSALE.String()))
 		}
 		if r.OrderID == nil || *r.OrderID == "" {
-			return fmt.Errorf("card_payment: order_id is required")
+			return NewValidationError("card_payment", "order_id", "is required")
 		}
 		// if len(*r.OrderID) > 32 {
-		// 	return fmt.Errorf("card_payment: order_id must be <= 32 characters")
+		// 	return NewValidationError("card_payment", "order_id", "must be <= 32 characters")
 		// }
 		if r.OrderAmount == "" {
-			return fmt.Errorf("card_payment: order_amount is required")
+			return NewValidationError("card_payment", "order_amount", "is required")
 		}
-		if !orderAmountRe.MatchString(r.OrderAmount) {
-			return fmt.Errorf("card_payment: order_amount must match %q (got %q)", orderAmountRe.String(), r.OrderAmount)
-		}
-		if v, err := parseOrderAmountMinorUnits(r.OrderAmount); err != nil || v <= 0 {
-			return fmt.Errorf("card_payment: order_amount must be > 0 (got %q)", r.OrderAmount)
+		if err := validateCurrencyAmount("card_payment", "order_amount", r.OrderAmount, r.OrderCurrency); err != nil {
+			return err
 		}
-		if err := validateSplitRules(r.SplitRules, r.OrderAmount, "card_payment"); err != nil {
+		if err := validateSplitRules(r.SplitRules, r.OrderAmount, r.OrderCurrency, "card_payment"); err != nil {
 			return err
 		}
 		if r.OrderCurrency == "" {
-			return fmt.Errorf("card_payment: order_currency is required")
+			return NewValidationError("card_payment", "order_currency", "is required")
 		}
 		if r.OrderDescription == nil || *r.OrderDescription == "" {
-			return fmt.Errorf("card_payment: order_description is required")
+			return NewValidationError("card_payment", "order_description", "is required")
 		}
 		if len(*r.OrderDescription) > 255 {
-			return fmt.Errorf("card_payment: order_description must be <= 255 characters")
+			return NewValidationError("card_payment", "order_description", "must be <= 255 characters")
 		}
 		if r.PayerIp == nil || *r.PayerIp == "" {
-			return fmt.Errorf("card_payment: payer_ip is required")
+			return NewValidationError("card_payment", "payer_ip", "is required")
 		}
 		if r.TermUrl3ds == nil || *r.TermUrl3ds == "" {
-			return fmt.Errorf("card_payment: term_url_3ds is required")
+			return NewValidationError("card_payment", "term_url_3ds", "is required")
 		}
 		if len(*r.TermUrl3ds) > 255 {
-			return fmt.Errorf("card_payment: term_url_3ds must be <= 255 characters")
+			return NewValidationError("card_payment", "term_url_3ds", "must be <= 255 characters")
 		}
 		if r.PayerEmail == nil || *r.PayerEmail == "" {
-			return fmt.Errorf("card_payment: payer_email is required")
+			return NewValidationError("card_payment", "payer_email", "is required")
 		}
 		if r.PayerPhone == nil || *r.PayerPhone == "" {
-			return fmt.Errorf("card_payment: payer_phone is required")
+			return NewValidationError("card_payment", "payer_phone", "is required")
 		}
 		if r.CardNumber == nil || *r.CardNumber == "" {
-			return fmt.Errorf("card_payment: card_number is required")
+			return NewValidationError("card_payment", "card_number", "is required")
+		}
+		if err := r.checkPAN("card_payment"); err != nil {
+			return err
 		}
 		if r.CardExpMonth == nil || *r.CardExpMonth == "" {
-			return fmt.Errorf("card_payment: card_exp_month is required")
+			return NewValidationError("card_payment", "card_exp_month", "is required")
 		}
 		if r.CardExpYear == nil || *r.CardExpYear == "" {
-			return fmt.Errorf("card_payment: card_exp_year is required")
+			return NewValidationError("card_payment", "card_exp_year", "is required")
+		}
+		if err := r.validateCardExpiry("card_payment"); err != nil {
+			return err
 		}
 		if r.CardCvv2 == nil || *r.CardCvv2 == "" {
-			return fmt.Errorf("card_payment: card_cvv2 is required")
+			return NewValidationError("card_payment", "card_cvv2", "is required")
 		}
 		if r.ReqToken == nil || *r.ReqToken == "" {
-			return fmt.Errorf("card_payment: req_token is required")
+			return NewValidationError("card_payment", "req_token", "is required")
 		}
 		if r.RecurringInit == nil || *r.RecurringInit == "" {
-			return fmt.Errorf("card_payment: recurring_init is required")
+			return NewValidationError("card_payment", "recurring_init", "is required")
 		}
 
 	case HashTypeCardTokenPayment:
 		if r.Action != ActionCodeSALE.String() {
-			return fmt.Errorf("card_token_payment: action must be %s", ActionCodeSALE.String())
+			return NewValidationError("card_token_payment", "action", fmt.Sprintf("must be %s", ActionCodeSALE.String()))
 		}
 		if r.CardToken == nil || *r.CardToken == "" {
-			return fmt.Errorf("card_token_payment: card_token is required")
+			return NewValidationError("card_token_payment", "card_token", "is required")
 		}
 		if r.OrderID == nil || *r.OrderID == "" {
-			return fmt.Errorf("card_token_payment: order_id is required")
+			return NewValidationError("card_token_payment", "order_id", "is required")
 		}
 		// if len(*r.OrderID) > 32 {
-		// 	return fmt.Errorf("card_token_payment: order_id must be <= 32 characters")
+		// 	return NewValidationError("card_token_payment", "order_id", "must be <= 32 characters")
 		// }
 		if r.OrderAmount == "" {
-			return fmt.Errorf("card_token_payment: order_amount is required")
+			return NewValidationError("card_token_payment", "order_amount", "is required")
 		}
-		if !orderAmountRe.MatchString(r.OrderAmount) {
-			return fmt.Errorf("card_token_payment: order_amount must match %q (got %q)", orderAmountRe.String(), r.OrderAmount)
-		}
-		if v, err := parseOrderAmountMinorUnits(r.OrderAmount); err != nil || v <= 0 {
-			return fmt.Errorf("card_token_payment: order_amount must be > 0 (got %q)", r.OrderAmount)
+		if err := validateCurrencyAmount("card_token_payment", "order_amount", r.OrderAmount, r.OrderCurrency); err != nil {
+			return err
 		}
-		if err := validateSplitRules(r.SplitRules, r.OrderAmount, "card_token_payment"); err != nil {
+		if err := validateSplitRules(r.SplitRules, r.OrderAmount, r.OrderCurrency, "card_token_payment"); err != nil {
 			return err
 		}
 		if r.OrderCurrency == "" {
-			return fmt.Errorf("card_token_payment: order_currency is required")
+			return NewValidationError("card_token_payment", "order_currency", "is required")
 		}
 		if r.OrderDescription == nil || *r.OrderDescription == "" {
-			return fmt.Errorf("card_token_payment: order_description is required")
+			return NewValidationError("card_token_payment", "order_description", "is required")
 		}
 		if len(*r.OrderDescription) > 255 {
-			return fmt.Errorf("card_token_payment: order_description must be <= 255 characters")
+			return NewValidationError("card_token_payment", "order_description", "must be <= 255 characters")
 		}
 		if r.PayerIp == nil || *r.PayerIp == "" {
-			return fmt.Errorf("card_token_payment: payer_ip is required")
+			return NewValidationError("card_token_payment", "payer_ip", "is required")
 		}
 		if r.TermUrl3ds == nil || *r.TermUrl3ds == "" {
-			return fmt.Errorf("card_token_payment: term_url_3ds is required")
+			return NewValidationError("card_token_payment", "term_url_3ds", "is required")
 		}
 		if len(*r.TermUrl3ds) > 255 {
-			return fmt.Errorf("card_token_payment: term_url_3ds must be <= 255 characters")
+			return NewValidationError("card_token_payment", "term_url_3ds", "must be <= 255 characters")
 		}
 		if r.PayerEmail == nil || *r.PayerEmail == "" {
-			return fmt.Errorf("card_token_payment: payer_email is required")
+			return NewValidationError("card_token_payment", "payer_email", "is required")
 		}
 
 	case HashTypeApplePay:
 		if r.Action != ActionCodeAPPLEPAY.String() {
-			return fmt.Errorf("apple_pay: action must be %s", ActionCodeAPPLEPAY.String())
+			return NewValidationError("apple_pay", "action", fmt.Sprintf("must be %s", ActionCodeAPPLEPAY.String()))
 		}
 		if r.PaymentToken == nil || *r.PaymentToken == "" {
-			return fmt.Errorf("apple_pay: payment_token is required")
+			return NewValidationError("apple_pay", "payment_token", "is required")
 		}
 		if r.OrderID == nil || *r.OrderID == "" {
-			return fmt.Errorf("apple_pay: order_id is required")
+			return NewValidationError("apple_pay", "order_id", "is required")
 		}
 		if len(*r.OrderID) > 255 {
-			return fmt.Errorf("apple_pay: order_id must be <= 255 characters")
+			return NewValidationError("apple_pay", "order_id", "must be <= 255 characters")
 		}
 		if r.OrderAmount == "" {
-			return fmt.Errorf("apple_pay: order_amount is required")
+			return NewValidationError("apple_pay", "order_amount", "is required")
 		}
-		if !orderAmountRe.MatchString(r.OrderAmount) {
-			return fmt.Errorf("apple_pay: order_amount must match %q (got %q)", orderAmountRe.String(), r.OrderAmount)
-		}
-		if v, err := parseOrderAmountMinorUnits(r.OrderAmount); err != nil || v <= 0 {
-			return fmt.Errorf("apple_pay: order_amount must be > 0 (got %q)", r.OrderAmount)
+		if err := validateCurrencyAmount("apple_pay", "order_amount", r.OrderAmount, r.OrderCurrency); err != nil {
+			return err
 		}
-		if err := validateSplitRules(r.SplitRules, r.OrderAmount, "apple_pay"); err != nil {
+		if err := validateSplitRules(r.SplitRules, r.OrderAmount, r.OrderCurrency, "apple_pay"); err != nil {
 			return err
 		}
 		if r.OrderCurrency == "" {
-			return fmt.Errorf("apple_pay: order_currency is required")
+			return NewValidationError("apple_pay", "order_currency", "is required")
 		}
 		if r.OrderDescription == nil || *r.OrderDescription == "" {
-			return fmt.Errorf("apple_pay: order_description is required")
+			return NewValidationError("apple_pay", "order_description", "is required")
 		}
 		if len(*r.OrderDescription) > 1024 {
-			return fmt.Errorf("apple_pay: order_description must be <= 1024 characters")
+			return NewValidationError("apple_pay", "order_description", "must be <= 1024 characters")
 		}
 		if r.PayerIp == nil || *r.PayerIp == "" {
-			return fmt.Errorf("apple_pay: payer_ip is required")
+			return NewValidationError("apple_pay", "payer_ip", "is required")
 		}
 		if r.TermUrl3ds == nil || *r.TermUrl3ds == "" {
-			return fmt.Errorf("apple_pay: term_url_3ds is required")
+			return NewValidationError("apple_pay", "term_url_3ds", "is required")
 		}
 		if len(*r.TermUrl3ds) > 1024 {
-			return fmt.Errorf("apple_pay: term_url_3ds must be <= 1024 characters")
+			return NewValidationError("apple_pay", "term_url_3ds", "must be <= 1024 characters")
 		}
 		if r.PayerEmail == nil || *r.PayerEmail == "" {
-			return fmt.Errorf("apple_pay: payer_email is required")
+			return NewValidationError("apple_pay", "payer_email", "is required")
 		}
 		if r.PayerPhone == nil || *r.PayerPhone == "" {
-			return fmt.Errorf("apple_pay: payer_phone is required")
+			return NewValidationError("apple_pay", "payer_phone", "is required")
 		}
 
 	case HashTypeGooglePay:
 		if r.Action != ActionCodeGOOGLEPAY.String() {
-			return fmt.Errorf("google_pay: action must be %s", ActionCodeGOOGLEPAY.String())
+			return NewValidationError("google_pay", "action", fmt.Sprintf("must be %s", ActionCodeGOOGLEPAY.String()))
 		}
 		if r.PaymentToken == nil || *r.PaymentToken == "" {
-			return fmt.Errorf("google_pay: payment_token is required")
+			return NewValidationError("google_pay", "payment_token", "is required")
 		}
 		if r.OrderID == nil || *r.OrderID == "" {
-			return fmt.Errorf("google_pay: order_id is required")
+			return NewValidationError("google_pay", "order_id", "is required")
 		}
 		if len(*r.OrderID) > 255 {
-			return fmt.Errorf("google_pay: order_id must be <= 255 characters")
+			return NewValidationError("google_pay", "order_id", "must be <= 255 characters")
 		}
 		if r.OrderAmount == "" {
-			return fmt.Errorf("google_pay: order_amount is required")
+			return NewValidationError("google_pay", "order_amount", "is required")
 		}
-		if !orderAmountRe.MatchString(r.OrderAmount) {
-			return fmt.Errorf("google_pay: order_amount must match %q (got %q)", orderAmountRe.String(), r.OrderAmount)
-		}
-		if v, err := parseOrderAmountMinorUnits(r.OrderAmount); err != nil || v <= 0 {
-			return fmt.Errorf("google_pay: order_amount must be > 0 (got %q)", r.OrderAmount)
+		if err := validateCurrencyAmount("google_pay", "order_amount", r.OrderAmount, r.OrderCurrency); err != nil {
+			return err
 		}
-		if err := validateSplitRules(r.SplitRules, r.OrderAmount, "google_pay"); err != nil {
+		if err := validateSplitRules(r.SplitRules, r.OrderAmount, r.OrderCurrency, "google_pay"); err != nil {
 			return err
 		}
 		if r.OrderCurrency == "" {
-			return fmt.Errorf("google_pay: order_currency is required")
+			return NewValidationError("google_pay", "order_currency", "is required")
 		}
 		if r.OrderDescription == nil || *r.OrderDescription == "" {
-			return fmt.Errorf("google_pay: order_description is required")
+			return NewValidationError("google_pay", "order_description", "is required")
 		}
 		if len(*r.OrderDescription) > 255 {
-			return fmt.Errorf("google_pay: order_description must be <= 255 characters")
+			return NewValidationError("google_pay", "order_description", "must be <= 255 characters")
 		}
 		if r.PayerIp == nil || *r.PayerIp == "" {
-			return fmt.Errorf("google_pay: payer_ip is required")
+			return NewValidationError("google_pay", "payer_ip", "is required")
 		}
 		if r.TermUrl3ds == nil || *r.TermUrl3ds == "" {
-			return fmt.Errorf("google_pay: term_url_3ds is required")
+			return NewValidationError("google_pay", "term_url_3ds", "is required")
 		}
 		if len(*r.TermUrl3ds) > 255 {
-			return fmt.Errorf("google_pay: term_url_3ds must be <= 255 characters")
+			return NewValidationError("google_pay", "term_url_3ds", "must be <= 255 characters")
 		}
 		if r.PayerEmail == nil || *r.PayerEmail == "" {
-			return fmt.Errorf("google_pay: payer_email is required")
+			return NewValidationError("google_pay", "payer_email", "is required")
 		}
 		if r.PayerPhone == nil || *r.PayerPhone == "" {
-			return fmt.Errorf("google_pay: payer_phone is required")
+			return NewValidationError("google_pay", "payer_phone", "is required")
 		}
 
 	case HashTypeRecurring:
 		if r.Action != ActionCodeSALE.String() {
-			return fmt.Errorf("recurring: action must be %s", ActionCodeSALE.String())
+			return NewValidationError("recurring", "action", fmt.Sprintf("must be %s", ActionCodeSALE.String()))
 		}
 		if r.CardToken == nil || *r.CardToken == "" {
-			return fmt.Errorf("recurring: card_token is required")
+			return NewValidationError("recurring", "card_token", "is required")
 		}
 		if r.Ext3 == nil || *r.Ext3 != "recurring" {
-			return fmt.Errorf("recurring: ext3 must be \"recurring\"")
+			return NewValidationError("recurring", "ext3", "must be \"recurring\"")
 		}
 		if r.OrderID == nil || *r.OrderID == "" {
-			return fmt.Errorf("recurring: order_id is required")
+			return NewValidationError("recurring", "order_id", "is required")
 		}
 		// if len(*r.OrderID) > 32 {
-		// 	return fmt.Errorf("recurring: order_id must be <= 32 characters")
+		// 	return NewValidationError("recurring", "order_id", "must be <= 32 characters")
 		// }
 		if r.OrderAmount == "" {
-			return fmt.Errorf("recurring: order_amount is required")
+			return NewValidationError("recurring", "order_amount", "is required")
 		}
-		if !orderAmountRe.MatchString(r.OrderAmount) {
-			return fmt.Errorf("recurring: order_amount must match %q (got %q)", orderAmountRe.String(), r.OrderAmount)
-		}
-		if v, err := parseOrderAmountMinorUnits(r.OrderAmount); err != nil || v <= 0 {
-			return fmt.Errorf("recurring: order_amount must be > 0 (got %q)", r.OrderAmount)
+		if err := validateCurrencyAmount("recurring", "order_amount", r.OrderAmount, r.OrderCurrency); err != nil {
+			return err
 		}
-		if err := validateSplitRules(r.SplitRules, r.OrderAmount, "recurring"); err != nil {
+		if err := validateSplitRules(r.SplitRules, r.OrderAmount, r.OrderCurrency, "recurring"); err != nil {
 			return err
 		}
 		if r.OrderCurrency == "" {
-			return fmt.Errorf("recurring: order_currency is required")
+			return NewValidationError("recurring", "order_currency", "is required")
 		}
 		if r.OrderDescription == nil || *r.OrderDescription == "" {
-			return fmt.Errorf("recurring: order_description is required")
+			return NewValidationError("recurring", "order_description", "is required")
 		}
 		if len(*r.OrderDescription) > 255 {
-			return fmt.Errorf("recurring: order_description must be <= 255 characters")
+			return NewValidationError("recurring", "order_description", "must be <= 255 characters")
 		}
 		if r.PayerIp == nil || *r.PayerIp == "" {
-			return fmt.Errorf("recurring: payer_ip is required")
+			return NewValidationError("recurring", "payer_ip", "is required")
 		}
 		if r.TermUrl3ds == nil || *r.TermUrl3ds == "" {
-			return fmt.Errorf("recurring: term_url_3ds is required")
+			return NewValidationError("recurring", "term_url_3ds", "is required")
 		}
 		if len(*r.TermUrl3ds) > 255 {
-			return fmt.Errorf("recurring: term_url_3ds must be <= 255 characters")
+			return NewValidationError("recurring", "term_url_3ds", "must be <= 255 characters")
 		}
 		if r.PayerEmail == nil || *r.PayerEmail == "" {
-			return fmt.Errorf("recurring: payer_email is required")
+			return NewValidationError("recurring", "payer_email", "is required")
 		}
 
 	case HashTypeGetTransStatus:
+		fallthrough
+	case HashTypeGetTransStatusA2C:
 		if r.Action != ActionCodeGetTransStatus.String() {
-			return fmt.Errorf("get_trans_status: action must be %s", ActionCodeGetTransStatus.String())
+			return NewValidationError("get_trans_status", "action", fmt.Sprintf("must be %s", ActionCodeGetTransStatus.String()))
 		}
 		if r.TransId == nil || *r.TransId == "" {
-			return fmt.Errorf("get_trans_status: trans_id is required")
+			return NewValidationError("get_trans_status", "trans_id", "is required")
+		}
+
+	case HashTypeGetTransDetails:
+		if r.Action != ActionCodeGetTransDetails.String() {
+			return NewValidationError("get_trans_details", "action", fmt.Sprintf("must be %s", ActionCodeGetTransDetails.String()))
+		}
+		if r.TransId == nil || *r.TransId == "" {
+			return NewValidationError("get_trans_details", "trans_id", "is required")
 		}
 
 	case HashTypeGetTransStatusByOrder:
 		fallthrough
 	case HashTypeGetTransStatusByOrderA2C:
 		if r.Action != ActionCodeGetTransStatusByOrder.String() {
-			return fmt.Errorf("get_trans_status_by_order: action must be %s", ActionCodeGetTransStatusByOrder.String())
+			return NewValidationError("get_trans_status_by_order", "action", fmt.Sprintf("must be %s", ActionCodeGetTransStatusByOrder.String()))
 		}
 		if r.OrderID == nil || strings.TrimSpace(*r.OrderID) == "" {
-			return fmt.Errorf("get_trans_status_by_order: order_id is required")
+			return NewValidationError("get_trans_status_by_order", "order_id", "is required")
 		}
 
 	case HashTypeCapture:
 		if r.Action != ActionCodeCAPTURE.String() {
-			return fmt.Errorf("capture: action must be %s", ActionCodeCAPTURE.String())
+			return NewValidationError("capture", "action", fmt.Sprintf("must be %s", ActionCodeCAPTURE.String()))
 		}
 		if r.TransId == nil || *r.TransId == "" {
-			return fmt.Errorf("capture: trans_id is required")
+			return NewValidationError("capture", "trans_id", "is required")
 		}
 		if r.Amount == "" {
-			return fmt.Errorf("capture: amount is required")
+			return NewValidationError("capture", "amount", "is required")
 		}
-		if !orderAmountRe.MatchString(r.Amount) {
-			return fmt.Errorf("capture: amount must match %q (got %q)", orderAmountRe.String(), r.Amount)
+		if err := validateCurrencyAmount("capture", "amount", r.Amount, r.OrderCurrency); err != nil {
+			return err
 		}
-		if v, err := parseOrderAmountMinorUnits(r.Amount); err != nil || v <= 0 {
-			return fmt.Errorf("capture: amount must be > 0 (got %q)", r.Amount)
+		if v, _ := parseAmountMinorUnits(r.Amount, currencyFractionDigits(r.OrderCurrency)); r.OriginalAmount != nil && v > Money(*r.OriginalAmount) {
+			return NewValidationError("capture", "amount", fmt.Sprintf("%d exceeds original amount %d (minor units)", v, *r.OriginalAmount))
 		}
-		if err := validateSplitRules(r.SplitRules, r.Amount, "capture"); err != nil {
+		if err := validateSplitRules(r.SplitRules, r.Amount, r.OrderCurrency, "capture"); err != nil {
 			return err
 		}
 
 	case HashTypeCreditVoid:
 		if r.Action != ActionCodeCREDITVOID.String() {
-			return fmt.Errorf("creditvoid: action must be %s", ActionCodeCREDITVOID.String())
+			return NewValidationError("creditvoid", "action", fmt.Sprintf("must be %s", ActionCodeCREDITVOID.String()))
 		}
 		if r.TransId == nil || *r.TransId == "" {
-			return fmt.Errorf("creditvoid: trans_id is required")
+			return NewValidationError("creditvoid", "trans_id", "is required")
 		}
 		if r.Amount == "" {
-			return fmt.Errorf("creditvoid: amount is required")
-		}
-		if !orderAmountRe.MatchString(r.Amount) {
-			return fmt.Errorf("creditvoid: amount must match %q (got %q)", orderAmountRe.String(), r.Amount)
+			return NewValidationError("creditvoid", "amount", "is required")
 		}
-		if v, err := parseOrderAmountMinorUnits(r.Amount); err != nil || v <= 0 {
-			return fmt.Errorf("creditvoid: amount must be > 0 (got %q)", r.Amount)
+		if err := validateCurrencyAmount("creditvoid", "amount", r.Amount, r.OrderCurrency); err != nil {
+			return err
 		}
-		if err := validateSplitRules(r.SplitRules, r.Amount, "creditvoid"); err != nil {
+		if err := validateSplitRules(r.SplitRules, r.Amount, r.OrderCurrency, "creditvoid"); err != nil {
 			return err
 		}
 
+	case HashTypeVoid:
+		if r.Action != ActionCodeCREDITVOID.String() {
+			return NewValidationError("void", "action", fmt.Sprintf("must be %s", ActionCodeCREDITVOID.String()))
+		}
+		if r.TransId == nil || *r.TransId == "" {
+			return NewValidationError("void", "trans_id", "is required")
+		}
+		if r.Amount != "" {
+			return NewValidationError("void", "amount", fmt.Sprintf("must be empty (use %s for refunds)", HashTypeCreditVoid))
+		}
+		if len(r.SplitRules) > 0 {
+			return NewValidationError("void", "split_rules", "are not allowed")
+		}
+
 	case HashTypeCredit2Card:
 		if r.Action != ActionCodeCREDIT2CARD.String() {
-			return fmt.Errorf("credit2card: action must be %s", ActionCodeCREDIT2CARD.String())
+			return NewValidationError("credit2card", "action", fmt.Sprintf("must be %s", ActionCodeCREDIT2CARD.String()))
 		}
 		if r.CardNumber == nil || *r.CardNumber == "" {
-			return fmt.Errorf("credit2card: card_number is required")
+			return NewValidationError("credit2card", "card_number", "is required")
+		}
+		if err := r.checkPAN("credit2card"); err != nil {
+			return err
+		}
+		if err := r.validateCardExpiry("credit2card"); err != nil {
+			return err
 		}
 		if r.OrderID == nil || *r.OrderID == "" {
-			return fmt.Errorf("credit2card: order_id is required")
+			return NewValidationError("credit2card", "order_id", "is required")
 		}
 		if r.Amount == "" {
-			return fmt.Errorf("credit2card: amount is required")
+			return NewValidationError("credit2card", "amount", "is required")
 		}
-		if !orderAmountRe.MatchString(r.Amount) {
-			return fmt.Errorf("credit2card: amount must match %q (got %q)", orderAmountRe.String(), r.Amount)
-		}
-		if v, err := parseOrderAmountMinorUnits(r.Amount); err != nil || v <= 0 {
-			return fmt.Errorf("credit2card: amount must be > 0 (got %q)", r.Amount)
+		if err := validateCurrencyAmount("credit2card", "amount", r.Amount, r.OrderCurrency); err != nil {
+			return err
 		}
 		if r.OrderCurrency == "" {
-			return fmt.Errorf("credit2card: order_currency is required")
+			return NewValidationError("credit2card", "order_currency", "is required")
 		}
 		if r.OrderDescription == nil || strings.TrimSpace(*r.OrderDescription) == "" {
-			return fmt.Errorf("credit2card: order_description is required")
+			return NewValidationError("credit2card", "order_description", "is required")
 		}
 		if r.PayerFirstName == nil || strings.TrimSpace(*r.PayerFirstName) == "" {
-			return fmt.Errorf("credit2card: payer_first_name is required")
+			return NewValidationError("credit2card", "payer_first_name", "is required")
 		}
 		if r.PayerLastName == nil || strings.TrimSpace(*r.PayerLastName) == "" {
-			return fmt.Errorf("credit2card: payer_last_name is required")
+			return NewValidationError("credit2card", "payer_last_name", "is required")
 		}
 		if r.PayerAddress == nil || strings.TrimSpace(*r.PayerAddress) == "" {
-			return fmt.Errorf("credit2card: payer_address is required")
+			return NewValidationError("credit2card", "payer_address", "is required")
 		}
 		if r.PayerCountry == nil || strings.TrimSpace(*r.PayerCountry) == "" {
-			return fmt.Errorf("credit2card: payer_country is required")
+			return NewValidationError("credit2card", "payer_country", "is required")
+		}
+		if err := ValidateCountryCode(*r.PayerCountry); err != nil {
+			return wrapValidationError("credit2card", "payer_country", err)
 		}
 		if r.PayerState == nil || strings.TrimSpace(*r.PayerState) == "" {
-			return fmt.Errorf("credit2card: payer_state is required")
+			return NewValidationError("credit2card", "payer_state", "is required")
+		}
+		if err := ValidateStateCode(*r.PayerState); err != nil {
+			return wrapValidationError("credit2card", "payer_state", err)
 		}
 		if r.PayerCity == nil || strings.TrimSpace(*r.PayerCity) == "" {
-			return fmt.Errorf("credit2card: payer_city is required")
+			return NewValidationError("credit2card", "payer_city", "is required")
 		}
 		if r.PayerZip == nil || strings.TrimSpace(*r.PayerZip) == "" {
-			return fmt.Errorf("credit2card: payer_zip is required")
+			return NewValidationError("credit2card", "payer_zip", "is required")
 		}
 		if len(r.SplitRules) > 0 {
-			return fmt.Errorf("credit2card: split_rules are not allowed")
+			return NewValidationError("credit2card", "split_rules", "are not allowed")
 		}
 
 	case HashTypeCredit2CardToken:
 		if r.Action != ActionCodeCREDIT2CARD.String() {
-			return fmt.Errorf("credit2card_token: action must be %s", ActionCodeCREDIT2CARD.String())
+			return NewValidationError("credit2card_token", "action", fmt.Sprintf("must be %s", ActionCodeCREDIT2CARD.String()))
 		}
 		if r.CardToken == nil || *r.CardToken == "" {
-			return fmt.Errorf("credit2card_token: card_token is required")
+			return NewValidationError("credit2card_token", "card_token", "is required")
 		}
 		if r.OrderID == nil || *r.OrderID == "" {
-			return fmt.Errorf("credit2card_token: order_id is required")
+			return NewValidationError("credit2card_token", "order_id", "is required")
 		}
 		if r.Amount == "" {
-			return fmt.Errorf("credit2card_token: amount is required")
+			return NewValidationError("credit2card_token", "amount", "is required")
 		}
-		if !orderAmountRe.MatchString(r.Amount) {
-			return fmt.Errorf("credit2card_token: amount must match %q (got %q)", orderAmountRe.String(), r.Amount)
-		}
-		if v, err := parseOrderAmountMinorUnits(r.Amount); err != nil || v <= 0 {
-			return fmt.Errorf("credit2card_token: amount must be > 0 (got %q)", r.Amount)
+		if err := validateCurrencyAmount("credit2card_token", "amount", r.Amount, r.OrderCurrency); err != nil {
+			return err
 		}
 		if r.OrderCurrency == "" {
-			return fmt.Errorf("credit2card_token: order_currency is required")
+			return NewValidationError("credit2card_token", "order_currency", "is required")
 		}
 		if r.OrderDescription == nil || strings.TrimSpace(*r.OrderDescription) == "" {
-			return fmt.Errorf("credit2card_token: order_description is required")
+			return NewValidationError("credit2card_token", "order_description", "is required")
 		}
 		if r.PayerFirstName == nil || strings.TrimSpace(*r.PayerFirstName) == "" {
-			return fmt.Errorf("credit2card_token: payer_first_name is required")
+			return NewValidationError("credit2card_token", "payer_first_name", "is required")
 		}
 		if r.PayerLastName == nil || strings.TrimSpace(*r.PayerLastName) == "" {
-			return fmt.Errorf("credit2card_token: payer_last_name is required")
+			return NewValidationError("credit2card_token", "payer_last_name", "is required")
 		}
 		if r.PayerAddress == nil || strings.TrimSpace(*r.PayerAddress) == "" {
-			return fmt.Errorf("credit2card_token: payer_address is required")
+			return NewValidationError("credit2card_token", "payer_address", "is required")
 		}
 		if r.PayerCountry == nil || strings.TrimSpace(*r.PayerCountry) == "" {
-			return fmt.Errorf("credit2card_token: payer_country is required")
+			return NewValidationError("credit2card_token", "payer_country", "is required")
+		}
+		if err := ValidateCountryCode(*r.PayerCountry); err != nil {
+			return wrapValidationError("credit2card_token", "payer_country", err)
 		}
 		if r.PayerState == nil || strings.TrimSpace(*r.PayerState) == "" {
-			return fmt.Errorf("credit2card_token: payer_state is required")
+			return NewValidationError("credit2card_token", "payer_state", "is required")
+		}
+		if err := ValidateStateCode(*r.PayerState); err != nil {
+			return wrapValidationError("credit2card_token", "payer_state", err)
 		}
 		if r.PayerCity == nil || strings.TrimSpace(*r.PayerCity) == "" {
-			return fmt.Errorf("credit2card_token: payer_city is required")
+			return NewValidationError("credit2card_token", "payer_city", "is required")
 		}
 		if r.PayerZip == nil || strings.TrimSpace(*r.PayerZip) == "" {
-			return fmt.Errorf("credit2card_token: payer_zip is required")
+			return NewValidationError("credit2card_token", "payer_zip", "is required")
 		}
 		if len(r.SplitRules) > 0 {
-			return fmt.Errorf("credit2card_token: split_rules are not allowed")
+			return NewValidationError("credit2card_token", "split_rules", "are not allowed")
 		}
 
 	case HashTypeGetSubmerchant:
 		if r.Action != ActionCodeGetSubmerchant.String() {
-			return fmt.Errorf("get_submerchant: action must be %s", ActionCodeGetSubmerchant.String())
+			return NewValidationError("get_submerchant", "action", fmt.Sprintf("must be %s", ActionCodeGetSubmerchant.String()))
 		}
 		if r.SubmerchantID == nil || strings.TrimSpace(*r.SubmerchantID) == "" {
-			return fmt.Errorf("get_submerchant: submerchant_id is required")
+			return NewValidationError("get_submerchant", "submerchant_id", "is required")
 		}
 		if len(r.SplitRules) > 0 {
-			return fmt.Errorf("get_submerchant: split_rules are not allowed")
+			return NewValidationError("get_submerchant", "split_rules", "are not allowed")
 		}
 	}
 
@@ -1088,55 +1212,91 @@ func refString(value string) *string {
 	return &value
 }
 
-func validateSplitRules(rules SplitRules, totalAmount string, context string) error {
+func validateSplitRules(rules SplitRules, totalAmount string, currencyCode string, context string) error {
 	if len(rules) == 0 {
 		return nil
 	}
 	if totalAmount == "" {
-		return fmt.Errorf("%s: amount is required when split_rules are provided", context)
+		return NewValidationError(context, "amount", "is required when split_rules are provided")
 	}
 
-	totalMinorUnits, err := parseOrderAmountMinorUnits(totalAmount)
+	digits := currencyFractionDigits(currencyCode)
+	totalMinorUnits, err := parseAmountMinorUnits(totalAmount, digits)
 	if err != nil || totalMinorUnits <= 0 {
-		return fmt.Errorf("%s: invalid amount %q for split_rules", context, totalAmount)
+		return NewValidationError(context, "amount", fmt.Sprintf("%q is invalid for split_rules", totalAmount))
 	}
 
-	splitMinorUnits := 0
 	for submerchantID, amount := range rules {
 		if strings.TrimSpace(submerchantID) == "" {
-			return fmt.Errorf("%s: split_rules key (submerchant_id) is required", context)
+			return NewValidationError(context, "split_rules", "key (submerchant_id) is required")
 		}
 
-		if !orderAmountRe.MatchString(amount) {
-			return fmt.Errorf("%s: split_rules[%q] amount must match %q (got %q)", context, submerchantID, orderAmountRe.String(), amount)
-		}
-		minorUnits, parseErr := parseOrderAmountMinorUnits(amount)
-		if parseErr != nil || minorUnits <= 0 {
-			return fmt.Errorf("%s: split_rules[%q] amount must be > 0 (got %q)", context, submerchantID, amount)
+		if err := validateCurrencyAmount(context, fmt.Sprintf("split_rules[%q] amount", submerchantID), amount, currencyCode); err != nil {
+			return err
 		}
-		splitMinorUnits += minorUnits
 	}
-	if splitMinorUnits != totalMinorUnits {
-		return fmt.Errorf("%s: split rules total must equal amount (%d != %d minor units)", context, splitMinorUnits, totalMinorUnits)
+
+	splitMinorUnits, err := splitRulesTotal(rules, digits)
+	if err != nil {
+		return wrapValidationError(context, "", err)
+	}
+	if err := compareSplitTotal(splitMinorUnits, totalMinorUnits, digits); err != nil {
+		return wrapValidationError(context, "", err)
 	}
 
 	return nil
 }
 
-func parseOrderAmountMinorUnits(amount string) (int, error) {
-	parts := strings.SplitN(amount, ".", 2)
-	if len(parts) != 2 {
-		return 0, fmt.Errorf("invalid amount format")
+// applySplitRemainder moves the difference between the split rules total and
+// the amount to SplitRemainderTo. Rules are copied, so the caller's map is not
+// modified. Anything it cannot fix is left for validateSplitRules to report.
+func (r *Request) applySplitRemainder() {
+	if len(r.SplitRules) == 0 || strings.TrimSpace(r.SplitRemainderTo) == "" {
+		return
+	}
+
+	totalAmount := r.OrderAmount
+	if totalAmount == "" {
+		totalAmount = r.Amount
 	}
-	major, err := strconv.Atoi(parts[0])
+	digits := currencyFractionDigits(r.OrderCurrency)
+	totalMinorUnits, err := parseAmountMinorUnits(totalAmount, digits)
 	if err != nil {
-		return 0, fmt.Errorf("invalid major amount")
+		return
 	}
-	minor, err := strconv.Atoi(parts[1])
+
+	splitMinorUnits, err := splitRulesTotal(r.SplitRules, digits)
 	if err != nil {
-		return 0, fmt.Errorf("invalid minor amount")
+		return
+	}
+
+	// Only a difference of up to one major unit is treated as a rounding
+	// remainder; larger mismatches are left to validation.
+	limit := Money(pow10(digits))
+	diff, err := totalMinorUnits.Sub(splitMinorUnits)
+	if err != nil || diff == 0 || diff > limit || -diff > limit {
+		return
 	}
-	return major*100 + minor, nil
+
+	target := strings.TrimSpace(r.SplitRemainderTo)
+	var current Money
+	if amount, ok := r.SplitRules[target]; ok {
+		current, _ = parseAmountMinorUnits(amount, digits)
+	}
+	if current+diff <= 0 {
+		return
+	}
+
+	adjusted := make(SplitRules, len(r.SplitRules)+1)
+	for submerchantID, amount := range r.SplitRules {
+		adjusted[submerchantID] = amount
+	}
+	adjusted[target] = formatAmountMinorUnits(current+diff, digits)
+	r.SplitRules = adjusted
+}
+
+func parseOrderAmountMinorUnits(amount string) (Money, error) {
+	return parseAmountMinorUnits(amount, 2)
 }
 
 // getFieldValueByJSONTag uses reflection to search for a struct field whose "json" tag (or field name)
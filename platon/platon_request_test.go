@@ -25,9 +25,15 @@
 package platon
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stremovskyy/go-platon/currency"
+	"github.com/stremovskyy/go-platon/log"
 )
 
 func TestSignAndPrepare_VerificationSignature(t *testing.T) {
@@ -62,6 +68,7 @@ func TestSignAndPrepare_VerificationSignature(t *testing.T) {
 		WithPayerPhone(&phone).
 		WithReqToken(true).
 		WithRecurringInitFlag(true).
+		WithClock(testClock).
 		SignForAction(HashTypeVerification)
 
 	signed, err := req.SignAndPrepare()
@@ -104,6 +111,7 @@ func TestSignAndPrepare_CardPaymentSignature(t *testing.T) {
 		WithCardCvv2(&cvv).
 		WithPayerEmail(&email).
 		WithPayerPhone(&phone).
+		WithClock(testClock).
 		SignForAction(HashTypeCardPayment)
 
 	signed, err := req.SignAndPrepare()
@@ -161,6 +169,83 @@ func TestSignAndPrepare_CardTokenPaymentSignature(t *testing.T) {
 	}
 }
 
+func TestWithPayerIPStrict(t *testing.T) {
+	newTokenSale := func() *Request {
+		orderID := "order-123"
+		term := "https://example.com/3ds"
+		token := "TOKEN123"
+		email := "payer@example.com"
+
+		return NewRequest(ActionCodeSALE).
+			WithAuth(&Auth{Key: "k", Secret: "secret123"}).
+			WithClientKey("clientKey").
+			WithPayerEmail(&email).
+			WithCardToken(&token).
+			WithOrderID(&orderID).
+			WithOrderAmount("1.00").
+			ForCurrency(currency.UAH).
+			WithDescription("one-click").
+			WithTermsURL(&term)
+	}
+
+	lenient := newTokenSale().WithPayerIP(nil)
+	if lenient.PayerIp == nil || *lenient.PayerIp != "127.0.0.1" {
+		t.Fatalf("WithPayerIP(nil) should default to loopback, got %v", lenient.PayerIp)
+	}
+
+	strict := newTokenSale().WithPayerIPStrict(nil).SignForAction(HashTypeCardTokenPayment)
+	if strict.PayerIp != nil {
+		t.Fatalf("WithPayerIPStrict(nil) should leave payer_ip unset, got %q", *strict.PayerIp)
+	}
+	if err := strict.Err(); err == nil || !strings.Contains(err.Error(), "payer_ip is required") {
+		t.Fatalf("expected payer_ip build error, got %v", err)
+	}
+	if _, err := strict.SignAndPrepare(); err == nil || !strings.Contains(err.Error(), "payer_ip is required") {
+		t.Fatalf("expected SignAndPrepare() to fail, got %v", err)
+	}
+
+	ip := "203.0.113.10"
+	ok := newTokenSale().WithPayerIPStrict(&ip).SignForAction(HashTypeCardTokenPayment)
+	if _, err := ok.SignAndPrepare(); err != nil {
+		t.Fatalf("SignAndPrepare() error: %v", err)
+	}
+	if *ok.PayerIp != ip {
+		t.Fatalf("payer_ip mismatch: want %q, got %q", ip, *ok.PayerIp)
+	}
+}
+
+func TestSignAndPrepare_RejectsInvalidUTF8(t *testing.T) {
+	orderID := "order-123"
+	ip := "127.0.0.1"
+	term := "https://example.com/3ds"
+	email := "payer@example.com"
+	token := "TOKEN123"
+
+	req := NewRequest(ActionCodeSALE).
+		WithAuth(&Auth{Key: "k", Secret: "secret123"}).
+		WithClientKey("clientKey").
+		WithCardToken(&token).
+		WithOrderID(&orderID).
+		WithOrderAmount("1.00").
+		ForCurrency(currency.UAH).
+		WithDescription("caf\xe9 order").
+		WithPayerIP(&ip).
+		WithTermsURL(&term).
+		WithPayerEmail(&email).
+		SignForAction(HashTypeCardTokenPayment)
+
+	_, err := req.SignAndPrepare()
+	if err == nil {
+		t.Fatalf("expected invalid UTF-8 error")
+	}
+	if !strings.Contains(err.Error(), "order_description contains invalid UTF-8") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Hash != "" {
+		t.Fatalf("request should not be signed, got hash %q", req.Hash)
+	}
+}
+
 func TestSignAndPrepare_ApplePaySignature(t *testing.T) {
 	auth := &Auth{Key: "k", Secret: "secret123"}
 
@@ -257,6 +342,53 @@ func TestSignAndPrepare_GetTransStatusSignature(t *testing.T) {
 	}
 }
 
+func TestSignAndPrepare_GetTransDetailsSignature(t *testing.T) {
+	auth := &Auth{Key: "k", Secret: "secret123"}
+
+	email := "payer@example.com"
+	transID := "632508054"
+
+	req := NewRequest(ActionCodeGetTransDetails).
+		WithAuth(auth).
+		WithClientKey("clientKey").
+		WithTransID(&transID).
+		WithHashEmail(&email).
+		SignForAction(HashTypeGetTransDetails)
+
+	signed, err := req.SignAndPrepare()
+	if err != nil {
+		t.Fatalf("SignAndPrepare() error: %v", err)
+	}
+
+	// Same signature as GET_TRANS_STATUS for the same trans_id and email.
+	const want = "ef374c28b6398c097e0b3d6230deebd6"
+	if signed.Hash != want {
+		t.Fatalf("hash mismatch: want %s, got %s", want, signed.Hash)
+	}
+	if got := signed.ToMap()["action"]; got != "GET_TRANS_DETAILS" {
+		t.Fatalf("action = %v, want GET_TRANS_DETAILS", got)
+	}
+
+	wrongAction := NewRequest(ActionCodeGetTransStatus).
+		WithAuth(auth).
+		WithClientKey("clientKey").
+		WithTransID(&transID).
+		SignForAction(HashTypeGetTransDetails)
+	if _, err := wrongAction.SignAndPrepare(); err == nil || !strings.Contains(err.Error(), "get_trans_details: action must be GET_TRANS_DETAILS") {
+		t.Fatalf("expected action error, got %v", err)
+	}
+
+	emptyTransID := ""
+	noTransID := NewRequest(ActionCodeGetTransDetails).
+		WithAuth(auth).
+		WithClientKey("clientKey").
+		WithTransID(&emptyTransID).
+		SignForAction(HashTypeGetTransDetails)
+	if _, err := noTransID.SignAndPrepare(); err == nil || !strings.Contains(err.Error(), "trans_id is required") {
+		t.Fatalf("expected trans_id error, got %v", err)
+	}
+}
+
 func TestSignAndPrepare_CaptureSignatureAndMap(t *testing.T) {
 	auth := &Auth{Key: "k", Secret: "secret123"}
 
@@ -294,6 +426,35 @@ func TestSignAndPrepare_CaptureSignatureAndMap(t *testing.T) {
 	}
 }
 
+func TestSignAndPrepare_CaptureOriginalAmount(t *testing.T) {
+	newCapture := func(amount string, original *int) *Request {
+		transID := "632508054"
+		return NewRequest(ActionCodeCAPTURE).
+			WithAuth(&Auth{Key: "k", Secret: "secret123"}).
+			WithClientKey("clientKey").
+			WithTransID(&transID).
+			WithAmount(amount).
+			WithOriginalAmount(original).
+			SignForAction(HashTypeCapture)
+	}
+	original := 1000
+
+	if _, err := newCapture("10.00", &original).SignAndPrepare(); err != nil {
+		t.Fatalf("equal amount: unexpected error: %v", err)
+	}
+	if _, err := newCapture("4.00", &original).SignAndPrepare(); err != nil {
+		t.Fatalf("smaller amount: unexpected error: %v", err)
+	}
+	if _, err := newCapture("12.00", nil).SignAndPrepare(); err != nil {
+		t.Fatalf("unset original amount: unexpected error: %v", err)
+	}
+
+	_, err := newCapture("12.00", &original).SignAndPrepare()
+	if err == nil || !strings.Contains(err.Error(), "capture: amount 1200 exceeds original amount 1000") {
+		t.Fatalf("larger amount: expected error, got %v", err)
+	}
+}
+
 func TestSignAndPrepare_CreditVoidSignature(t *testing.T) {
 	auth := &Auth{Key: "k", Secret: "secret123"}
 
@@ -319,6 +480,54 @@ func TestSignAndPrepare_CreditVoidSignature(t *testing.T) {
 	}
 }
 
+func TestSignAndPrepare_VoidReusesTransIDSignature(t *testing.T) {
+	auth := &Auth{Key: "k", Secret: "secret123"}
+
+	email := "payer@example.com"
+	transID := "632508054"
+
+	void, err := NewRequest(ActionCodeCREDITVOID).
+		WithAuth(auth).
+		WithClientKey("clientKey").
+		WithTransID(&transID).
+		WithAmount("1.00").
+		WithHashEmail(&email).
+		ForVoid().
+		SignAndPrepare()
+	if err != nil {
+		t.Fatalf("SignAndPrepare() error: %v", err)
+	}
+
+	status, err := NewRequest(ActionCodeGetTransStatus).
+		WithAuth(auth).
+		WithClientKey("clientKey").
+		WithTransID(&transID).
+		WithHashEmail(&email).
+		SignForAction(HashTypeGetTransStatus).
+		SignAndPrepare()
+	if err != nil {
+		t.Fatalf("SignAndPrepare() error: %v", err)
+	}
+
+	if void.Hash != status.Hash {
+		t.Fatalf("hash mismatch: want %s, got %s", status.Hash, void.Hash)
+	}
+	if _, ok := void.ToMap()["amount"]; ok {
+		t.Fatalf("void request must not contain amount")
+	}
+
+	withAmount := NewRequest(ActionCodeCREDITVOID).
+		WithAuth(auth).
+		WithClientKey("clientKey").
+		WithTransID(&transID).
+		WithHashEmail(&email).
+		SignForAction(HashTypeVoid).
+		WithAmount("1.00")
+	if _, err := withAmount.SignAndPrepare(); err == nil || !strings.Contains(err.Error(), "void: amount must be empty") {
+		t.Fatalf("expected amount error, got %v", err)
+	}
+}
+
 func TestSignAndPrepare_Credit2CardSignature(t *testing.T) {
 	auth := &Auth{Key: "k", Secret: "secret123"}
 
@@ -503,6 +712,127 @@ func TestSignAndPrepare_CreditVoidSplitRulesExceedAmount(t *testing.T) {
 	}
 }
 
+func TestSignAndPrepare_SplitRulesMismatchReportsDifference(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules SplitRules
+		want  string
+	}{
+		{
+			name:  "shortfall",
+			rules: SplitRules{"submerchant_01": "1.20", "submerchant_02": "0.70"},
+			want:  "creditvoid: split rules total is 0.10 short of amount (1.90 != 2.00)",
+		},
+		{
+			name:  "overage",
+			rules: SplitRules{"submerchant_01": "1.20", "submerchant_02": "1.05"},
+			want:  "creditvoid: split rules total is 0.25 over amount (2.25 != 2.00)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				email := "payer@example.com"
+				transID := "632508054"
+
+				_, err := NewRequest(ActionCodeCREDITVOID).
+					WithAuth(&Auth{Key: "k", Secret: "secret123"}).
+					WithClientKey("clientKey").
+					WithTransID(&transID).
+					WithAmount("2.00").
+					WithSplitRules(tt.rules).
+					WithHashEmail(&email).
+					SignForAction(HashTypeCreditVoid).
+					SignAndPrepare()
+				if err == nil || err.Error() != tt.want {
+					t.Fatalf("SignAndPrepare() error = %v, want %q", err, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestSignAndPrepare_SplitRemainderTo(t *testing.T) {
+	tests := []struct {
+		name   string
+		rules  SplitRules
+		target string
+		want   SplitRules
+	}{
+		{
+			name:   "shortfall to existing",
+			rules:  SplitRules{"submerchant_01": "3.33", "submerchant_02": "6.66"},
+			target: "submerchant_02",
+			want:   SplitRules{"submerchant_01": "3.33", "submerchant_02": "6.67"},
+		},
+		{
+			name:   "overage from existing",
+			rules:  SplitRules{"submerchant_01": "3.34", "submerchant_02": "6.67"},
+			target: "submerchant_01",
+			want:   SplitRules{"submerchant_01": "3.33", "submerchant_02": "6.67"},
+		},
+		{
+			name:   "shortfall to new submerchant",
+			rules:  SplitRules{"submerchant_01": "9.50"},
+			target: "platform",
+			want:   SplitRules{"submerchant_01": "9.50", "platform": "0.50"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				email := "payer@example.com"
+				transID := "632508054"
+				original := SplitRules{}
+				for k, v := range tt.rules {
+					original[k] = v
+				}
+
+				signed, err := NewRequest(ActionCodeCAPTURE).
+					WithAuth(&Auth{Key: "k", Secret: "secret123"}).
+					WithClientKey("clientKey").
+					WithTransID(&transID).
+					WithAmount("10.00").
+					WithSplitRules(tt.rules).
+					WithSplitRemainderTo(tt.target).
+					WithHashEmail(&email).
+					SignForAction(HashTypeCapture).
+					SignAndPrepare()
+				if err != nil {
+					t.Fatalf("SignAndPrepare() error: %v", err)
+				}
+				if !reflect.DeepEqual(signed.SplitRules, tt.want) {
+					t.Fatalf("split rules mismatch: want %v, got %v", tt.want, signed.SplitRules)
+				}
+				if !reflect.DeepEqual(tt.rules, original) {
+					t.Fatalf("caller split rules were modified: %v", tt.rules)
+				}
+			},
+		)
+	}
+}
+
+func TestSignAndPrepare_SplitRemainderToRejectsLargeDifference(t *testing.T) {
+	email := "payer@example.com"
+	transID := "632508054"
+
+	_, err := NewRequest(ActionCodeCAPTURE).
+		WithAuth(&Auth{Key: "k", Secret: "secret123"}).
+		WithClientKey("clientKey").
+		WithTransID(&transID).
+		WithAmount("10.00").
+		WithSplitRules(SplitRules{"submerchant_01": "5.00"}).
+		WithSplitRemainderTo("submerchant_01").
+		WithHashEmail(&email).
+		SignForAction(HashTypeCapture).
+		SignAndPrepare()
+	if err == nil || !strings.Contains(err.Error(), "5.00 short of amount") {
+		t.Fatalf("SignAndPrepare() expected shortfall error, got %v", err)
+	}
+}
+
 func TestSignAndPrepare_GetSubmerchantSignature(t *testing.T) {
 	auth := &Auth{Key: "k", Secret: "secret123"}
 	submerchantID := "12345678"
@@ -553,6 +883,7 @@ func TestSignAndPrepare_OrderAmountValidation(t *testing.T) {
 		WithCardCvv2(&cvv).
 		WithPayerEmail(&email).
 		WithPayerPhone(&phone).
+		WithClock(testClock).
 		SignForAction(HashTypeCardPayment)
 
 	if _, err := req.SignAndPrepare(); err == nil {
@@ -638,10 +969,144 @@ func TestRequest_NilReceiver_BuilderChainIsSafe(t *testing.T) {
 		WithSplitRules(SplitRules{"submerchant": "1.00"}).
 		WithImmediately(true).
 		WithHashEmail(&email).
+		WithExt(1, &value).
+		WithExt1(&value).
+		WithExt2(&value).
 		WithExt3(&value).
+		WithExt4(&value).
+		WithExt5(&value).
+		WithExt6(&value).
+		WithExt7(&value).
+		WithExt8(&value).
+		WithExt9(&value).
+		WithExt10(&value).
 		SignForAction(HashTypeCardPayment)
 
 	if got != nil {
 		t.Fatalf("expected nil request after nil receiver builder chain, got %#v", got)
 	}
 }
+
+func TestRequest_WithExtSetters(t *testing.T) {
+	setters := []func(*Request, *string) *Request{
+		(*Request).WithExt1, (*Request).WithExt2, (*Request).WithExt3, (*Request).WithExt4, (*Request).WithExt5,
+		(*Request).WithExt6, (*Request).WithExt7, (*Request).WithExt8, (*Request).WithExt9, (*Request).WithExt10,
+	}
+
+	for i, set := range setters {
+		n := i + 1
+		value := fmt.Sprintf("value-%d", n)
+		req := set(NewRequest(ActionCodeSALE), &value)
+
+		fields := req.ToMap()
+		for m := 1; m <= 10; m++ {
+			got, ok := fields[fmt.Sprintf("ext%d", m)]
+			if m == n {
+				if !ok || got != value {
+					t.Fatalf("WithExt%d: ext%d = %v, want %q", n, m, got, value)
+				}
+			} else if ok {
+				t.Fatalf("WithExt%d: unexpected ext%d = %v", n, m, got)
+			}
+		}
+
+		if got := NewRequest(ActionCodeSALE).WithExt(n, &value).ToMap()[fmt.Sprintf("ext%d", n)]; got != value {
+			t.Fatalf("WithExt(%d): ext%d = %v, want %q", n, n, got, value)
+		}
+	}
+
+	for _, n := range []int{0, 11, -1} {
+		value := "value"
+		req := NewRequest(ActionCodeSALE).WithExt(n, &value)
+		if !errors.Is(req.Err(), ErrValidation) {
+			t.Fatalf("WithExt(%d): Err() = %v, want ErrValidation", n, req.Err())
+		}
+	}
+}
+
+type capturingSink struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (s *capturingSink) Log(level log.Level, prefix, msg string, args ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lines = append(s.lines, prefix+fmt.Sprintf(msg, args...))
+}
+
+func TestSignAndPrepare_SignatureLogsAreRoutedAndRedacted(t *testing.T) {
+	previousLevel := log.GetLevel()
+	log.SetLevel(log.LevelAll)
+	t.Cleanup(func() { log.SetLevel(previousLevel) })
+
+	const (
+		secret = "merchant-secret-123"
+		pan    = "4111111111111111"
+		cvv    = "987"
+	)
+
+	sink := &capturingSink{}
+	orderID := "order-log"
+	ip := "203.0.113.10"
+	term := "https://example.com/3ds"
+	email := "payer@example.com"
+	phone := "380631234567"
+	panValue, cvvValue := pan, cvv
+	month, year := "01", "2030"
+
+	req := NewRequest(ActionCodeSALE).
+		WithAuth(&Auth{Key: "k", Secret: secret}).
+		WithClientKey("clientKey").
+		WithOrderID(&orderID).
+		WithOrderAmountMinorUnits(100).
+		ForCurrency(currency.UAH).
+		WithDescription("logging").
+		WithPayerIP(&ip).
+		WithTermsURL(&term).
+		WithCardNumber(&panValue).
+		WithCardExpMonth(&month).
+		WithCardExpYear(&year).
+		WithCardCvv2(&cvvValue).
+		WithPayerEmail(&email).
+		WithPayerPhone(&phone).
+		WithLogSink(sink).
+		SignForAction(HashTypeCardPayment)
+	if _, err := req.SignAndPrepare(); err != nil {
+		t.Fatalf("SignAndPrepare() error: %v", err)
+	}
+
+	// The generic generator used to log every value and the concatenation.
+	if _, err := req.generateSignature([]string{"key", "pass", "card_number", "card_cvv2"}); err != nil {
+		t.Fatalf("generateSignature() error: %v", err)
+	}
+
+	if len(sink.lines) == 0 {
+		t.Fatal("signature logs did not reach the request sink")
+	}
+	output := strings.ToUpper(strings.Join(sink.lines, "\n"))
+	for _, leaked := range []string{secret, reverseString(secret), pan, reverseString(pan)} {
+		if strings.Contains(output, strings.ToUpper(leaked)) {
+			t.Fatalf("signature logs leak %q:\n%s", leaked, output)
+		}
+	}
+	for _, line := range sink.lines {
+		if strings.Contains(line, "='"+cvv) || strings.Contains(line, reverseString(cvv)+"'") {
+			t.Fatalf("signature logs leak the CVV: %s", line)
+		}
+	}
+}
+
+func TestForCurrency_RejectsUnsupportedCurrency(t *testing.T) {
+	req := NewRequest(ActionCodeSALE).ForCurrency("UAG")
+	if err := req.Err(); err == nil || !strings.Contains(err.Error(), `unsupported currency "UAG"`) {
+		t.Fatalf("Err() = %v, want an unsupported currency error", err)
+	}
+	if _, err := req.SignAndPrepare(); err == nil {
+		t.Fatal("SignAndPrepare() must fail for an unsupported currency")
+	}
+
+	if err := NewRequest(ActionCodeSALE).ForCurrency(currency.PLN).Err(); err != nil {
+		t.Fatalf("ForCurrency(PLN) error: %v", err)
+	}
+}
@@ -28,6 +28,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
+	"net/url"
 	"strings"
 )
 
@@ -41,6 +43,7 @@ const (
 	ResultAccepted Result = "ACCEPTED"
 	ResultDeclined Result = "DECLINED"
 	ResultError    Result = "ERROR"
+	ResultRedirect Result = "REDIRECT"
 )
 
 type Response struct {
@@ -53,6 +56,105 @@ type Response struct {
 	ResponseData  *ResponseData `json:"response,omitempty"`
 	ErrorMessage  string        `json:"error_message"`
 	DeclineReason string        `json:"decline_reason"`
+	// Amount is the transaction amount as returned by Platon (e.g. "10.00"), if present.
+	Amount string `json:"amount,omitempty"`
+	// ApprovedAmountRaw is the amount actually authorized when the acquirer
+	// approved less than Amount (partial approval), if present.
+	ApprovedAmountRaw string `json:"approved_amount,omitempty"`
+
+	// Card is the masked card number (card, or card_mask), if present.
+	Card string `json:"card,omitempty"`
+	// Brand is the card brand (brand, or card_brand), e.g. "VISA", if present.
+	Brand string `json:"brand,omitempty"`
+	// IssuingBank is the bank that issued the card (issuing_bank), if present.
+	IssuingBank string `json:"issuing_bank,omitempty"`
+	// PayerName is the payer name (payer_name, or name), if present.
+	PayerName string `json:"payer_name,omitempty"`
+	// FeeRaw is the fee charged for the transaction (fee), if present.
+	FeeRaw string `json:"fee,omitempty"`
+
+	// Transactions is the transaction history reported by GET_TRANS_STATUS,
+	// in the order Platon returns it (oldest first).
+	Transactions []ResponseTransaction `json:"transactions,omitempty"`
+	// TransactionErrors lists the transactions entries that could not be
+	// decoded and were left out of Transactions. The rest of the response is
+	// still decoded.
+	TransactionErrors []error `json:"-"`
+
+	// RedirectURL, RedirectMethod and RedirectParams describe the ACS page the
+	// payer must be sent to when the payment requires 3DS.
+	RedirectURL    string            `json:"redirect_url,omitempty"`
+	RedirectMethod string            `json:"redirect_method,omitempty"`
+	RedirectParams map[string]string `json:"redirect_params,omitempty"`
+}
+
+// ResponseTransaction is one entry of the GET_TRANS_STATUS transaction list.
+type ResponseTransaction struct {
+	ID            string `json:"id,omitempty"`
+	Type          string `json:"type,omitempty"`
+	Status        string `json:"status,omitempty"`
+	Amount        string `json:"amount,omitempty"`
+	Date          string `json:"date,omitempty"`
+	DeclineReason string `json:"decline_reason,omitempty"`
+}
+
+// AmountMinorUnits returns Amount in minor units. It returns false when the
+// transaction has no amount or it cannot be parsed.
+func (t ResponseTransaction) AmountMinorUnits() (int, bool) {
+	return parseResponseAmountMinorUnits(t.Amount)
+}
+
+// IsSuccessful reports whether the transaction status is SUCCESS or SETTLED.
+func (t ResponseTransaction) IsSuccessful() bool {
+	switch strings.ToUpper(strings.TrimSpace(t.Status)) {
+	case "SUCCESS", "SETTLED":
+		return true
+	}
+
+	return false
+}
+
+func (t *ResponseTransaction) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID            json.RawMessage `json:"id"`
+		TransID       json.RawMessage `json:"trans_id"`
+		Type          string          `json:"type"`
+		Status        string          `json:"status"`
+		Amount        json.RawMessage `json:"amount"`
+		Date          string          `json:"date"`
+		DeclineReason json.RawMessage `json:"decline_reason"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	// IDs may be sent as JSON numbers; reuse the string-or-number decoding.
+	id, err := normalizeOptionalResponseAmount(raw.ID)
+	if err != nil {
+		return fmt.Errorf("decode id: %w", err)
+	}
+	if id == "" {
+		if id, err = normalizeOptionalResponseAmount(raw.TransID); err != nil {
+			return fmt.Errorf("decode trans_id: %w", err)
+		}
+	}
+	amount, err := normalizeOptionalResponseAmount(raw.Amount)
+	if err != nil {
+		return fmt.Errorf("decode amount: %w", err)
+	}
+	declineReason, err := normalizeOptionalResponseString(raw.DeclineReason)
+	if err != nil {
+		return fmt.Errorf("decode decline_reason: %w", err)
+	}
+
+	t.ID = id
+	t.Type = strings.TrimSpace(raw.Type)
+	t.Status = strings.TrimSpace(raw.Status)
+	t.Amount = amount
+	t.Date = strings.TrimSpace(raw.Date)
+	t.DeclineReason = declineReason
+
+	return nil
 }
 
 type ResponseData struct {
@@ -102,17 +204,23 @@ func (p *Response) PrettyPrint() {
 	fmt.Println("------------------------------------------------------")
 }
 
+// GetError returns a *APIError when the response reports an error or a
+// decline. A response that requires 3DS is never an error.
 func (p *Response) GetError() error {
 	if p == nil {
 		return nil
 	}
+	// A payment waiting for 3DS is not failed yet.
+	if p.Requires3DS() {
+		return nil
+	}
 
 	if msg := strings.TrimSpace(p.ErrorMessage); msg != "" {
-		return fmt.Errorf("platon api error: %s", msg)
+		return NewAPIError(APIErrorKindError, msg)
 	}
 
 	if declineReason := strings.TrimSpace(p.DeclineReason); declineReason != "" {
-		return fmt.Errorf("platon api declined: %s", declineReason)
+		return NewAPIError(APIErrorKindDeclined, declineReason)
 	}
 
 	if p.Result == nil {
@@ -121,14 +229,299 @@ func (p *Response) GetError() error {
 
 	switch strings.ToUpper(strings.TrimSpace(p.Result.String())) {
 	case ResultError.String():
-		return fmt.Errorf("unknown platon api error")
+		return NewAPIError(APIErrorKindError, "")
 	case ResultDeclined.String():
-		return fmt.Errorf("unknown platon api decline")
+		return NewAPIError(APIErrorKindDeclined, "")
 	}
 
 	return nil
 }
 
+// pendingStatuses are the statuses of a transaction Platon has not finished
+// processing yet, e.g. an async SALE or a payment waiting for 3DS.
+var pendingStatuses = map[string]struct{}{
+	"PENDING":    {},
+	"PREPARE":    {},
+	"PROCESSING": {},
+	"3DS":        {},
+	"REDIRECT":   {},
+}
+
+// IsPending reports whether the transaction is still being processed: the
+// result is REDIRECT or the status is PENDING, PREPARE, PROCESSING, 3DS or
+// REDIRECT. A completed HOLD also reports status PENDING.
+func (p *Response) IsPending() bool {
+	if p == nil {
+		return false
+	}
+	if p.Result != nil && strings.EqualFold(strings.TrimSpace(p.Result.String()), ResultRedirect.String()) {
+		return true
+	}
+	if p.Status == nil {
+		return false
+	}
+
+	_, ok := pendingStatuses[strings.ToUpper(strings.TrimSpace(*p.Status))]
+	return ok
+}
+
+// alreadyCapturedSignals are the error_message/decline_reason fragments Platon
+// returns when CAPTURE is repeated for a transaction that was already captured
+// (result=ERROR, e.g. "Transaction already captured" or "Transaction is already settled").
+var alreadyCapturedSignals = []string{"already captured", "already settled"}
+
+// IsAlreadyCaptured reports whether the response rejects a CAPTURE because the
+// transaction was already captured. Matching is case-insensitive.
+func (p *Response) IsAlreadyCaptured() bool {
+	if p == nil {
+		return false
+	}
+
+	for _, text := range []string{p.ErrorMessage, p.DeclineReason} {
+		lower := strings.ToLower(text)
+		for _, signal := range alreadyCapturedSignals {
+			if strings.Contains(lower, signal) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// orderNotFoundSignal is the error_message fragment of a status lookup for an
+// order Platon does not know ("Order not found").
+const orderNotFoundSignal = "order not found"
+
+// IsOrderNotFound reports whether a status lookup was rejected because Platon
+// does not know the order. Matching is case-insensitive.
+func (p *Response) IsOrderNotFound() bool {
+	if p == nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(p.ErrorMessage), orderNotFoundSignal)
+}
+
+// AmountMinorUnits returns Amount in minor units. It returns false when the
+// response has no amount or it cannot be parsed.
+func (p *Response) AmountMinorUnits() (int, bool) {
+	if p == nil {
+		return 0, false
+	}
+
+	return parseResponseAmountMinorUnits(p.Amount)
+}
+
+// MaskedCard returns the masked card number (e.g. "411111****1111"), or ""
+// when the response has none.
+func (p *Response) MaskedCard() string {
+	if p == nil {
+		return ""
+	}
+
+	return p.Card
+}
+
+// First6 returns the first 6 digits (BIN) of the masked card, or "" when the
+// card is missing or too short.
+func (p *Response) First6() string {
+	source, err := webhookCardSignSource(p.MaskedCard())
+	if err != nil {
+		return ""
+	}
+
+	return source[:6]
+}
+
+// Last4 returns the last 4 digits of the masked card, or "" when the card is
+// missing or too short.
+func (p *Response) Last4() string {
+	source, err := webhookCardSignSource(p.MaskedCard())
+	if err != nil {
+		return ""
+	}
+
+	return source[6:]
+}
+
+// Fee returns the transaction fee in minor units. It returns false when the
+// response does not report fee.
+func (p *Response) Fee() (int, bool) {
+	if p == nil {
+		return 0, false
+	}
+
+	return parseResponseAmountMinorUnits(p.FeeRaw)
+}
+
+// ApprovedAmount returns the partially approved amount in minor units. It
+// returns false when the response does not report approved_amount.
+func (p *Response) ApprovedAmount() (int, bool) {
+	if p == nil {
+		return 0, false
+	}
+
+	return parseResponseAmountMinorUnits(p.ApprovedAmountRaw)
+}
+
+// IsPartialApproval reports whether the acquirer approved less than the
+// requested amount. Use ApprovedAmount to decide whether to keep or void the
+// authorization.
+func (p *Response) IsPartialApproval() bool {
+	approved, ok := p.ApprovedAmount()
+	if !ok {
+		return false
+	}
+	requested, ok := p.AmountMinorUnits()
+	if !ok {
+		return false
+	}
+
+	return approved < requested
+}
+
+// AuthAmount returns the amount held by the authorization in minor units:
+// the amount of the SALE in Transactions, else Amount. It returns false when
+// neither is present.
+func (p *Response) AuthAmount() (int, bool) {
+	if p == nil {
+		return 0, false
+	}
+	for _, transaction := range p.Transactions {
+		if strings.EqualFold(strings.TrimSpace(transaction.Type), ActionCodeSALE.String()) {
+			if amount, ok := transaction.AmountMinorUnits(); ok {
+				return amount, true
+			}
+		}
+	}
+
+	return p.AmountMinorUnits()
+}
+
+// CapturedAmount returns the total captured on a HOLD in minor units.
+//
+// When Transactions lists CAPTURE entries, it is the sum of the successful
+// ones. Otherwise a SETTLED transaction is treated as captured for Amount, and
+// a PENDING hold as not captured yet. It returns false when the capture state
+// is unknown.
+func (p *Response) CapturedAmount() (int, bool) {
+	if p == nil {
+		return 0, false
+	}
+
+	var captured int
+	var listed bool
+	for _, transaction := range p.Transactions {
+		if !strings.EqualFold(strings.TrimSpace(transaction.Type), ActionCodeCAPTURE.String()) {
+			continue
+		}
+		listed = true
+		if !transaction.IsSuccessful() {
+			continue
+		}
+		amount, ok := transaction.AmountMinorUnits()
+		if !ok {
+			return 0, false
+		}
+		captured += amount
+	}
+	if listed {
+		return captured, true
+	}
+	if p.Status == nil {
+		return 0, false
+	}
+
+	switch strings.ToUpper(strings.TrimSpace(*p.Status)) {
+	case "SETTLED":
+		return p.AmountMinorUnits()
+	case "PENDING":
+		if _, ok := p.AuthAmount(); ok {
+			return 0, true
+		}
+	}
+
+	return 0, false
+}
+
+// RemainingAuth returns how much of the authorization can still be captured,
+// in minor units (AuthAmount minus CapturedAmount, never negative). Use it to
+// make sure the sum of captures never exceeds the hold.
+func (p *Response) RemainingAuth() (int, bool) {
+	auth, ok := p.AuthAmount()
+	if !ok {
+		return 0, false
+	}
+	captured, ok := p.CapturedAmount()
+	if !ok {
+		return 0, false
+	}
+	if captured >= auth {
+		return 0, true
+	}
+
+	return auth - captured, true
+}
+
+// IsPendingHold reports whether the transaction is a HOLD that can still be
+// captured (status PENDING). SETTLED, REVERSAL and other final statuses report
+// false.
+func (p *Response) IsPendingHold() bool {
+	if p == nil || p.Status == nil {
+		return false
+	}
+
+	return strings.EqualFold(strings.TrimSpace(*p.Status), "PENDING")
+}
+
+// LastTransaction returns the most recent entry of Transactions. It returns
+// false when the response carries no transaction history.
+func (p *Response) LastTransaction() (ResponseTransaction, bool) {
+	if p == nil || len(p.Transactions) == 0 {
+		return ResponseTransaction{}, false
+	}
+
+	return p.Transactions[len(p.Transactions)-1], true
+}
+
+// HasSuccessfulSale reports whether Transactions contains a successful SALE.
+func (p *Response) HasSuccessfulSale() bool {
+	if p == nil {
+		return false
+	}
+
+	for _, transaction := range p.Transactions {
+		if strings.EqualFold(transaction.Type, ActionCodeSALE.String()) && transaction.IsSuccessful() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseResponseAmountMinorUnits(amount string) (int, bool) {
+	if amount == "" {
+		return 0, false
+	}
+
+	if !strings.Contains(amount, ".") {
+		amount += ".00"
+	} else if parts := strings.SplitN(amount, ".", 2); len(parts[1]) == 1 {
+		amount += "0"
+	}
+	if !orderAmountRe.MatchString(amount) {
+		return 0, false
+	}
+
+	minor, err := parseOrderAmountMinorUnits(amount)
+	if err != nil || minor.MinorUnits() > math.MaxInt {
+		return 0, false
+	}
+
+	return int(minor.MinorUnits()), true
+}
+
 func (p *Response) SubmerchantIDStatus() (string, bool) {
 	if p == nil || p.ResponseData == nil || p.ResponseData.SubmerchantIDStatus == nil {
 		return "", false
@@ -137,9 +530,26 @@ func (p *Response) SubmerchantIDStatus() (string, bool) {
 	return *p.ResponseData.SubmerchantIDStatus, true
 }
 
+// SubmerchantStatus returns the parsed submerchant_id_status, if present.
+func (p *Response) SubmerchantStatus() (SubmerchantStatus, bool) {
+	raw, ok := p.SubmerchantIDStatus()
+	if !ok {
+		return "", false
+	}
+
+	return ParseSubmerchantStatus(raw), true
+}
+
+// utf8BOM is prepended to JSON bodies by some proxies.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// UnmarshalJSONResponse parses a Platon JSON response. A leading UTF-8 BOM and
+// surrounding whitespace are ignored.
 func UnmarshalJSONResponse(data []byte) (*Response, error) {
 	var resp Response
 
+	data = bytes.TrimSpace(bytes.TrimPrefix(bytes.TrimSpace(data), utf8BOM))
+
 	if err := json.Unmarshal(data, &resp); err != nil {
 		return nil, fmt.Errorf("error unmarshalling JSON response: %w", err)
 	}
@@ -161,6 +571,20 @@ func (p *Response) UnmarshalJSON(data []byte) error {
 		Hash                *string         `json:"hash,omitempty"`
 		ErrorMessage        json.RawMessage `json:"error_message"`
 		DeclineReason       json.RawMessage `json:"decline_reason"`
+		Amount              json.RawMessage `json:"amount"`
+		ApprovedAmount      json.RawMessage `json:"approved_amount"`
+		Card                json.RawMessage `json:"card"`
+		CardMask            json.RawMessage `json:"card_mask"`
+		Brand               json.RawMessage `json:"brand"`
+		CardBrand           json.RawMessage `json:"card_brand"`
+		IssuingBank         json.RawMessage `json:"issuing_bank"`
+		PayerName           json.RawMessage `json:"payer_name"`
+		Name                json.RawMessage `json:"name"`
+		Fee                 json.RawMessage `json:"fee"`
+		Transactions        json.RawMessage `json:"transactions"`
+		RedirectURL         string          `json:"redirect_url,omitempty"`
+		RedirectMethod      string          `json:"redirect_method,omitempty"`
+		RedirectParams      json.RawMessage `json:"redirect_params,omitempty"`
 	}
 
 	var raw responseJSON
@@ -204,13 +628,188 @@ func (p *Response) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	amount, err := normalizeOptionalResponseAmount(raw.Amount)
+	if err != nil {
+		return fmt.Errorf("decode amount: %w", err)
+	}
+	approvedAmount, err := normalizeOptionalResponseAmount(raw.ApprovedAmount)
+	if err != nil {
+		return fmt.Errorf("decode approved_amount: %w", err)
+	}
+
+	fee, err := normalizeOptionalResponseAmount(raw.Fee)
+	if err != nil {
+		return fmt.Errorf("decode fee: %w", err)
+	}
+	card, err := firstResponseString(raw.Card, raw.CardMask)
+	if err != nil {
+		return fmt.Errorf("decode card: %w", err)
+	}
+	brand, err := firstResponseString(raw.Brand, raw.CardBrand)
+	if err != nil {
+		return fmt.Errorf("decode brand: %w", err)
+	}
+	issuingBank, err := normalizeOptionalResponseString(raw.IssuingBank)
+	if err != nil {
+		return fmt.Errorf("decode issuing_bank: %w", err)
+	}
+	payerName, err := firstResponseString(raw.PayerName, raw.Name)
+	if err != nil {
+		return fmt.Errorf("decode payer_name: %w", err)
+	}
+
+	transactions, transactionErrs := decodeResponseTransactions(raw.Transactions)
+	redirectParams, err := decodeRedirectParams(raw.RedirectParams)
+	if err != nil {
+		return fmt.Errorf("decode redirect_params: %w", err)
+	}
+
 	p.ResponseData = responseData
+	p.Amount = amount
+	p.ApprovedAmountRaw = approvedAmount
+	p.Card = card
+	p.Brand = brand
+	p.IssuingBank = issuingBank
+	p.PayerName = payerName
+	p.FeeRaw = fee
+	p.Transactions = transactions
+	p.TransactionErrors = transactionErrs
 	p.ErrorMessage = errorMessage
 	p.DeclineReason = declineReason
+	p.RedirectURL = strings.TrimSpace(raw.RedirectURL)
+	p.RedirectMethod = strings.TrimSpace(raw.RedirectMethod)
+	p.RedirectParams = redirectParams
 
 	return nil
 }
 
+// firstResponseString returns the first non-empty of the optional string
+// fields.
+func firstResponseString(fields ...json.RawMessage) (string, error) {
+	for _, field := range fields {
+		value, err := normalizeOptionalResponseString(field)
+		if err != nil {
+			return "", err
+		}
+		if value != "" {
+			return value, nil
+		}
+	}
+
+	return "", nil
+}
+
+// decodeResponseTransactions accepts the transaction list either as a JSON
+// array or, when Platon reports a single transaction, as a bare object.
+// Entries that cannot be decoded are skipped and reported in the returned
+// errors.
+func decodeResponseTransactions(raw json.RawMessage) ([]ResponseTransaction, []error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	if raw[0] == '{' {
+		var transaction ResponseTransaction
+		if err := json.Unmarshal(raw, &transaction); err != nil {
+			return nil, []error{fmt.Errorf("decode transactions: %w", err)}
+		}
+
+		return []ResponseTransaction{transaction}, nil
+	}
+
+	var entries []json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, []error{fmt.Errorf("decode transactions: %w", err)}
+	}
+
+	var (
+		transactions []ResponseTransaction
+		errs         []error
+	)
+	for i, entry := range entries {
+		var transaction ResponseTransaction
+		if err := json.Unmarshal(entry, &transaction); err != nil {
+			errs = append(errs, fmt.Errorf("decode transactions[%d]: %w", i, err))
+			continue
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, errs
+}
+
+// decodeRedirectParams accepts redirect_params as a JSON object (non-string
+// values are kept in their JSON form), a urlencoded string ("PaReq=...&MD=...") or a string holding
+// a JSON object.
+func decodeRedirectParams(raw json.RawMessage) (map[string]string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	if raw[0] == '"' {
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, err
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, nil
+		}
+		if strings.HasPrefix(text, "{") {
+			return decodeRedirectParams(json.RawMessage(text))
+		}
+
+		values, err := url.ParseQuery(text)
+		if err != nil {
+			return nil, err
+		}
+		params := make(map[string]string, len(values))
+		for key := range values {
+			params[key] = values.Get(key)
+		}
+
+		return params, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string, len(fields))
+	for key, value := range fields {
+		text, err := normalizeOptionalResponseString(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", key, err)
+		}
+		params[key] = text
+	}
+
+	return params, nil
+}
+
+// normalizeOptionalResponseAmount accepts amount encoded either as a JSON string
+// or as a JSON number and returns its textual form.
+func normalizeOptionalResponseAmount(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return "", nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strings.TrimSpace(text), nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(raw, &number); err != nil {
+		return "", err
+	}
+
+	return number.String(), nil
+}
+
 func normalizeOptionalResponseString(raw json.RawMessage) (string, error) {
 	raw = bytes.TrimSpace(raw)
 	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
@@ -25,6 +25,8 @@
 package platon
 
 import (
+	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -82,6 +84,22 @@ func TestUnmarshalJSONResponse_AllowsNullErrorMessage(t *testing.T) {
 	}
 }
 
+func TestUnmarshalJSONResponse_TrimsBOMAndWhitespace(t *testing.T) {
+	raw := []byte("\xef\xbb\xbf \r\n{\"action\":\"SALE\",\"result\":\"ACCEPTED\",\"trans_id\":\"t-1\"}\n")
+
+	resp, err := UnmarshalJSONResponse(raw)
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+	}
+
+	if resp.Result == nil || *resp.Result != ResultAccepted {
+		t.Fatalf("expected ACCEPTED result, got %v", resp.Result)
+	}
+	if resp.TransId == nil || *resp.TransId != "t-1" {
+		t.Fatalf("expected trans_id t-1, got %v", resp.TransId)
+	}
+}
+
 func TestUnmarshalJSONResponse_DeclinedReasonReturnsError(t *testing.T) {
 	raw := []byte(`{"result":"DECLINED","decline_reason":"102: Token is not active","error_message":null}`)
 
@@ -138,3 +156,430 @@ func TestUnmarshalJSONResponse_ErrorMessageObject(t *testing.T) {
 		t.Fatalf("expected parsed object in error, got %q", gotErr.Error())
 	}
 }
+
+func TestResponse_IsAlreadyCaptured(t *testing.T) {
+	tests := []struct {
+		payload string
+		want    bool
+	}{
+		{payload: `{"result":"ERROR","error_message":"Transaction already captured"}`, want: true},
+		{payload: `{"result":"ERROR","error_message":"TRANSACTION IS ALREADY SETTLED"}`, want: true},
+		{payload: `{"result":"DECLINED","decline_reason":"Already captured"}`, want: true},
+		{payload: `{"result":"ERROR","error_message":"Invalid hash"}`, want: false},
+		{payload: `{"result":"ACCEPTED"}`, want: false},
+	}
+
+	for _, tt := range tests {
+		resp, err := UnmarshalJSONResponse([]byte(tt.payload))
+		if err != nil {
+			t.Fatalf("UnmarshalJSONResponse(%s) error: %v", tt.payload, err)
+		}
+		if got := resp.IsAlreadyCaptured(); got != tt.want {
+			t.Fatalf("IsAlreadyCaptured(%s) = %v, want %v", tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestResponse_IsOrderNotFound(t *testing.T) {
+	tests := []struct {
+		payload string
+		want    bool
+	}{
+		{payload: `{"result":"ERROR","error_message":"Order not found"}`, want: true},
+		{payload: `{"result":"ERROR","error_message":"ORDER NOT FOUND"}`, want: true},
+		{payload: `{"result":"ERROR","error_message":"Invalid hash"}`, want: false},
+		{payload: `{"result":"SUCCESS","status":"SETTLED"}`, want: false},
+	}
+
+	for _, tt := range tests {
+		resp, err := UnmarshalJSONResponse([]byte(tt.payload))
+		if err != nil {
+			t.Fatalf("UnmarshalJSONResponse(%s) error: %v", tt.payload, err)
+		}
+		if got := resp.IsOrderNotFound(); got != tt.want {
+			t.Fatalf("IsOrderNotFound(%s) = %v, want %v", tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestResponse_IsPending(t *testing.T) {
+	tests := []struct {
+		payload string
+		want    bool
+	}{
+		{payload: `{"result":"ACCEPTED","status":"PENDING"}`, want: true},
+		{payload: `{"result":"ACCEPTED","status":"3ds"}`, want: true},
+		{payload: `{"result":"REDIRECT"}`, want: true},
+		{payload: `{"result":"ACCEPTED","status":"SETTLED"}`, want: false},
+		{payload: `{"result":"DECLINED","status":"DECLINED"}`, want: false},
+		{payload: `{"result":"ACCEPTED"}`, want: false},
+	}
+
+	for _, tt := range tests {
+		var resp Response
+		if err := json.Unmarshal([]byte(tt.payload), &resp); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error: %v", tt.payload, err)
+		}
+		if got := resp.IsPending(); got != tt.want {
+			t.Fatalf("IsPending(%s) = %v, want %v", tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestResponse_AmountMinorUnits(t *testing.T) {
+	tests := []struct {
+		payload string
+		want    int
+		ok      bool
+	}{
+		{payload: `{"amount":"10.00"}`, want: 1000, ok: true},
+		{payload: `{"amount":10.5}`, want: 1050, ok: true},
+		{payload: `{"amount":7}`, want: 700, ok: true},
+		{payload: `{"amount":"abc"}`, ok: false},
+		{payload: `{}`, ok: false},
+	}
+
+	for _, tt := range tests {
+		resp, err := UnmarshalJSONResponse([]byte(tt.payload))
+		if err != nil {
+			t.Fatalf("UnmarshalJSONResponse(%s) error: %v", tt.payload, err)
+		}
+		got, ok := resp.AmountMinorUnits()
+		if ok != tt.ok || got != tt.want {
+			t.Fatalf("AmountMinorUnits(%s) = %d, %v; want %d, %v", tt.payload, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestResponse_PartialApproval(t *testing.T) {
+	tests := []struct {
+		name         string
+		payload      string
+		wantApproved int
+		wantOK       bool
+		wantPartial  bool
+	}{
+		{
+			name:         "partial",
+			payload:      `{"action":"SALE","result":"SUCCESS","status":"PENDING","trans_id":"t-1","amount":"100.00","approved_amount":"60.00"}`,
+			wantApproved: 6000,
+			wantOK:       true,
+			wantPartial:  true,
+		},
+		{
+			name:         "full",
+			payload:      `{"action":"SALE","result":"SUCCESS","amount":"100.00","approved_amount":100}`,
+			wantApproved: 10000,
+			wantOK:       true,
+		},
+		{
+			name:    "not reported",
+			payload: `{"action":"SALE","result":"SUCCESS","amount":"100.00"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				resp, err := UnmarshalJSONResponse([]byte(tt.payload))
+				if err != nil {
+					t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+				}
+
+				approved, ok := resp.ApprovedAmount()
+				if approved != tt.wantApproved || ok != tt.wantOK {
+					t.Fatalf("ApprovedAmount() = %d, %v; want %d, %v", approved, ok, tt.wantApproved, tt.wantOK)
+				}
+				if got := resp.IsPartialApproval(); got != tt.wantPartial {
+					t.Fatalf("IsPartialApproval() = %v, want %v", got, tt.wantPartial)
+				}
+			},
+		)
+	}
+
+	var nilResp *Response
+	if nilResp.IsPartialApproval() {
+		t.Fatal("nil response must not be a partial approval")
+	}
+}
+
+// partialCaptureStatusFixture is a GET_TRANS_STATUS response for a 250.00 HOLD
+// that was captured for 100.00.
+const partialCaptureStatusFixture = `{
+  "action": "GET_TRANS_STATUS",
+  "result": "SUCCESS",
+  "status": "SETTLED",
+  "order_id": "order-42",
+  "trans_id": "d1a1a8d0-8ea2-11ee-8ae2-0242ac120002",
+  "trans_date": "2024-11-29 10:15:02",
+  "amount": "100.00",
+  "currency": "UAH",
+  "hash": "2a5e7b1f9c0d43e8a6b3f1c2d4e5f607",
+  "transactions": [
+    {"id": "90861", "type": "SALE", "status": "SUCCESS", "amount": "250.00", "date": "2024-11-29 10:15:02"},
+    {"id": "90862", "type": "CAPTURE", "status": "SUCCESS", "amount": "100.00", "date": "2024-11-29 10:20:11"}
+  ]
+}`
+
+func TestUnmarshalJSONResponse_PartialCaptureFixture(t *testing.T) {
+	resp, err := UnmarshalJSONResponse([]byte(partialCaptureStatusFixture))
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+	}
+
+	if got, ok := resp.AuthAmount(); !ok || got != 25000 {
+		t.Fatalf("AuthAmount() = %d, %v; want 25000, true", got, ok)
+	}
+	if got, ok := resp.CapturedAmount(); !ok || got != 10000 {
+		t.Fatalf("CapturedAmount() = %d, %v; want 10000, true", got, ok)
+	}
+	if got, ok := resp.RemainingAuth(); !ok || got != 15000 {
+		t.Fatalf("RemainingAuth() = %d, %v; want 15000, true", got, ok)
+	}
+}
+
+func TestResponse_RemainingAuth(t *testing.T) {
+	tests := []struct {
+		name         string
+		payload      string
+		wantCaptured int
+		wantRemain   int
+		wantOK       bool
+	}{
+		{
+			name:       "pending hold",
+			payload:    `{"status":"PENDING","amount":"250.00"}`,
+			wantRemain: 25000,
+			wantOK:     true,
+		},
+		{
+			name:         "settled without capture entries",
+			payload:      `{"status":"SETTLED","amount":"40.00","transactions":[{"type":"SALE","status":"SUCCESS","amount":"50.00"}]}`,
+			wantCaptured: 4000,
+			wantRemain:   1000,
+			wantOK:       true,
+		},
+		{
+			name:         "failed capture is not counted",
+			payload:      `{"status":"PENDING","amount":"50.00","transactions":[{"type":"SALE","status":"SUCCESS","amount":"50.00"},{"type":"CAPTURE","status":"FAIL","amount":"20.00"},{"type":"CAPTURE","status":"SUCCESS","amount":"10.00"}]}`,
+			wantCaptured: 1000,
+			wantRemain:   4000,
+			wantOK:       true,
+		},
+		{
+			name:         "over captured",
+			payload:      `{"status":"SETTLED","transactions":[{"type":"SALE","status":"SUCCESS","amount":"50.00"},{"type":"CAPTURE","status":"SUCCESS","amount":"60.00"}]}`,
+			wantCaptured: 6000,
+			wantOK:       true,
+		},
+		{
+			name:    "no amount",
+			payload: `{"status":"SETTLED"}`,
+		},
+		{
+			name:    "unknown capture state",
+			payload: `{"status":"REFUND","amount":"40.00"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				resp, err := UnmarshalJSONResponse([]byte(tt.payload))
+				if err != nil {
+					t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+				}
+
+				remain, ok := resp.RemainingAuth()
+				if ok != tt.wantOK || remain != tt.wantRemain {
+					t.Fatalf("RemainingAuth() = %d, %v; want %d, %v", remain, ok, tt.wantRemain, tt.wantOK)
+				}
+				if tt.wantOK {
+					if captured, _ := resp.CapturedAmount(); captured != tt.wantCaptured {
+						t.Fatalf("CapturedAmount() = %d, want %d", captured, tt.wantCaptured)
+					}
+				}
+			},
+		)
+	}
+
+	var nilResp *Response
+	if _, ok := nilResp.RemainingAuth(); ok {
+		t.Fatal("RemainingAuth() on nil response should report false")
+	}
+}
+
+// multiTransactionStatusFixture is a GET_TRANS_STATUS response for a SALE that
+// was later partially refunded.
+const multiTransactionStatusFixture = `{
+  "action": "GET_TRANS_STATUS",
+  "result": "SUCCESS",
+  "status": "REFUND",
+  "order_id": "order-43",
+  "trans_id": "5e3a9c2a-8ea3-11ee-b9d1-0242ac120002",
+  "amount": "50.00",
+  "transactions": [
+    {"id": 90871, "type": "SALE", "status": "SUCCESS", "amount": "50.00", "date": "2024-11-29 10:15:02"},
+    {"id": "90872", "type": "CREDITVOID", "status": "FAIL", "amount": 20, "date": "2024-11-29 11:00:40", "decline_reason": "Insufficient funds on merchant balance"},
+    {"trans_id": "90873", "type": "CREDITVOID", "status": "SUCCESS", "amount": "20.00", "date": "2024-11-29 11:05:13", "decline_reason": null}
+  ]
+}`
+
+func TestUnmarshalJSONResponse_Transactions(t *testing.T) {
+	resp, err := UnmarshalJSONResponse([]byte(multiTransactionStatusFixture))
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+	}
+
+	want := []ResponseTransaction{
+		{ID: "90871", Type: "SALE", Status: "SUCCESS", Amount: "50.00", Date: "2024-11-29 10:15:02"},
+		{ID: "90872", Type: "CREDITVOID", Status: "FAIL", Amount: "20", Date: "2024-11-29 11:00:40", DeclineReason: "Insufficient funds on merchant balance"},
+		{ID: "90873", Type: "CREDITVOID", Status: "SUCCESS", Amount: "20.00", Date: "2024-11-29 11:05:13"},
+	}
+	if !reflect.DeepEqual(resp.Transactions, want) {
+		t.Fatalf("Transactions mismatch:\nwant %+v\ngot  %+v", want, resp.Transactions)
+	}
+
+	last, ok := resp.LastTransaction()
+	if !ok || last.ID != "90873" {
+		t.Fatalf("LastTransaction() = %+v, %v; want id 90873", last, ok)
+	}
+	if minor, ok := resp.Transactions[1].AmountMinorUnits(); !ok || minor != 2000 {
+		t.Fatalf("AmountMinorUnits() = %d, %v; want 2000, true", minor, ok)
+	}
+	if !resp.HasSuccessfulSale() {
+		t.Fatal("HasSuccessfulSale() = false, want true")
+	}
+}
+
+func TestUnmarshalJSONResponse_TransactionsForms(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     string
+		wantCount   int
+		wantSuccess bool
+	}{
+		{
+			name:        "single object",
+			payload:     `{"result":"SUCCESS","transactions":{"id":"1","type":"sale","status":"settled","amount":"1.00"}}`,
+			wantCount:   1,
+			wantSuccess: true,
+		},
+		{
+			name:      "failed sale",
+			payload:   `{"result":"SUCCESS","transactions":[{"id":"1","type":"SALE","status":"FAIL","amount":"1.00"}]}`,
+			wantCount: 1,
+		},
+		{
+			name:    "empty list",
+			payload: `{"result":"SUCCESS","transactions":[]}`,
+		},
+		{
+			name:    "null",
+			payload: `{"result":"SUCCESS","transactions":null}`,
+		},
+		{
+			name:    "absent",
+			payload: `{"result":"SUCCESS"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				resp, err := UnmarshalJSONResponse([]byte(tt.payload))
+				if err != nil {
+					t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+				}
+				if len(resp.Transactions) != tt.wantCount {
+					t.Fatalf("len(Transactions) = %d, want %d", len(resp.Transactions), tt.wantCount)
+				}
+				if _, ok := resp.LastTransaction(); ok != (tt.wantCount > 0) {
+					t.Fatalf("LastTransaction() ok = %v, want %v", ok, tt.wantCount > 0)
+				}
+				if got := resp.HasSuccessfulSale(); got != tt.wantSuccess {
+					t.Fatalf("HasSuccessfulSale() = %v, want %v", got, tt.wantSuccess)
+				}
+			},
+		)
+	}
+
+	resp, err := UnmarshalJSONResponse([]byte(`{"status":"SETTLED","transactions":"bogus"}`))
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() malformed transactions error: %v", err)
+	}
+	if resp.Status == nil || *resp.Status != "SETTLED" || len(resp.Transactions) != 0 || len(resp.TransactionErrors) != 1 {
+		t.Fatalf("malformed transactions: status %v, %d transactions, errors %v", resp.Status, len(resp.Transactions), resp.TransactionErrors)
+	}
+
+	resp, err = UnmarshalJSONResponse([]byte(`{"status":"SETTLED","transactions":[{"id":1,"type":"SALE","status":"SUCCESS","amount":"10.00"},{"id":{"nested":true}},"bogus",{"id":2,"type":"CAPTURE","status":"SUCCESS","amount":"4.00"}]}`))
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() partial transactions error: %v", err)
+	}
+	if len(resp.Transactions) != 2 || resp.Transactions[0].ID != "1" || resp.Transactions[1].ID != "2" {
+		t.Fatalf("partial transactions: Transactions = %+v, want ids 1 and 2", resp.Transactions)
+	}
+	if len(resp.TransactionErrors) != 2 || !strings.Contains(resp.TransactionErrors[0].Error(), "transactions[1]") || !strings.Contains(resp.TransactionErrors[1].Error(), "transactions[2]") {
+		t.Fatalf("partial transactions: TransactionErrors = %v, want entries 1 and 2", resp.TransactionErrors)
+	}
+}
+
+func TestUnmarshalJSONResponse_TransDetails(t *testing.T) {
+	raw := []byte(`{"action":"GET_TRANS_DETAILS","result":"SUCCESS","status":"SETTLED","order_id":"order-1","trans_id":"632508054","amount":"100.00","fee":"2.75","card":"411111****1111","payer_name":"Ivan Petrenko"}`)
+
+	resp, err := UnmarshalJSONResponse(raw)
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+	}
+	if resp.Card != "411111****1111" || resp.PayerName != "Ivan Petrenko" || resp.FeeRaw != "2.75" {
+		t.Fatalf("detail fields mismatch: card=%q name=%q fee=%q", resp.Card, resp.PayerName, resp.FeeRaw)
+	}
+	if got, ok := resp.Fee(); !ok || got != 275 {
+		t.Fatalf("Fee() = %d, %v; want 275, true", got, ok)
+	}
+
+	alt, err := UnmarshalJSONResponse([]byte(`{"result":"SUCCESS","card_mask":"535555****4444","name":"Olena","fee":null}`))
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+	}
+	if alt.Card != "535555****4444" || alt.PayerName != "Olena" {
+		t.Fatalf("fallback keys mismatch: card=%q name=%q", alt.Card, alt.PayerName)
+	}
+	if _, ok := alt.Fee(); ok {
+		t.Fatal("Fee() should report false without fee")
+	}
+}
+
+const saleCardFixture = `{
+  "action": "SALE",
+  "result": "SUCCESS",
+  "status": "SETTLED",
+  "order_id": "order-42",
+  "trans_id": "632508054",
+  "card": "411111****1111",
+  "brand": "VISA",
+  "issuing_bank": "PrivatBank"
+}`
+
+func TestUnmarshalJSONResponse_CardFixture(t *testing.T) {
+	resp, err := UnmarshalJSONResponse([]byte(saleCardFixture))
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+	}
+
+	if resp.MaskedCard() != "411111****1111" || resp.Brand != "VISA" || resp.IssuingBank != "PrivatBank" {
+		t.Fatalf("card fields mismatch: card=%q brand=%q bank=%q", resp.MaskedCard(), resp.Brand, resp.IssuingBank)
+	}
+	if resp.First6() != "411111" || resp.Last4() != "1111" {
+		t.Fatalf("First6/Last4 = %q/%q, want 411111/1111", resp.First6(), resp.Last4())
+	}
+
+	var empty *Response
+	if empty.MaskedCard() != "" || empty.First6() != "" || empty.Last4() != "" {
+		t.Fatal("nil response must have no card")
+	}
+	short := &Response{Card: "1111"}
+	if short.First6() != "" || short.Last4() != "" {
+		t.Fatal("short card must have no First6/Last4")
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// countryCodes is the ISO 3166-1 alpha-2 list of officially assigned codes.
+var countryCodes = codeSet(
+	"AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ " +
+		"BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ " +
+		"CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ " +
+		"DE DJ DK DM DO DZ " +
+		"EC EE EG EH ER ES ET " +
+		"FI FJ FK FM FO FR " +
+		"GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY " +
+		"HK HM HN HR HT HU " +
+		"ID IE IL IM IN IO IQ IR IS IT " +
+		"JE JM JO JP " +
+		"KE KG KH KI KM KN KP KR KW KY KZ " +
+		"LA LB LC LI LK LR LS LT LU LV LY " +
+		"MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ " +
+		"NA NC NE NF NG NI NL NO NP NR NU NZ " +
+		"OM " +
+		"PA PE PF PG PH PK PL PM PN PR PS PT PW PY " +
+		"QA " +
+		"RE RO RS RU RW " +
+		"SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ " +
+		"TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ " +
+		"UA UG UM US UY UZ " +
+		"VA VC VE VG VI VN VU " +
+		"WF WS " +
+		"YE YT " +
+		"ZA ZM ZW",
+)
+
+var (
+	stateCodesMu sync.RWMutex
+	// stateCodes holds two-letter subdivision codes accepted as payer_state in
+	// addition to country codes. US states are registered by default.
+	stateCodes = codeSet(
+		"AL AK AZ AR CA CO CT DE DC FL GA HI ID IL IN IA KS KY LA ME MD MA MI MN MS MO " +
+			"MT NE NV NH NJ NM NY NC ND OH OK OR PA RI SC SD TN TX UT VT VA WA WV WI WY",
+	)
+)
+
+func codeSet(codes string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, code := range strings.Fields(codes) {
+		set[code] = struct{}{}
+	}
+
+	return set
+}
+
+// ValidateCountryCode checks that code is an ISO 3166-1 alpha-2 country code.
+// The check is case-insensitive and ignores surrounding whitespace.
+func ValidateCountryCode(code string) error {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	if _, ok := countryCodes[normalized]; !ok {
+		return fmt.Errorf("invalid country code %q (expected ISO 3166-1 alpha-2, e.g. UA)", code)
+	}
+
+	return nil
+}
+
+// ValidateStateCode checks a two-letter payer_state value. Platon accepts the
+// country code where a country has no states, so any valid country code passes,
+// as does any subdivision registered with RegisterStateCodes.
+func ValidateStateCode(code string) error {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	if _, ok := countryCodes[normalized]; ok {
+		return nil
+	}
+
+	stateCodesMu.RLock()
+	_, ok := stateCodes[normalized]
+	stateCodesMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("invalid state code %q (expected a two-letter subdivision or country code)", code)
+	}
+
+	return nil
+}
+
+// RegisterStateCodes adds two-letter subdivision codes accepted by
+// ValidateStateCode. It is safe for concurrent use.
+func RegisterStateCodes(codes ...string) {
+	stateCodesMu.Lock()
+	defer stateCodesMu.Unlock()
+
+	for _, code := range codes {
+		normalized := strings.ToUpper(strings.TrimSpace(code))
+		if len(normalized) == 2 {
+			stateCodes[normalized] = struct{}{}
+		}
+	}
+}
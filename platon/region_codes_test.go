@@ -0,0 +1,85 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCountryCode(t *testing.T) {
+	for _, code := range []string{"UA", "ua", " PL ", "US", "GB"} {
+		if err := ValidateCountryCode(code); err != nil {
+			t.Fatalf("ValidateCountryCode(%q) unexpected error: %v", code, err)
+		}
+	}
+
+	for _, code := range []string{"", "XX", "UK", "UKR", "U", "1A"} {
+		err := ValidateCountryCode(code)
+		if err == nil {
+			t.Fatalf("ValidateCountryCode(%q) expected error", code)
+		}
+		if !strings.Contains(err.Error(), "invalid country code") {
+			t.Fatalf("ValidateCountryCode(%q) unexpected error text: %v", code, err)
+		}
+	}
+}
+
+func TestValidateStateCode(t *testing.T) {
+	for _, code := range []string{"UA", "ny", "DC"} {
+		if err := ValidateStateCode(code); err != nil {
+			t.Fatalf("ValidateStateCode(%q) unexpected error: %v", code, err)
+		}
+	}
+
+	if err := ValidateStateCode("QQ"); err == nil {
+		t.Fatal("ValidateStateCode(QQ) expected error")
+	}
+	if err := ValidateStateCode("KYV"); err == nil {
+		t.Fatal("ValidateStateCode(KYV) expected error")
+	}
+}
+
+func TestRegisterStateCodes(t *testing.T) {
+	if err := ValidateStateCode("QC"); err == nil {
+		t.Fatal("QC must not be accepted before registration")
+	}
+
+	RegisterStateCodes(" qc ", "ONTARIO")
+	t.Cleanup(
+		func() {
+			stateCodesMu.Lock()
+			delete(stateCodes, "QC")
+			stateCodesMu.Unlock()
+		},
+	)
+
+	if err := ValidateStateCode("QC"); err != nil {
+		t.Fatalf("ValidateStateCode(QC) after registration: %v", err)
+	}
+	if err := ValidateStateCode("ONTARIO"); err == nil {
+		t.Fatal("codes longer than two letters must be ignored")
+	}
+}
@@ -26,9 +26,12 @@ package platon
 
 import (
 	"fmt"
+	"math"
+	"strings"
 
 	"github.com/stremovskyy/go-platon/currency"
 	"github.com/stremovskyy/go-platon/internal/utils"
+	"github.com/stremovskyy/go-platon/log"
 )
 
 func NewRequest(action ActionCode) *Request {
@@ -137,20 +140,58 @@ func (r *Request) WithChannelNoAmountVerification() *Request {
 	return r
 }
 
+// Err returns the first error reported by a builder method, if any.
+// SignAndPrepare returns the same error.
+func (r *Request) Err() error {
+	if r == nil {
+		return nil
+	}
+
+	return r.buildErr
+}
+
+func (r *Request) setBuildErr(err error) {
+	if r.buildErr == nil {
+		r.buildErr = err
+	}
+}
+
+// WithPayerIP sets payer_ip, normalized with NormalizeIP. A nil ip is
+// replaced with DefaultPayerIP; use WithPayerIPStrict to treat a missing IP as
+// an error instead.
 func (r *Request) WithPayerIP(ip *string) *Request {
 	if r == nil {
 		return nil
 	}
 
 	if ip == nil {
-		r.PayerIp = utils.Ref("127.0.0.1")
+		r.PayerIp = utils.Ref(DefaultPayerIP)
 	} else {
-		r.PayerIp = ip
+		r.PayerIp = utils.Ref(NormalizeIP(*ip))
 	}
 
 	return r
 }
 
+// WithPayerIPStrict sets payer_ip, normalized with NormalizeIP, without a
+// fallback. A nil or empty ip is recorded as a build error (see Err) and fails
+// SignAndPrepare, so a missing client IP is caught instead of being masked
+// with a loopback address.
+func (r *Request) WithPayerIPStrict(ip *string) *Request {
+	if r == nil {
+		return nil
+	}
+
+	if ip == nil || strings.TrimSpace(*ip) == "" {
+		r.setBuildErr(NewValidationError("", "payer_ip", "is required"))
+		return r
+	}
+
+	r.PayerIp = utils.Ref(NormalizeIP(*ip))
+
+	return r
+}
+
 func (r *Request) WithTermsURL(url *string) *Request {
 	if r == nil {
 		return nil
@@ -171,6 +212,17 @@ func (r *Request) WithCardNumber(pan *string) *Request {
 	return r
 }
 
+// WithSkipLuhn disables the card_number Luhn check performed by SignAndPrepare.
+func (r *Request) WithSkipLuhn(skip bool) *Request {
+	if r == nil {
+		return nil
+	}
+
+	r.SkipLuhn = skip
+
+	return r
+}
+
 func (r *Request) WithCardToken(token *string) *Request {
 	if r == nil {
 		return nil
@@ -201,6 +253,31 @@ func (r *Request) WithCardExpYear(year *string) *Request {
 	return r
 }
 
+// WithClock sets the clock used by the card expiry check. A nil clock uses SystemClock.
+func (r *Request) WithClock(clock Clock) *Request {
+	if r == nil {
+		return nil
+	}
+
+	r.Clock = clock
+
+	return r
+}
+
+// WithCardExpiry sets card_exp_month and card_exp_year, normalized with
+// NormalizeCardExpiry: "1"/"27" is sent as "01"/"2027".
+func (r *Request) WithCardExpiry(month, year string) *Request {
+	if r == nil {
+		return nil
+	}
+
+	month, year = NormalizeCardExpiry(month, year)
+	r.CardExpMonth = &month
+	r.CardExpYear = &year
+
+	return r
+}
+
 func (r *Request) WithCardCvv2(cvv2 *string) *Request {
 	if r == nil {
 		return nil
@@ -221,16 +298,33 @@ func (r *Request) WithPayerEmail(email *string) *Request {
 	return r
 }
 
+// WithPayerPhone sets payer_phone, normalized with NormalizePhone.
 func (r *Request) WithPayerPhone(phone *string) *Request {
 	if r == nil {
 		return nil
 	}
 
+	if phone != nil {
+		normalized := NormalizePhone(*phone)
+		phone = &normalized
+	}
 	r.PayerPhone = phone
 
 	return r
 }
 
+// WithPayerPhonePrefixes restricts payer_phone to numbers starting with one of
+// prefixes. Without prefixes any number is accepted.
+func (r *Request) WithPayerPhonePrefixes(prefixes ...string) *Request {
+	if r == nil {
+		return nil
+	}
+
+	r.PayerPhonePrefixes = prefixes
+
+	return r
+}
+
 func (r *Request) WithPayerFirstName(firstName *string) *Request {
 	if r == nil {
 		return nil
@@ -323,6 +417,16 @@ func (r *Request) WithPaymentToken(token *string) *Request {
 	return r
 }
 
+// WithCardNetwork sets the wallet card network recorded with the request.
+func (r *Request) WithCardNetwork(network string) *Request {
+	if r == nil {
+		return nil
+	}
+
+	r.CardNetwork = strings.TrimSpace(network)
+	return r
+}
+
 func (r *Request) WithHoldAuth() *Request {
 	if r == nil {
 		return nil
@@ -337,7 +441,8 @@ func (r *Request) WithVerifyAmount(amount float32) *Request {
 		return nil
 	}
 
-	r.OrderAmount = fmt.Sprintf("%.2f", amount)
+	r.OrderAmount = MoneyFromMinorUnits(int64(math.Round(float64(amount) * 100))).String()
+	r.orderAmountMinorUnits = nil
 
 	if amount <= 0 {
 		r.OrderAmount = VerifyNoAmount.String()
@@ -351,8 +456,11 @@ func (r *Request) WithOrderAmountMinorUnits(amount int) *Request {
 		return nil
 	}
 
-	// amount is in minor units (e.g. kopecks); Platon expects a decimal string with 2 digits.
-	r.OrderAmount = fmt.Sprintf("%.2f", float64(amount)/100)
+	// amount is in minor units (e.g. kopecks); Platon expects a decimal string
+	// with the currency's number of digits ("10.00" for UAH, "1000" for JPY).
+	minorUnits := MoneyFromMinorUnits(int64(amount))
+	r.orderAmountMinorUnits = &minorUnits
+	r.OrderAmount = formatAmountForCurrency(minorUnits, r.OrderCurrency)
 	return r
 }
 
@@ -362,15 +470,34 @@ func (r *Request) WithOrderAmount(amount string) *Request {
 	}
 
 	r.OrderAmount = amount
+	r.orderAmountMinorUnits = nil
 	return r
 }
 
-func (r *Request) ForCurrency(currency currency.Code) *Request {
+// ForCurrency sets order_currency. A non-empty code outside
+// currency.Supported is recorded as a build error (see Err), so typos fail
+// before signing instead of at the gateway.
+func (r *Request) ForCurrency(code currency.Code) *Request {
 	if r == nil {
 		return nil
 	}
 
-	r.OrderCurrency = currency.String()
+	if code != "" && !currency.IsSupported(code) {
+		r.setBuildErr(wrapValidationError("", "order_currency", fmt.Errorf("unsupported currency %q (supported: %v)", code, currency.Supported())))
+	}
+
+	previous := r.OrderCurrency
+	r.OrderCurrency = code.String()
+
+	// Amounts set in minor units follow the new currency's exponent, unless
+	// they were overwritten since.
+	if m := r.orderAmountMinorUnits; m != nil && r.OrderAmount == formatAmountForCurrency(*m, previous) {
+		r.OrderAmount = formatAmountForCurrency(*m, r.OrderCurrency)
+	}
+	if m := r.amountMinorUnits; m != nil && r.Amount == formatAmountForCurrency(*m, previous) {
+		r.Amount = formatAmountForCurrency(*m, r.OrderCurrency)
+	}
+
 	return r
 }
 
@@ -426,8 +553,21 @@ func (r *Request) WithAmountMinorUnits(amount int) *Request {
 		return nil
 	}
 
-	// amount is in minor units (e.g. kopecks); Platon expects a decimal string with 2 digits.
-	r.Amount = fmt.Sprintf("%.2f", float64(amount)/100)
+	// amount is in minor units; it is formatted like WithOrderAmountMinorUnits.
+	minorUnits := MoneyFromMinorUnits(int64(amount))
+	r.amountMinorUnits = &minorUnits
+	r.Amount = formatAmountForCurrency(minorUnits, r.OrderCurrency)
+	return r
+}
+
+// WithOriginalAmount sets the held amount (minor units) used to validate a CAPTURE amount.
+// It is not sent to Platon.
+func (r *Request) WithOriginalAmount(amount *int) *Request {
+	if r == nil {
+		return nil
+	}
+
+	r.OriginalAmount = amount
 	return r
 }
 
@@ -437,6 +577,7 @@ func (r *Request) WithAmount(amount string) *Request {
 	}
 
 	r.Amount = amount
+	r.amountMinorUnits = nil
 	return r
 }
 
@@ -453,6 +594,19 @@ func (r *Request) WithSplitRules(splitRules SplitRules) *Request {
 	return r
 }
 
+// WithSplitRemainderTo lets submerchantID absorb a split rules total that is
+// short of or over the amount by at most one major unit (1.00 for UAH), e.g.
+// a rounding remainder.
+// The submerchant is added to the rules when it is not in them yet.
+func (r *Request) WithSplitRemainderTo(submerchantID string) *Request {
+	if r == nil {
+		return nil
+	}
+
+	r.SplitRemainderTo = strings.TrimSpace(submerchantID)
+	return r
+}
+
 func (r *Request) WithImmediately(flag bool) *Request {
 	if r == nil {
 		return nil
@@ -477,11 +631,115 @@ func (r *Request) WithHashEmail(email *string) *Request {
 	return r
 }
 
-func (r *Request) WithExt3(value *string) *Request {
+// WithExt sets ext field n (1..10). Any other n is reported by Err and SignAndPrepare.
+func (r *Request) WithExt(n int, value *string) *Request {
+	if r == nil {
+		return nil
+	}
+
+	field := r.extField(n)
+	if field == nil {
+		r.setBuildErr(NewValidationError("", "ext", fmt.Sprintf("index %d out of range 1..10", n)))
+		return r
+	}
+
+	*field = value
+	return r
+}
+
+// extField returns the address of ext field n, or nil when n is not 1..10.
+func (r *Request) extField(n int) **string {
+	switch n {
+	case 1:
+		return &r.Ext1
+	case 2:
+		return &r.Ext2
+	case 3:
+		return &r.Ext3
+	case 4:
+		return &r.Ext4
+	case 5:
+		return &r.Ext5
+	case 6:
+		return &r.Ext6
+	case 7:
+		return &r.Ext7
+	case 8:
+		return &r.Ext8
+	case 9:
+		return &r.Ext9
+	case 10:
+		return &r.Ext10
+	default:
+		return nil
+	}
+}
+
+func (r *Request) WithExt1(value *string) *Request { return r.WithExt(1, value) }
+func (r *Request) WithExt2(value *string) *Request { return r.WithExt(2, value) }
+func (r *Request) WithExt3(value *string) *Request { return r.WithExt(3, value) }
+func (r *Request) WithExt4(value *string) *Request { return r.WithExt(4, value) }
+func (r *Request) WithExt5(value *string) *Request { return r.WithExt(5, value) }
+func (r *Request) WithExt6(value *string) *Request { return r.WithExt(6, value) }
+func (r *Request) WithExt7(value *string) *Request { return r.WithExt(7, value) }
+func (r *Request) WithExt8(value *string) *Request { return r.WithExt(8, value) }
+func (r *Request) WithExt9(value *string) *Request { return r.WithExt(9, value) }
+
+// WithExt10 sets ext10, which WithReference also uses for the merchant reference.
+func (r *Request) WithExt10(value *string) *Request { return r.WithExt(10, value) }
+
+// ReferenceExtField is the ext field reserved for the merchant reference set
+// by WithReference. Platon echoes it back in callbacks.
+const ReferenceExtField = "ext10"
+
+// WithReference sets a merchant-defined reference, separate from order_id,
+// that Platon echoes in callbacks (see WebhookForm.Reference). It is sent in
+// ext10, which must not be used for anything else.
+func (r *Request) WithReference(reference string) *Request {
+	if r == nil {
+		return nil
+	}
+
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return r
+	}
+
+	r.Ext10 = &reference
+	return r
+}
+
+// WithLogSink routes the signature generators' debug logs to sink instead of
+// the global log sink.
+func (r *Request) WithLogSink(sink log.Sink) *Request {
+	if r == nil {
+		return nil
+	}
+
+	r.logSink = sink
+	return r
+}
+
+// WithIdempotencyKey sets the key used to recognise retries of this request.
+func (r *Request) WithIdempotencyKey(key string) *Request {
 	if r == nil {
 		return nil
 	}
 
-	r.Ext3 = value
+	r.IdempotencyKey = strings.TrimSpace(key)
 	return r
 }
+
+// ForVoid prepares a CREDITVOID request that cancels an uncaptured HOLD:
+// amount and split rules are cleared and the request is signed as HashTypeVoid.
+func (r *Request) ForVoid() *Request {
+	if r == nil {
+		return nil
+	}
+
+	r.Amount = ""
+	r.amountMinorUnits = nil
+	r.SplitRules = nil
+
+	return r.SignForAction(HashTypeVoid)
+}
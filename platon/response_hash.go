@@ -0,0 +1,95 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ExpectedHash computes the hash Platon puts into synchronous JSON responses:
+//
+//   - GET_SUBMERCHANT: md5(strtoupper(pass+submerchant_id)).
+//   - other actions (GET_TRANS_STATUS, SALE, CAPTURE, ...):
+//     md5(strtoupper(strrev(email)+pass+trans_id+strrev(first6+last4))).
+//
+// email is the payer email of the original payment and may be empty. card is
+// the full or masked card number (e.g. "411111****1111"); when it is empty the
+// card part is left out, as for GET_TRANS_STATUS requests.
+func (p *Response) ExpectedHash(secret string, email string, card string) (string, error) {
+	if p == nil {
+		return "", fmt.Errorf("response is nil")
+	}
+
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return "", fmt.Errorf("secret is required")
+	}
+
+	var raw string
+	if p.Action != nil && strings.EqualFold(strings.TrimSpace(*p.Action), ActionCodeGetSubmerchant.String()) {
+		if p.ResponseData == nil || p.ResponseData.SubmerchantID == nil || strings.TrimSpace(*p.ResponseData.SubmerchantID) == "" {
+			return "", fmt.Errorf("submerchant_id is required")
+		}
+		raw = secret + strings.TrimSpace(*p.ResponseData.SubmerchantID)
+	} else {
+		if p.TransId == nil || strings.TrimSpace(*p.TransId) == "" {
+			return "", fmt.Errorf("trans_id is required")
+		}
+		raw = reverseString(strings.TrimSpace(email)) + secret + strings.TrimSpace(*p.TransId)
+
+		if strings.TrimSpace(card) != "" {
+			cardSource, err := webhookCardSignSource(card)
+			if err != nil {
+				return "", err
+			}
+			raw += reverseString(cardSource)
+		}
+	}
+
+	hash := md5.Sum([]byte(strings.ToUpper(raw)))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// VerifyHash validates the response `hash` field (ResponseData.Hash) against
+// ExpectedHash. It protects against spoofed responses when traffic goes
+// through untrusted infrastructure.
+func (p *Response) VerifyHash(secret string, email string, card string) (bool, error) {
+	if p == nil {
+		return false, fmt.Errorf("response is nil")
+	}
+	if p.ResponseData == nil || p.ResponseData.Hash == nil || strings.TrimSpace(*p.ResponseData.Hash) == "" {
+		return false, fmt.Errorf("hash is required (response does not contain hash)")
+	}
+
+	expected, err := p.ExpectedHash(secret, email, card)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.EqualFold(strings.TrimSpace(*p.ResponseData.Hash), expected), nil
+}
@@ -0,0 +1,107 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResponse_VerifyHash_GetTransStatus(t *testing.T) {
+	raw := []byte(`{"action":"GET_TRANS_STATUS","result":"SUCCESS","status":"SETTLED","order_id":"order-1","trans_id":"632508054","hash":"77a4785689636b4d3875ec7acf47d5e2"}`)
+
+	resp, err := UnmarshalJSONResponse(raw)
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+	}
+
+	expected, err := resp.ExpectedHash("secret123", "payer@example.com", "411111****1111")
+	if err != nil {
+		t.Fatalf("ExpectedHash() error: %v", err)
+	}
+	if expected != "77a4785689636b4d3875ec7acf47d5e2" {
+		t.Fatalf("expected hash mismatch: got %q", expected)
+	}
+
+	ok, err := resp.VerifyHash("secret123", "payer@example.com", "411111****1111")
+	if err != nil {
+		t.Fatalf("VerifyHash() error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("VerifyHash() expected true")
+	}
+
+	ok, err = resp.VerifyHash("WRONG_SECRET", "payer@example.com", "411111****1111")
+	if err != nil {
+		t.Fatalf("VerifyHash() with wrong secret error: %v", err)
+	}
+	if ok {
+		t.Fatalf("VerifyHash() expected false for wrong secret")
+	}
+}
+
+func TestResponse_ExpectedHash_WithoutCard(t *testing.T) {
+	resp, err := UnmarshalJSONResponse([]byte(`{"action":"GET_TRANS_STATUS","trans_id":"632508054"}`))
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+	}
+
+	expected, err := resp.ExpectedHash("secret123", "payer@example.com", "")
+	if err != nil {
+		t.Fatalf("ExpectedHash() error: %v", err)
+	}
+	if expected != "ef374c28b6398c097e0b3d6230deebd6" {
+		t.Fatalf("expected hash mismatch: got %q", expected)
+	}
+}
+
+func TestResponse_VerifyHash_GetSubmerchant(t *testing.T) {
+	raw := []byte(`{"status":"SUCCESS","action":"GET_SUBMERCHANT","submerchant_id":"12345678","submerchant_id_status":"ENABLED","hash":"15F549D19F26CE89022396A649C4AC9F"}`)
+
+	resp, err := UnmarshalJSONResponse(raw)
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+	}
+
+	ok, err := resp.VerifyHash("secret123", "", "")
+	if err != nil {
+		t.Fatalf("VerifyHash() error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("VerifyHash() expected true")
+	}
+}
+
+func TestResponse_VerifyHash_MissingHash(t *testing.T) {
+	resp, err := UnmarshalJSONResponse([]byte(`{"action":"GET_TRANS_STATUS","trans_id":"632508054"}`))
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+	}
+
+	_, err = resp.VerifyHash("secret123", "payer@example.com", "")
+	if err == nil || !strings.Contains(err.Error(), "hash is required") {
+		t.Fatalf("expected missing hash error, got %v", err)
+	}
+}
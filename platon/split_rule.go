@@ -24,6 +24,117 @@
 
 package platon
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
 // SplitRules is serialized as JSON object where key is submerchant identifier
 // and value is amount formatted as "100.00".
+//
+// The `split_rules` form field carries this object as a compact JSON string,
+// e.g. {"1001":"70.00","1002":"30.00"}. Keys are sorted so the encoded value
+// is stable across calls.
 type SplitRules map[string]string
+
+// Encode returns the `split_rules` form field value. Empty rules encode to "".
+func (s SplitRules) Encode() (string, error) {
+	if len(s) == 0 {
+		return "", nil
+	}
+
+	raw, err := s.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw), nil
+}
+
+// MarshalJSON encodes rules as a JSON object with sorted keys.
+func (s SplitRules) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for idx, key := range keys {
+		if idx > 0 {
+			buf.WriteByte(',')
+		}
+
+		rawKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		rawValue, err := json.Marshal(s[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(rawKey)
+		buf.WriteByte(':')
+		buf.Write(rawValue)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// SplitEquals checks that the split amounts (two decimals each) add up to
+// total exactly.
+func (s SplitRules) SplitEquals(total Money) error {
+	sum, err := splitRulesTotal(s, 2)
+	if err != nil {
+		return err
+	}
+
+	return compareSplitTotal(sum, total, 2)
+}
+
+// splitRulesTotal sums the split amounts written with digits fraction digits.
+func splitRulesTotal(rules SplitRules, digits int) (Money, error) {
+	var total Money
+	for submerchantID, value := range rules {
+		amount, err := parseAmountMinorUnits(value, digits)
+		if err != nil {
+			return 0, fmt.Errorf("split_rules[%q] amount %q: %w", submerchantID, value, err)
+		}
+		if total, err = total.Add(amount); err != nil {
+			return 0, fmt.Errorf("split rules total: %w", err)
+		}
+	}
+
+	return total, nil
+}
+
+// compareSplitTotal reports how far the split total is from the amount.
+func compareSplitTotal(splitTotal, total Money, digits int) error {
+	switch {
+	case splitTotal < total:
+		return fmt.Errorf(
+			"split rules total is %s short of amount (%s != %s)",
+			formatAmountMinorUnits(total-splitTotal, digits),
+			formatAmountMinorUnits(splitTotal, digits),
+			formatAmountMinorUnits(total, digits),
+		)
+	case splitTotal > total:
+		return fmt.Errorf(
+			"split rules total is %s over amount (%s != %s)",
+			formatAmountMinorUnits(splitTotal-total, digits),
+			formatAmountMinorUnits(splitTotal, digits),
+			formatAmountMinorUnits(total, digits),
+		)
+	default:
+		return nil
+	}
+}
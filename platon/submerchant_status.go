@@ -0,0 +1,65 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import "strings"
+
+// SubmerchantStatus is the submerchant_id_status returned by GET_SUBMERCHANT.
+type SubmerchantStatus string
+
+func (s SubmerchantStatus) String() string {
+	return string(s)
+}
+
+const (
+	SubmerchantStatusEnabled  SubmerchantStatus = "ENABLED"
+	SubmerchantStatusDisabled SubmerchantStatus = "DISABLED"
+	SubmerchantStatusLocked   SubmerchantStatus = "LOCKED"
+	SubmerchantStatusPending  SubmerchantStatus = "PENDING"
+	// SubmerchantStatusUnknown is returned by ParseSubmerchantStatus for values it does not recognize.
+	SubmerchantStatusUnknown SubmerchantStatus = "UNKNOWN"
+)
+
+// ParseSubmerchantStatus parses a submerchant_id_status value case-insensitively.
+// Unrecognized values map to SubmerchantStatusUnknown.
+func ParseSubmerchantStatus(value string) SubmerchantStatus {
+	switch status := SubmerchantStatus(strings.ToUpper(strings.TrimSpace(value))); status {
+	case SubmerchantStatusEnabled, SubmerchantStatusDisabled, SubmerchantStatusLocked, SubmerchantStatusPending:
+		return status
+	default:
+		return SubmerchantStatusUnknown
+	}
+}
+
+// IsKnown reports whether the status is one of the documented values.
+func (s SubmerchantStatus) IsKnown() bool {
+	return ParseSubmerchantStatus(string(s)) != SubmerchantStatusUnknown
+}
+
+// IsSplitEligible reports whether split payouts to the sub-merchant are allowed.
+// Only ENABLED sub-merchants can receive split payouts.
+func (s SubmerchantStatus) IsSplitEligible() bool {
+	return s == SubmerchantStatusEnabled
+}
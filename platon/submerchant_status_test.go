@@ -0,0 +1,74 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import "testing"
+
+func TestParseSubmerchantStatus(t *testing.T) {
+	tests := []struct {
+		raw           string
+		want          SubmerchantStatus
+		splitEligible bool
+		known         bool
+	}{
+		{raw: "ENABLED", want: SubmerchantStatusEnabled, splitEligible: true, known: true},
+		{raw: " enabled ", want: SubmerchantStatusEnabled, splitEligible: true, known: true},
+		{raw: "DISABLED", want: SubmerchantStatusDisabled, known: true},
+		{raw: "LOCKED", want: SubmerchantStatusLocked, known: true},
+		{raw: "pending", want: SubmerchantStatusPending, known: true},
+		{raw: "ARCHIVED", want: SubmerchantStatusUnknown},
+		{raw: "", want: SubmerchantStatusUnknown},
+	}
+
+	for _, tt := range tests {
+		got := ParseSubmerchantStatus(tt.raw)
+		if got != tt.want {
+			t.Fatalf("ParseSubmerchantStatus(%q) mismatch: want %q, got %q", tt.raw, tt.want, got)
+		}
+		if got.IsSplitEligible() != tt.splitEligible {
+			t.Fatalf("%q: IsSplitEligible() mismatch: want %v", tt.raw, tt.splitEligible)
+		}
+		if got.IsKnown() != tt.known {
+			t.Fatalf("%q: IsKnown() mismatch: want %v", tt.raw, tt.known)
+		}
+	}
+}
+
+func TestResponse_SubmerchantStatus(t *testing.T) {
+	resp, err := UnmarshalJSONResponse([]byte(`{"status":"SUCCESS","submerchant_id":"1","submerchant_id_status":"locked"}`))
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+	}
+
+	status, ok := resp.SubmerchantStatus()
+	if !ok || status != SubmerchantStatusLocked {
+		t.Fatalf("SubmerchantStatus() mismatch: want LOCKED, got %q (ok=%v)", status, ok)
+	}
+
+	var nilResp *Response
+	if _, ok := nilResp.SubmerchantStatus(); ok {
+		t.Fatalf("expected no status for nil response")
+	}
+}
@@ -0,0 +1,238 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorCategory groups SDK errors so that callers can branch on them instead
+// of matching error text.
+type ErrorCategory string
+
+const (
+	// ErrorCategoryValidation means the request was rejected before sending.
+	ErrorCategoryValidation ErrorCategory = "validation"
+	// ErrorCategoryGateway means Platon answered with an HTTP or API error.
+	ErrorCategoryGateway ErrorCategory = "gateway"
+	// ErrorCategoryDecline means Platon declined the operation.
+	ErrorCategoryDecline ErrorCategory = "decline"
+	// ErrorCategorySignature means the request could not be signed.
+	ErrorCategorySignature ErrorCategory = "signature"
+)
+
+// Sentinels matched by errors.Is for each ErrorCategory.
+var (
+	ErrValidation = errors.New("platon: request validation failed")
+	ErrGateway    = errors.New("platon: gateway error")
+	ErrDeclined   = errors.New("platon: operation declined")
+	ErrSignature  = errors.New("platon: signature error")
+)
+
+// Error is implemented by ValidationError, GatewayError, DeclineError,
+// SignatureError and APIError.
+type Error interface {
+	error
+	Category() ErrorCategory
+}
+
+var (
+	_ Error = (*ValidationError)(nil)
+	_ Error = (*GatewayError)(nil)
+	_ Error = (*DeclineError)(nil)
+	_ Error = (*SignatureError)(nil)
+	_ Error = (*APIError)(nil)
+)
+
+// ValidationError reports a request field that failed a check before the
+// request was sent, e.g. "capture: trans_id is required".
+type ValidationError struct {
+	// Op is the operation or hash type being validated, e.g. "capture".
+	Op string
+	// Field is the Platon form field, e.g. "trans_id".
+	Field string
+	// Reason describes the failed check, e.g. "is required".
+	Reason string
+	// Err is the underlying cause, if any.
+	Err error
+
+	// subject replaces Field in the message, e.g. "merchant client_key".
+	subject string
+}
+
+// NewValidationError returns a ValidationError for field of op.
+func NewValidationError(op, field, reason string) *ValidationError {
+	return &ValidationError{Op: op, Field: field, Reason: reason}
+}
+
+// NewClientKeyRequiredError returns the ValidationError for a request of op
+// without the merchant client_key.
+func NewClientKeyRequiredError(op string) *ValidationError {
+	return &ValidationError{Op: op, Field: "client_key", Reason: "is required", subject: "merchant client_key"}
+}
+
+func wrapValidationError(op, field string, err error) *ValidationError {
+	return &ValidationError{Op: op, Field: field, Err: err}
+}
+
+func (e *ValidationError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	subject := e.Field
+	if e.subject != "" {
+		subject = e.subject
+	}
+
+	msg := strings.TrimSpace(subject + " " + e.Reason)
+	if e.Err != nil {
+		if msg != "" {
+			msg += ": "
+		}
+		msg += e.Err.Error()
+	}
+	if e.Op != "" {
+		msg = e.Op + ": " + msg
+	}
+
+	return msg
+}
+
+func (e *ValidationError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+
+	return e.Err
+}
+
+// Is reports whether target is ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func (e *ValidationError) Category() ErrorCategory {
+	return ErrorCategoryValidation
+}
+
+// GatewayError is a non-2xx HTTP answer from Platon.
+type GatewayError struct {
+	StatusCode int
+	// Body is the response body, truncated for error messages.
+	Body string
+}
+
+func (e *GatewayError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("status=%d body=%s", e.StatusCode, e.Body)
+}
+
+// Is reports whether target is ErrGateway.
+func (e *GatewayError) Is(target error) bool {
+	return target == ErrGateway
+}
+
+func (e *GatewayError) Category() ErrorCategory {
+	return ErrorCategoryGateway
+}
+
+// DeclineError is a declined operation. errors.As finds it in any error that
+// wraps an APIError of kind APIErrorKindDeclined.
+type DeclineError struct {
+	// Code is the numeric decline code, 0 when the reason has none.
+	Code int
+	// Reason is the decline reason without the code.
+	Reason string
+	// Message is the raw decline_reason value.
+	Message string
+}
+
+func (e *DeclineError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+	if e.Message == "" {
+		return "unknown platon api decline"
+	}
+
+	return "platon api declined: " + e.Message
+}
+
+// Is reports whether target is ErrDeclined.
+func (e *DeclineError) Is(target error) bool {
+	return target == ErrDeclined
+}
+
+func (e *DeclineError) Category() ErrorCategory {
+	return ErrorCategoryDecline
+}
+
+// SignatureError is a failure to compute the request signature.
+type SignatureError struct {
+	HashType HashType
+	Err      error
+}
+
+// signatureError wraps err from a signature generator in a SignatureError.
+// Validation errors, such as an invalid card_number, are returned as they are.
+func signatureError(hashType HashType, err error) error {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return err
+	}
+
+	return &SignatureError{HashType: hashType, Err: err}
+}
+
+func (e *SignatureError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("signature generation failed: %v", e.Err)
+}
+
+func (e *SignatureError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+
+	return e.Err
+}
+
+// Is reports whether target is ErrSignature.
+func (e *SignatureError) Is(target error) bool {
+	return target == ErrSignature
+}
+
+func (e *SignatureError) Category() ErrorCategory {
+	return ErrorCategorySignature
+}
@@ -0,0 +1,151 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSignAndPrepare_ReturnsValidationError(t *testing.T) {
+	transID := "trans-1"
+	email := "payer@example.com"
+
+	req := NewRequest(ActionCodeCAPTURE).
+		WithAuth(&Auth{Key: "k", Secret: "secret123"}).
+		WithClientKey("clientKey").
+		WithTransID(&transID).
+		WithPayerEmail(&email).
+		SignForAction(HashTypeCapture)
+
+	_, err := req.SignAndPrepare()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected *ValidationError, got %T (%v)", err, err)
+	}
+	if validationErr.Op != "capture" || validationErr.Field != "amount" {
+		t.Fatalf("unexpected validation error: %+v", validationErr)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("errors.Is(err, ErrValidation) should be true")
+	}
+	if validationErr.Category() != ErrorCategoryValidation {
+		t.Fatalf("category mismatch: got %q", validationErr.Category())
+	}
+}
+
+func TestSignAndPrepare_ErrValidation(t *testing.T) {
+	newTokenSale := func(amount string, email string) *Request {
+		return newSignableTokenPayment().WithOrderAmount(amount).WithPayerEmail(&email)
+	}
+
+	tests := map[string]*Request{
+		"bad order_amount": newTokenSale("10.5.0", "payer@example.com"),
+		"validator tag":    newTokenSale("1.00", "not-an-email"),
+		"invalid UTF-8":    newTokenSale("1.00", "payer@example.com").WithDescription("\xff"),
+		"build error":      newTokenSale("1.00", "payer@example.com").WithPayerIPStrict(nil),
+		"unknown currency": newTokenSale("1.00", "payer@example.com").ForCurrency("UAG"),
+	}
+	for name, req := range tests {
+		_, err := req.SignAndPrepare()
+		if !errors.Is(err, ErrValidation) {
+			t.Fatalf("%s: errors.Is(%v, ErrValidation) = false", name, err)
+		}
+		if errors.Is(err, ErrSignature) {
+			t.Fatalf("%s: validation error must not match ErrSignature", name)
+		}
+	}
+
+	if _, err := newTokenSale("1.00", "payer@example.com").SignAndPrepare(); err != nil {
+		t.Fatalf("valid request: unexpected error: %v", err)
+	}
+	if _, err := (*Request)(nil).SignAndPrepare(); errors.Is(err, ErrValidation) {
+		t.Fatal("nil request must not match ErrValidation")
+	}
+}
+
+func TestSignAndPrepare_ReturnsSignatureError(t *testing.T) {
+	transID := "trans-1"
+	email := "payer@example.com"
+
+	req := NewRequest(ActionCodeCAPTURE).
+		WithAuth(&Auth{Key: "k"}).
+		WithClientKey("clientKey").
+		WithTransID(&transID).
+		WithPayerEmail(&email).
+		SignForAction(HashTypeCapture)
+
+	_, err := req.SignAndPrepare()
+
+	var signatureErr *SignatureError
+	if !errors.As(err, &signatureErr) {
+		t.Fatalf("expected *SignatureError, got %T (%v)", err, err)
+	}
+	if signatureErr.HashType != HashTypeCapture {
+		t.Fatalf("hash type mismatch: got %q", signatureErr.HashType)
+	}
+	if !errors.Is(err, ErrSignature) {
+		t.Fatalf("errors.Is(err, ErrSignature) should be true")
+	}
+}
+
+func TestAPIError_AsDeclineError(t *testing.T) {
+	resp, err := UnmarshalJSONResponse([]byte(`{"result":"DECLINED","decline_reason":"102: Token is not active"}`))
+	if err != nil {
+		t.Fatalf("UnmarshalJSONResponse() error: %v", err)
+	}
+
+	wrapped := fmt.Errorf("payment API call: %w", resp.GetError())
+
+	var declineErr *DeclineError
+	if !errors.As(wrapped, &declineErr) {
+		t.Fatalf("expected *DeclineError in %v", wrapped)
+	}
+	if declineErr.Code != 102 || declineErr.Reason != "Token is not active" {
+		t.Fatalf("unexpected decline: %+v", declineErr)
+	}
+	if !errors.Is(wrapped, ErrDeclined) || errors.Is(wrapped, ErrGateway) {
+		t.Fatalf("declined APIError should match ErrDeclined only")
+	}
+
+	var apiErr *APIError
+	if !errors.As(wrapped, &apiErr) {
+		t.Fatalf("expected *APIError to remain reachable")
+	}
+}
+
+func TestAPIError_ErrorKindIsGateway(t *testing.T) {
+	err := fmt.Errorf("refund API call: %w", NewAPIError(APIErrorKindError, "204 - Duplicate request"))
+
+	var declineErr *DeclineError
+	if errors.As(err, &declineErr) {
+		t.Fatalf("APIError of kind error should not convert to *DeclineError")
+	}
+	if !errors.Is(err, ErrGateway) {
+		t.Fatalf("errors.Is(err, ErrGateway) should be true")
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	platonAmountRe   = regexp.MustCompile(`^[0-9]+(\.[0-9]{1,3})?$`)
+	platonPhoneRe    = regexp.MustCompile(`^[0-9]{1,32}$`)
+	cardExpMonthRe   = regexp.MustCompile(`^(0[1-9]|1[0-2])$`)
+	cardExpYearRe    = regexp.MustCompile(`^[0-9]{4}$`)
+	requestValidate  *validator.Validate
+	requestValidOnce sync.Once
+)
+
+// requestValidator returns the validator shared by every SignAndPrepare call.
+// Building it once keeps the struct cache warm and registers the custom tags:
+//
+//   - platon_amount: a non-negative decimal with at most 3 fraction digits;
+//     the currency-specific precision is checked by validateByHashType.
+//   - platon_phone: digits only, at most 32 (380-prefixed numbers included);
+//     country prefixes are restricted by PayerPhonePrefixes.
+//   - card_exp=month: "01".."12"; card_exp=year: four digits.
+func requestValidator() *validator.Validate {
+	requestValidOnce.Do(
+		func() {
+			v := validator.New()
+			for tag, fn := range map[string]validator.Func{
+				"platon_amount": matchString(platonAmountRe),
+				"platon_phone":  matchString(platonPhoneRe),
+				"card_exp":      validateCardExpTag,
+			} {
+				if err := v.RegisterValidation(tag, fn); err != nil {
+					panic(fmt.Sprintf("platon: cannot register %s validation: %v", tag, err))
+				}
+			}
+			requestValidate = v
+		},
+	)
+
+	return requestValidate
+}
+
+func matchString(re *regexp.Regexp) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return re.MatchString(fl.Field().String())
+	}
+}
+
+func validateCardExpTag(fl validator.FieldLevel) bool {
+	switch fl.Param() {
+	case "month":
+		return cardExpMonthRe.MatchString(fl.Field().String())
+	case "year":
+		return cardExpYearRe.MatchString(fl.Field().String())
+	default:
+		return false
+	}
+}
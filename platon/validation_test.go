@@ -0,0 +1,179 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestRequestValidator_CustomTags(t *testing.T) {
+	tests := []struct {
+		tag    string
+		accept []string
+		reject []string
+	}{
+		{
+			tag:    "platon_amount",
+			accept: []string{"1.00", "0.40", "1000", "12.5", "1.234"},
+			reject: []string{"-1.00", "1,00", "1.", ".50", "1.2345", "1e3", "abc"},
+		},
+		{
+			tag:    "platon_phone",
+			accept: []string{"380631234567", "48221234567", "1"},
+			reject: []string{"+380631234567", "380-63-123", "-1.5", "call-me", "123456789012345678901234567890123"},
+		},
+		{
+			tag:    "card_exp=month",
+			accept: []string{"01", "09", "10", "12"},
+			reject: []string{"00", "13", "1", "001", "ab", "-1"},
+		},
+		{
+			tag:    "card_exp=year",
+			accept: []string{"2030", "2099"},
+			reject: []string{"30", "20300", "20a0", "-203"},
+		},
+	}
+
+	v := requestValidator()
+	for _, tt := range tests {
+		for _, value := range tt.accept {
+			if err := v.Var(value, tt.tag); err != nil {
+				t.Fatalf("%s: %q rejected: %v", tt.tag, value, err)
+			}
+		}
+		for _, value := range tt.reject {
+			if err := v.Var(value, tt.tag); err == nil {
+				t.Fatalf("%s: %q accepted", tt.tag, value)
+			}
+		}
+	}
+
+	if v != requestValidator() {
+		t.Fatal("requestValidator() returned a different instance")
+	}
+}
+
+func TestSignAndPrepare_CustomTagFailureIsValidationError(t *testing.T) {
+	req := newSignableTokenPayment()
+	req.OrderAmount = "1.00"
+	req.Amount = "1,00"
+
+	_, err := req.SignAndPrepare()
+	var fieldErrs validator.ValidationErrors
+	if !errors.Is(err, ErrValidation) || !errors.As(err, &fieldErrs) || fieldErrs[0].Tag() != "platon_amount" {
+		t.Fatalf("SignAndPrepare() error = %v, want platon_amount validation error", err)
+	}
+}
+
+// newSignableCardPayment returns a card SALE that passes SignAndPrepare;
+// tests override the field under test with the builder methods.
+func newSignableCardPayment() *Request {
+	pan := "4111111111111111"
+	month := "01"
+	year := "2026"
+	cvv := "123"
+
+	return newSignablePayment().
+		WithCardNumber(&pan).
+		WithCardExpMonth(&month).
+		WithCardExpYear(&year).
+		WithCardCvv2(&cvv).
+		SignForAction(HashTypeCardPayment)
+}
+
+// newSignableTokenPayment returns a token SALE that passes SignAndPrepare;
+// tests override the field under test with the builder methods.
+func newSignableTokenPayment() *Request {
+	token := "TOKEN123"
+
+	return newSignablePayment().
+		WithCardToken(&token).
+		SignForAction(HashTypeCardTokenPayment)
+}
+
+func newSignablePayment() *Request {
+	orderID := "order-1"
+	ip := "203.0.113.10"
+	term := "https://example.com/3ds"
+	email := "payer@example.com"
+	phone := "380631234567"
+
+	return NewRequest(ActionCodeSALE).
+		WithAuth(&Auth{Key: "k", Secret: "secret123"}).
+		WithClientKey("clientKey").
+		WithOrderID(&orderID).
+		WithOrderAmount("1.00").
+		ForCurrency("UAH").
+		WithDescription("payment").
+		WithPayerIP(&ip).
+		WithTermsURL(&term).
+		WithPayerEmail(&email).
+		WithPayerPhone(&phone).
+		WithClock(testClock)
+}
+
+func BenchmarkSignAndPrepare_Validator(b *testing.B) {
+	signed, err := newSignableTokenPayment().SignAndPrepare()
+	if err != nil {
+		b.Fatalf("SignAndPrepare() error: %v", err)
+	}
+
+	b.Run(
+		"shared", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := requestValidator().Struct(signed); err != nil {
+					b.Fatal(err)
+				}
+			}
+		},
+	)
+	b.Run(
+		"new per call", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				v := validator.New()
+				_ = v.RegisterValidation("platon_amount", matchString(platonAmountRe))
+				_ = v.RegisterValidation("platon_phone", matchString(platonPhoneRe))
+				_ = v.RegisterValidation("card_exp", validateCardExpTag)
+				if err := v.Struct(signed); err != nil {
+					b.Fatal(err)
+				}
+			}
+		},
+	)
+}
+
+func BenchmarkSignAndPrepare(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := newSignableTokenPayment().SignAndPrepare(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
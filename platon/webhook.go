@@ -27,6 +27,7 @@ package platon
 import (
 	"encoding/xml"
 	"fmt"
+	"strings"
 )
 
 // Payment represents the root element of the notification with an ID.
@@ -88,13 +89,28 @@ func (t *Transactions) Last() *Transaction {
 
 // Transaction represents an individual transaction.
 type Transaction struct {
-	ID      int64   `xml:"id,attr" json:"id"`          // Transaction ID in the Platon system
-	MchID   int     `xml:"mch_id" json:"mch_id"`       // Merchant ID
-	SrvID   int     `xml:"srv_id" json:"srv_id"`       // Legal entity for which the operation is carried out
-	Invoice int     `xml:"invoice" json:"invoice"`     // Payment amount in kopecks
-	Amount  int     `xml:"amount" json:"amount"`       // Amount to be paid (including commission) in kopecks
-	Desc    string  `xml:"desc" json:"desc"`           // Payment description
-	Info    *string `xml:"info" json:"info,omitempty"` // Information for the payment provided by the merchant
+	ID        int64   `xml:"id,attr" json:"id"`          // Transaction ID in the Platon system
+	MchID     int     `xml:"mch_id" json:"mch_id"`       // Merchant ID
+	SrvID     int     `xml:"srv_id" json:"srv_id"`       // Legal entity for which the operation is carried out
+	Invoice   int     `xml:"invoice" json:"invoice"`     // Payment amount in kopecks
+	Amount    int     `xml:"amount" json:"amount"`       // Amount to be paid (including commission) in kopecks
+	Desc      string  `xml:"desc" json:"desc"`           // Payment description
+	Info      *string `xml:"info" json:"info,omitempty"` // Information for the payment provided by the merchant
+	Status    string  `xml:"status" json:"status"`       // Transaction status (SALE, CAPTURE, REFUND, DECLINED, ...)
+	Currency  string  `xml:"currency" json:"currency"`   // Currency code
+	Card      string  `xml:"card" json:"card"`           // Masked card number
+	RcToken   string  `xml:"rc_token" json:"rc_token"`   // Recurring token issued for the card, optional
+	Order     string  `xml:"order" json:"order"`         // Merchant order ID
+	Timestamp int64   `xml:"timestamp" json:"timestamp"` // Date of the transaction in UNIX-timestamp, optional
+}
+
+// AmountMinorUnits returns the transaction amount (including commission) in minor units.
+func (t *Transaction) AmountMinorUnits() Money {
+	if t == nil {
+		return 0
+	}
+
+	return Money(t.Amount)
 }
 
 func ParsePaymentXML(data []byte) (*Payment, error) {
@@ -148,3 +164,25 @@ func (p *Payment) GetTransactionByID(id int64) *Transaction {
 	}
 	return nil
 }
+
+// LatestByStatus returns the most recent transaction with the given status
+// (case-insensitive). Transactions are ordered by timestamp; when timestamps
+// are equal or missing, the one appearing later in the notification wins.
+func (p *Payment) LatestByStatus(status string) *Transaction {
+	if p == nil {
+		return nil
+	}
+
+	var latest *Transaction
+	for idx := range p.Transactions.Transaction {
+		tx := &p.Transactions.Transaction[idx]
+		if !strings.EqualFold(strings.TrimSpace(tx.Status), strings.TrimSpace(status)) {
+			continue
+		}
+		if latest == nil || tx.Timestamp >= latest.Timestamp {
+			latest = tx
+		}
+	}
+
+	return latest
+}
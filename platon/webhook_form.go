@@ -28,8 +28,10 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // WebhookForm represents Platon callback payload sent as
@@ -120,6 +122,103 @@ func ParseWebhookValues(values url.Values) *WebhookForm {
 	}
 }
 
+// Reference returns the merchant reference set with Request.WithReference.
+func (f *WebhookForm) Reference() string {
+	if f == nil {
+		return ""
+	}
+
+	return f.Ext10
+}
+
+// AmountMinorUnits returns the callback amount in minor units of Currency
+// ("0.40", "12" and "12.5" for UAH, "100" for JPY); an empty or unknown
+// currency uses two decimals. Empty and malformed values, including thousands
+// separators, are reported as errors.
+func (f *WebhookForm) AmountMinorUnits() (int, error) {
+	if f == nil {
+		return 0, fmt.Errorf("webhook form is nil")
+	}
+
+	amount := strings.TrimSpace(f.Amount)
+	if amount == "" {
+		return 0, fmt.Errorf("webhook amount is empty")
+	}
+
+	minor, ok := parseWebhookAmount(amount, currencyFractionDigits(f.Currency))
+	if !ok {
+		return 0, fmt.Errorf("invalid webhook amount %q", f.Amount)
+	}
+
+	return minor, nil
+}
+
+// parseWebhookAmount parses a callback amount with at most digits fraction
+// digits. Further fraction digits are accepted only when they are zeros, so
+// "100.00" is 100 for JPY.
+func parseWebhookAmount(amount string, digits int) (int, bool) {
+	major, fraction, hasFraction := strings.Cut(amount, ".")
+	if hasFraction && fraction == "" {
+		return 0, false
+	}
+	if len(fraction) > digits {
+		if strings.Trim(fraction[digits:], "0") != "" {
+			return 0, false
+		}
+		fraction = fraction[:digits]
+	}
+
+	normalized := major
+	if digits > 0 {
+		normalized += "." + fraction + strings.Repeat("0", digits-len(fraction))
+	}
+	minor, err := parseAmountMinorUnits(normalized, digits)
+	if err != nil || minor.MinorUnits() > math.MaxInt {
+		return 0, false
+	}
+
+	return int(minor.MinorUnits()), true
+}
+
+// ParsedAmount returns the callback amount as Money in minor units of
+// Currency. It accepts the same values as AmountMinorUnits.
+func (f *WebhookForm) ParsedAmount() (Money, error) {
+	minor, err := f.AmountMinorUnits()
+	if err != nil {
+		return 0, err
+	}
+
+	return Money(minor), nil
+}
+
+// ParsedDate parses the callback `date` (WebhookDateLayout) in loc, or in
+// KyivLocation when loc is nil.
+func (f *WebhookForm) ParsedDate(loc *time.Location) (time.Time, error) {
+	if f == nil {
+		return time.Time{}, fmt.Errorf("webhook form is nil")
+	}
+
+	return parseWebhookDate(f.Date, loc)
+}
+
+// CardMask returns the first 6 and last 4 characters of the masked callback
+// card (e.g. "411111****1111").
+func (f *WebhookForm) CardMask() (first6, last4 string, err error) {
+	if f == nil {
+		return "", "", fmt.Errorf("webhook form is nil")
+	}
+	if strings.TrimSpace(f.Card) == "" {
+		return "", "", fmt.Errorf("webhook card is empty")
+	}
+
+	source, err := webhookCardSignSource(f.Card)
+	if err != nil {
+		return "", "", err
+	}
+
+	return source[:6], source[6:], nil
+}
+
 // ExpectedSign computes the callback signature based on Platon docs:
 // md5(strtoupper(strrev(email)+pass+order+strrev(first6+last4)+strrev(status))).
 //
@@ -28,6 +28,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 const webhookFormPayload = "id=47097-87770-07123&order=47097-87309-6110&status=SALE&card=411111%2A%2A%2A%2A1111&description=%D0%9F%D0%BE%D0%BF%D0%BE%D0%B2%D0%BD%D0%B5%D0%BD%D0%BD%D1%8F+%D0%B1%D0%B0%D0%BB%D0%B0%D0%BD%D1%81%D1%83+%D0%B2%D0%BE%D0%B4%D1%96%D1%8F+%28Platon+split+one+receiver%29&amount=0.40&currency=UAH&name=+&phone=&email=&date=2026-02-13+10%3A32%3A57&ip=250.137.176.130&sign=582d658d7d422e76b2639fac131d093e&rc_id=47097-87770-07123&rc_token=fa0500fb3f4869247b4c5532eaf799bc&issuing_bank=JPMORGAN+CHASE+BANK%2C+N.A.&ext1=merchant-core&ext2=payments&ext3=sale&ext4=wallet-topup&ext10=v1&cardholder_email=&brand=VISA&terminal="
@@ -204,3 +205,124 @@ func TestParseWebhookValues_ExtFields(t *testing.T) {
 		t.Fatalf("ext10 mismatch: got %q", form.Ext10)
 	}
 }
+
+func TestWebhookForm_AmountMinorUnits(t *testing.T) {
+	fixture, err := ParseWebhookForm([]byte(webhookFormPayload))
+	if err != nil {
+		t.Fatalf("ParseWebhookForm() error: %v", err)
+	}
+	if got, err := fixture.AmountMinorUnits(); err != nil || got != 40 {
+		t.Fatalf("fixture AmountMinorUnits() = %d, %v; want 40", got, err)
+	}
+
+	tests := []struct {
+		amount   string
+		currency string
+		want     int
+		wantErr  bool
+	}{
+		{amount: "0.40", want: 40},
+		{amount: "0.40", currency: "UAH", want: 40},
+		{amount: "100", currency: "JPY", want: 100},
+		{amount: "100.00", currency: "jpy", want: 100},
+		{amount: "100.5", currency: "JPY", wantErr: true},
+		{amount: "1500", currency: "KRW", want: 1500},
+		{amount: "12", want: 1200},
+		{amount: "12.5", want: 1250},
+		{amount: " 100.00 ", want: 10000},
+		{amount: "", wantErr: true},
+		{amount: "1,000.00", wantErr: true},
+		{amount: "1 000.00", wantErr: true},
+		{amount: "1.234", wantErr: true},
+		{amount: "-1.00", wantErr: true},
+		{amount: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		form := &WebhookForm{Amount: tt.amount, Currency: tt.currency}
+		got, err := form.AmountMinorUnits()
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("AmountMinorUnits(%q %s) expected error, got %d", tt.amount, tt.currency, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Fatalf("AmountMinorUnits(%q %s) = %d, %v; want %d", tt.amount, tt.currency, got, err, tt.want)
+		}
+		if form.Amount != tt.amount {
+			t.Fatalf("AmountMinorUnits(%q) mutated the form: %q", tt.amount, form.Amount)
+		}
+	}
+}
+
+func TestWebhookForm_ParsedAmount(t *testing.T) {
+	fixture, err := ParseWebhookForm([]byte(webhookFormPayload))
+	if err != nil {
+		t.Fatalf("ParseWebhookForm() error: %v", err)
+	}
+
+	got, err := fixture.ParsedAmount()
+	if err != nil || got != Money(40) || got.String() != "0.40" {
+		t.Fatalf("fixture ParsedAmount() = %v, %v; want 0.40", got, err)
+	}
+
+	for _, value := range []string{"", "0,40", "0.401"} {
+		if _, err := (&WebhookForm{Amount: value}).ParsedAmount(); err == nil {
+			t.Fatalf("ParsedAmount(%q) expected error", value)
+		}
+	}
+	if got, err := (&WebhookForm{Amount: "100", Currency: "JPY"}).ParsedAmount(); err != nil || got != Money(100) {
+		t.Fatalf("ParsedAmount(100 JPY) = %v, %v; want 100 minor units", got, err)
+	}
+	if _, err := (*WebhookForm)(nil).ParsedAmount(); err == nil {
+		t.Fatalf("ParsedAmount() on nil form expected error")
+	}
+}
+
+func TestWebhookForm_ParsedDate(t *testing.T) {
+	fixture, err := ParseWebhookForm([]byte(webhookFormPayload))
+	if err != nil {
+		t.Fatalf("ParseWebhookForm() error: %v", err)
+	}
+
+	got, err := fixture.ParsedDate(nil)
+	if err != nil {
+		t.Fatalf("ParsedDate(nil) error: %v", err)
+	}
+	// 2026-02-13 10:32:57 in Kyiv (UTC+02:00 in winter).
+	if want := time.Date(2026, 2, 13, 8, 32, 57, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("ParsedDate(nil) = %v, want %v", got.UTC(), want)
+	}
+
+	got, err = fixture.ParsedDate(time.UTC)
+	if err != nil {
+		t.Fatalf("ParsedDate(UTC) error: %v", err)
+	}
+	if want := time.Date(2026, 2, 13, 10, 32, 57, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("ParsedDate(UTC) = %v, want %v", got, want)
+	}
+
+	for _, value := range []string{"", "13.02.2026 10:32:57", "2026-02-13T10:32:57Z"} {
+		if _, err := (&WebhookForm{Date: value}).ParsedDate(nil); err == nil {
+			t.Fatalf("ParsedDate(%q) expected error", value)
+		}
+	}
+}
+
+func TestWebhookForm_CardMask(t *testing.T) {
+	fixture, err := ParseWebhookForm([]byte(webhookFormPayload))
+	if err != nil {
+		t.Fatalf("ParseWebhookForm() error: %v", err)
+	}
+
+	first6, last4, err := fixture.CardMask()
+	if err != nil || first6 != "411111" || last4 != "1111" {
+		t.Fatalf("CardMask() = %q, %q, %v", first6, last4, err)
+	}
+
+	for _, card := range []string{"", "4111"} {
+		if _, _, err := (&WebhookForm{Card: card}).CardMask(); err == nil {
+			t.Fatalf("CardMask(%q) expected error", card)
+		}
+	}
+}
@@ -74,3 +74,95 @@ func TestTransactions_NilReceiver_IsSafe(t *testing.T) {
 		t.Fatalf("Last() expected nil transaction for nil receiver")
 	}
 }
+
+func TestParsePaymentXML_LegacyTransactions(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<payment id="987654">
+	<ident>7c3e-ident</ident>
+	<status>5</status>
+	<amount>150.00</amount>
+	<currency>UAH</currency>
+	<timestamp>1767225600</timestamp>
+	<card_mask>411111****1111</card_mask>
+	<transactions>
+		<transaction id="1001">
+			<mch_id>12</mch_id>
+			<srv_id>34</srv_id>
+			<invoice>15000</invoice>
+			<amount>15150</amount>
+			<desc>Order 42</desc>
+			<status>SALE</status>
+			<currency>UAH</currency>
+			<card>411111****1111</card>
+			<rc_token>rc-token-1</rc_token>
+			<order>order-42</order>
+			<timestamp>1767225600</timestamp>
+		</transaction>
+		<transaction id="1002">
+			<amount>5000</amount>
+			<desc>Partial refund</desc>
+			<status>REFUND</status>
+			<currency>UAH</currency>
+			<card>411111****1111</card>
+			<order>order-42</order>
+			<timestamp>1767229200</timestamp>
+		</transaction>
+		<transaction id="1003">
+			<amount>2500</amount>
+			<desc>Second refund</desc>
+			<status>refund</status>
+			<currency>UAH</currency>
+			<card>411111****1111</card>
+			<order>order-42</order>
+			<timestamp>1767232800</timestamp>
+		</transaction>
+	</transactions>
+</payment>`)
+
+	payment, err := ParsePaymentXML(data)
+	if err != nil {
+		t.Fatalf("ParsePaymentXML() error: %v", err)
+	}
+	if payment.ID != 987654 || payment.Currency != "UAH" || !payment.Status.Is(PaymentStatusSuccess) {
+		t.Fatalf("unexpected payment header: %s", payment.String())
+	}
+	if got := payment.Transactions.Len(); got != 3 {
+		t.Fatalf("Transactions.Len() = %d, want 3", got)
+	}
+
+	sale := payment.Transactions.First()
+	want := Transaction{
+		ID:        1001,
+		MchID:     12,
+		SrvID:     34,
+		Invoice:   15000,
+		Amount:    15150,
+		Desc:      "Order 42",
+		Status:    "SALE",
+		Currency:  "UAH",
+		Card:      "411111****1111",
+		RcToken:   "rc-token-1",
+		Order:     "order-42",
+		Timestamp: 1767225600,
+	}
+	if *sale != want {
+		t.Fatalf("first transaction mismatch:\n got %+v\nwant %+v", *sale, want)
+	}
+	if got := sale.AmountMinorUnits(); got != 15150 {
+		t.Fatalf("AmountMinorUnits() = %d, want 15150", got)
+	}
+
+	refund := payment.LatestByStatus("REFUND")
+	if refund == nil || refund.ID != 1003 {
+		t.Fatalf("LatestByStatus(REFUND) = %+v, want transaction 1003", refund)
+	}
+	if tx := payment.LatestByStatus("sale"); tx != sale {
+		t.Fatalf("LatestByStatus(sale) must return a pointer to the SALE transaction")
+	}
+	if tx := payment.LatestByStatus("CAPTURE"); tx != nil {
+		t.Fatalf("LatestByStatus(CAPTURE) = %+v, want nil", tx)
+	}
+	if tx := (*Payment)(nil).LatestByStatus("SALE"); tx != nil {
+		t.Fatalf("nil payment must return nil transaction")
+	}
+}
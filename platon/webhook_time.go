@@ -0,0 +1,130 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stremovskyy/go-platon/log"
+)
+
+// WebhookDateLayout is the layout of the callback `date` field (Kyiv local time).
+const WebhookDateLayout = "2006-01-02 15:04:05"
+
+// DefaultClockSkewWarnThreshold is the absolute skew above which
+// MeasureClockSkew logs a warning.
+const DefaultClockSkewWarnThreshold = 5 * time.Minute
+
+var (
+	kyivLocationOnce sync.Once
+	kyivLocation     *time.Location
+)
+
+// KyivLocation returns the Europe/Kyiv time zone used by Platon timestamps.
+// If tzdata is unavailable it falls back to a fixed UTC+02:00 zone, which
+// ignores daylight saving time.
+func KyivLocation() *time.Location {
+	kyivLocationOnce.Do(
+		func() {
+			for _, name := range []string{"Europe/Kyiv", "Europe/Kiev"} {
+				if loc, err := time.LoadLocation(name); err == nil {
+					kyivLocation = loc
+					return
+				}
+			}
+			kyivLocation = time.FixedZone("EET", 2*60*60)
+		},
+	)
+
+	return kyivLocation
+}
+
+func parseWebhookDate(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("webhook date is empty")
+	}
+	if loc == nil {
+		loc = KyivLocation()
+	}
+
+	parsed, err := time.ParseInLocation(WebhookDateLayout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot parse webhook date %q: %w", value, err)
+	}
+
+	return parsed, nil
+}
+
+// MeasureClockSkew returns receivedAt minus the callback `date`.
+//
+// The result also includes delivery latency, so small positive values are
+// expected. Negative values mean the local clock is behind Platon. Skew above
+// DefaultClockSkewWarnThreshold is logged as a warning; use ClockSkewProbe for
+// another threshold. Zero is returned when the form is nil or its date cannot
+// be parsed.
+func MeasureClockSkew(form *WebhookForm, receivedAt time.Time) time.Duration {
+	return ClockSkewProbe{}.Measure(form, receivedAt)
+}
+
+// ClockSkewProbe measures webhook clock skew like MeasureClockSkew with its
+// own warning threshold and log sink.
+type ClockSkewProbe struct {
+	// WarnThreshold is the absolute skew above which a warning is logged.
+	// Zero or less means DefaultClockSkewWarnThreshold.
+	WarnThreshold time.Duration
+	// Sink receives the warnings. Nil means the global log sink.
+	Sink log.Sink
+}
+
+// Measure returns receivedAt minus the callback `date`, see MeasureClockSkew.
+func (p ClockSkewProbe) Measure(form *WebhookForm, receivedAt time.Time) time.Duration {
+	logger := log.NewLogger("Platon Webhook: ").WithSink(p.Sink)
+
+	if form == nil {
+		return 0
+	}
+
+	sentAt, err := parseWebhookDate(form.Date, nil)
+	if err != nil {
+		logger.Warning("cannot measure clock skew: %v", err)
+		return 0
+	}
+
+	threshold := p.WarnThreshold
+	if threshold <= 0 {
+		threshold = DefaultClockSkewWarnThreshold
+	}
+
+	skew := receivedAt.Sub(sentAt)
+	if skew.Abs() > threshold {
+		logger.Warning("webhook clock skew %v exceeds %v (order=%s)", skew, threshold, form.Order)
+	}
+
+	return skew
+}
@@ -0,0 +1,88 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platon
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stremovskyy/go-platon/log"
+)
+
+func TestMeasureClockSkew(t *testing.T) {
+	form, err := ParseWebhookForm([]byte(webhookFormPayload))
+	if err != nil {
+		t.Fatalf("ParseWebhookForm() error: %v", err)
+	}
+
+	// 2026-02-13 10:32:57 in Kyiv (UTC+02:00 in winter).
+	receivedAt := time.Date(2026, 2, 13, 8, 33, 2, 0, time.UTC)
+	if got := MeasureClockSkew(form, receivedAt); got != 5*time.Second {
+		t.Fatalf("skew mismatch: want %v, got %v", 5*time.Second, got)
+	}
+
+	behind := time.Date(2026, 2, 13, 8, 30, 57, 0, time.UTC)
+	if got := MeasureClockSkew(form, behind); got != -2*time.Minute {
+		t.Fatalf("skew mismatch: want %v, got %v", -2*time.Minute, got)
+	}
+}
+
+func TestMeasureClockSkew_InvalidInput(t *testing.T) {
+	if got := MeasureClockSkew(nil, time.Now()); got != 0 {
+		t.Fatalf("expected zero skew for nil form, got %v", got)
+	}
+	if got := MeasureClockSkew(&WebhookForm{Date: "13.02.2026"}, time.Now()); got != 0 {
+		t.Fatalf("expected zero skew for invalid date, got %v", got)
+	}
+}
+
+func TestClockSkewProbe_WarnThreshold(t *testing.T) {
+	previousLevel := log.GetLevel()
+	log.SetLevel(log.LevelWarning)
+	t.Cleanup(func() { log.SetLevel(previousLevel) })
+
+	form, err := ParseWebhookForm([]byte(webhookFormPayload))
+	if err != nil {
+		t.Fatalf("ParseWebhookForm() error: %v", err)
+	}
+	receivedAt := time.Date(2026, 2, 13, 8, 33, 2, 0, time.UTC)
+
+	sink := &capturingSink{}
+	if got := (ClockSkewProbe{Sink: sink}).Measure(form, receivedAt); got != 5*time.Second {
+		t.Fatalf("skew mismatch: want %v, got %v", 5*time.Second, got)
+	}
+	if len(sink.lines) != 0 {
+		t.Fatalf("default threshold: unexpected warnings %q", sink.lines)
+	}
+
+	sink = &capturingSink{}
+	if got := (ClockSkewProbe{WarnThreshold: time.Second, Sink: sink}).Measure(form, receivedAt); got != 5*time.Second {
+		t.Fatalf("skew mismatch: want %v, got %v", 5*time.Second, got)
+	}
+	if len(sink.lines) != 1 || !strings.Contains(sink.lines[0], "webhook clock skew 5s exceeds 1s") {
+		t.Fatalf("1s threshold: warnings = %q, want one skew warning", sink.lines)
+	}
+}
@@ -0,0 +1,451 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platontest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	go_platon "github.com/stremovskyy/go-platon"
+	"github.com/stremovskyy/go-platon/log"
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+// Method names a go_platon.Platon method. WithContext variants share the
+// name of the plain method.
+type Method string
+
+const (
+	MethodVerification                 Method = "Verification"
+	MethodVerificationLink             Method = "VerificationLink"
+	MethodStatus                       Method = "Status"
+	MethodStatusByTransID              Method = "StatusByTransID"
+	MethodDetails                      Method = "Details"
+	MethodPayment                      Method = "Payment"
+	MethodPaymentAsync                 Method = "PaymentAsync"
+	MethodPaymentByCard                Method = "PaymentByCard"
+	MethodHold                         Method = "Hold"
+	MethodRecurring                    Method = "Recurring"
+	MethodSubmerchant                  Method = "Submerchant"
+	MethodSubmerchantAvailableForSplit Method = "SubmerchantAvailableForSplit"
+	MethodCapture                      Method = "Capture"
+	MethodCaptureFull                  Method = "CaptureFull"
+	MethodRefund                       Method = "Refund"
+	MethodRefundByOrder                Method = "RefundByOrder"
+	MethodVoid                         Method = "Void"
+	MethodCredit                       Method = "Credit"
+)
+
+// Call is one recorded call of a FakeClient method.
+type Call struct {
+	Method  Method
+	Request *go_platon.Request
+}
+
+type scripted struct {
+	response    *platon.Response
+	url         *url.URL
+	available   bool
+	submerchant *go_platon.SubmerchantInfo
+	err         error
+}
+
+// FakeClient implements go_platon.Platon with scripted results and records
+// every call. Create it with NewFakeClient; it is safe for concurrent use.
+//
+// Methods without a script return an ACCEPTED response (a verification URL
+// of https://platontest.invalid/verify, an ENABLED sub-merchant and true for
+// split availability).
+type FakeClient struct {
+	mu      sync.Mutex
+	scripts map[Method][]scripted
+	calls   []Call
+}
+
+var _ go_platon.Platon = (*FakeClient)(nil)
+
+// NewFakeClient returns a FakeClient with no scripted results.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{scripts: make(map[Method][]scripted)}
+}
+
+// On queues the result of the next call to method. Results are returned in
+// the order they were queued; the last one keeps being returned once the
+// queue is drained.
+func (f *FakeClient) On(method Method, response *platon.Response, err error) *FakeClient {
+	return f.enqueue(method, scripted{response: response, err: err})
+}
+
+// OnVerification queues the result of the next Verification or
+// VerificationLink call.
+func (f *FakeClient) OnVerification(verificationURL *url.URL, err error) *FakeClient {
+	f.enqueue(MethodVerification, scripted{url: verificationURL, err: err})
+	return f.enqueue(MethodVerificationLink, scripted{url: verificationURL, err: err})
+}
+
+// OnSubmerchantAvailableForSplit queues the result of the next
+// SubmerchantAvailableForSplit call.
+func (f *FakeClient) OnSubmerchantAvailableForSplit(available bool, err error) *FakeClient {
+	return f.enqueue(MethodSubmerchantAvailableForSplit, scripted{available: available, err: err})
+}
+
+// OnSubmerchant queues the result of the next Submerchant call.
+func (f *FakeClient) OnSubmerchant(info *go_platon.SubmerchantInfo, err error) *FakeClient {
+	return f.enqueue(MethodSubmerchant, scripted{submerchant: info, err: err})
+}
+
+func (f *FakeClient) enqueue(method Method, result scripted) *FakeClient {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.scripts[method] = append(f.scripts[method], result)
+	return f
+}
+
+// Calls returns the recorded calls in call order.
+func (f *FakeClient) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Call(nil), f.calls...)
+}
+
+// CallsTo returns the recorded calls of method.
+func (f *FakeClient) CallsTo(method Method) []Call {
+	var calls []Call
+	for _, call := range f.Calls() {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+func (f *FakeClient) next(method Method, request *go_platon.Request) (scripted, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{Method: method, Request: request})
+
+	queue := f.scripts[method]
+	if len(queue) == 0 {
+		return scripted{}, false
+	}
+	if len(queue) > 1 {
+		f.scripts[method] = queue[1:]
+	}
+
+	return queue[0], true
+}
+
+func (f *FakeClient) respond(ctx context.Context, method Method, request *go_platon.Request) (*platon.Response, error) {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			f.next(method, request)
+			return nil, err
+		}
+	}
+
+	result, ok := f.next(method, request)
+	if !ok {
+		accepted := platon.ResultAccepted
+		return &platon.Response{Result: &accepted}, nil
+	}
+
+	return result.response, result.err
+}
+
+func (f *FakeClient) verify(ctx context.Context, method Method, request *go_platon.Request) (*url.URL, error) {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			f.next(method, request)
+			return nil, err
+		}
+	}
+
+	result, ok := f.next(method, request)
+	if !ok {
+		return &url.URL{Scheme: "https", Host: "platontest.invalid", Path: "/verify"}, nil
+	}
+
+	return result.url, result.err
+}
+
+func (f *FakeClient) Verification(request *go_platon.Request, _ ...go_platon.RunOption) (*url.URL, error) {
+	return f.verify(context.Background(), MethodVerification, request)
+}
+
+func (f *FakeClient) VerificationLink(request *go_platon.Request, _ ...go_platon.RunOption) (*url.URL, error) {
+	return f.verify(context.Background(), MethodVerificationLink, request)
+}
+
+func (f *FakeClient) Status(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodStatus, request)
+}
+
+func (f *FakeClient) StatusByTransID(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodStatusByTransID, request)
+}
+
+func (f *FakeClient) Details(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodDetails, request)
+}
+
+func (f *FakeClient) Payment(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodPayment, request)
+}
+
+func (f *FakeClient) PaymentAsync(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodPaymentAsync, request)
+}
+
+// PollStatus calls Status until the scripted response is no longer pending or
+// the call fails, without waiting between calls. When the Status script ends
+// on a pending response it returns that response and an error wrapping
+// context.DeadlineExceeded, as a real poll would on timeout.
+func (f *FakeClient) PollStatus(ctx context.Context, request *go_platon.Request, _, _ time.Duration) (*platon.Response, error) {
+	var last *platon.Response
+	for {
+		response, err := f.respond(ctx, MethodStatus, request)
+		if err != nil || !response.IsPending() {
+			return response, err
+		}
+		if response == last {
+			return response, fmt.Errorf("poll status: still pending: %w", context.DeadlineExceeded)
+		}
+		last = response
+	}
+}
+
+// WaitForFinalStatus behaves like PollStatus; WaitOption values are ignored.
+func (f *FakeClient) WaitForFinalStatus(ctx context.Context, request *go_platon.Request, _ ...go_platon.WaitOption) (*platon.Response, error) {
+	return f.PollStatus(ctx, request, 0, 0)
+}
+
+func (f *FakeClient) PaymentByCard(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodPaymentByCard, request)
+}
+
+func (f *FakeClient) Hold(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodHold, request)
+}
+
+func (f *FakeClient) Recurring(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodRecurring, request)
+}
+
+func (f *FakeClient) Submerchant(request *go_platon.Request, runOpts ...go_platon.RunOption) (*go_platon.SubmerchantInfo, error) {
+	return f.SubmerchantWithContext(context.Background(), request, runOpts...)
+}
+
+func (f *FakeClient) SubmerchantAvailableForSplit(request *go_platon.Request, runOpts ...go_platon.RunOption) (bool, error) {
+	return f.SubmerchantAvailableForSplitWithContext(context.Background(), request, runOpts...)
+}
+
+func (f *FakeClient) Capture(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodCapture, request)
+}
+
+func (f *FakeClient) CaptureFull(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodCaptureFull, request)
+}
+
+func (f *FakeClient) Refund(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodRefund, request)
+}
+
+func (f *FakeClient) RefundByOrder(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodRefundByOrder, request)
+}
+
+func (f *FakeClient) Void(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodVoid, request)
+}
+
+func (f *FakeClient) Credit(request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(context.Background(), MethodCredit, request)
+}
+
+// CreditBatch calls Credit for every request in order, so Credit scripts
+// apply to each payout.
+func (f *FakeClient) CreditBatch(ctx context.Context, requests []*go_platon.Request, _ int, _ ...go_platon.RunOption) ([]go_platon.CreditResult, error) {
+	results := make([]go_platon.CreditResult, len(requests))
+	failed := 0
+	for idx, request := range requests {
+		response, err := f.respond(ctx, MethodCredit, request)
+		results[idx] = go_platon.CreditResult{Index: idx, Request: request, Response: response, Err: err}
+		if err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, &go_platon.BatchError{Op: "credit batch", Succeeded: len(requests) - failed, Failed: failed}
+	}
+
+	return results, nil
+}
+
+// StatusBatch calls Status for every request in order, so Status scripts
+// apply to each order.
+func (f *FakeClient) StatusBatch(requests []*go_platon.Request, opts ...go_platon.BatchOption) ([]*platon.Response, []error) {
+	return f.StatusBatchWithContext(context.Background(), requests, opts...)
+}
+
+func (f *FakeClient) StatusBatchWithContext(ctx context.Context, requests []*go_platon.Request, _ ...go_platon.BatchOption) ([]*platon.Response, []error) {
+	responses := make([]*platon.Response, len(requests))
+	errs := make([]error, len(requests))
+	for idx, request := range requests {
+		responses[idx], errs[idx] = f.respond(ctx, MethodStatus, request)
+	}
+
+	return responses, errs
+}
+
+func (f *FakeClient) VerificationWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*url.URL, error) {
+	return f.verify(ctx, MethodVerification, request)
+}
+
+func (f *FakeClient) VerificationLinkWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*url.URL, error) {
+	return f.verify(ctx, MethodVerificationLink, request)
+}
+
+func (f *FakeClient) StatusWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodStatus, request)
+}
+
+func (f *FakeClient) StatusByTransIDWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodStatusByTransID, request)
+}
+
+func (f *FakeClient) DetailsWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodDetails, request)
+}
+
+func (f *FakeClient) PaymentWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodPayment, request)
+}
+
+func (f *FakeClient) PaymentAsyncWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodPaymentAsync, request)
+}
+
+func (f *FakeClient) PaymentByCardWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodPaymentByCard, request)
+}
+
+func (f *FakeClient) HoldWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodHold, request)
+}
+
+func (f *FakeClient) RecurringWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodRecurring, request)
+}
+
+func (f *FakeClient) SubmerchantWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*go_platon.SubmerchantInfo, error) {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			f.next(MethodSubmerchant, request)
+			return nil, err
+		}
+	}
+
+	result, ok := f.next(MethodSubmerchant, request)
+	if !ok {
+		info := &go_platon.SubmerchantInfo{
+			Status:    platon.SubmerchantStatusEnabled,
+			RawStatus: string(platon.SubmerchantStatusEnabled),
+		}
+		if submerchantID := request.GetSubmerchantID(); submerchantID != nil {
+			info.ID = *submerchantID
+		}
+		return info, nil
+	}
+
+	return result.submerchant, result.err
+}
+
+func (f *FakeClient) SubmerchantAvailableForSplitWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (bool, error) {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			f.next(MethodSubmerchantAvailableForSplit, request)
+			return false, err
+		}
+	}
+
+	result, ok := f.next(MethodSubmerchantAvailableForSplit, request)
+	if !ok {
+		return true, nil
+	}
+
+	return result.available, result.err
+}
+
+func (f *FakeClient) CaptureWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodCapture, request)
+}
+
+func (f *FakeClient) CaptureFullWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodCaptureFull, request)
+}
+
+func (f *FakeClient) RefundWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodRefund, request)
+}
+
+func (f *FakeClient) RefundByOrderWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodRefundByOrder, request)
+}
+
+func (f *FakeClient) VoidWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodVoid, request)
+}
+
+func (f *FakeClient) CreditWithContext(ctx context.Context, request *go_platon.Request, _ ...go_platon.RunOption) (*platon.Response, error) {
+	return f.respond(ctx, MethodCredit, request)
+}
+
+// MeasureClockSkew measures the skew like platon.MeasureClockSkew.
+func (f *FakeClient) MeasureClockSkew(form *platon.WebhookForm, receivedAt time.Time) time.Duration {
+	return platon.MeasureClockSkew(form, receivedAt)
+}
+
+// ParseWebhookXML parses data like the real client.
+//
+// Deprecated: use go_platon.ParseWebhookForm, as the real client suggests.
+func (f *FakeClient) ParseWebhookXML(data []byte) (*platon.Payment, error) {
+	return platon.ParsePaymentXML(data)
+}
+
+// SetLogLevel is a no-op.
+func (f *FakeClient) SetLogLevel(log.Level) {}
+
+// Stats returns empty stats; the fake client has no rate limiter.
+func (f *FakeClient) Stats() go_platon.ClientStats {
+	return go_platon.ClientStats{}
+}
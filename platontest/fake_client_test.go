@@ -0,0 +1,146 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platontest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	go_platon "github.com/stremovskyy/go-platon"
+	"github.com/stremovskyy/go-platon/platon"
+	"github.com/stremovskyy/go-platon/platontest"
+)
+
+// chargeOrder stands in for merchant code that depends on the Platon interface.
+func chargeOrder(client go_platon.Platon, request *go_platon.Request) (string, error) {
+	resp, err := client.Payment(request)
+	if err != nil {
+		return "", err
+	}
+
+	return *resp.TransId, nil
+}
+
+func TestFakeClient_ScriptedResponses(t *testing.T) {
+	declined := errors.New("declined")
+	fake := platontest.NewFakeClient().
+		On(platontest.MethodPayment, nil, declined).
+		On(platontest.MethodPayment, &platon.Response{TransId: ref("trans-2")}, nil)
+
+	if _, err := chargeOrder(fake, newTokenPayment()); !errors.Is(err, declined) {
+		t.Fatalf("first call error = %v, want scripted error", err)
+	}
+	for i := 0; i < 2; i++ {
+		transID, err := chargeOrder(fake, newTokenPayment())
+		if err != nil || transID != "trans-2" {
+			t.Fatalf("call %d = %q, %v; want trans-2", i+2, transID, err)
+		}
+	}
+
+	calls := fake.CallsTo(platontest.MethodPayment)
+	if len(calls) != 3 {
+		t.Fatalf("recorded %d Payment calls, want 3", len(calls))
+	}
+	if got := *calls[0].Request.PaymentData.PaymentID; got != "order-1" {
+		t.Fatalf("recorded request order = %q", got)
+	}
+}
+
+func TestFakeClient_Defaults(t *testing.T) {
+	fake := platontest.NewFakeClient()
+
+	resp, err := fake.CaptureWithContext(context.Background(), newTokenPayment())
+	if err != nil || resp.Result == nil || *resp.Result != platon.ResultAccepted {
+		t.Fatalf("CaptureWithContext() = %+v, %v; want ACCEPTED", resp, err)
+	}
+	if ok, err := fake.SubmerchantAvailableForSplit(newTokenPayment()); !ok || err != nil {
+		t.Fatalf("SubmerchantAvailableForSplit() = %v, %v; want true", ok, err)
+	}
+	if info, err := fake.Submerchant(newTokenPayment()); err != nil || info.Status != platon.SubmerchantStatusEnabled {
+		t.Fatalf("Submerchant() = %+v, %v; want ENABLED", info, err)
+	}
+	if u, err := fake.Verification(newTokenPayment()); err != nil || u == nil {
+		t.Fatalf("Verification() = %v, %v", u, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fake.RefundWithContext(ctx, newTokenPayment()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("RefundWithContext() error = %v, want context.Canceled", err)
+	}
+
+	if got := len(fake.Calls()); got != 5 {
+		t.Fatalf("recorded %d calls, want 5", got)
+	}
+}
+
+func TestFakeClient_CreditBatch(t *testing.T) {
+	fake := platontest.NewFakeClient().
+		On(platontest.MethodCredit, &platon.Response{}, nil).
+		On(platontest.MethodCredit, nil, errors.New("limit exceeded"))
+
+	results, err := fake.CreditBatch(context.Background(), []*go_platon.Request{newTokenPayment(), newTokenPayment()}, 2)
+	var batchErr *go_platon.BatchError
+	if !errors.As(err, &batchErr) || batchErr.Succeeded != 1 || batchErr.Failed != 1 {
+		t.Fatalf("CreditBatch() error = %v, want 1 succeeded / 1 failed", err)
+	}
+	if !results[0].Succeeded() || results[1].Succeeded() {
+		t.Fatalf("CreditBatch() results = %+v", results)
+	}
+}
+
+func TestFakeClient_StatusBatch(t *testing.T) {
+	fake := platontest.NewFakeClient().
+		On(platontest.MethodStatus, &platon.Response{}, nil).
+		On(platontest.MethodStatus, nil, errors.New("order not found"))
+
+	responses, errs := fake.StatusBatch([]*go_platon.Request{newTokenPayment(), newTokenPayment()})
+	if responses[0] == nil || errs[0] != nil {
+		t.Fatalf("StatusBatch()[0] = %v, %v; want a response", responses[0], errs[0])
+	}
+	if responses[1] != nil || errs[1] == nil {
+		t.Fatalf("StatusBatch()[1] = %v, %v; want an error", responses[1], errs[1])
+	}
+}
+
+func TestFakeClient_PollStatus(t *testing.T) {
+	pending := "PENDING"
+	settled := "SETTLED"
+	fake := platontest.NewFakeClient().
+		On(platontest.MethodStatus, &platon.Response{Status: &pending}, nil).
+		On(platontest.MethodStatus, &platon.Response{Status: &settled}, nil)
+
+	resp, err := fake.PollStatus(context.Background(), newTokenPayment(), time.Second, time.Minute)
+	if err != nil || resp.Status == nil || *resp.Status != settled {
+		t.Fatalf("PollStatus() = %+v, %v; want SETTLED", resp, err)
+	}
+
+	stuck := platontest.NewFakeClient().On(platontest.MethodStatus, &platon.Response{Status: &pending}, nil)
+	if _, err := stuck.PollStatus(context.Background(), newTokenPayment(), time.Second, time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PollStatus() error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,253 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+// Package platontest provides test doubles for code that uses go_platon:
+// a fake Platon HTTP server with canned responses and a scripted fake client.
+package platontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+// Outcome selects the canned response the fake server returns for an action.
+type Outcome string
+
+const (
+	// OutcomeAccepted answers with result=ACCEPTED and a status that fits the action.
+	OutcomeAccepted Outcome = "ACCEPTED"
+	// OutcomeDeclined answers with result=DECLINED and a decline_reason.
+	OutcomeDeclined Outcome = "DECLINED"
+	// OutcomeError answers with result=ERROR and an error_message.
+	OutcomeError Outcome = "ERROR"
+)
+
+// DeclineReason and ErrorMessage are the texts of the canned declined and
+// error responses.
+const (
+	DeclineReason = "Declined by platontest"
+	ErrorMessage  = "Error from platontest"
+)
+
+// ServerOption configures NewFakeServer.
+type ServerOption func(*fakeServer)
+
+// WithOutcome sets the canned response for action. Actions default to
+// OutcomeAccepted.
+func WithOutcome(action platon.ActionCode, outcome Outcome) ServerOption {
+	return func(s *fakeServer) {
+		s.outcomes[action] = outcome
+	}
+}
+
+// WithResponse makes the server answer action with a fixed status code and
+// JSON body instead of a canned response.
+func WithResponse(action platon.ActionCode, statusCode int, body string) ServerOption {
+	return func(s *fakeServer) {
+		s.responses[action] = fixedResponse{statusCode: statusCode, body: body}
+	}
+}
+
+// WithRequestHook registers fn to receive the form of every request the
+// server handles, e.g. to assert on what the SDK sent.
+func WithRequestHook(fn func(action platon.ActionCode, form url.Values)) ServerOption {
+	return func(s *fakeServer) {
+		s.hook = fn
+	}
+}
+
+type fixedResponse struct {
+	statusCode int
+	body       string
+}
+
+type fakeServer struct {
+	mu        sync.Mutex
+	outcomes  map[platon.ActionCode]Outcome
+	responses map[platon.ActionCode]fixedResponse
+	hook      func(platon.ActionCode, url.Values)
+	transSeq  atomic.Int64
+}
+
+// NewFakeServer starts an HTTP server that answers Platon form requests with
+// canned JSON responses keyed by the `action` field. Close it when done.
+//
+// Point a client at it with NewHTTPClient:
+//
+//	srv := platontest.NewFakeServer(platontest.WithOutcome(platon.ActionCodeSALE, platontest.OutcomeDeclined))
+//	defer srv.Close()
+//	client := go_platon.NewClient(go_platon.WithClient(platontest.NewHTTPClient(srv)))
+func NewFakeServer(opts ...ServerOption) *httptest.Server {
+	s := &fakeServer{
+		outcomes:  make(map[platon.ActionCode]Outcome),
+		responses: make(map[platon.ActionCode]fixedResponse),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+
+	return httptest.NewServer(s)
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	action := platon.ActionCode(strings.TrimSpace(r.PostForm.Get("action")))
+	if s.hook != nil {
+		s.mu.Lock()
+		s.hook(action, r.PostForm)
+		s.mu.Unlock()
+	}
+
+	if fixed, ok := s.responses[action]; ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(fixed.statusCode)
+		_, _ = w.Write([]byte(fixed.body))
+		return
+	}
+
+	body := s.cannedResponse(action, r.PostForm)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (s *fakeServer) cannedResponse(action platon.ActionCode, form url.Values) map[string]string {
+	if action == "" {
+		return map[string]string{"result": platon.ResultError.String(), "error_message": "platontest: action is required"}
+	}
+
+	outcome, ok := s.outcomes[action]
+	if !ok {
+		outcome = OutcomeAccepted
+	}
+
+	body := map[string]string{"action": action.String()}
+	setIfPresent(body, "order_id", form.Get("order_id"))
+	if outcome == OutcomeError {
+		body["result"] = platon.ResultError.String()
+		body["error_message"] = ErrorMessage
+		return body
+	}
+
+	transID := form.Get("trans_id")
+	if transID == "" {
+		transID = fmt.Sprintf("platontest-%d", s.transSeq.Add(1))
+	}
+	body["trans_id"] = transID
+	body["trans_date"] = "2026-01-01 12:00:00"
+	setIfPresent(body, "amount", firstNonEmpty(form.Get("amount"), form.Get("order_amount")))
+
+	if outcome == OutcomeDeclined {
+		body["result"] = platon.ResultDeclined.String()
+		body["status"] = "DECLINED"
+		body["decline_reason"] = DeclineReason
+		return body
+	}
+
+	body["result"] = platon.ResultAccepted.String()
+	body["status"] = acceptedStatus(action, form)
+	if action == platon.ActionCodeGetSubmerchant {
+		delete(body, "trans_id")
+		delete(body, "trans_date")
+		body["submerchant_id"] = form.Get("submerchant_id")
+		body["submerchant_id_status"] = platon.SubmerchantStatusEnabled.String()
+	}
+
+	return body
+}
+
+func acceptedStatus(action platon.ActionCode, form url.Values) string {
+	switch action {
+	case platon.ActionCodeSALE, platon.ActionCodeAPPLEPAY, platon.ActionCodeGOOGLEPAY:
+		if form.Get("auth") == "Y" {
+			return "PENDING"
+		}
+	case platon.ActionCodeCREDITVOID:
+		if form.Get("amount") == "" {
+			return "REVERSAL"
+		}
+		return "REFUND"
+	case platon.ActionCodeGetSubmerchant:
+		return ""
+	}
+
+	return "SETTLED"
+}
+
+func setIfPresent(body map[string]string, key, value string) {
+	if value != "" {
+		body[key] = value
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// NewHTTPClient returns an *http.Client that sends every request to server,
+// whatever host the SDK targets. Pass it to go_platon.WithClient.
+func NewHTTPClient(server *httptest.Server) *http.Client {
+	target, _ := url.Parse(server.URL)
+
+	return &http.Client{
+		Transport: &rewriteTransport{target: target, next: server.Client().Transport},
+	}
+}
+
+type rewriteTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rewritten := req.Clone(req.Context())
+	rewritten.URL.Scheme = t.target.Scheme
+	rewritten.URL.Host = t.target.Host
+	rewritten.Host = t.target.Host
+
+	return t.next.RoundTrip(rewritten)
+}
@@ -0,0 +1,154 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package platontest_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	go_platon "github.com/stremovskyy/go-platon"
+	"github.com/stremovskyy/go-platon/currency"
+	"github.com/stremovskyy/go-platon/platon"
+	"github.com/stremovskyy/go-platon/platontest"
+)
+
+func ref[T any](value T) *T {
+	return &value
+}
+
+func newTokenPayment() *go_platon.Request {
+	return &go_platon.Request{
+		Merchant: &go_platon.Merchant{
+			MerchantKey: "CLIENT_KEY",
+			SecretKey:   "CLIENT_PASS",
+			TermsURL:    ref("https://merchant.example/3ds"),
+			ClientIP:    ref("203.0.113.10"),
+		},
+		PaymentMethod: &go_platon.PaymentMethod{
+			Card: &go_platon.Card{Token: ref("CARD_TOKEN")},
+		},
+		PaymentData: &go_platon.PaymentData{
+			PaymentID:   ref("order-1"),
+			Amount:      12345,
+			Currency:    currency.UAH,
+			Description: "platontest",
+		},
+		PersonalData: &go_platon.PersonalData{Email: ref("payer@example.com")},
+	}
+}
+
+// TestFakeServer_CapturesRequest shows how to assert on what the SDK sent.
+func TestFakeServer_CapturesRequest(t *testing.T) {
+	var sent url.Values
+	srv := platontest.NewFakeServer(
+		platontest.WithRequestHook(
+			func(action platon.ActionCode, form url.Values) {
+				sent = form
+			},
+		),
+	)
+	defer srv.Close()
+
+	client := go_platon.NewClient(go_platon.WithClient(platontest.NewHTTPClient(srv)))
+
+	resp, err := client.Payment(newTokenPayment())
+	if err != nil {
+		t.Fatalf("Payment() error: %v", err)
+	}
+	if resp.Result == nil || *resp.Result != platon.ResultAccepted {
+		t.Fatalf("Payment() result = %v, want ACCEPTED", resp.Result)
+	}
+	if resp.TransId == nil || *resp.TransId == "" {
+		t.Fatal("Payment() response has no trans_id")
+	}
+
+	if got := sent.Get("action"); got != platon.ActionCodeSALE.String() {
+		t.Fatalf("action = %q, want SALE", got)
+	}
+	if got := sent.Get("order_amount"); got != "123.45" {
+		t.Fatalf("order_amount = %q, want 123.45", got)
+	}
+	if got := sent.Get("card_token"); got != "CARD_TOKEN" {
+		t.Fatalf("card_token = %q, want CARD_TOKEN", got)
+	}
+	if sent.Get("hash") == "" {
+		t.Fatal("request was not signed")
+	}
+}
+
+func TestFakeServer_Outcomes(t *testing.T) {
+	srv := platontest.NewFakeServer(
+		platontest.WithOutcome(platon.ActionCodeSALE, platontest.OutcomeDeclined),
+		platontest.WithOutcome(platon.ActionCodeCAPTURE, platontest.OutcomeError),
+	)
+	defer srv.Close()
+
+	client := go_platon.NewClient(go_platon.WithClient(platontest.NewHTTPClient(srv)))
+
+	_, err := client.Payment(newTokenPayment())
+	var apiErr *platon.APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != platon.APIErrorKindDeclined {
+		t.Fatalf("Payment() error = %v, want declined APIError", err)
+	}
+
+	capture := newTokenPayment()
+	capture.PaymentData.PlatonTransID = ref("trans-1")
+	if _, err := client.Capture(capture); !errors.As(err, &apiErr) || apiErr.Kind != platon.APIErrorKindError {
+		t.Fatalf("Capture() error = %v, want error APIError", err)
+	}
+
+	status := newTokenPayment()
+	status.PaymentData.PlatonTransID = ref("trans-1")
+	resp, err := client.Status(status)
+	if err != nil {
+		t.Fatalf("Status() error: %v", err)
+	}
+	if resp.Status == nil || *resp.Status != "SETTLED" || resp.TransId == nil || *resp.TransId != "trans-1" {
+		t.Fatalf("Status() unexpected response: %+v", resp)
+	}
+}
+
+func TestFakeServer_WithResponse(t *testing.T) {
+	srv := platontest.NewFakeServer(
+		platontest.WithResponse(
+			platon.ActionCodeGetSubmerchant, 200,
+			`{"action":"GET_SUBMERCHANT","result":"ACCEPTED","submerchant_id_status":"LOCKED"}`,
+		),
+	)
+	defer srv.Close()
+
+	client := go_platon.NewClient(go_platon.WithClient(platontest.NewHTTPClient(srv)))
+
+	req := newTokenPayment()
+	req.PaymentData.SubmerchantID = ref("SUB-1")
+	available, err := client.SubmerchantAvailableForSplit(req)
+	if err != nil {
+		t.Fatalf("SubmerchantAvailableForSplit() error: %v", err)
+	}
+	if available {
+		t.Fatal("SubmerchantAvailableForSplit() = true for a LOCKED submerchant")
+	}
+}
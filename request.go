@@ -26,13 +26,12 @@ package go_platon
 
 import (
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/stremovskyy/go-platon/consts"
 	"github.com/stremovskyy/go-platon/currency"
+	"github.com/stremovskyy/go-platon/money"
 	"github.com/stremovskyy/go-platon/platon"
 )
 
@@ -46,6 +45,10 @@ type Request struct {
 // BuildClientServerVerificationForm builds signed browser form fields for
 // Client-Server card verification (`/payment/auth`).
 func BuildClientServerVerificationForm(request *Request) (*platon.ClientServerVerificationForm, error) {
+	return buildClientServerVerificationForm(request, consts.ApiPaymentAuthURL)
+}
+
+func buildClientServerVerificationForm(request *Request, endpoint string) (*platon.ClientServerVerificationForm, error) {
 	if request == nil {
 		return nil, platon.ErrRequestIsNil
 	}
@@ -68,7 +71,7 @@ func BuildClientServerVerificationForm(request *Request) (*platon.ClientServerVe
 			OrderID:     request.GetPaymentID(),
 			Metadata:    request.GetMetadata(),
 		},
-		consts.ApiPaymentAuthURL,
+		endpoint,
 	)
 }
 
@@ -146,6 +149,12 @@ func (r *Request) GetPlatonTransID() *string {
 	return nil
 }
 
+// GetMerchantOrderID returns the merchant order id (Platon `order_id`).
+// It is the same value as GetPaymentID.
+func (r *Request) GetMerchantOrderID() *string {
+	return r.GetPaymentID()
+}
+
 func (r *Request) GetCardToken() *string {
 	if r == nil {
 		return nil
@@ -219,6 +228,10 @@ func (r *Request) SetRedirects(successURL string, failURL string) {
 	r.Merchant.FailRedirect = failURL
 }
 
+// GetAmount returns the payment amount in major units.
+//
+// Deprecated: float32 loses precision for large amounts and is unsafe for
+// comparisons. Use GetAmountMoney.
 func (r *Request) GetAmount() float32 {
 	if r == nil {
 		return 0
@@ -231,6 +244,25 @@ func (r *Request) GetAmount() float32 {
 	return float32(r.PaymentData.Amount) / 100
 }
 
+// GetAmountMoney returns the payment amount as exact minor units with currency.
+func (r *Request) GetAmountMoney() money.Amount {
+	if r == nil || r.PaymentData == nil {
+		return money.Amount{}
+	}
+
+	return money.New(int64(r.PaymentData.Amount), r.PaymentData.Currency)
+}
+
+// IsPartialCapture reports whether the capture amount is below PaymentData.OriginalAmount.
+// It is false when OriginalAmount is not set.
+func (r *Request) IsPartialCapture() bool {
+	if r == nil || r.PaymentData == nil || r.PaymentData.OriginalAmount == nil {
+		return false
+	}
+
+	return r.PaymentData.Amount > 0 && r.PaymentData.Amount < *r.PaymentData.OriginalAmount
+}
+
 func (r *Request) GetDescription() string {
 	if r == nil {
 		return ""
@@ -268,16 +300,7 @@ func (r *Request) IsMobile() bool {
 	if r.PaymentData.IsMobile {
 		return true
 	}
-	if r.PaymentMethod == nil {
-		return false
-	}
-	if r.PaymentMethod.AppleContainer != nil && *r.PaymentMethod.AppleContainer != "" {
-		return true
-	}
-	if r.PaymentMethod.GoogleToken != nil && *r.PaymentMethod.GoogleToken != "" {
-		return true
-	}
-	return false
+	return r.IsApplePay() || r.IsGooglePay()
 }
 
 func (r *Request) GetAppleContainer() (*string, error) {
@@ -292,22 +315,12 @@ func (r *Request) GetAppleContainer() (*string, error) {
 		return nil, fmt.Errorf("Apple Container is empty")
 	}
 
-	decoded, err := base64.StdEncoding.DecodeString(*r.PaymentMethod.AppleContainer)
-	if err != nil {
-		return nil, fmt.Errorf("cannot decode Apple Container: %w", err)
-	}
-
-	var token map[string]interface{}
-	if errr := json.Unmarshal(decoded, &token); errr != nil {
-		return nil, fmt.Errorf("json unmarshal error: %w", errr)
-	}
-
-	outputJSON, err := json.Marshal(token["token"])
+	container, err := platon.ParseApplePayContainer(*r.PaymentMethod.AppleContainer)
 	if err != nil {
-		return nil, fmt.Errorf("json marshal error: %w", err)
+		return nil, err
 	}
 
-	outputBase64 := base64.StdEncoding.EncodeToString(outputJSON)
+	outputBase64 := container.Base64()
 	return &outputBase64, nil
 }
 
@@ -319,42 +332,62 @@ func (r *Request) IsApplePay() bool {
 	return r.PaymentMethod != nil && r.PaymentMethod.AppleContainer != nil && *r.PaymentMethod.AppleContainer != ""
 }
 
-func (r *Request) GetGoogleToken() (*string, error) {
+// IsGooglePay reports whether the request carries a non-empty Google Pay token.
+func (r *Request) IsGooglePay() bool {
 	if r == nil {
-		return nil, fmt.Errorf("request is nil")
+		return false
 	}
 
-	if r.PaymentMethod == nil || r.PaymentMethod.GoogleToken == nil {
-		return nil, fmt.Errorf("Google Token is not set")
-	}
-	if *r.PaymentMethod.GoogleToken == "" {
-		return nil, fmt.Errorf("Google Token is empty")
+	return r.PaymentMethod != nil && r.PaymentMethod.GoogleToken != nil && *r.PaymentMethod.GoogleToken != ""
+}
+
+// PaymentMethodKind reports which payment method the client will use for the
+// request. Wallets win over card data: Apple Pay, then Google Pay, then
+// CARD_TOKEN, then card PAN.
+func (r *Request) PaymentMethodKind() PaymentMethodKind {
+	switch {
+	case r.IsApplePay():
+		return PaymentMethodApplePay
+	case r.IsGooglePay():
+		return PaymentMethodGooglePay
 	}
 
-	decoded, err := base64.StdEncoding.DecodeString(*r.PaymentMethod.GoogleToken)
-	if err != nil {
-		return nil, fmt.Errorf("cannot decode Google Token: %w", err)
+	if token := r.GetCardToken(); token != nil && *token != "" {
+		return PaymentMethodCardToken
+	}
+	if pan := r.GetCardPan(); pan != nil && *pan != "" {
+		return PaymentMethodCardPAN
 	}
 
-	var data struct {
-		PaymentMethodData struct {
-			TokenizationData struct {
-				Token string `json:"token"`
-			} `json:"tokenizationData"`
-		} `json:"paymentMethodData"`
+	return PaymentMethodUnknown
+}
+
+func (r *Request) GetGoogleToken() (*string, error) {
+	token, _, err := r.GetGooglePayDetails()
+	return token, err
+}
+
+// GetGooglePayDetails returns the base64 Google Pay token for payment_token
+// together with the card network and details from the wallet payload.
+func (r *Request) GetGooglePayDetails() (*string, *platon.GooglePayInfo, error) {
+	if r == nil {
+		return nil, nil, fmt.Errorf("request is nil")
 	}
 
-	if errr := json.Unmarshal(decoded, &data); errr != nil {
-		return nil, fmt.Errorf("json unmarshal error: %w", errr)
+	if r.PaymentMethod == nil || r.PaymentMethod.GoogleToken == nil {
+		return nil, nil, fmt.Errorf("Google Token is not set")
+	}
+	if *r.PaymentMethod.GoogleToken == "" {
+		return nil, nil, fmt.Errorf("Google Token is empty")
 	}
 
-	unescapedToken, err := strconv.Unquote(fmt.Sprintf("%q", data.PaymentMethodData.TokenizationData.Token))
+	token, info, err := platon.ParseGooglePayToken(*r.PaymentMethod.GoogleToken)
 	if err != nil {
-		return nil, fmt.Errorf("unquote error: %w", err)
+		return nil, nil, err
 	}
 
-	outputBase64 := base64.StdEncoding.EncodeToString([]byte(unescapedToken))
-	return &outputBase64, nil
+	outputBase64 := base64.StdEncoding.EncodeToString([]byte(token))
+	return &outputBase64, info, nil
 }
 
 func (r *Request) GetTrackingData() *int64 {
@@ -402,7 +435,7 @@ func (r *Request) GetSplitRules() (platon.SplitRules, error) {
 			return nil, fmt.Errorf("split_rules[%d]: duplicate submerchant identification %q", idx, identification)
 		}
 
-		result[identification] = fmt.Sprintf("%.2f", float64(rule.Amount)/100)
+		result[identification] = platon.MoneyFromMinorUnits(int64(rule.Amount)).Format(r.GetCurrency())
 	}
 
 	if totalMinorUnits != r.PaymentData.Amount {
@@ -429,6 +462,22 @@ func (r *Request) GetSubmerchantID() *string {
 	return &id
 }
 
+// GetRecurringFirstTransID returns PaymentData.RecurringFirstTransID, falling
+// back to the "recurring_first_trans_id" metadata key.
+func (r *Request) GetRecurringFirstTransID() *string {
+	if r == nil || r.PaymentData == nil {
+		return nil
+	}
+
+	if r.PaymentData.RecurringFirstTransID != nil {
+		if id := strings.TrimSpace(*r.PaymentData.RecurringFirstTransID); id != "" {
+			return &id
+		}
+	}
+
+	return stringPointerFromMetadata(r.PaymentData.Metadata, platonMetaRecurringFirstTransID)
+}
+
 func (r *Request) GetReceiverTIN() *string {
 	if r == nil {
 		return nil
@@ -477,11 +526,16 @@ func (r *Request) GetMerchantKey() string {
 	return r.Merchant.MerchantKey
 }
 
+// GetClientIP returns the payer IP: PaymentData.PayerIP when set, otherwise
+// Merchant.ClientIP.
 func (r *Request) GetClientIP() *string {
 	if r == nil {
 		return nil
 	}
 
+	if r.PaymentData != nil && nonEmpty(r.PaymentData.PayerIP) {
+		return r.PaymentData.PayerIP
+	}
 	if r.Merchant == nil {
 		return nil
 	}
@@ -489,11 +543,16 @@ func (r *Request) GetClientIP() *string {
 	return r.Merchant.ClientIP
 }
 
+// GetTermsURL returns the 3DS terms URL: PaymentData.TermsURL when set,
+// otherwise Merchant.TermsURL.
 func (r *Request) GetTermsURL() *string {
 	if r == nil {
 		return nil
 	}
 
+	if r.PaymentData != nil && nonEmpty(r.PaymentData.TermsURL) {
+		return r.PaymentData.TermsURL
+	}
 	if r.Merchant == nil {
 		return nil
 	}
@@ -501,6 +560,10 @@ func (r *Request) GetTermsURL() *string {
 	return r.Merchant.TermsURL
 }
 
+func nonEmpty(value *string) bool {
+	return value != nil && strings.TrimSpace(*value) != ""
+}
+
 func (r *Request) GetCardNumber() *string {
 	if r == nil {
 		return nil
@@ -25,8 +25,13 @@
 package go_platon
 
 import (
+	"encoding/base64"
+	"errors"
 	"math"
 	"testing"
+
+	"github.com/stremovskyy/go-platon/currency"
+	"github.com/stremovskyy/go-platon/platon"
 )
 
 func TestRequest_GetAmount_UsesMinorUnits(t *testing.T) {
@@ -42,6 +47,28 @@ func TestRequest_GetAmount_UsesMinorUnits(t *testing.T) {
 	}
 }
 
+func TestRequest_GetAmountMoney_PreservesPrecision(t *testing.T) {
+	// 1677721.71 does not fit float32 exactly: it becomes 1677721.75.
+	req := &Request{
+		PaymentData: &PaymentData{
+			Amount:   167772171,
+			Currency: currency.UAH,
+		},
+	}
+
+	if got := float64(req.GetAmount()); got == 1677721.71 {
+		t.Fatalf("expected float32 GetAmount() to lose precision, got %.2f", got)
+	}
+
+	got := req.GetAmountMoney()
+	if got.Minor != 167772171 || got.Currency != currency.UAH {
+		t.Fatalf("GetAmountMoney() mismatch: got %#v", got)
+	}
+	if got.String() != "1677721.71 UAH" {
+		t.Fatalf("GetAmountMoney().String() mismatch: want %q, got %q", "1677721.71 UAH", got.String())
+	}
+}
+
 func TestRequest_NilReceiver_GettersAreSafe(t *testing.T) {
 	var req *Request
 
@@ -78,6 +105,9 @@ func TestRequest_NilReceiver_GettersAreSafe(t *testing.T) {
 	if req.GetAmount() != 0 {
 		t.Fatalf("GetAmount() expected zero value")
 	}
+	if !req.GetAmountMoney().IsZero() {
+		t.Fatalf("GetAmountMoney() expected zero value")
+	}
 	if req.GetDescription() != "" {
 		t.Fatalf("GetDescription() expected empty value")
 	}
@@ -96,6 +126,12 @@ func TestRequest_NilReceiver_GettersAreSafe(t *testing.T) {
 	if _, err := req.GetGoogleToken(); err == nil {
 		t.Fatalf("GetGoogleToken() expected error")
 	}
+	if req.IsGooglePay() {
+		t.Fatalf("IsGooglePay() expected false")
+	}
+	if req.PaymentMethodKind() != PaymentMethodUnknown {
+		t.Fatalf("PaymentMethodKind() expected unknown")
+	}
 	if req.GetTrackingData() != nil {
 		t.Fatalf("GetTrackingData() expected nil")
 	}
@@ -136,3 +172,167 @@ func TestRequest_NilReceiver_GettersAreSafe(t *testing.T) {
 		t.Fatalf("GetCardCvv2() expected nil")
 	}
 }
+
+func TestRequest_GetClientIPAndTermsURL_PreferPaymentData(t *testing.T) {
+	req := &Request{
+		Merchant: &Merchant{
+			ClientIP: ref("203.0.113.10"),
+			TermsURL: ref("https://merchant.example/terms"),
+		},
+		PaymentData: &PaymentData{
+			PayerIP:  ref("2001:db8::1"),
+			TermsURL: ref("https://merchant.example/order-terms"),
+		},
+	}
+	if got := req.GetClientIP(); got == nil || *got != "2001:db8::1" {
+		t.Fatalf("GetClientIP() = %v, want PaymentData.PayerIP", got)
+	}
+	if got := req.GetTermsURL(); got == nil || *got != "https://merchant.example/order-terms" {
+		t.Fatalf("GetTermsURL() = %v, want PaymentData.TermsURL", got)
+	}
+
+	req.PaymentData.PayerIP = ref("  ")
+	req.PaymentData.TermsURL = nil
+	if got := req.GetClientIP(); got == nil || *got != "203.0.113.10" {
+		t.Fatalf("GetClientIP() = %v, want Merchant.ClientIP fallback", got)
+	}
+	if got := req.GetTermsURL(); got == nil || *got != "https://merchant.example/terms" {
+		t.Fatalf("GetTermsURL() = %v, want Merchant.TermsURL fallback", got)
+	}
+
+	req.Merchant = nil
+	if req.GetClientIP() != nil || req.GetTermsURL() != nil {
+		t.Fatal("expected nil without PaymentData values and merchant")
+	}
+}
+
+func TestRequest_GetSplitRules_FormatsLargeAmountsExactly(t *testing.T) {
+	req := &Request{
+		PaymentData: &PaymentData{
+			Amount: 200000001,
+			SplitRules: []SplitRule{
+				{SubmerchantIdentification: "submerchant_01", Amount: 100000001},
+				{SubmerchantIdentification: "submerchant_02", Amount: 100000000},
+			},
+		},
+	}
+
+	splitRules, err := req.GetSplitRules()
+	if err != nil {
+		t.Fatalf("GetSplitRules() error: %v", err)
+	}
+	if got := splitRules["submerchant_01"]; got != "1000000.01" {
+		t.Fatalf("submerchant_01 amount mismatch: want %q, got %q", "1000000.01", got)
+	}
+	if got := splitRules["submerchant_02"]; got != "1000000.00" {
+		t.Fatalf("submerchant_02 amount mismatch: want %q, got %q", "1000000.00", got)
+	}
+}
+
+func TestRequest_PaymentMethodKind(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    *PaymentMethod
+		want      PaymentMethodKind
+		applePay  bool
+		googlePay bool
+	}{
+		{name: "nil method", method: nil, want: PaymentMethodUnknown},
+		{name: "empty method", method: &PaymentMethod{}, want: PaymentMethodUnknown},
+		{name: "empty card", method: &PaymentMethod{Card: &Card{Token: ref(""), Pan: ref("")}}, want: PaymentMethodUnknown},
+		{name: "empty wallets", method: &PaymentMethod{AppleContainer: ref(""), GoogleToken: ref("")}, want: PaymentMethodUnknown},
+		{name: "apple pay", method: &PaymentMethod{AppleContainer: ref("APPLE")}, want: PaymentMethodApplePay, applePay: true},
+		{name: "google pay", method: &PaymentMethod{GoogleToken: ref("GOOGLE")}, want: PaymentMethodGooglePay, googlePay: true},
+		{name: "card token", method: &PaymentMethod{Card: &Card{Token: ref("TOKEN")}}, want: PaymentMethodCardToken},
+		{name: "card pan", method: &PaymentMethod{Card: &Card{Pan: ref("4111111111111111")}}, want: PaymentMethodCardPAN},
+		{
+			name:   "token wins over pan",
+			method: &PaymentMethod{Card: &Card{Token: ref("TOKEN"), Pan: ref("4111111111111111")}},
+			want:   PaymentMethodCardToken,
+		},
+		{
+			name:      "apple pay wins over google pay",
+			method:    &PaymentMethod{AppleContainer: ref("APPLE"), GoogleToken: ref("GOOGLE")},
+			want:      PaymentMethodApplePay,
+			applePay:  true,
+			googlePay: true,
+		},
+		{
+			name:      "google pay wins over card token",
+			method:    &PaymentMethod{GoogleToken: ref("GOOGLE"), Card: &Card{Token: ref("TOKEN")}},
+			want:      PaymentMethodGooglePay,
+			googlePay: true,
+		},
+		{
+			name:     "apple pay wins over card pan",
+			method:   &PaymentMethod{AppleContainer: ref("APPLE"), Card: &Card{Pan: ref("4111111111111111")}},
+			want:     PaymentMethodApplePay,
+			applePay: true,
+		},
+		{
+			name:   "empty google token falls back to card token",
+			method: &PaymentMethod{GoogleToken: ref(""), Card: &Card{Token: ref("TOKEN")}},
+			want:   PaymentMethodCardToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				req := &Request{PaymentData: &PaymentData{}, PaymentMethod: tt.method}
+				if got := req.PaymentMethodKind(); got != tt.want {
+					t.Fatalf("PaymentMethodKind() = %s, want %s", got, tt.want)
+				}
+				if got := req.IsApplePay(); got != tt.applePay {
+					t.Fatalf("IsApplePay() = %v, want %v", got, tt.applePay)
+				}
+				if got := req.IsGooglePay(); got != tt.googlePay {
+					t.Fatalf("IsGooglePay() = %v, want %v", got, tt.googlePay)
+				}
+				if got := req.IsMobile(); got != (tt.applePay || tt.googlePay) {
+					t.Fatalf("IsMobile() = %v, want %v", got, tt.applePay || tt.googlePay)
+				}
+			},
+		)
+	}
+}
+
+func TestRequest_GetAppleContainer_ValidatesAndUnwraps(t *testing.T) {
+	inner := `{"version":"EC_v1","data":"ZA==","signature":"cw==",` +
+		`"header":{"ephemeralPublicKey":"aw==","publicKeyHash":"aA==","transactionId":"t1"}}`
+	wrapped := base64.StdEncoding.EncodeToString([]byte(`{"token":` + inner + `}`))
+
+	req := &Request{PaymentMethod: &PaymentMethod{AppleContainer: &wrapped}}
+	got, err := req.GetAppleContainer()
+	if err != nil {
+		t.Fatalf("GetAppleContainer() error: %v", err)
+	}
+	if want := base64.StdEncoding.EncodeToString([]byte(inner)); *got != want {
+		t.Fatalf("GetAppleContainer() = %q, want %q", *got, want)
+	}
+
+	nested := base64.StdEncoding.EncodeToString([]byte(`{"token":{"paymentData":` + inner + `,"paymentMethod":{"network":"Visa"}}}`))
+	req.PaymentMethod.AppleContainer = &nested
+	got, err = req.GetAppleContainer()
+	if err != nil {
+		t.Fatalf("GetAppleContainer() nested paymentData error: %v", err)
+	}
+	if want := base64.StdEncoding.EncodeToString([]byte(inner)); *got != want {
+		t.Fatalf("GetAppleContainer() nested paymentData = %q, want %q", *got, want)
+	}
+
+	broken := base64.StdEncoding.EncodeToString([]byte(`{"token":{"version":"EC_v1"}}`))
+	req.PaymentMethod.AppleContainer = &broken
+	if _, err := req.GetAppleContainer(); !errors.Is(err, platon.ErrInvalidApplePayContainer) {
+		t.Fatalf("GetAppleContainer() error = %v, want ErrInvalidApplePayContainer", err)
+	}
+}
+
+func TestRequest_GetGoogleToken_RejectsMissingToken(t *testing.T) {
+	empty := base64.StdEncoding.EncodeToString([]byte(`{"paymentMethodData":{"tokenizationData":{}}}`))
+	req := &Request{PaymentMethod: &PaymentMethod{GoogleToken: &empty}}
+
+	if _, err := req.GetGoogleToken(); !errors.Is(err, platon.ErrInvalidGooglePayToken) {
+		t.Fatalf("GetGoogleToken() error = %v, want ErrInvalidGooglePayToken", err)
+	}
+}
@@ -27,7 +27,9 @@ package go_platon
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
+	internalhttp "github.com/stremovskyy/go-platon/internal/http"
 	"github.com/stremovskyy/go-platon/log"
 	"github.com/stremovskyy/go-platon/platon"
 )
@@ -41,6 +43,21 @@ type DryRunHandler func(endpoint string, payload any)
 type runOptions struct {
 	dryRun       bool
 	dryRunHandle DryRunHandler
+
+	capturedRequest func(*platon.Request)
+
+	retry *retryOverride
+
+	tokenizationMetadata map[string]string
+
+	immediately *bool
+
+	validateSplitSubmerchants bool
+}
+
+type retryOverride struct {
+	maxRetries int
+	baseDelay  time.Duration
 }
 
 var dryRunLogger = log.NewLogger("Platon DryRun:")
@@ -59,6 +76,57 @@ func DryRun(handler ...DryRunHandler) RunOption {
 	}
 }
 
+// WithCapturedRequest registers a hook that receives the signed request right
+// before it is sent. Unlike DryRun, the request is still sent.
+func WithCapturedRequest(hook func(*platon.Request)) RunOption {
+	return func(o *runOptions) {
+		o.capturedRequest = hook
+	}
+}
+
+// OverrideRetry replaces the client retry policy (see WithRetry) for a single
+// call. OverrideRetry(0, 0) disables retries.
+func OverrideRetry(maxRetries int, baseDelay time.Duration) RunOption {
+	return func(o *runOptions) {
+		o.retry = &retryOverride{maxRetries: maxRetries, baseDelay: baseDelay}
+	}
+}
+
+// Immediately turns the fast refund (immediately=Y) of Refund on or off. It
+// takes precedence over PaymentData.Metadata["immediately"], so
+// Immediately(false) suppresses a flag set in metadata.
+func Immediately(enabled bool) RunOption {
+	return func(o *runOptions) {
+		o.immediately = &enabled
+	}
+}
+
+// ValidateSplitSubmerchants makes Payment, PaymentByCard and Hold check every
+// split rules submerchant with GET_SUBMERCHANT before posting the SALE. The
+// SALE is not sent when any of them is not ENABLED.
+func ValidateSplitSubmerchants() RunOption {
+	return func(o *runOptions) {
+		o.validateSplitSubmerchants = true
+	}
+}
+
+// WithTokenizationMetadata adds ext fields to a Verification request so that
+// its callback can be routed back to the order (see ExtractTokenFromWebhook).
+// Keys must be "ext1".."ext10"; values override PaymentData.Metadata.
+func WithTokenizationMetadata(metadata map[string]string) RunOption {
+	return func(o *runOptions) {
+		if len(metadata) == 0 {
+			return
+		}
+		if o.tokenizationMetadata == nil {
+			o.tokenizationMetadata = make(map[string]string, len(metadata))
+		}
+		for key, value := range metadata {
+			o.tokenizationMetadata[key] = value
+		}
+	}
+}
+
 func collectRunOptions(opts []RunOption) *runOptions {
 	if len(opts) == 0 {
 		return nil
@@ -79,6 +147,20 @@ func (o *runOptions) isDryRun() bool {
 	return o != nil && o.dryRun
 }
 
+// immediatelyOr returns the Immediately option when it was given and fallback
+// otherwise.
+func (o *runOptions) immediatelyOr(fallback bool) bool {
+	if o == nil || o.immediately == nil {
+		return fallback
+	}
+
+	return *o.immediately
+}
+
+func (o *runOptions) shouldValidateSplitSubmerchants() bool {
+	return o != nil && o.validateSplitSubmerchants
+}
+
 func (o *runOptions) handleDryRun(endpoint string, payload any) {
 	if o == nil || !o.dryRun {
 		return
@@ -89,6 +171,51 @@ func (o *runOptions) handleDryRun(endpoint string, payload any) {
 	}
 }
 
+// withTokenizationMetadata returns a copy of request whose metadata carries
+// the WithTokenizationMetadata ext fields. The caller's request is not modified.
+func (o *runOptions) withTokenizationMetadata(request *Request) (*Request, error) {
+	if o == nil || len(o.tokenizationMetadata) == 0 || request == nil {
+		return request, nil
+	}
+
+	metadata := make(map[string]string, len(request.GetMetadata())+len(o.tokenizationMetadata))
+	for key, value := range request.GetMetadata() {
+		metadata[key] = value
+	}
+	for key, value := range o.tokenizationMetadata {
+		if !isExtField(key) {
+			return nil, fmt.Errorf("verification: tokenization metadata key %q must be one of ext1..ext10", key)
+		}
+		metadata[key] = value
+	}
+
+	withMetadata := *request
+	paymentData := PaymentData{}
+	if request.PaymentData != nil {
+		paymentData = *request.PaymentData
+	}
+	paymentData.Metadata = metadata
+	withMetadata.PaymentData = &paymentData
+
+	return &withMetadata, nil
+}
+
+func (o *runOptions) callOptions() []internalhttp.CallOption {
+	if o == nil {
+		return nil
+	}
+
+	var callOpts []internalhttp.CallOption
+	if o.capturedRequest != nil {
+		callOpts = append(callOpts, internalhttp.OnSigned(o.capturedRequest))
+	}
+	if o.retry != nil {
+		callOpts = append(callOpts, internalhttp.WithRetry(o.retry.maxRetries, o.retry.baseDelay))
+	}
+
+	return callOpts
+}
+
 func defaultDryRunHandler(endpoint string, payload any) {
 	dryRunLogger.Info("Dry run: skipping request to %s", endpoint)
 
@@ -103,12 +230,24 @@ func defaultDryRunHandler(endpoint string, payload any) {
 			dryRunLogger.Info("Dry run payload: <nil>")
 			return
 		}
-		dryRunLogger.Info("Dry run payload:\n%s", marshalIndent(req.ToMap()))
+		dryRunLogger.Info("Dry run payload:\n%s", marshalIndent(redactRequestMap(req.ToMap())))
 	default:
 		dryRunLogger.Info("Dry run payload:\n%s", marshalIndent(req))
 	}
 }
 
+// redactRequestMap masks card data, tokens and signatures in place, like the
+// debug logs of real requests.
+func redactRequestMap(fields map[string]interface{}) map[string]interface{} {
+	for key, value := range fields {
+		if text, ok := value.(string); ok {
+			fields[key] = internalhttp.RedactValue(key, text)
+		}
+	}
+
+	return fields
+}
+
 func marshalIndent(v any) string {
 	if v == nil {
 		return "<nil>"
@@ -25,6 +25,11 @@
 package go_platon
 
 import (
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stremovskyy/go-platon/consts"
@@ -167,6 +172,54 @@ func TestVerificationLink_DryRun(t *testing.T) {
 	}
 }
 
+func TestRefund_DryRunImmediately(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		runOpts  []RunOption
+		want     *string
+	}{
+		{name: "option", runOpts: []RunOption{Immediately(true)}, want: utils.Ref("Y")},
+		{name: "metadata fallback", metadata: map[string]string{"immediately": "true"}, want: utils.Ref("Y")},
+		{name: "option on wins over metadata off", metadata: map[string]string{"immediately": "N"}, runOpts: []RunOption{Immediately(true)}, want: utils.Ref("Y")},
+		{name: "option off wins over metadata on", metadata: map[string]string{"immediately": "Y"}, runOpts: []RunOption{Immediately(false)}},
+		{name: "not requested"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var apiRequest *platon.Request
+				runOpts := append(
+					tt.runOpts, DryRun(
+						func(_ string, payload any) {
+							apiRequest = payload.(*platon.Request)
+						},
+					),
+				)
+
+				_, err := NewDefaultClient().Refund(
+					&Request{
+						Merchant:     &Merchant{MerchantKey: "clientKey", SecretKey: "secret123"},
+						PersonalData: &PersonalData{Email: utils.Ref("payer@example.com")},
+						PaymentData: &PaymentData{
+							PlatonTransID: utils.Ref("trans-1"),
+							Amount:        100,
+							Metadata:      tt.metadata,
+						},
+					}, runOpts...,
+				)
+				if err != nil {
+					t.Fatalf("Refund() dry run error: %v", err)
+				}
+				if !reflect.DeepEqual(apiRequest.Immediately, tt.want) {
+					t.Fatalf("Immediately = %v, want %v", apiRequest.Immediately, tt.want)
+				}
+			},
+		)
+	}
+}
+
 func TestDryRun_DefaultHandler_NilPlatonRequestPayload(t *testing.T) {
 	opts := collectRunOptions([]RunOption{DryRun()})
 	var req *platon.Request
@@ -179,3 +232,93 @@ func TestDryRun_DefaultHandler_NilPlatonRequestPayload(t *testing.T) {
 
 	opts.handleDryRun(consts.ApiPostUnqURL, req)
 }
+
+func TestPayment_WithCapturedRequest_ReceivesSignedRequest(t *testing.T) {
+	var sentBody string
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(
+			func(req *http.Request) (*http.Response, error) {
+				b, _ := io.ReadAll(req.Body)
+				sentBody = string(b)
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"result":"ACCEPTED"}`)),
+				}, nil
+			},
+		),
+	}
+
+	var captured *platon.Request
+	cl := NewClient(WithClient(httpClient))
+	_, err := cl.Payment(
+		&Request{
+			Merchant: &Merchant{
+				MerchantKey: "clientKey",
+				SecretKey:   "secret123",
+				TermsURL:    utils.Ref("https://merchant.example/3ds"),
+				ClientIP:    utils.Ref("203.0.113.10"),
+			},
+			PaymentData: &PaymentData{
+				PaymentID:   utils.Ref("order-1"),
+				Amount:      100,
+				Currency:    currency.UAH,
+				Description: "captured",
+			},
+			PaymentMethod: &PaymentMethod{
+				Card: &Card{Token: utils.Ref("CARD_TOKEN")},
+			},
+			PersonalData: &PersonalData{Email: utils.Ref("payer@example.com")},
+		},
+		WithCapturedRequest(
+			func(req *platon.Request) {
+				captured = req
+			},
+		),
+	)
+	if err != nil {
+		t.Fatalf("Payment() error: %v", err)
+	}
+	if captured == nil {
+		t.Fatalf("expected captured request")
+	}
+	if captured.Hash == "" {
+		t.Fatalf("expected captured request to be signed")
+	}
+	if captured.HashType != platon.HashTypeCardTokenPayment {
+		t.Fatalf("hash type mismatch: want %q, got %q", platon.HashTypeCardTokenPayment, captured.HashType)
+	}
+	if captured.OrderAmount != "1.00" || captured.OrderID == nil || *captured.OrderID != "order-1" {
+		t.Fatalf("unexpected captured fields: order_amount=%q order_id=%v", captured.OrderAmount, captured.OrderID)
+	}
+
+	values, err := url.ParseQuery(sentBody)
+	if err != nil {
+		t.Fatalf("ParseQuery() error: %v", err)
+	}
+	if values.Get("hash") != captured.Hash {
+		t.Fatalf("sent hash mismatch: want %q, got %q", captured.Hash, values.Get("hash"))
+	}
+}
+
+func TestRedactRequestMap_MasksDryRunPayload(t *testing.T) {
+	fields := redactRequestMap(
+		map[string]interface{}{
+			"card_number": "4111111111111111",
+			"card_cvv2":   "123",
+			"hash":        "0123456789abcdef0123456789abcdef",
+			"order_id":    "order-1",
+		},
+	)
+
+	want := map[string]interface{}{
+		"card_number": "411111******1111",
+		"card_cvv2":   "***",
+		"hash":        "***",
+		"order_id":    "order-1",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("redactRequestMap() = %v, want %v", fields, want)
+	}
+}
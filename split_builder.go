@@ -0,0 +1,260 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// commissionPercentRe matches a plain decimal percentage such as "2.75".
+var commissionPercentRe = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// RoundingPolicy selects how a fractional commission is rounded to minor units.
+type RoundingPolicy int
+
+const (
+	// RoundHalfUp rounds the commission to the nearest minor unit, halves away from zero.
+	RoundHalfUp RoundingPolicy = iota
+	// RoundDown truncates the commission to whole minor units (in favor of the seller).
+	RoundDown
+)
+
+func (p RoundingPolicy) String() string {
+	switch p {
+	case RoundHalfUp:
+		return "half_up"
+	case RoundDown:
+		return "down"
+	default:
+		return fmt.Sprintf("RoundingPolicy(%d)", int(p))
+	}
+}
+
+// RoundingReport describes how the platform commission was rounded.
+// Callers can sum Delta across orders to reconcile leftover minor units.
+type RoundingReport struct {
+	// Policy is the rounding policy that was applied.
+	Policy RoundingPolicy
+	// Exact is the unrounded commission in minor units (e.g. 278.25).
+	Exact float64
+	// Rounded is the commission assigned to the platform in minor units.
+	Rounded int64
+	// Delta is Exact - Rounded in minor units. Positive values mean the
+	// platform received less than its exact share.
+	Delta float64
+}
+
+type splitCommission struct {
+	percent               string
+	fixedMinor            int64
+	platformSubmerchantID string
+}
+
+// SplitBuilder builds SplitRule slices that always sum to the order total.
+//
+// Rules are either added explicitly with Add, or computed in commission mode:
+// the platform receives percent of the total plus a fixed part, and the seller
+// receives the remainder.
+type SplitBuilder struct {
+	totalMinor int64
+	rules      []SplitRule
+	commission *splitCommission
+	sellerID   string
+	policy     RoundingPolicy
+}
+
+// NewSplitBuilder creates a builder for an order total given in minor units.
+func NewSplitBuilder(totalMinor int64) *SplitBuilder {
+	return &SplitBuilder{totalMinor: totalMinor, policy: RoundHalfUp}
+}
+
+// Add appends an explicit rule for a sub-merchant.
+func (b *SplitBuilder) Add(submerchantID string, amountMinor int) *SplitBuilder {
+	if b == nil {
+		return nil
+	}
+	b.rules = append(b.rules, SplitRule{SubmerchantIdentification: submerchantID, Amount: amountMinor})
+	return b
+}
+
+// Commission switches the builder to commission mode. The platform share is
+// totalMinor*percent/100 + fixedMinor, rounded with the configured policy.
+// percent is a decimal string such as "2.75" so that it is applied exactly.
+func (b *SplitBuilder) Commission(percent string, fixedMinor int64, platformSubmerchantID string) *SplitBuilder {
+	if b == nil {
+		return nil
+	}
+	b.commission = &splitCommission{
+		percent:               percent,
+		fixedMinor:            fixedMinor,
+		platformSubmerchantID: platformSubmerchantID,
+	}
+	return b
+}
+
+// Seller sets the sub-merchant that receives the remainder in commission mode.
+func (b *SplitBuilder) Seller(submerchantID string) *SplitBuilder {
+	if b == nil {
+		return nil
+	}
+	b.sellerID = submerchantID
+	return b
+}
+
+// WithRounding sets the rounding policy for commission mode (RoundHalfUp by default).
+func (b *SplitBuilder) WithRounding(policy RoundingPolicy) *SplitBuilder {
+	if b == nil {
+		return nil
+	}
+	b.policy = policy
+	return b
+}
+
+// Build returns the split rules. In commission mode it also returns the rounding report.
+func (b *SplitBuilder) Build() ([]SplitRule, *RoundingReport, error) {
+	if b == nil {
+		return nil, nil, fmt.Errorf("split builder: builder is nil")
+	}
+	if b.totalMinor <= 0 {
+		return nil, nil, fmt.Errorf("split builder: total (minor units) must be > 0")
+	}
+	if b.commission == nil {
+		return b.buildExplicit()
+	}
+	if len(b.rules) > 0 {
+		return nil, nil, fmt.Errorf("split builder: explicit rules cannot be combined with commission mode")
+	}
+
+	return b.buildCommission()
+}
+
+func (b *SplitBuilder) buildExplicit() ([]SplitRule, *RoundingReport, error) {
+	if len(b.rules) == 0 {
+		return nil, nil, fmt.Errorf("split builder: no rules added")
+	}
+
+	var sum int64
+	for idx, rule := range b.rules {
+		if strings.TrimSpace(rule.SubmerchantIdentification) == "" {
+			return nil, nil, fmt.Errorf("split builder: rules[%d]: submerchant identification is required", idx)
+		}
+		if rule.Amount <= 0 {
+			return nil, nil, fmt.Errorf("split builder: rules[%d]: amount (minor units) must be > 0", idx)
+		}
+		sum += int64(rule.Amount)
+	}
+	if sum != b.totalMinor {
+		return nil, nil, fmt.Errorf("split builder: rules total must equal order total (%d != %d minor units)", sum, b.totalMinor)
+	}
+
+	rules := make([]SplitRule, len(b.rules))
+	copy(rules, b.rules)
+	return rules, nil, nil
+}
+
+func (b *SplitBuilder) buildCommission() ([]SplitRule, *RoundingReport, error) {
+	commission := b.commission
+	platformID := strings.TrimSpace(commission.platformSubmerchantID)
+	sellerID := strings.TrimSpace(b.sellerID)
+
+	if platformID == "" {
+		return nil, nil, fmt.Errorf("split builder: platform submerchant id is required")
+	}
+	if sellerID == "" {
+		return nil, nil, fmt.Errorf("split builder: seller submerchant id is required in commission mode")
+	}
+	if platformID == sellerID {
+		return nil, nil, fmt.Errorf("split builder: platform and seller submerchant ids must differ")
+	}
+	percent := strings.TrimSpace(commission.percent)
+	if !commissionPercentRe.MatchString(percent) {
+		return nil, nil, fmt.Errorf("split builder: commission percent must be a decimal number such as \"2.75\" (got %q)", commission.percent)
+	}
+	exact, _ := new(big.Rat).SetString(percent)
+	if exact.Cmp(big.NewRat(100, 1)) > 0 {
+		return nil, nil, fmt.Errorf("split builder: commission percent must be within [0, 100] (got %s)", percent)
+	}
+	if commission.fixedMinor < 0 {
+		return nil, nil, fmt.Errorf("split builder: fixed commission (minor units) must be >= 0")
+	}
+
+	exact.Mul(exact, new(big.Rat).SetInt64(b.totalMinor))
+	exact.Quo(exact, big.NewRat(100, 1))
+	exact.Add(exact, new(big.Rat).SetInt64(commission.fixedMinor))
+
+	rounded, err := roundRat(exact, b.policy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rounded > b.totalMinor {
+		return nil, nil, fmt.Errorf("split builder: commission exceeds order total (%d > %d minor units)", rounded, b.totalMinor)
+	}
+
+	delta := new(big.Rat).Sub(exact, new(big.Rat).SetInt64(rounded))
+	exactFloat, _ := exact.Float64()
+	deltaFloat, _ := delta.Float64()
+	report := &RoundingReport{
+		Policy:  b.policy,
+		Exact:   exactFloat,
+		Rounded: rounded,
+		Delta:   deltaFloat,
+	}
+
+	// Zero-amount rules are rejected by Platon, so a party with no share is omitted.
+	rules := make([]SplitRule, 0, 2)
+	if rounded > 0 {
+		rules = append(rules, SplitRule{SubmerchantIdentification: platformID, Amount: int(rounded)})
+	}
+	if seller := b.totalMinor - rounded; seller > 0 {
+		rules = append(rules, SplitRule{SubmerchantIdentification: sellerID, Amount: int(seller)})
+	}
+
+	return rules, report, nil
+}
+
+func roundRat(value *big.Rat, policy RoundingPolicy) (int64, error) {
+	num := new(big.Int).Set(value.Num())
+	den := value.Denom()
+
+	switch policy {
+	case RoundHalfUp:
+		// floor((2*num + den) / (2*den)) for non-negative values.
+		num.Mul(num, big.NewInt(2))
+		num.Add(num, den)
+		num.Quo(num, new(big.Int).Mul(den, big.NewInt(2)))
+	case RoundDown:
+		num.Quo(num, den)
+	default:
+		return 0, fmt.Errorf("split builder: unsupported rounding policy %s", policy)
+	}
+
+	if !num.IsInt64() {
+		return 0, fmt.Errorf("split builder: commission overflows int64")
+	}
+	return num.Int64(), nil
+}
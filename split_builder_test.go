@@ -0,0 +1,213 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestSplitBuilder_Commission_SumsToTotalForPrimeTotals(t *testing.T) {
+	primes := []int64{2, 3, 7, 101, 997, 7919, 104729, 1299709}
+
+	for _, policy := range []RoundingPolicy{RoundHalfUp, RoundDown} {
+		for _, total := range primes {
+			rules, report, err := NewSplitBuilder(total).
+				Commission("2.75", 300, "platform").
+				Seller("seller").
+				WithRounding(policy).
+				Build()
+			if total < 300 {
+				if err == nil || !strings.Contains(err.Error(), "exceeds order total") {
+					t.Fatalf("%s/%d: expected exceeds-total error, got %v", policy, total, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s/%d: Build() error: %v", policy, total, err)
+			}
+
+			var sum int64
+			for _, rule := range rules {
+				if rule.Amount <= 0 {
+					t.Fatalf("%s/%d: rule %q has non-positive amount %d", policy, total, rule.SubmerchantIdentification, rule.Amount)
+				}
+				sum += int64(rule.Amount)
+			}
+			if sum != total {
+				t.Fatalf("%s/%d: rules sum mismatch: want %d, got %d", policy, total, total, sum)
+			}
+			if report == nil || report.Policy != policy {
+				t.Fatalf("%s/%d: unexpected report %#v", policy, total, report)
+			}
+			if math.Abs(report.Exact-float64(report.Rounded)-report.Delta) > 1e-9 {
+				t.Fatalf("%s/%d: report is inconsistent: %#v", policy, total, report)
+			}
+		}
+	}
+}
+
+func TestSplitBuilder_Commission_RoundingPolicies(t *testing.T) {
+	// 10007 * 2.75% = 275.1925, plus 300 fixed.
+	_, halfUp, err := NewSplitBuilder(10007).Commission("2.75", 300, "platform").Seller("seller").Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	if halfUp.Rounded != 575 {
+		t.Fatalf("half-up rounded mismatch: want 575, got %d", halfUp.Rounded)
+	}
+
+	// 10019 * 2.75% = 275.5225, plus 300 fixed.
+	rules, down, err := NewSplitBuilder(10019).
+		Commission("2.75", 300, "platform").
+		Seller("seller").
+		WithRounding(RoundDown).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	if down.Rounded != 575 {
+		t.Fatalf("down rounded mismatch: want 575, got %d", down.Rounded)
+	}
+	if math.Abs(down.Delta-0.5225) > 1e-9 {
+		t.Fatalf("down delta mismatch: want 0.5225, got %v", down.Delta)
+	}
+	if len(rules) != 2 || rules[0].SubmerchantIdentification != "platform" || rules[1].Amount != 10019-575 {
+		t.Fatalf("unexpected rules: %#v", rules)
+	}
+}
+
+func TestSplitBuilder_Commission_AccumulatedDeltaMatchesAnalytic(t *testing.T) {
+	const orders = 10000
+	rng := rand.New(rand.NewSource(1))
+
+	for _, policy := range []RoundingPolicy{RoundHalfUp, RoundDown} {
+		accumulated := 0.0
+		// 2.75% == 11/400, so exact commission * 400 is an integer.
+		var analytic400 int64
+
+		for i := 0; i < orders; i++ {
+			total := int64(1000 + rng.Intn(1_000_000))
+			_, report, err := NewSplitBuilder(total).
+				Commission("2.75", 300, "platform").
+				Seller("seller").
+				WithRounding(policy).
+				Build()
+			if err != nil {
+				t.Fatalf("%s: Build() error: %v", policy, err)
+			}
+			accumulated += report.Delta
+
+			exact400 := total*11 + 300*400
+			var rounded int64
+			if policy == RoundDown {
+				rounded = exact400 / 400
+			} else {
+				rounded = (exact400 + 200) / 400
+			}
+			if rounded != report.Rounded {
+				t.Fatalf("%s/%d: rounded mismatch: want %d, got %d", policy, total, rounded, report.Rounded)
+			}
+			analytic400 += exact400 - rounded*400
+		}
+
+		analytic := float64(analytic400) / 400
+		if math.Abs(accumulated-analytic) >= 1 {
+			t.Fatalf("%s: accumulated delta drifted: want %.4f, got %.4f", policy, analytic, accumulated)
+		}
+	}
+}
+
+func TestSplitBuilder_Commission_DecimalPercentIsExact(t *testing.T) {
+	// As float64, 0.35 and 1.15 sit just below the half, so half-up rounding
+	// would lose one minor unit on both.
+	tests := []struct {
+		percent string
+		want    int
+	}{
+		{percent: "0.35", want: 4},
+		{percent: "1.15", want: 12},
+	}
+
+	for _, tt := range tests {
+		rules, report, err := NewSplitBuilder(1000).Commission(tt.percent, 0, "platform").Seller("seller").Build()
+		if err != nil {
+			t.Fatalf("%s%%: Build() error: %v", tt.percent, err)
+		}
+		if rules[0].Amount != tt.want || rules[1].Amount != 1000-tt.want {
+			t.Fatalf("%s%%: unexpected rules %#v", tt.percent, rules)
+		}
+		if report.Delta != -0.5 {
+			t.Fatalf("%s%%: Delta = %v, want -0.5", tt.percent, report.Delta)
+		}
+	}
+}
+
+func TestSplitBuilder_ValidatesInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		builder   *SplitBuilder
+		wantError string
+	}{
+		{
+			name:      "missing seller",
+			builder:   NewSplitBuilder(1000).Commission("1", 0, "platform"),
+			wantError: "seller submerchant id is required",
+		},
+		{
+			name:      "same ids",
+			builder:   NewSplitBuilder(1000).Commission("1", 0, "same").Seller("same"),
+			wantError: "must differ",
+		},
+		{
+			name:      "percent out of range",
+			builder:   NewSplitBuilder(1000).Commission("101", 0, "platform").Seller("seller"),
+			wantError: "within [0, 100]",
+		},
+		{
+			name:      "percent not a decimal",
+			builder:   NewSplitBuilder(1000).Commission("-1", 0, "platform").Seller("seller"),
+			wantError: "must be a decimal number",
+		},
+		{
+			name:      "explicit rules do not sum",
+			builder:   NewSplitBuilder(1000).Add("a", 400).Add("b", 500),
+			wantError: "rules total must equal order total",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				_, _, err := tt.builder.Build()
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantError, err)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+// SubmerchantInfo is the GET_SUBMERCHANT answer for one sub-merchant.
+type SubmerchantInfo struct {
+	ID string
+	// Status is the parsed submerchant_id_status; values the SDK does not
+	// recognize are reported as platon.SubmerchantStatusUnknown.
+	Status platon.SubmerchantStatus
+	// RawStatus is submerchant_id_status exactly as Platon sent it.
+	RawStatus string
+	Hash      string
+	Response  *platon.Response
+}
+
+// submerchantInfo reads a GET_SUBMERCHANT response. It fails when the
+// request failed or the response has no submerchant_id_status.
+func submerchantInfo(response *platon.Response, submerchantID string, op string) (*SubmerchantInfo, error) {
+	if response == nil {
+		return nil, fmt.Errorf("%s: empty response", op)
+	}
+
+	raw, ok := response.SubmerchantIDStatus()
+	if !ok {
+		if response.Status != nil {
+			apiStatus := strings.ToUpper(strings.TrimSpace(*response.Status))
+			if apiStatus == "FAILED" {
+				return nil, fmt.Errorf("%s: request failed (status=FAILED)", op)
+			}
+			if apiStatus != "" {
+				return nil, fmt.Errorf("%s: response status %q without submerchant_id_status", op, apiStatus)
+			}
+		}
+		return nil, fmt.Errorf("%s: response does not contain submerchant_id_status", op)
+	}
+
+	info := &SubmerchantInfo{
+		ID:        submerchantID,
+		Status:    platon.ParseSubmerchantStatus(raw),
+		RawStatus: raw,
+		Response:  response,
+	}
+	if data := response.ResponseData; data != nil {
+		if data.SubmerchantID != nil && *data.SubmerchantID != "" {
+			info.ID = *data.SubmerchantID
+		}
+		if data.Hash != nil {
+			info.Hash = *data.Hash
+		}
+	}
+
+	return info, nil
+}
@@ -0,0 +1,98 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"sync"
+	"time"
+)
+
+// submerchantCache keeps GET_SUBMERCHANT split availability answers for a
+// fixed TTL. A nil cache never hits and ignores writes.
+type submerchantCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[submerchantCacheKey]submerchantCacheEntry
+}
+
+type submerchantCacheKey struct {
+	merchantKey   string
+	submerchantID string
+}
+
+type submerchantCacheEntry struct {
+	available bool
+	expiresAt time.Time
+}
+
+// newSubmerchantCache returns nil when ttl disables caching.
+func newSubmerchantCache(ttl time.Duration) *submerchantCache {
+	if ttl <= 0 {
+		return nil
+	}
+
+	return &submerchantCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[submerchantCacheKey]submerchantCacheEntry),
+	}
+}
+
+func (c *submerchantCache) get(merchantKey, submerchantID string) (bool, bool) {
+	if c == nil {
+		return false, false
+	}
+
+	key := submerchantCacheKey{merchantKey: merchantKey, submerchantID: submerchantID}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return false, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return false, false
+	}
+
+	return entry.available, true
+}
+
+func (c *submerchantCache) put(merchantKey, submerchantID string, available bool) {
+	if c == nil {
+		return
+	}
+
+	key := submerchantCacheKey{merchantKey: merchantKey, submerchantID: submerchantID}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = submerchantCacheEntry{available: available, expiresAt: c.now().Add(c.ttl)}
+}
@@ -0,0 +1,131 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSubmerchantAvailableForSplit_CachesWithinTTL(t *testing.T) {
+	var calls atomic.Int32
+	cl := NewClient(
+		WithSubmerchantCache(time.Minute),
+		WithClient(
+			&http.Client{
+				Transport: splitRoundTripFunc(
+					func(_ *http.Request) (*http.Response, error) {
+						calls.Add(1)
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body: io.NopCloser(
+								strings.NewReader(`{"status":"SUCCESS","action":"GET_SUBMERCHANT","submerchant_id":"123456789","submerchant_id_status":"ENABLED"}`),
+							),
+						}, nil
+					},
+				),
+			},
+		),
+	).(*client)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cl.submerchantCache.now = func() time.Time { return now }
+
+	req := &Request{
+		Merchant:    &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+		PaymentData: &PaymentData{SubmerchantID: ref("123456789")},
+	}
+
+	check := func(wantCalls int32) {
+		t.Helper()
+		available, err := cl.SubmerchantAvailableForSplit(req)
+		if err != nil {
+			t.Fatalf("SubmerchantAvailableForSplit() error: %v", err)
+		}
+		if !available {
+			t.Fatal("SubmerchantAvailableForSplit() = false, want true")
+		}
+		if got := calls.Load(); got != wantCalls {
+			t.Fatalf("API calls = %d, want %d", got, wantCalls)
+		}
+	}
+
+	check(1)
+	now = now.Add(30 * time.Second)
+	check(1)
+
+	other := &Request{
+		Merchant:    &Merchant{MerchantKey: "OTHER_KEY", SecretKey: "CLIENT_PASS"},
+		PaymentData: &PaymentData{SubmerchantID: ref("123456789")},
+	}
+	if _, err := cl.SubmerchantAvailableForSplit(other); err != nil {
+		t.Fatalf("SubmerchantAvailableForSplit() error: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("API calls for another merchant = %d, want 2", got)
+	}
+
+	now = now.Add(time.Minute)
+	check(3)
+}
+
+func TestSubmerchantAvailableForSplit_DoesNotCacheFailures(t *testing.T) {
+	var calls atomic.Int32
+	cl := NewClient(
+		WithSubmerchantCache(time.Minute),
+		WithClient(
+			&http.Client{
+				Transport: splitRoundTripFunc(
+					func(_ *http.Request) (*http.Response, error) {
+						calls.Add(1)
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(`{"status":"FAILED"}`)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+
+	req := &Request{
+		Merchant:    &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+		PaymentData: &PaymentData{SubmerchantID: ref("123456789")},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := cl.SubmerchantAvailableForSplit(req); err == nil {
+			t.Fatal("SubmerchantAvailableForSplit() expected error")
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("API calls = %d, want 2", got)
+	}
+}
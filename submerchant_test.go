@@ -0,0 +1,118 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+func newSubmerchantClient(body string) Platon {
+	return NewClient(
+		WithClient(
+			&http.Client{
+				Transport: splitRoundTripFunc(
+					func(_ *http.Request) (*http.Response, error) {
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Header:     http.Header{"Content-Type": []string{"application/json"}},
+							Body:       io.NopCloser(strings.NewReader(body)),
+						}, nil
+					},
+				),
+			},
+		),
+	)
+}
+
+func newSubmerchantRequest() *Request {
+	return &Request{
+		Merchant:    &Merchant{MerchantKey: "CLIENT_KEY", SecretKey: "CLIENT_PASS"},
+		PaymentData: &PaymentData{SubmerchantID: ref("123456789")},
+	}
+}
+
+func TestSubmerchant_Statuses(t *testing.T) {
+	tests := []struct {
+		raw    string
+		status platon.SubmerchantStatus
+	}{
+		{raw: "ENABLED", status: platon.SubmerchantStatusEnabled},
+		{raw: "disabled", status: platon.SubmerchantStatusDisabled},
+		{raw: "Locked", status: platon.SubmerchantStatusLocked},
+		{raw: "SUSPENDED", status: platon.SubmerchantStatusUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			client := newSubmerchantClient(`{"status":"SUCCESS","action":"GET_SUBMERCHANT","submerchant_id":"123456789","submerchant_id_status":"` + tt.raw + `","hash":"abc123"}`)
+
+			info, err := client.Submerchant(newSubmerchantRequest())
+			if err != nil {
+				t.Fatalf("Submerchant() error: %v", err)
+			}
+			if info.ID != "123456789" {
+				t.Fatalf("ID = %q, want 123456789", info.ID)
+			}
+			if info.Status != tt.status {
+				t.Fatalf("Status = %q, want %q", info.Status, tt.status)
+			}
+			if info.RawStatus != tt.raw {
+				t.Fatalf("RawStatus = %q, want %q", info.RawStatus, tt.raw)
+			}
+			if info.Hash != "abc123" {
+				t.Fatalf("Hash = %q, want abc123", info.Hash)
+			}
+			if info.Response == nil {
+				t.Fatal("Response is nil")
+			}
+		})
+	}
+}
+
+func TestSubmerchant_MissingStatusReturnsError(t *testing.T) {
+	client := newSubmerchantClient(`{"status":"SUCCESS","action":"GET_SUBMERCHANT","submerchant_id":"123456789"}`)
+
+	info, err := client.Submerchant(newSubmerchantRequest())
+	if err == nil {
+		t.Fatalf("expected error, got %+v", info)
+	}
+	if !strings.Contains(err.Error(), "without submerchant_id_status") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSubmerchantAvailableForSplit_UnknownStatusReturnsError(t *testing.T) {
+	client := newSubmerchantClient(`{"status":"SUCCESS","action":"GET_SUBMERCHANT","submerchant_id":"123456789","submerchant_id_status":"SUSPENDED"}`)
+
+	_, err := client.SubmerchantAvailableForSplit(newSubmerchantRequest())
+	if err == nil || !strings.Contains(err.Error(), `unknown submerchant_id_status "SUSPENDED"`) {
+		t.Fatalf("expected unknown status error, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+// TokenizationResult is the card token issued by a card verification
+// (VERIFY_ZERO) callback.
+type TokenizationResult struct {
+	// OrderID is the callback `order`.
+	OrderID string
+	// TransID is the verification transaction id (`rc_id`, or `id` when absent).
+	TransID string
+	// CardToken is the reusable card token (`rc_token`) for one-click and
+	// recurring payments.
+	CardToken string
+	// CardFirst6 and CardLast4 come from the masked callback `card`.
+	CardFirst6 string
+	CardLast4  string
+	// Brand is the card brand reported by Platon (e.g. "VISA").
+	Brand string
+	// Metadata holds the non-empty ext1..ext10 callback fields, e.g. those set
+	// with WithTokenizationMetadata.
+	Metadata map[string]string
+}
+
+// ExtractTokenFromWebhook returns the card token from a verification
+// callback. It fails unless the callback is a SALE or 3DS for one of the
+// verification amounts and carries rc_token.
+//
+// Verify the callback signature first (see WebhookHandler).
+func ExtractTokenFromWebhook(form *platon.WebhookForm) (*TokenizationResult, error) {
+	if form == nil {
+		return nil, fmt.Errorf("tokenization: webhook form is nil")
+	}
+
+	status := strings.ToUpper(strings.TrimSpace(form.Status))
+	if status != string(WebhookEventSale) && status != string(WebhookEvent3DS) {
+		return nil, fmt.Errorf("tokenization: unexpected callback status %q (want SALE or 3DS)", form.Status)
+	}
+
+	amount, err := form.AmountMinorUnits()
+	if err != nil {
+		return nil, fmt.Errorf("tokenization: %w", err)
+	}
+	if !isVerificationAmount(amount) {
+		return nil, fmt.Errorf(
+			"tokenization: callback amount %s is not a verification amount (%s or %s)",
+			form.Amount, platon.VerifyNoAmount, platon.VerifyFixedAmount,
+		)
+	}
+
+	token := strings.TrimSpace(form.RCToken)
+	if token == "" {
+		return nil, fmt.Errorf("tokenization: rc_token is missing for order %q", form.Order)
+	}
+
+	result := &TokenizationResult{
+		OrderID:   strings.TrimSpace(form.Order),
+		TransID:   strings.TrimSpace(form.RCID),
+		CardToken: token,
+		Brand:     strings.TrimSpace(form.Brand),
+		Metadata:  webhookExtFields(form),
+	}
+	if result.TransID == "" {
+		result.TransID = strings.TrimSpace(form.ID)
+	}
+	if strings.TrimSpace(form.Card) != "" {
+		if result.CardFirst6, result.CardLast4, err = form.CardMask(); err != nil {
+			return nil, fmt.Errorf("tokenization: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+func isVerificationAmount(minorUnits int) bool {
+	for _, amount := range []platon.FixedAmount{platon.VerifyNoAmount, platon.VerifyFixedAmount} {
+		money, err := platon.ParseMoney(amount.String())
+		if err == nil && money.MinorUnits() == int64(minorUnits) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func webhookExtFields(form *platon.WebhookForm) map[string]string {
+	values := []string{
+		form.Ext1, form.Ext2, form.Ext3, form.Ext4, form.Ext5,
+		form.Ext6, form.Ext7, form.Ext8, form.Ext9, form.Ext10,
+	}
+
+	fields := make(map[string]string)
+	for idx, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			fields["ext"+strconv.Itoa(idx+1)] = value
+		}
+	}
+
+	return fields
+}
+
+// isExtField reports whether key names one of the ext1..ext10 fields.
+func isExtField(key string) bool {
+	suffix, ok := strings.CutPrefix(key, "ext")
+	if !ok || suffix == "" || suffix[0] == '0' {
+		return false
+	}
+	n, err := strconv.Atoi(suffix)
+
+	return err == nil && n >= 1 && n <= 10
+}
@@ -0,0 +1,164 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stremovskyy/go-platon/currency"
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+// verificationWebhookPayload is a recorded VERIFY_ZERO callback.
+const verificationWebhookPayload = "id=47097-87770-07123&order=47097-87309-6110&status=SALE&card=411111%2A%2A%2A%2A1111&description=%D0%9F%D0%BE%D0%BF%D0%BE%D0%B2%D0%BD%D0%B5%D0%BD%D0%BD%D1%8F+%D0%B1%D0%B0%D0%BB%D0%B0%D0%BD%D1%81%D1%83+%D0%B2%D0%BE%D0%B4%D1%96%D1%8F+%28Platon+split+one+receiver%29&amount=0.40&currency=UAH&name=+&phone=&email=&date=2026-02-13+10%3A32%3A57&ip=250.137.176.130&sign=582d658d7d422e76b2639fac131d093e&rc_id=47097-87770-07123&rc_token=fa0500fb3f4869247b4c5532eaf799bc&issuing_bank=JPMORGAN+CHASE+BANK%2C+N.A.&ext1=merchant-core&ext2=payments&ext3=sale&ext4=wallet-topup&ext10=v1&cardholder_email=&brand=VISA&terminal="
+
+func TestExtractTokenFromWebhook(t *testing.T) {
+	form, err := ParseWebhookForm([]byte(verificationWebhookPayload))
+	if err != nil {
+		t.Fatalf("ParseWebhookForm() error: %v", err)
+	}
+
+	result, err := ExtractTokenFromWebhook(form)
+	if err != nil {
+		t.Fatalf("ExtractTokenFromWebhook() error: %v", err)
+	}
+
+	want := &TokenizationResult{
+		OrderID:    "47097-87309-6110",
+		TransID:    "47097-87770-07123",
+		CardToken:  "fa0500fb3f4869247b4c5532eaf799bc",
+		CardFirst6: "411111",
+		CardLast4:  "1111",
+		Brand:      "VISA",
+		Metadata: map[string]string{
+			"ext1":  "merchant-core",
+			"ext2":  "payments",
+			"ext3":  "sale",
+			"ext4":  "wallet-topup",
+			"ext10": "v1",
+		},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Fatalf("ExtractTokenFromWebhook() mismatch:\nwant %+v\ngot  %+v", want, result)
+	}
+}
+
+func TestExtractTokenFromWebhook_Rejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*platon.WebhookForm)
+		wantErr string
+	}{
+		{name: "nil form", wantErr: "webhook form is nil"},
+		{name: "refund", mutate: func(f *platon.WebhookForm) { f.Status = "REFUND" }, wantErr: "unexpected callback status"},
+		{name: "regular sale", mutate: func(f *platon.WebhookForm) { f.Amount = "150.00" }, wantErr: "not a verification amount"},
+		{name: "missing token", mutate: func(f *platon.WebhookForm) { f.RCToken = " " }, wantErr: "rc_token is missing"},
+		{name: "bad card mask", mutate: func(f *platon.WebhookForm) { f.Card = "4111" }, wantErr: "tokenization:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var form *platon.WebhookForm
+				if tt.mutate != nil {
+					var err error
+					if form, err = ParseWebhookForm([]byte(verificationWebhookPayload)); err != nil {
+						t.Fatalf("ParseWebhookForm() error: %v", err)
+					}
+					tt.mutate(form)
+				}
+
+				_, err := ExtractTokenFromWebhook(form)
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ExtractTokenFromWebhook() error = %v, want %q", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
+
+func TestExtractTokenFromWebhook_Accepts3DSAndFixedAmount(t *testing.T) {
+	form, err := ParseWebhookForm([]byte(verificationWebhookPayload))
+	if err != nil {
+		t.Fatalf("ParseWebhookForm() error: %v", err)
+	}
+	form.Status = "3ds"
+	form.Amount = platon.VerifyFixedAmount.String()
+
+	if _, err := ExtractTokenFromWebhook(form); err != nil {
+		t.Fatalf("ExtractTokenFromWebhook() error: %v", err)
+	}
+}
+
+func TestVerification_WithTokenizationMetadata(t *testing.T) {
+	paymentID := "order-7"
+	req := &Request{
+		Merchant: &Merchant{
+			MerchantKey:     "CLIENT_KEY",
+			SecretKey:       "SECRET_KEY",
+			SuccessRedirect: "https://merchant.example/success",
+		},
+		PaymentData: &PaymentData{
+			PaymentID:   &paymentID,
+			Currency:    currency.UAH,
+			Description: "Verify card",
+			Metadata:    map[string]string{"ext1": "merchant-core", "ext4": "old"},
+		},
+	}
+
+	var form *platon.ClientServerVerificationForm
+	c := &client{}
+	_, err := c.Verification(
+		req,
+		WithTokenizationMetadata(map[string]string{"ext4": "user:42", "ext5": "tokenize"}),
+		DryRun(
+			func(_ string, payload any) {
+				form, _ = payload.(*platon.ClientServerVerificationForm)
+			},
+		),
+	)
+	if err != nil {
+		t.Fatalf("Verification() error: %v", err)
+	}
+	if form == nil {
+		t.Fatal("Verification() dry run payload is not a verification form")
+	}
+
+	for key, want := range map[string]string{"ext1": "merchant-core", "ext4": "user:42", "ext5": "tokenize"} {
+		if got := form.Fields[key]; got != want {
+			t.Fatalf("form field %s = %q, want %q", key, got, want)
+		}
+	}
+	if req.PaymentData.Metadata["ext4"] != "old" || len(req.PaymentData.Metadata) != 2 {
+		t.Fatalf("caller metadata was modified: %v", req.PaymentData.Metadata)
+	}
+
+	_, err = c.Verification(req, WithTokenizationMetadata(map[string]string{"user_id": "42"}), DryRun(func(string, any) {}))
+	if err == nil || !strings.Contains(err.Error(), "ext1..ext10") {
+		t.Fatalf("Verification() expected ext key error, got %v", err)
+	}
+}
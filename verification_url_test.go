@@ -25,10 +25,15 @@
 package go_platon
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/stremovskyy/go-platon/currency"
+	internalhttp "github.com/stremovskyy/go-platon/internal/http"
+	"github.com/stremovskyy/go-platon/log"
 	"github.com/stremovskyy/go-platon/platon"
 )
 
@@ -64,7 +69,7 @@ func TestResolveClientServerVerificationURL_UsesLocationHeader(t *testing.T) {
 		},
 	}
 
-	urlResult, err := resolveClientServerVerificationURL(form)
+	urlResult, err := resolveClientServerVerificationURL(context.Background(), form, nil, nil)
 	if err != nil {
 		t.Fatalf("resolveClientServerVerificationURL() error: %v", err)
 	}
@@ -72,3 +77,115 @@ func TestResolveClientServerVerificationURL_UsesLocationHeader(t *testing.T) {
 		t.Fatalf("URL mismatch: want %q, got %q", wantURL, urlResult.String())
 	}
 }
+
+func TestResolveClientServerVerificationURL_MasksConfiguredKeys(t *testing.T) {
+	previousLevel := log.GetLevel()
+	log.SetLevel(log.LevelDebug)
+	t.Cleanup(func() { log.SetLevel(previousLevel) })
+
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Location", "https://secure.platononline.com/payment/purchase?token=ABC123")
+				w.WriteHeader(http.StatusFound)
+			},
+		),
+	)
+	defer server.Close()
+
+	form := &platon.ClientServerVerificationForm{
+		Method:   http.MethodPost,
+		Endpoint: server.URL,
+		Fields: map[string]string{
+			"payment": "CC",
+			"key":     "client",
+			"data":    "payer-secret-payload",
+			"sign":    "signature",
+		},
+	}
+
+	options := internalhttp.DefaultOptions()
+	options.SensitiveLogKeys = []string{"data"}
+	sink := &logCaptureSink{}
+
+	if _, err := resolveClientServerVerificationURL(context.Background(), form, internalhttp.NewClient(options), sink); err != nil {
+		t.Fatalf("resolveClientServerVerificationURL() error: %v", err)
+	}
+
+	logged := strings.Join(sink.lines, "\n")
+	if !strings.Contains(logged, "payment=CC") {
+		t.Fatalf("request body was not logged: %q", logged)
+	}
+	for _, secret := range []string{"payer-secret-payload", "signature"} {
+		if strings.Contains(logged, secret) {
+			t.Fatalf("log leaks %q: %q", secret, logged)
+		}
+	}
+}
+
+func TestVerification_PurchaseURLFromOverriddenBaseURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		body     func(base string) string
+		want     func(base string) string
+	}{
+		{
+			name: "absolute URL in body",
+			body: func(base string) string {
+				return `<html><a href="` + base + `/payment/purchase?token=SANDBOX1">continue</a></html>`
+			},
+			want: func(base string) string { return base + "/payment/purchase?token=SANDBOX1" },
+		},
+		{
+			name: "relative URL in body",
+			body: func(string) string { return `<html><a href="/payment/purchase?token=SANDBOX2">continue</a></html>` },
+			want: func(base string) string { return base + "/payment/purchase?token=SANDBOX2" },
+		},
+		{
+			name:     "relative location",
+			location: "/payment/purchase?token=SANDBOX3",
+			want:     func(base string) string { return base + "/payment/purchase?token=SANDBOX3" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var base string
+				server := httptest.NewServer(
+					http.HandlerFunc(
+						func(w http.ResponseWriter, r *http.Request) {
+							if tt.location != "" {
+								w.Header().Set("Location", tt.location)
+								w.WriteHeader(http.StatusFound)
+								return
+							}
+							w.Header().Set("Content-Type", "text/html")
+							_, _ = w.Write([]byte(tt.body(base)))
+						},
+					),
+				)
+				defer server.Close()
+				base = server.URL
+
+				got, err := NewClient(WithBaseURL(base)).Verification(
+					&Request{
+						Merchant: &Merchant{
+							MerchantKey:     "CLIENT_KEY",
+							SecretKey:       "CLIENT_PASS",
+							SuccessRedirect: "https://merchant.example/success",
+						},
+						PaymentData: &PaymentData{PaymentID: ref("order-1"), Currency: currency.UAH, Description: "verify"},
+					},
+				)
+				if err != nil {
+					t.Fatalf("Verification() error: %v", err)
+				}
+				if got.String() != tt.want(base) {
+					t.Fatalf("URL mismatch: want %q, got %q", tt.want(base), got.String())
+				}
+			},
+		)
+	}
+}
@@ -25,7 +25,10 @@
 package go_platon
 
 import (
+	"net/url"
 	"testing"
+
+	"github.com/stremovskyy/go-platon/platon"
 )
 
 const webhookFormPayload = "id=47097-87770-07123&order=47097-87309-6110&status=SALE&card=411111%2A%2A%2A%2A1111&description=test&amount=0.40&currency=UAH&email=&date=2026-02-13+10%3A32%3A57&ip=250.137.176.130&sign=582d658d7d422e76b2639fac131d093e"
@@ -46,3 +49,49 @@ func TestParseWebhookForm(t *testing.T) {
 		t.Fatalf("card mismatch: got %q", form.Card)
 	}
 }
+
+func TestReference_RoundTripsFromRequestToCallback(t *testing.T) {
+	var captured *platon.Request
+
+	req := newCardPANPaymentRequest()
+	req.PaymentData.Reference = ref("INV-2026-0042")
+	req.PaymentData.Metadata = map[string]string{"ext4": "wallet-topup", "ext10": "ignored"}
+
+	c := &client{clock: testClock}
+	if _, err := c.Payment(
+		req, DryRun(
+			func(_ string, payload any) {
+				captured, _ = payload.(*platon.Request)
+			},
+		),
+	); err != nil {
+		t.Fatalf("Payment() error: %v", err)
+	}
+	signed, err := captured.SignAndPrepare()
+	if err != nil {
+		t.Fatalf("SignAndPrepare() error: %v", err)
+	}
+
+	sent := signed.ToMap()
+	if sent[platon.ReferenceExtField] != "INV-2026-0042" {
+		t.Fatalf("request %s = %v, want reference", platon.ReferenceExtField, sent[platon.ReferenceExtField])
+	}
+	if sent["ext4"] != "wallet-topup" {
+		t.Fatalf("other ext fields must be kept, ext4 = %v", sent["ext4"])
+	}
+
+	// Platon echoes ext fields back in the callback.
+	callback := url.Values{
+		"order":                  {"order-1"},
+		"status":                 {"SALE"},
+		"card":                   {"411111****1111"},
+		platon.ReferenceExtField: {sent[platon.ReferenceExtField].(string)},
+	}
+	form, err := ParseWebhookForm([]byte(callback.Encode()))
+	if err != nil {
+		t.Fatalf("ParseWebhookForm() error: %v", err)
+	}
+	if form.Reference() != "INV-2026-0042" {
+		t.Fatalf("Reference() = %q, want %q", form.Reference(), "INV-2026-0042")
+	}
+}
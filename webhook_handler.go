@@ -0,0 +1,218 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	internalhttp "github.com/stremovskyy/go-platon/internal/http"
+	"github.com/stremovskyy/go-platon/log"
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+// WebhookEventType is the kind of a Platon callback, taken from its status.
+// It is the same type as platon.CallbackStatus.
+type WebhookEventType = platon.CallbackStatus
+
+const (
+	WebhookEventSale       = platon.CallbackStatusSale
+	WebhookEventCapture    = platon.CallbackStatusCapture
+	WebhookEventCreditVoid = platon.CallbackStatusCreditVoid
+	WebhookEventRefund     = platon.CallbackStatusRefund
+	WebhookEventChargeback = platon.CallbackStatusChargeback
+	WebhookEvent3DS        = platon.CallbackStatus3DS
+	WebhookEventReversal   = platon.CallbackStatusReversal
+	// WebhookEventUnknown is used for correctly signed callbacks with a status
+	// this package does not know yet. They are still dispatched.
+	WebhookEventUnknown = platon.CallbackStatusUnknown
+)
+
+// ParseWebhookEventType maps a callback status to a WebhookEventType. It is
+// platon.ParseCallbackStatus.
+func ParseWebhookEventType(status string) WebhookEventType {
+	return platon.ParseCallbackStatus(status)
+}
+
+// WebhookEvent is a verified Platon callback.
+type WebhookEvent struct {
+	Type WebhookEventType
+	Form *platon.WebhookForm
+}
+
+// WebhookFunc handles a verified callback. A non-nil error makes the handler
+// respond 500 so that Platon retries the callback.
+type WebhookFunc func(ctx context.Context, event WebhookEvent) error
+
+// WebhookSecretLookup returns the merchant secret used to verify form, e.g.
+// by looking up the order in merchant storage.
+type WebhookSecretLookup func(ctx context.Context, form *platon.WebhookForm) (string, error)
+
+// WebhookPayerEmailLookup returns the payer email of the original payment.
+// Platon may send callbacks with an empty email while the signature still
+// covers it.
+type WebhookPayerEmailLookup func(ctx context.Context, form *platon.WebhookForm) (string, error)
+
+// WebhookHandlerOption configures WebhookHandler.
+type WebhookHandlerOption func(*webhookHandler)
+
+// WithWebhookSecretLookup resolves the secret per callback instead of using
+// the static secret passed to WebhookHandler.
+func WithWebhookSecretLookup(lookup WebhookSecretLookup) WebhookHandlerOption {
+	return func(h *webhookHandler) {
+		h.secretLookup = lookup
+	}
+}
+
+// WithWebhookPayerEmail sets a resolver for the payer email used in the
+// signature.
+func WithWebhookPayerEmail(lookup WebhookPayerEmailLookup) WebhookHandlerOption {
+	return func(h *webhookHandler) {
+		h.payerEmail = lookup
+	}
+}
+
+// WithWebhookMaxBodyBytes limits the callback body size (64 KiB by default).
+func WithWebhookMaxBodyBytes(n int64) WebhookHandlerOption {
+	return func(h *webhookHandler) {
+		if n > 0 {
+			h.maxBodyBytes = n
+		}
+	}
+}
+
+const defaultWebhookMaxBodyBytes = 64 << 10
+
+type webhookHandler struct {
+	secret       string
+	secretLookup WebhookSecretLookup
+	payerEmail   WebhookPayerEmailLookup
+	maxBodyBytes int64
+	fn           WebhookFunc
+	logger       *log.Logger
+}
+
+// WebhookHandler returns an http.Handler that accepts Platon callbacks,
+// verifies their signature with secret and passes them to fn.
+//
+// It responds 405 to non-POST requests, 415 to non form-urlencoded bodies,
+// 413 to bodies above the size limit, 400 to unparsable payloads, 403 to bad
+// signatures and 500 when fn (or a lookup) fails; 200 otherwise.
+func WebhookHandler(secret string, fn WebhookFunc, opts ...WebhookHandlerOption) http.Handler {
+	h := &webhookHandler{
+		secret:       secret,
+		maxBodyBytes: defaultWebhookMaxBodyBytes,
+		fn:           fn,
+		logger:       log.NewLogger("Platon Webhook: "),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(h)
+		}
+	}
+
+	return h
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !internalhttp.IsFormURLEncodedContentType(r.Header.Get("Content-Type")) {
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "cannot read request body", http.StatusBadRequest)
+		return
+	}
+
+	form, err := platon.ParseWebhookForm(body)
+	if err != nil {
+		h.logger.Error("cannot parse callback: %v", err)
+		http.Error(w, "invalid callback payload", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	secret, email, err := h.credentials(ctx, form)
+	if err != nil {
+		h.logger.Error("cannot resolve callback credentials for order %q: %v", form.Order, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	ok, err := form.VerifySign(secret, email)
+	if err != nil || !ok {
+		h.logger.Error("invalid callback signature for order %q: %v", form.Order, err)
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	if h.fn != nil {
+		event := WebhookEvent{Type: form.ParsedStatus(), Form: form}
+		if err := h.fn(ctx, event); err != nil {
+			h.logger.Error("callback handler failed for order %q: %v", form.Order, err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, "OK")
+}
+
+func (h *webhookHandler) credentials(ctx context.Context, form *platon.WebhookForm) (string, string, error) {
+	secret := h.secret
+	if h.secretLookup != nil {
+		var err error
+		if secret, err = h.secretLookup(ctx, form); err != nil {
+			return "", "", fmt.Errorf("secret lookup: %w", err)
+		}
+	}
+
+	var email string
+	if h.payerEmail != nil {
+		var err error
+		if email, err = h.payerEmail(ctx, form); err != nil {
+			return "", "", fmt.Errorf("payer email lookup: %w", err)
+		}
+	}
+
+	return secret, email, nil
+}
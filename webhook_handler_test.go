@@ -0,0 +1,218 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2026 Anton Stremovskyy
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package go_platon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stremovskyy/go-platon/platon"
+)
+
+func signedWebhookBody(t *testing.T, status string) string {
+	t.Helper()
+
+	values := url.Values{
+		"id":       {"47097-87770-07123"},
+		"order":    {"order-1"},
+		"status":   {status},
+		"card":     {"411111****1111"},
+		"amount":   {"0.40"},
+		"currency": {"UAH"},
+		"email":    {"payer@example.com"},
+	}
+	sign, err := platon.ParseWebhookValues(values).ExpectedSign("SECRET", "")
+	if err != nil {
+		t.Fatalf("ExpectedSign() error: %v", err)
+	}
+	values.Set("sign", sign)
+
+	return values.Encode()
+}
+
+func postWebhook(h http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/platon/callback", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestWebhookHandler_ValidCallback(t *testing.T) {
+	var got WebhookEvent
+	h := WebhookHandler(
+		"SECRET", func(_ context.Context, event WebhookEvent) error {
+			got = event
+			return nil
+		},
+	)
+
+	rec := postWebhook(h, signedWebhookBody(t, "REFUND"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %q", rec.Code, rec.Body.String())
+	}
+	if got.Type != WebhookEventRefund {
+		t.Fatalf("event type = %q, want %q", got.Type, WebhookEventRefund)
+	}
+	if got.Form == nil || got.Form.Order != "order-1" {
+		t.Fatalf("event form mismatch: %+v", got.Form)
+	}
+}
+
+func TestWebhookHandler_TamperedSign(t *testing.T) {
+	called := false
+	h := WebhookHandler(
+		"SECRET", func(context.Context, WebhookEvent) error {
+			called = true
+			return nil
+		},
+	)
+
+	body := strings.Replace(signedWebhookBody(t, "SALE"), "amount=0.40", "amount=400.00", 1)
+	body = strings.Replace(body, "status=SALE", "status=REFUND", 1)
+	rec := postWebhook(h, body)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if called {
+		t.Fatal("handler must not run for an invalid signature")
+	}
+}
+
+func TestWebhookHandler_UnknownStatusIsDispatched(t *testing.T) {
+	var got WebhookEventType
+	h := WebhookHandler(
+		"SECRET", func(_ context.Context, event WebhookEvent) error {
+			got = event.Type
+			return nil
+		},
+	)
+
+	rec := postWebhook(h, signedWebhookBody(t, "SOMETHING_NEW"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %q", rec.Code, rec.Body.String())
+	}
+	if got != WebhookEventUnknown {
+		t.Fatalf("event type = %q, want %q", got, WebhookEventUnknown)
+	}
+}
+
+func TestWebhookHandler_CaptureAndCreditVoidAreTyped(t *testing.T) {
+	for status, want := range map[string]WebhookEventType{"CAPTURE": WebhookEventCapture, "CREDITVOID": WebhookEventCreditVoid} {
+		var got WebhookEventType
+		h := WebhookHandler(
+			"SECRET", func(_ context.Context, event WebhookEvent) error {
+				got = event.Type
+				return nil
+			},
+		)
+
+		if rec := postWebhook(h, signedWebhookBody(t, status)); rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body %q", status, rec.Code, rec.Body.String())
+		}
+		if got != want || got != platon.ParseCallbackStatus(status) {
+			t.Fatalf("%s: event type = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestWebhookHandler_HandlerErrorReturns500(t *testing.T) {
+	h := WebhookHandler(
+		"SECRET", func(context.Context, WebhookEvent) error {
+			return errors.New("db is down")
+		},
+	)
+
+	if rec := postWebhook(h, signedWebhookBody(t, "SALE")); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestWebhookHandler_SecretAndEmailLookup(t *testing.T) {
+	values := url.Values{
+		"order":  {"order-2"},
+		"status": {"SALE"},
+		"card":   {"411111****1111"},
+	}
+	sign, err := platon.ParseWebhookValues(values).ExpectedSign("PER_ORDER_SECRET", "payer@example.com")
+	if err != nil {
+		t.Fatalf("ExpectedSign() error: %v", err)
+	}
+	values.Set("sign", sign)
+
+	h := WebhookHandler(
+		"", func(context.Context, WebhookEvent) error { return nil },
+		WithWebhookSecretLookup(
+			func(_ context.Context, form *platon.WebhookForm) (string, error) {
+				if form.Order != "order-2" {
+					return "", errors.New("unknown order")
+				}
+				return "PER_ORDER_SECRET", nil
+			},
+		),
+		WithWebhookPayerEmail(
+			func(context.Context, *platon.WebhookForm) (string, error) {
+				return "payer@example.com", nil
+			},
+		),
+	)
+
+	if rec := postWebhook(h, values.Encode()); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestWebhookHandler_RejectsBadRequests(t *testing.T) {
+	h := WebhookHandler("SECRET", nil, WithWebhookMaxBodyBytes(64))
+
+	req := httptest.NewRequest(http.MethodGet, "/platon/callback", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != http.MethodPost {
+		t.Fatalf("GET: status = %d, Allow = %q", rec.Code, rec.Header().Get("Allow"))
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/platon/callback", strings.NewReader(`{"order":"1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("JSON: status = %d, want %d", rec.Code, http.StatusUnsupportedMediaType)
+	}
+
+	if rec := postWebhook(h, signedWebhookBody(t, "SALE")); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized: status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+
+	if rec := postWebhook(h, ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("empty: status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}